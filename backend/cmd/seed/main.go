@@ -0,0 +1,348 @@
+// backend/cmd/seed/main.go
+// ==========================================================================
+// Demo data for local development. Applies the schema migrations, then, if
+// the database has no users or tickets yet, creates users in every role,
+// tags, FAQs and a spread of tickets in each status with comments. When file
+// storage is configured, a few tickets also get small text attachments.
+// Running it again against a populated database does nothing.
+//
+// Usage (from backend/, with the same environment as the server):
+//
+//	go run ./cmd/seed
+// ==========================================================================
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/henrythedeveloper/it-ticket-system/internal/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/file"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// demoPassword is the password of every seeded account.
+const demoPassword = "password"
+
+// demoUser is a seeded account.
+type demoUser struct {
+	key   string // Reference used by the ticket and comment data below
+	name  string
+	email string
+	role  models.UserRole
+}
+
+// demoComment is a seeded ticket comment.
+type demoComment struct {
+	author   string // demoUser key
+	text     string
+	internal bool
+	after    time.Duration // Offset from the ticket's creation
+}
+
+// demoTicket is a seeded ticket.
+type demoTicket struct {
+	submitterName string
+	email         string
+	issueType     string
+	urgency       models.TicketUrgency
+	status        models.TicketStatus
+	assignee      string        // demoUser key; "" for unassigned
+	age           time.Duration // How long ago the ticket was submitted
+	subject       string
+	description   string
+	resolution    string
+	tags          []string
+	comments      []demoComment
+	attachment    string // Name of a text attachment to store, if storage is configured
+}
+
+var demoUsers = []demoUser{
+	{key: "admin", name: "Alice Admin", email: "admin@example.com", role: models.RoleAdmin},
+	{key: "bob", name: "Bob Staff", email: "staff@example.com", role: models.RoleStaff},
+	{key: "taylor", name: "Taylor Nguyen", email: "taylor@example.com", role: models.RoleStaff},
+	{key: "user", name: "Uma User", email: "user@example.com", role: models.RoleUser},
+}
+
+var demoTags = []string{"Network", "Hardware", "Software", "Account", "Printer", "VPN", "Email", "Onboarding"}
+
+var demoFAQs = []struct{ question, answer, category string }{
+	{"How do I reset my password?", "Staff and admins can use the \"Forgot Password\" link on the login page. Submitters do not need an account.", "Account"},
+	{"How do I connect to the VPN?", "Install the VPN client from the software portal, sign in with your work email and choose the nearest gateway.", "Network"},
+	{"My printer shows \"offline\". What should I try first?", "Check that the printer is powered on and connected to the office network, then remove and re-add it from your printer settings.", "Hardware"},
+	{"How long does it take to get a response?", "Critical and High urgency tickets are triaged within the hour during business hours; other tickets within one business day.", "General"},
+	{"Can I add more details after submitting a ticket?", "Yes. Reply to any email about your ticket or use the link in your confirmation email to add comments and files.", "General"},
+}
+
+var demoTickets = []demoTicket{
+	{
+		submitterName: "Charlie Park", email: "charlie@example.com", issueType: "Hardware", urgency: models.UrgencyHigh,
+		status: models.StatusOpen, age: 3 * time.Hour,
+		subject: "Printer on the 2nd floor keeps jamming", description: "The shared printer near room 201 jams on every second page. We have tried new paper.",
+		tags: []string{"Printer", "Hardware"}, attachment: "printer-error-log.txt",
+	},
+	{
+		submitterName: "Dana Lopez", email: "dana@example.com", issueType: "Network", urgency: models.UrgencyMedium,
+		status: models.StatusInProgress, assignee: "bob", age: 26 * time.Hour,
+		subject: "WiFi drops in the conference room", description: "Laptops lose the WiFi connection every few minutes in the large conference room.",
+		tags: []string{"Network"},
+		comments: []demoComment{
+			{author: "bob", text: "I'm checking the access point logs for that room.", after: 2 * time.Hour},
+			{author: "bob", text: "The access point firmware is outdated; update scheduled for tonight.", internal: true, after: 5 * time.Hour},
+		},
+	},
+	{
+		submitterName: "Eve Martin", email: "eve@example.com", issueType: "Software", urgency: models.UrgencyLow,
+		status: models.StatusOpen, assignee: "taylor", age: 50 * time.Hour,
+		subject: "Accounting software needs an update", description: "The accounting application asks for an update but I don't have permission to install it.",
+		tags: []string{"Software"},
+	},
+	{
+		submitterName: "Frank Osei", email: "frank@example.com", issueType: "Hardware", urgency: models.UrgencyCritical,
+		status: models.StatusInProgress, assignee: "admin", age: 90 * time.Minute,
+		subject: "File server is not responding", description: "Nobody in the finance team can open the shared drive. It started about an hour ago.",
+		tags: []string{"Hardware", "Network"}, attachment: "ping-results.txt",
+		comments: []demoComment{
+			{author: "admin", text: "The server is reachable but the disk array is degraded. Working on it.", after: 30 * time.Minute},
+		},
+	},
+	{
+		submitterName: "Grace Kim", email: "grace@example.com", issueType: "Network", urgency: models.UrgencyHigh,
+		status: models.StatusWaitingOnCustomer, assignee: "taylor", age: 72 * time.Hour,
+		subject: "Cannot connect to the VPN from home", description: "The VPN client says \"authentication failed\" even though my password works elsewhere.",
+		tags: []string{"VPN", "Network"},
+		comments: []demoComment{
+			{author: "taylor", text: "Could you send a screenshot of the error and the client version shown under Help > About?", after: 4 * time.Hour},
+		},
+	},
+	{
+		submitterName: "Hiro Tanaka", email: "hiro@example.com", issueType: "Account", urgency: models.UrgencyMedium,
+		status: models.StatusClosed, assignee: "bob", age: 8 * 24 * time.Hour,
+		subject: "Locked out of my email account", description: "I entered the wrong password too many times and now my account is locked.",
+		resolution: "Unlocked the account and asked the user to set a new password.",
+		tags:       []string{"Account", "Email"},
+		comments: []demoComment{
+			{author: "bob", text: "Your account is unlocked. Please choose a new password at your next sign-in.", after: 3 * time.Hour},
+		},
+	},
+	{
+		submitterName: "Ines Duarte", email: "ines@example.com", issueType: "Onboarding", urgency: models.UrgencyLow,
+		status: models.StatusClosed, assignee: "taylor", age: 15 * 24 * time.Hour,
+		subject: "Laptop and accounts for a new hire", description: "A new analyst starts next Monday and needs a laptop, email and VPN access.",
+		resolution: "Laptop imaged and handed over; email and VPN accounts created.",
+		tags:       []string{"Onboarding", "Hardware"},
+	},
+	{
+		submitterName: "Jon Berg", email: "jon@example.com", issueType: "Software", urgency: models.UrgencyMedium,
+		status: models.StatusOpen, age: 20 * time.Minute,
+		subject: "Spreadsheet add-in crashes on startup", description: "Since this morning the reporting add-in crashes as soon as I open a spreadsheet.",
+		tags: []string{"Software"},
+	},
+}
+
+func main() {
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	if err := run(context.Background()); err != nil {
+		slog.Error("Seeding failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run connects, migrates and seeds the database unless it already has data.
+func run(ctx context.Context) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+	if err := database.Migrate(ctx); err != nil {
+		return err
+	}
+
+	var hasData bool
+	if err := database.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM users) OR EXISTS(SELECT 1 FROM tickets)`).Scan(&hasData); err != nil {
+		return fmt.Errorf("failed to check for existing data: %w", err)
+	}
+	if hasData {
+		slog.Info("Database already contains users or tickets; nothing to seed")
+		return nil
+	}
+
+	passwordHash, err := auth.NewService(cfg.Auth).HashPassword(demoPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash demo password: %w", err)
+	}
+
+	tx, err := database.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	userIDs, err := seedUsers(ctx, tx, passwordHash)
+	if err != nil {
+		return err
+	}
+	tagIDs, err := seedTags(ctx, tx)
+	if err != nil {
+		return err
+	}
+	if err := seedFAQs(ctx, tx); err != nil {
+		return err
+	}
+	ticketIDs, err := seedTickets(ctx, tx, userIDs, tagIDs)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit demo data: %w", err)
+	}
+	slog.Info("Seeded demo data", "users", len(demoUsers), "tags", len(demoTags), "faqs", len(demoFAQs), "tickets", len(demoTickets))
+
+	seedAttachments(ctx, cfg, database, ticketIDs, userIDs)
+
+	for _, u := range demoUsers {
+		slog.Info("Demo account", "email", u.email, "role", u.role, "password", demoPassword)
+	}
+	return nil
+}
+
+// seedUsers creates the demo accounts and returns their IDs by key.
+func seedUsers(ctx context.Context, tx pgx.Tx, passwordHash string) (map[string]string, error) {
+	ids := make(map[string]string, len(demoUsers))
+	for _, u := range demoUsers {
+		var id string
+		if err := tx.QueryRow(ctx, `
+            INSERT INTO users (name, email, password_hash, role) VALUES ($1, $2, $3, $4)
+            RETURNING id`, u.name, u.email, passwordHash, u.role).Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to create user %s: %w", u.email, err)
+		}
+		ids[u.key] = id
+	}
+	return ids, nil
+}
+
+// seedTags creates the demo tags and returns their IDs by name.
+func seedTags(ctx context.Context, tx pgx.Tx) (map[string]string, error) {
+	ids := make(map[string]string, len(demoTags))
+	for _, name := range demoTags {
+		var id string
+		if err := tx.QueryRow(ctx, `INSERT INTO tags (name) VALUES ($1) RETURNING id`, name).Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to create tag %s: %w", name, err)
+		}
+		ids[name] = id
+	}
+	return ids, nil
+}
+
+// seedFAQs creates the demo FAQ entries.
+func seedFAQs(ctx context.Context, tx pgx.Tx) error {
+	for _, f := range demoFAQs {
+		if _, err := tx.Exec(ctx, `INSERT INTO faq_entries (question, answer, category) VALUES ($1, $2, $3)`,
+			f.question, f.answer, f.category); err != nil {
+			return fmt.Errorf("failed to create FAQ %q: %w", f.question, err)
+		}
+	}
+	return nil
+}
+
+// seedTickets creates the demo tickets with their tags and comments and
+// returns the ticket IDs in demoTickets order.
+func seedTickets(ctx context.Context, tx pgx.Tx, userIDs, tagIDs map[string]string) ([]string, error) {
+	now := time.Now()
+	ids := make([]string, 0, len(demoTickets))
+	for _, t := range demoTickets {
+		createdAt := now.Add(-t.age)
+		var assigneeID, assignedAt, closedAt, resolution, waitingSince, slaPausedAt any
+		if t.assignee != "" {
+			assigneeID, assignedAt = userIDs[t.assignee], createdAt.Add(15*time.Minute)
+		}
+		switch t.status {
+		case models.StatusClosed:
+			closedAt, resolution = createdAt.Add(t.age/2), t.resolution
+			slaPausedAt = closedAt
+		case models.StatusWaitingOnCustomer:
+			waitingSince = now.Add(-24 * time.Hour)
+			slaPausedAt = waitingSince
+		}
+
+		var id string
+		if err := tx.QueryRow(ctx, `
+            INSERT INTO tickets (submitter_name, end_user_email, issue_type, urgency, subject, description, status,
+                assigned_to_user_id, assigned_at, accepted_at, created_at, updated_at, closed_at, resolution_notes, waiting_since)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9, $10, $10, $11, $12, $13)
+            RETURNING id`,
+			t.submitterName, t.email, t.issueType, t.urgency, t.subject, t.description, t.status,
+			assigneeID, assignedAt, createdAt, closedAt, resolution, waitingSince).Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to create ticket %q: %w", t.subject, err)
+		}
+
+		if slaPausedAt != nil { // The SLA clock stops while waiting or closed
+			if _, err := tx.Exec(ctx, `
+                INSERT INTO ticket_sla_pauses (ticket_id, status, paused_at) VALUES ($1, $2, $3)`, id, t.status, slaPausedAt); err != nil {
+				return nil, fmt.Errorf("failed to pause SLA clock for ticket %q: %w", t.subject, err)
+			}
+		}
+
+		for _, tag := range t.tags {
+			if _, err := tx.Exec(ctx, `INSERT INTO ticket_tags (ticket_id, tag_id) VALUES ($1, $2)`, id, tagIDs[tag]); err != nil {
+				return nil, fmt.Errorf("failed to tag ticket %q: %w", t.subject, err)
+			}
+		}
+		for _, c := range t.comments {
+			if _, err := tx.Exec(ctx, `
+                INSERT INTO ticket_updates (ticket_id, user_id, comment, is_internal_note, created_at)
+                VALUES ($1, $2, $3, $4, $5)`, id, userIDs[c.author], c.text, c.internal, createdAt.Add(c.after)); err != nil {
+				return nil, fmt.Errorf("failed to comment on ticket %q: %w", t.subject, err)
+			}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// seedAttachments stores the demo text attachments. It is skipped when no
+// storage bucket is configured, and failures are logged without undoing the
+// rest of the seed.
+func seedAttachments(ctx context.Context, cfg *config.Config, database *db.DB, ticketIDs []string, userIDs map[string]string) {
+	if cfg.Storage.Bucket == "" {
+		slog.Info("No storage bucket configured; skipping demo attachments")
+		return
+	}
+	fileService, err := file.NewService(cfg.Storage)
+	if err != nil {
+		slog.Warn("File storage unavailable; skipping demo attachments", "error", err)
+		return
+	}
+
+	for i, t := range demoTickets {
+		if t.attachment == "" {
+			continue
+		}
+		content := fmt.Sprintf("Demo attachment for ticket %q.\nGenerated %s.\n", t.subject, time.Now().Format(time.RFC3339))
+		storagePath := fmt.Sprintf("tickets/%s/%s_%s", ticketIDs[i], uuid.New().String(), t.attachment)
+		if _, err := fileService.UploadFile(ctx, storagePath, strings.NewReader(content), int64(len(content)), "text/plain"); err != nil {
+			slog.Warn("Failed to store demo attachment", "filename", t.attachment, "error", err)
+			continue
+		}
+		if _, err := database.Pool.Exec(ctx, `
+            INSERT INTO attachments (ticket_id, filename, storage_path, mime_type, size, uploaded_by_user_id, uploaded_by_role)
+            VALUES ($1, $2, $3, 'text/plain', $4, $5, $6)`,
+			ticketIDs[i], t.attachment, storagePath, len(content), userIDs["bob"], models.RoleStaff); err != nil {
+			slog.Warn("Failed to record demo attachment", "filename", t.attachment, "error", err)
+		}
+	}
+}
@@ -12,10 +12,16 @@ import (
 	"time"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/api"
+	"github.com/henrythedeveloper/it-ticket-system/internal/captcha"
 	"github.com/henrythedeveloper/it-ticket-system/internal/config"
 	"github.com/henrythedeveloper/it-ticket-system/internal/db"
 	"github.com/henrythedeveloper/it-ticket-system/internal/email"
 	"github.com/henrythedeveloper/it-ticket-system/internal/file"
+	"github.com/henrythedeveloper/it-ticket-system/internal/jobs"
+	"github.com/henrythedeveloper/it-ticket-system/internal/logging"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preferences"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preview"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
 	"github.com/labstack/echo/v4" // Import Echo
 )
 
@@ -46,6 +52,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Rebuild the logger with PII redaction now that configuration is known.
+	if cfg.Logging.RedactPII {
+		opts.ReplaceAttr = logging.RedactAttr
+		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, &opts)))
+		slog.Info("Log PII redaction enabled")
+	}
+
 	// --- Initialize Database ---
 	database, err := db.Connect(cfg.Database)
 	if err != nil {
@@ -55,6 +68,20 @@ func main() {
 	defer database.Close()
 	slog.Info("Database connection established")
 
+	// --- Apply Schema Migrations ---
+	if cfg.Database.AutoMigrate {
+		if err := database.Migrate(context.Background()); err != nil {
+			slog.Error("Failed to apply database migrations. Exiting.", "error", err)
+			os.Exit(1)
+		}
+	} else {
+		slog.Info("Automatic database migrations disabled")
+	}
+	if err := database.EnsureTicketNumberStart(context.Background(), cfg.Tickets.NumberStart); err != nil {
+		slog.Error("Failed to apply TICKET_NUMBER_START. Exiting.", "error", err)
+		os.Exit(1)
+	}
+
 	// --- Initialize Email Service ---
 	emailService, err := email.NewService(cfg.Email, cfg.Server.PortalBaseURL)
 	if err != nil {
@@ -63,6 +90,25 @@ func main() {
 	}
 	slog.Info("Email service initialized")
 
+	// Startup self-check: warn (but keep running) if the email provider is unreachable.
+	checkCtx, cancelCheck := context.WithTimeout(context.Background(), 5*time.Second)
+	if checkErr := emailService.CheckConnection(checkCtx); checkErr != nil {
+		slog.Warn("Email provider connectivity check failed; notifications may not be delivered", "error", checkErr)
+	} else {
+		slog.Info("Email provider connectivity check passed")
+	}
+	cancelCheck()
+
+	// Queue emails in the persistent outbox; the outbox worker delivers them with retry.
+	var emailOutbox *email.OutboxService
+	if cfg.EmailOutbox.Enabled {
+		emailOutbox = email.NewOutboxService(database, emailService, cfg.EmailOutbox)
+		emailService = emailOutbox
+		slog.Info("Email outbox enabled", "pollInterval", cfg.EmailOutbox.PollInterval)
+	}
+	// Drop notification emails the recipient has opted out of.
+	emailService = email.NewPreferenceFilter(emailService, preferences.NewService(database))
+
 	// --- Initialize File Storage Service ---
 	fileService, err := file.NewService(cfg.Storage)
 	if err != nil {
@@ -71,8 +117,26 @@ func main() {
 	}
 	slog.Info("File storage service initialized", "endpoint", cfg.Storage.Endpoint)
 
+	// --- Initialize CAPTCHA Service ---
+	captchaService, err := captcha.NewService(cfg.Captcha)
+	if err != nil {
+		slog.Error("Failed to initialize CAPTCHA service. Exiting.", "error", err)
+		os.Exit(1)
+	}
+
+	// --- Initialize Attachment Preview Service ---
+	previewService, err := preview.NewService(cfg.Previews)
+	if err != nil {
+		slog.Error("Failed to initialize attachment preview service. Exiting.", "error", err)
+		os.Exit(1)
+	}
+
+	// --- Initialize Webhook Service ---
+	// Events are queued in webhook_deliveries and delivered with retry by the webhook job.
+	webhookService := webhook.NewService(database, cfg.Webhooks)
+
 	// --- Setup API Server ---
-	server := api.NewServer(database, emailService, fileService, cfg)
+	server := api.NewServer(database, emailService, fileService, captchaService, previewService, webhookService, cfg)
 	slog.Info("API server setup complete")
 
 	// --- Add Health Check Endpoint ---
@@ -86,6 +150,40 @@ func main() {
 	slog.Info("Registered /api/healthz endpoint")
 	// --- End Health Check ---
 
+	// --- Start Background Jobs ---
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if emailOutbox != nil {
+		jobs.NewEmailOutboxJob(emailOutbox, cfg.EmailOutbox.PollInterval).Start(jobsCtx)
+	}
+	if cfg.Retention.Enabled {
+		jobs.NewRetentionJob(database, fileService, cfg.Retention).Start(jobsCtx)
+	} else {
+		slog.Info("Attachment retention job disabled")
+	}
+	if cfg.Assignment.EscalationEnabled {
+		jobs.NewAssignmentEscalationJob(database, emailService, cfg.Assignment).Start(jobsCtx)
+	} else {
+		slog.Info("Assignment escalation job disabled")
+	}
+	if cfg.WaitingOnCustomer.RemindersEnabled {
+		jobs.NewWaitingOnCustomerJob(database, emailService, cfg.WaitingOnCustomer).Start(jobsCtx)
+	} else {
+		slog.Info("Waiting on Customer reminder job disabled")
+	}
+	jobs.NewStalledAttachmentsJob(database).Start(jobsCtx)
+	jobs.NewPasswordResetCleanupJob(database, cfg.Auth).Start(jobsCtx)
+	if webhookService.Enabled() {
+		jobs.NewWebhookDeliveryJob(webhookService, cfg.Webhooks.PollInterval).Start(jobsCtx)
+	} else {
+		slog.Info("Webhook delivery job disabled")
+	}
+	if cfg.SpikeAlerts.Enabled {
+		jobs.NewTicketSpikeJob(database, emailService, webhookService, server.Cache(), cfg.SpikeAlerts).Start(jobsCtx)
+	} else {
+		slog.Info("Ticket spike alert job disabled")
+	}
+
 	// --- Log Registered Routes (Use Debug level) ---
 	// This helper function should be defined in internal/api/server.go
 	// logRegisteredRoutes(echoInstance) // Assuming logRegisteredRoutes exists
@@ -111,6 +209,7 @@ func main() {
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	recSignal := <-quit
 	slog.Info("Received signal, initiating shutdown...", "signal", recSignal.String())
+	stopJobs()
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 	if err := server.Shutdown(shutdownCtx); err != nil {
@@ -56,14 +56,15 @@ require (
 	golang.org/x/exp v0.0.0-20230905200255-921286631fa9 // indirect
 	// Updated x/net to patch vulnerabilities (#2, #5, #9)
 	golang.org/x/net v0.38.0 // indirect
-	golang.org/x/sync v0.12.0 // indirect
+	golang.org/x/sync v0.12.0
 	golang.org/x/sys v0.31.0 // indirect; indirect // Auto-updated by crypto/net usually
-	golang.org/x/text v0.23.0 // indirect; indirect // Auto-updated by crypto/net usually
+	golang.org/x/text v0.23.0 // Unicode normalization of attachment filenames
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
 require (
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/google/uuid v1.4.0
 	github.com/redis/go-redis/v9 v9.8.0
 	github.com/resend/resend-go/v2 v2.18.0
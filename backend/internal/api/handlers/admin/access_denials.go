@@ -0,0 +1,137 @@
+// backend/internal/api/handlers/admin/access_denials.go
+// ==========================================================================
+// Admin report on authorization failures: the 403 responses recorded in the
+// audit log by auth.DenialAuditMiddleware, grouped per user and per route.
+// ==========================================================================
+
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultDenialReportDays = 7
+	maxDenialReportDays     = 90
+	maxDenialReportRows     = 100 // Users and routes listed, most denials first
+)
+
+// --- Handler Functions ---
+
+// GetAccessDenialReport summarizes recent authorization failures. Users whose
+// denials reach AUTH_DENIAL_REPORT_THRESHOLD are flagged.
+//
+// Query Parameters:
+//   - days: How many days back to report (1-90, default 7).
+//   - flagged: "true" to list only flagged users.
+//
+// Returns:
+//   - JSON APIResponse containing a models.AccessDenialReport, or 400 for invalid parameters.
+func (h *Handler) GetAccessDenialReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetAccessDenialReport")
+
+	days := defaultDenialReportDays
+	if param := c.QueryParam("days"); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed <= 0 || parsed > maxDenialReportDays {
+			return echo.NewHTTPError(http.StatusBadRequest, "days must be between 1 and 90.")
+		}
+		days = parsed
+	}
+	flaggedOnly := false
+	if param := c.QueryParam("flagged"); param != "" {
+		parsed, err := strconv.ParseBool(param)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "flagged must be true or false.")
+		}
+		flaggedOnly = parsed
+	}
+
+	report := models.AccessDenialReport{
+		Since:     time.Now().UTC().AddDate(0, 0, -days),
+		Threshold: h.config.Auth.DenialReportThreshold,
+		Users:     make([]models.AccessDenialUser, 0),
+		Routes:    make([]models.AccessDenialRoute, 0),
+	}
+	minDenials := 1
+	if flaggedOnly {
+		minDenials = report.Threshold
+	}
+
+	// --- 1. Total ---
+	if err := h.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*)::int FROM audit_log WHERE action = $1 AND created_at >= $2`,
+		audit.ActionAccessDenied, report.Since).Scan(&report.Total); err != nil {
+		logger.ErrorContext(ctx, "Failed to count access denials", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build access denial report.")
+	}
+
+	// --- 2. Per User ---
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT a.actor_user_id::text, u.name, u.email,
+               COUNT(*)::int, COUNT(DISTINCT a.resource_id)::int,
+               MIN(a.created_at), MAX(a.created_at)
+        FROM audit_log a
+        LEFT JOIN users u ON u.id = a.actor_user_id
+        WHERE a.action = $1 AND a.created_at >= $2 AND a.actor_user_id IS NOT NULL
+        GROUP BY a.actor_user_id, u.name, u.email
+        HAVING COUNT(*) >= $3
+        ORDER BY COUNT(*) DESC, MAX(a.created_at) DESC
+        LIMIT $4`, audit.ActionAccessDenied, report.Since, minDenials, maxDenialReportRows)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query access denials per user", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build access denial report.")
+	}
+	for rows.Next() {
+		var user models.AccessDenialUser
+		if err := rows.Scan(&user.UserID, &user.Name, &user.Email, &user.Denials, &user.Routes, &user.FirstDenied, &user.LastDenied); err != nil {
+			rows.Close()
+			logger.ErrorContext(ctx, "Failed to scan access denial user row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build access denial report.")
+		}
+		user.Flagged = user.Denials >= report.Threshold
+		report.Users = append(report.Users, user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating access denial user rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build access denial report.")
+	}
+
+	// --- 3. Per Route ---
+	rows, err = h.db.Pool.Query(ctx, `
+        SELECT COALESCE(metadata->>'method', ''), resource_id,
+               COUNT(*)::int, COUNT(DISTINCT actor_user_id)::int
+        FROM audit_log
+        WHERE action = $1 AND created_at >= $2
+        GROUP BY metadata->>'method', resource_id
+        ORDER BY COUNT(*) DESC, resource_id
+        LIMIT $3`, audit.ActionAccessDenied, report.Since, maxDenialReportRows)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query access denials per route", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build access denial report.")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var route models.AccessDenialRoute
+		if err := rows.Scan(&route.Method, &route.Route, &route.Denials, &route.Users); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan access denial route row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build access denial report.")
+		}
+		report.Routes = append(report.Routes, route)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating access denial route rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build access denial report.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: report})
+}
@@ -0,0 +1,222 @@
+// backend/internal/api/handlers/admin/assignment_rules.go
+// ==========================================================================
+// Admin handlers for managing email-domain assignment routing rules. New
+// tickets whose submitter email domain matches a rule are auto-assigned to
+// the rule's user (see ticket.applyRoutingRules).
+// ==========================================================================
+
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// assignmentRuleSelect lists the columns scanned by scanAssignmentRule.
+const assignmentRuleSelect = `
+        SELECT r.id, r.domain, r.assignee_user_id, u.name, r.position, r.enabled,
+               r.description, r.created_at, r.updated_at
+        FROM assignment_rules r
+        JOIN users u ON u.id = r.assignee_user_id`
+
+// --- Handler Functions ---
+
+// GetAssignmentRules lists all routing rules in evaluation order.
+//
+// Returns:
+//   - JSON APIResponse containing AssignmentRule objects or an error response.
+func (h *Handler) GetAssignmentRules(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetAssignmentRules")
+
+	rows, err := h.db.Pool.Query(ctx, assignmentRuleSelect+` ORDER BY r.position, r.created_at`)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query assignment rules", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assignment rules.")
+	}
+	defer rows.Close()
+
+	rules := make([]models.AssignmentRule, 0)
+	for rows.Next() {
+		rule, scanErr := scanAssignmentRule(rows)
+		if scanErr != nil {
+			logger.ErrorContext(ctx, "Failed to scan assignment rule", "error", scanErr)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process assignment rules.")
+		}
+		rules = append(rules, rule)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating assignment rules", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process assignment rules.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: rules})
+}
+
+// CreateAssignmentRule adds a routing rule.
+//
+// Request Body:
+//   - AssignmentRuleInput (domain and assignee_user_id are required).
+//
+// Returns:
+//   - JSON APIResponse containing the created AssignmentRule or an error response.
+func (h *Handler) CreateAssignmentRule(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "CreateAssignmentRule")
+
+	input, err := h.bindAssignmentRule(c)
+	if err != nil {
+		return err
+	}
+
+	var ruleID string
+	err = h.db.Pool.QueryRow(ctx, `
+        INSERT INTO assignment_rules (domain, assignee_user_id, position, enabled, description)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING id`,
+		input.Domain, input.AssigneeUserID, input.Position, *input.Enabled, input.Description,
+	).Scan(&ruleID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to insert assignment rule", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create assignment rule.")
+	}
+
+	rule, err := scanAssignmentRule(h.db.Pool.QueryRow(ctx, assignmentRuleSelect+` WHERE r.id = $1`, ruleID))
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to fetch created assignment rule", "ruleID", ruleID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Assignment rule created, but failed to retrieve it.")
+	}
+	logger.InfoContext(ctx, "Assignment rule created", "ruleID", ruleID, "domain", rule.Domain, "assigneeUserID", rule.AssigneeUserID)
+	return c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Assignment rule created successfully.",
+		Data:    rule,
+	})
+}
+
+// UpdateAssignmentRule replaces a routing rule (including its position, which
+// is how admins reorder rules).
+//
+// Path Parameters:
+//   - id: The rule ID.
+//
+// Request Body:
+//   - AssignmentRuleInput (domain and assignee_user_id are required).
+//
+// Returns:
+//   - JSON APIResponse containing the updated AssignmentRule or an error response.
+func (h *Handler) UpdateAssignmentRule(c echo.Context) error {
+	ctx := c.Request().Context()
+	ruleID := c.Param("id")
+	logger := slog.With("handler", "UpdateAssignmentRule", "ruleID", ruleID)
+
+	input, err := h.bindAssignmentRule(c)
+	if err != nil {
+		return err
+	}
+
+	tag, err := h.db.Pool.Exec(ctx, `
+        UPDATE assignment_rules
+        SET domain = $2, assignee_user_id = $3, position = $4, enabled = $5, description = $6, updated_at = NOW()
+        WHERE id = $1`,
+		ruleID, input.Domain, input.AssigneeUserID, input.Position, *input.Enabled, input.Description)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to update assignment rule", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update assignment rule.")
+	}
+	if tag.RowsAffected() == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "Assignment rule not found.")
+	}
+
+	rule, err := scanAssignmentRule(h.db.Pool.QueryRow(ctx, assignmentRuleSelect+` WHERE r.id = $1`, ruleID))
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to fetch updated assignment rule", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Assignment rule updated, but failed to retrieve it.")
+	}
+	logger.InfoContext(ctx, "Assignment rule updated", "domain", rule.Domain, "position", rule.Position)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Assignment rule updated successfully.",
+		Data:    rule,
+	})
+}
+
+// DeleteAssignmentRule removes a routing rule.
+//
+// Path Parameters:
+//   - id: The rule ID.
+//
+// Returns:
+//   - 204 No Content on success or an error response.
+func (h *Handler) DeleteAssignmentRule(c echo.Context) error {
+	ctx := c.Request().Context()
+	ruleID := c.Param("id")
+	logger := slog.With("handler", "DeleteAssignmentRule", "ruleID", ruleID)
+
+	tag, err := h.db.Pool.Exec(ctx, `DELETE FROM assignment_rules WHERE id = $1`, ruleID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to delete assignment rule", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to delete assignment rule.")
+	}
+	if tag.RowsAffected() == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "Assignment rule not found.")
+	}
+	logger.InfoContext(ctx, "Assignment rule deleted")
+	return c.NoContent(http.StatusNoContent)
+}
+
+// --- Helper Functions ---
+
+// bindAssignmentRule binds and validates an AssignmentRuleInput. The domain is
+// normalized to lowercase (a leading "@" is dropped) and the assignee must be
+// an existing Staff or Admin user.
+func (h *Handler) bindAssignmentRule(c echo.Context) (models.AssignmentRuleInput, error) {
+	ctx := c.Request().Context()
+	var input models.AssignmentRuleInput
+	if err := c.Bind(&input); err != nil {
+		return input, echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+
+	input.Domain = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(input.Domain)), "@")
+	if input.Domain == "" || input.Domain == "*." || strings.ContainsAny(input.Domain, "@ %_") ||
+		strings.Contains(strings.TrimPrefix(input.Domain, "*."), "*") {
+		return input, echo.NewHTTPError(http.StatusBadRequest, "A valid domain is required (e.g., 'example.com' or '*.example.com').")
+	}
+	if input.AssigneeUserID == "" {
+		return input, echo.NewHTTPError(http.StatusBadRequest, "assignee_user_id is required.")
+	}
+	if input.Enabled == nil {
+		enabled := true
+		input.Enabled = &enabled
+	}
+
+	var role models.UserRole
+	err := h.db.Pool.QueryRow(ctx, `SELECT role FROM users WHERE id = $1`, input.AssigneeUserID).Scan(&role)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return input, echo.NewHTTPError(http.StatusBadRequest, "Assignee user not found.")
+		}
+		slog.ErrorContext(ctx, "Failed to look up assignee for assignment rule", "assigneeUserID", input.AssigneeUserID, "error", err)
+		return input, echo.NewHTTPError(http.StatusInternalServerError, "Failed to validate assignee.")
+	}
+	if role != models.RoleStaff && role != models.RoleAdmin {
+		return input, echo.NewHTTPError(http.StatusBadRequest, "Assignee must be a Staff or Admin user.")
+	}
+	return input, nil
+}
+
+// scanAssignmentRule scans a row selected with assignmentRuleSelect.
+func scanAssignmentRule(row pgx.Row) (models.AssignmentRule, error) {
+	var rule models.AssignmentRule
+	err := row.Scan(
+		&rule.ID, &rule.Domain, &rule.AssigneeUserID, &rule.AssigneeName, &rule.Position, &rule.Enabled,
+		&rule.Description, &rule.CreatedAt, &rule.UpdatedAt,
+	)
+	return rule, err
+}
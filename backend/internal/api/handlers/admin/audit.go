@@ -0,0 +1,99 @@
+// backend/internal/api/handlers/admin/audit.go
+// ==========================================================================
+// Admin handler for querying the audit log (e.g., attachment downloads).
+// ==========================================================================
+
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/timezone"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// GetAuditLog returns a paginated, filterable view of recorded audit events.
+//
+// Query Parameters:
+//   - action: Filter by action (e.g., "attachment.download").
+//   - actor: Filter by acting user ID, or "public" for anonymous events.
+//   - resource_type / resource_id: Filter by the affected resource.
+//   - from_date / to_date: Inclusive date range (YYYY-MM-DD in the X-Timezone zone or
+//     the admin's saved timezone, or RFC 3339).
+//   - page / limit: Pagination (default limit 50, max 200).
+//
+// Returns:
+//   - JSON PaginatedResponse containing AuditLogEntry objects or an error response.
+func (h *Handler) GetAuditLog(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetAuditLog")
+
+	// --- 1. Parse Filters ---
+	filter := audit.Filter{
+		Action:       c.QueryParam("action"),
+		ActorUserID:  c.QueryParam("actor"),
+		ResourceType: c.QueryParam("resource_type"),
+		ResourceID:   c.QueryParam("resource_id"),
+	}
+	if filter.ActorUserID != "" && filter.ActorUserID != audit.PublicActor {
+		if _, err := uuid.Parse(filter.ActorUserID); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid actor: expected a user ID or \"public\".")
+		}
+	}
+	loc := timezone.RequestLocation(c, h.db)
+	if fromDate := c.QueryParam("from_date"); fromDate != "" {
+		from, err := timezone.ParseBoundary(fromDate, loc, false)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid from_date: "+err.Error())
+		}
+		filter.From = &from
+	}
+	if toDate := c.QueryParam("to_date"); toDate != "" {
+		to, err := timezone.ParseBoundary(toDate, loc, true)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid to_date: "+err.Error())
+		}
+		filter.To = &to
+	}
+
+	limit := 50
+	if parsed, err := strconv.Atoi(c.QueryParam("limit")); err == nil && parsed > 0 && parsed <= 200 {
+		limit = parsed
+	}
+	page := 1
+	if parsed, err := strconv.Atoi(c.QueryParam("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
+
+	// --- 2. Query Audit Log ---
+	entries, total, err := h.auditService.List(ctx, filter)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query audit log", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve audit log.")
+	}
+
+	// --- 3. Return Paginated Response ---
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	logger.InfoContext(ctx, "Fetched audit log entries", "count", len(entries), "total", total, "page", page)
+	return c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       entries,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
+	})
+}
@@ -0,0 +1,102 @@
+// backend/internal/api/handlers/admin/base.go
+// ==========================================================================
+// Base setup for the admin handler package. Defines the Handler struct and
+// registers routes for admin-only operational endpoints (/api/admin/*).
+// ==========================================================================
+
+package admin
+
+import (
+	"log/slog"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/features"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/maintenance"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+	"github.com/henrythedeveloper/it-ticket-system/internal/file"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Struct ---
+
+// Handler holds dependencies for admin request handlers.
+type Handler struct {
+	db             *db.DB            // Database connection pool
+	auditService   audit.Service     // Service for reading/recording audit events
+	emailService   email.Service     // Service for sending emails (used by the email self-test)
+	webhookService *webhook.Service  // Webhook dead-letter listing and re-drive
+	fileService    file.Service      // Attachment storage (read by data exports)
+	maintenance    *maintenance.Mode // Read-only maintenance state (toggled by SetMaintenanceMode)
+	features       *features.Flags   // Feature flags (overridden by SetFeature)
+	config         *config.Config    // Application configuration
+}
+
+// --- Constructor ---
+
+// NewHandler creates a new instance of the admin Handler.
+//
+// Parameters:
+//   - db: The database connection pool (*db.DB).
+//   - auditService: The audit event service (audit.Service).
+//   - emailService: The email sending service (email.Service).
+//   - webhookService: The outbound webhook service (*webhook.Service).
+//   - fileService: The file storage service (file.Service).
+//   - maintenanceMode: The maintenance mode state (*maintenance.Mode).
+//   - featureFlags: The feature flags (*features.Flags).
+//   - cfg: The application configuration (*config.Config).
+//
+// Returns:
+//   - *Handler: A pointer to the newly created Handler.
+func NewHandler(db *db.DB, auditService audit.Service, emailService email.Service, webhookService *webhook.Service, fileService file.Service, maintenanceMode *maintenance.Mode, featureFlags *features.Flags, cfg *config.Config) *Handler {
+	return &Handler{
+		db:             db,
+		auditService:   auditService,
+		emailService:   emailService,
+		webhookService: webhookService,
+		fileService:    fileService,
+		maintenance:    maintenanceMode,
+		features:       featureFlags,
+		config:         cfg,
+	}
+}
+
+// --- Route Registration ---
+
+// RegisterRoutes registers the admin routes. The caller is responsible for
+// applying JWT and Admin middleware to the group.
+//
+// Parameters:
+//   - g: The echo group (e.g., /api/admin) to register routes onto (*echo.Group).
+//   - h: The admin Handler instance (*Handler).
+func RegisterRoutes(g *echo.Group, h *Handler) {
+	slog.Debug("Registering admin routes")
+
+	g.GET("/audit-log", h.GetAuditLog)       // GET /api/admin/audit-log
+	g.GET("/email-outbox", h.GetEmailOutbox) // GET /api/admin/email-outbox
+	g.POST("/email-test", h.SendTestEmail)   // POST /api/admin/email-test
+
+	g.GET("/assignment-rules", h.GetAssignmentRules)          // GET /api/admin/assignment-rules
+	g.POST("/assignment-rules", h.CreateAssignmentRule)       // POST /api/admin/assignment-rules
+	g.PUT("/assignment-rules/:id", h.UpdateAssignmentRule)    // PUT /api/admin/assignment-rules/:id
+	g.DELETE("/assignment-rules/:id", h.DeleteAssignmentRule) // DELETE /api/admin/assignment-rules/:id
+
+	g.GET("/webhooks/dead-letters", h.GetWebhookDeadLetters)                // GET /api/admin/webhooks/dead-letters
+	g.POST("/webhooks/dead-letters/:id/redrive", h.RedriveWebhookDeadLetter) // POST /api/admin/webhooks/dead-letters/:id/redrive
+
+	g.POST("/data-export", h.ExportSubjectData) // POST /api/admin/data-export
+	g.POST("/data-erase", h.EraseSubjectData)   // POST /api/admin/data-erase (two-step, confirmation token)
+
+	g.GET("/explain", h.ExplainQuery) // GET /api/admin/explain?query_id= (catalog queries only)
+
+	g.GET("/reports/access-denials", h.GetAccessDenialReport) // GET /api/admin/reports/access-denials
+
+	g.GET("/maintenance", h.GetMaintenanceMode) // GET /api/admin/maintenance
+	g.PUT("/maintenance", h.SetMaintenanceMode) // PUT /api/admin/maintenance (exempt from maintenance mode)
+
+	g.PUT("/features/:name", h.SetFeature) // PUT /api/admin/features/:name
+
+	slog.Debug("Finished registering admin routes")
+}
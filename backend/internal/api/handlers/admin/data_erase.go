@@ -0,0 +1,326 @@
+// backend/internal/api/handlers/admin/data_erase.go
+// ==========================================================================
+// Admin handler for right-to-erasure requests. A submitter's personal data is
+// anonymized in place: names and email addresses on their tickets are replaced
+// with placeholders (and, optionally, descriptions and their own comments are
+// scrubbed), while ticket numbers, statuses and timestamps are kept so
+// statistics stay intact. Erasure is irreversible, so it takes two calls: the
+// first previews what would be erased and returns a short-lived confirmation
+// token bound to that preview; the second, carrying the token, performs it.
+// ==========================================================================
+
+package admin
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// Confirmation token errors.
+var (
+	errErasureTokenInvalid = errors.New("invalid confirmation token")
+	errErasureTokenExpired = errors.New("confirmation token has expired")
+)
+
+// dataEraseRequest is the body accepted by EraseSubjectData.
+type dataEraseRequest struct {
+	Email             string `json:"email"`
+	ScrubContent      bool   `json:"scrub_content"`      // Also replace descriptions and the submitter's comments
+	ConfirmationToken string `json:"confirmation_token"` // Omit to preview and obtain a token
+}
+
+// erasurePreview is returned by the first (unconfirmed) call.
+type erasurePreview struct {
+	Email             string    `json:"email"`
+	TicketCount       int       `json:"ticket_count"`
+	ScrubContent      bool      `json:"scrub_content"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// erasureResult summarizes a completed erasure.
+type erasureResult struct {
+	TicketsAnonymized   int64 `json:"tickets_anonymized"`
+	CommentsScrubbed    int64 `json:"comments_scrubbed"`
+	OutboxEmailsPurged  int64 `json:"outbox_emails_purged"`
+	WebhookEventsPurged int64 `json:"webhook_events_purged"`
+}
+
+// --- Handler Functions ---
+
+// EraseSubjectData anonymizes all tickets associated with an email address.
+// Without confirmation_token it only reports how many tickets would be
+// affected and issues a token; with a valid token for the same email and
+// options it performs the erasure in a single transaction, recording it in
+// the audit log as part of that transaction.
+//
+// Request Body:
+//   - email: The data subject's email address.
+//   - scrub_content: Optional; also replace ticket descriptions and the submitter's comments.
+//   - confirmation_token: The token from the preview call.
+//
+// Returns:
+//   - JSON APIResponse with an erasurePreview or erasureResult, 404 if nothing
+//     is held for the address, 409 if the data changed since the preview.
+func (h *Handler) EraseSubjectData(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "EraseSubjectData")
+
+	// --- 1. Bind & Validate ---
+	var req dataEraseRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "A valid email address is required.")
+	}
+	if email == strings.ToLower(h.config.Privacy.ErasedEmailPlaceholder) {
+		return echo.NewHTTPError(http.StatusBadRequest, "This address is the erasure placeholder and cannot be erased.")
+	}
+
+	// --- 2. Preview (no token) ---
+	if req.ConfirmationToken == "" {
+		ticketIDs, err := h.subjectTicketIDs(ctx, email)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to find tickets for erasure preview", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to prepare data erasure.")
+		}
+		if len(ticketIDs) == 0 {
+			return echo.NewHTTPError(http.StatusNotFound, "No data is held for this email address.")
+		}
+		expiresAt := time.Now().Add(h.config.Privacy.ErasureTokenTTL)
+		return c.JSON(http.StatusOK, models.APIResponse{
+			Success: true,
+			Message: "Erasure is irreversible. Repeat the request with confirmation_token to proceed.",
+			Data: erasurePreview{
+				Email:             email,
+				TicketCount:       len(ticketIDs),
+				ScrubContent:      req.ScrubContent,
+				ConfirmationToken: h.erasureToken(email, req.ScrubContent, len(ticketIDs), expiresAt),
+				ExpiresAt:         expiresAt,
+			},
+		})
+	}
+
+	// --- 3. Verify Confirmation ---
+	confirmedCount, err := h.verifyErasureToken(req.ConfirmationToken, email, req.ScrubContent)
+	if err != nil {
+		logger.WarnContext(ctx, "Data erasure confirmation rejected", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired confirmation token. Request a new one.")
+	}
+
+	// --- 4. Erase (single transaction) ---
+	result, err := h.eraseSubject(ctx, c, email, req.ScrubContent, confirmedCount)
+	if err != nil {
+		var httpErr *echo.HTTPError
+		if errors.As(err, &httpErr) {
+			return httpErr
+		}
+		logger.ErrorContext(ctx, "Data erasure failed; nothing was changed", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to erase data.")
+	}
+
+	logger.InfoContext(ctx, "Data subject erased", "tickets", result.TicketsAnonymized, "scrubContent", req.ScrubContent)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Personal data erased.",
+		Data:    result,
+	})
+}
+
+// --- Helpers ---
+
+// eraseSubject anonymizes the subject's tickets, scrubs their address from
+// comment text and earlier audit entries, and drops queued email to them, all
+// in one transaction.
+func (h *Handler) eraseSubject(ctx context.Context, c echo.Context, email string, scrubContent bool, confirmedCount int) (erasureResult, error) {
+	privacy := h.config.Privacy
+	var result erasureResult
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op after commit
+
+	// Lock the subject's tickets; the set must still match the preview.
+	rows, err := tx.Query(ctx, `
+        SELECT t.id FROM tickets t
+        WHERE LOWER(t.end_user_email) = $1
+           OR t.submitter_id IN (SELECT id FROM users WHERE LOWER(email) = $1)
+        FOR UPDATE`, email)
+	if err != nil {
+		return result, fmt.Errorf("failed to lock tickets: %w", err)
+	}
+	ticketIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return result, fmt.Errorf("failed to read tickets: %w", err)
+	}
+	if len(ticketIDs) != confirmedCount {
+		return result, echo.NewHTTPError(http.StatusConflict, "The data held for this address changed since the preview. Request a new confirmation token.")
+	}
+
+	// Any mention of the address in free text (e.g. a staff reply quoting it).
+	emailPattern := regexp.QuoteMeta(email)
+
+	tag, err := tx.Exec(ctx, `
+        UPDATE tickets SET
+            end_user_email = $2,
+            submitter_name = $3,
+            submitter_id = NULL,
+            submitter_token_hash = NULL,
+            locale = NULL,
+            subject = regexp_replace(subject, $4, $2, 'gi'),
+            description = CASE WHEN $5 THEN $6 ELSE regexp_replace(description, $4, $2, 'gi') END,
+            resolution_notes = regexp_replace(resolution_notes, $4, $2, 'gi')
+        WHERE id = ANY($1)`,
+		ticketIDs, privacy.ErasedEmailPlaceholder, privacy.ErasedNamePlaceholder, emailPattern,
+		scrubContent, privacy.ErasedContentPlaceholder)
+	if err != nil {
+		return result, fmt.Errorf("failed to anonymize tickets: %w", err)
+	}
+	result.TicketsAnonymized = tag.RowsAffected()
+
+	// The submitter's own replies are replaced when scrubbing; every other
+	// comment only loses occurrences of the address.
+	tag, err = tx.Exec(ctx, `
+        UPDATE ticket_updates SET comment = CASE WHEN $3 AND from_submitter THEN $4
+                                            ELSE regexp_replace(comment, $2, $5, 'gi') END
+        WHERE ticket_id = ANY($1)
+          AND (($3 AND from_submitter) OR comment ~* $2)`,
+		ticketIDs, emailPattern, scrubContent, privacy.ErasedContentPlaceholder, privacy.ErasedEmailPlaceholder)
+	if err != nil {
+		return result, fmt.Errorf("failed to scrub comments: %w", err)
+	}
+	result.CommentsScrubbed = tag.RowsAffected()
+
+	if _, err := tx.Exec(ctx, `
+        UPDATE ticket_update_revisions r SET previous_comment = regexp_replace(r.previous_comment, $2, $3, 'gi')
+        FROM ticket_updates tu
+        WHERE tu.id = r.update_id AND tu.ticket_id = ANY($1) AND r.previous_comment ~* $2`,
+		ticketIDs, emailPattern, privacy.ErasedEmailPlaceholder); err != nil {
+		return result, fmt.Errorf("failed to scrub comment history: %w", err)
+	}
+
+	tag, err = tx.Exec(ctx, `DELETE FROM email_outbox WHERE LOWER(recipient) = $1`, email)
+	if err != nil {
+		return result, fmt.Errorf("failed to purge queued email: %w", err)
+	}
+	result.OutboxEmailsPurged = tag.RowsAffected()
+
+	// Queued and dead-lettered webhook events carry the ticket JSON (submitter
+	// email, description); a re-drive would send it out again.
+	for _, table := range []string{"webhook_deliveries", "webhook_dead_letters"} {
+		tag, err = tx.Exec(ctx, `
+            DELETE FROM `+table+`
+            WHERE payload::text ~* $2
+               OR EXISTS (SELECT 1 FROM unnest($1::text[]) AS ticket_id WHERE payload::text LIKE '%' || ticket_id || '%')`,
+			ticketIDs, emailPattern)
+		if err != nil {
+			return result, fmt.Errorf("failed to purge %s: %w", table, err)
+		}
+		result.WebhookEventsPurged += tag.RowsAffected()
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM password_reset_requests WHERE email = $1`, email); err != nil {
+		return result, fmt.Errorf("failed to purge password reset requests: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM solution_effectiveness WHERE email = $1`, email); err != nil {
+		return result, fmt.Errorf("failed to purge solution feedback: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM solution_suggestions WHERE email = $1`, email); err != nil {
+		return result, fmt.Errorf("failed to purge solution suggestions: %w", err)
+	}
+
+	// Earlier audit entries (data exports, resent notifications, email tests)
+	// name the address in their metadata; they keep the keyed hash instead, and
+	// the new entry must not reintroduce the address either.
+	emailHash := h.subjectHash(email)
+	if _, err := tx.Exec(ctx, `
+        UPDATE audit_log SET metadata = metadata
+            || CASE WHEN LOWER(metadata->>'email') = $1 THEN jsonb_build_object('email', $2::text) ELSE '{}'::jsonb END
+            || CASE WHEN LOWER(metadata->>'recipient') = $1 THEN jsonb_build_object('recipient', $2::text) ELSE '{}'::jsonb END
+        WHERE LOWER(metadata->>'email') = $1 OR LOWER(metadata->>'recipient') = $1`,
+		email, emailHash); err != nil {
+		return result, fmt.Errorf("failed to scrub audit log: %w", err)
+	}
+	if err := h.auditService.RecordTx(ctx, tx, audit.Event{
+		Action:       audit.ActionDataErase,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "data_subject",
+		ResourceID:   emailHash,
+		IPAddress:    c.RealIP(),
+		Metadata: map[string]interface{}{
+			"tickets":        result.TicketsAnonymized,
+			"comments":       result.CommentsScrubbed,
+			"outbox_emails":  result.OutboxEmailsPurged,
+			"webhook_events": result.WebhookEventsPurged,
+			"scrub_content":  scrubContent,
+			"ticket_ids":     ticketIDs,
+		},
+	}); err != nil {
+		return result, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return result, fmt.Errorf("failed to commit erasure: %w", err)
+	}
+	return result, nil
+}
+
+// erasureToken signs the previewed request so it can be confirmed unchanged.
+// Format: "<unix expiry>.<ticket count>.<hex HMAC-SHA256>".
+func (h *Handler) erasureToken(email string, scrubContent bool, ticketCount int, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expiry + "." + strconv.Itoa(ticketCount) + "." + h.erasureMAC(email, scrubContent, ticketCount, expiry)
+}
+
+// verifyErasureToken checks a token against the confirmed request and
+// returns the ticket count it was issued for.
+func (h *Handler) verifyErasureToken(token, email string, scrubContent bool) (int, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, errErasureTokenInvalid
+	}
+	expiry, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, errErasureTokenInvalid
+	}
+	ticketCount, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, errErasureTokenInvalid
+	}
+	expected := h.erasureMAC(email, scrubContent, ticketCount, parts[0])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return 0, errErasureTokenInvalid
+	}
+	if time.Now().Unix() > expiry {
+		return 0, errErasureTokenExpired
+	}
+	return ticketCount, nil
+}
+
+// erasureMAC computes the token signature with the server's JWT secret.
+func (h *Handler) erasureMAC(email string, scrubContent bool, ticketCount int, expiry string) string {
+	mac := hmac.New(sha256.New, []byte(h.config.Auth.JWTSecret))
+	fmt.Fprintf(mac, "data-erase\n%s\n%t\n%d\n%s", email, scrubContent, ticketCount, expiry)
+	return hex.EncodeToString(mac.Sum(nil))
+}
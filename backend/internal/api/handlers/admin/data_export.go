@@ -0,0 +1,365 @@
+// backend/internal/api/handlers/admin/data_export.go
+// ==========================================================================
+// Admin handler for subject-access (GDPR) data exports. Everything held about
+// a submitter's email address -- their tickets, the public comment threads and
+// the attached files -- is streamed back as a zip archive with a JSON
+// manifest. Entries are written one at a time straight to the response, so
+// the archive is never held in memory.
+// ==========================================================================
+
+package admin
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/labstack/echo/v4"
+)
+
+// dataExportRequest is the body accepted by ExportSubjectData.
+type dataExportRequest struct {
+	Email              string `json:"email"`
+	IncludeAttachments *bool  `json:"include_attachments,omitempty"` // Default true
+}
+
+// Attachment states reported in the manifest.
+const (
+	exportAttachmentIncluded = "included"
+	exportAttachmentPurged   = "purged"
+	exportAttachmentSkipped  = "skipped"
+	exportAttachmentFailed   = "failed"
+)
+
+// --- Export Types ---
+
+// exportTicket is the ticket.json entry for one ticket.
+type exportTicket struct {
+	TicketNumber    int32      `json:"ticket_number"`
+	Subject         string     `json:"subject"`
+	Description     string     `json:"description"`
+	Status          string     `json:"status"`
+	Urgency         string     `json:"urgency"`
+	IssueType       *string    `json:"issue_type,omitempty"`
+	SubmitterName   *string    `json:"submitter_name,omitempty"`
+	Email           string     `json:"email"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+	ResolutionNotes *string    `json:"resolution_notes,omitempty"`
+	Tags            []string   `json:"tags"`
+}
+
+// exportComment is one entry of comments.json (public comments only).
+type exportComment struct {
+	Author        string    `json:"author"`
+	FromSubmitter bool      `json:"from_submitter"`
+	Comment       string    `json:"comment"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// exportAttachment describes one attachment in the manifest.
+type exportAttachment struct {
+	Filename string `json:"filename"`
+	Path     string `json:"path,omitempty"` // Location inside the archive, when included
+	Size     int64  `json:"size"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+}
+
+// manifestTicket lists the archive entries written for one ticket.
+type manifestTicket struct {
+	TicketNumber int32              `json:"ticket_number"`
+	Subject      string             `json:"subject"`
+	Files        []string           `json:"files"`
+	Comments     int                `json:"comments"`
+	Attachments  []exportAttachment `json:"attachments"`
+}
+
+// exportManifest is written last as manifest.json.
+type exportManifest struct {
+	Email       string           `json:"email"`
+	GeneratedAt time.Time        `json:"generated_at"`
+	TicketCount int              `json:"ticket_count"`
+	Tickets     []manifestTicket `json:"tickets"`
+	Errors      []string         `json:"errors,omitempty"`
+}
+
+// exportAttachmentRow is an attachment as loaded from the database.
+type exportAttachmentRow struct {
+	id          string
+	filename    string
+	storagePath string
+	size        int64
+	purged      bool
+}
+
+// --- Handler Functions ---
+
+// ExportSubjectData streams a zip archive of all data associated with an
+// email address: tickets submitted with it (or by the user account that owns
+// it), their non-internal comments and their attachments.
+//
+// Request Body:
+//   - email: The data subject's email address.
+//   - include_attachments: Optional; set false to leave attachment files out.
+//
+// Returns:
+//   - A zip archive (application/zip), or 404 if nothing is held for the address.
+func (h *Handler) ExportSubjectData(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "ExportSubjectData")
+
+	// --- 1. Bind & Validate ---
+	var req dataExportRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if _, err := mail.ParseAddress(email); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "A valid email address is required.")
+	}
+	includeAttachments := req.IncludeAttachments == nil || *req.IncludeAttachments
+
+	// --- 2. Find Tickets ---
+	// Only IDs are loaded up front; each ticket's data is fetched as it is written.
+	ticketIDs, err := h.subjectTicketIDs(ctx, email)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to find tickets for data export", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to prepare data export.")
+	}
+	if len(ticketIDs) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "No data is held for this email address.")
+	}
+
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionDataExport,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "data_subject",
+		ResourceID:   h.subjectHash(email), // The audit log must not hold the address itself
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"tickets": len(ticketIDs), "include_attachments": includeAttachments},
+	})
+
+	// --- 3. Stream Archive ---
+	// Once the headers are sent the status can no longer change, so failures
+	// from here on are recorded in the manifest or end the stream early.
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/zip")
+	res.Header().Set(echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=\"data-export-%s.zip\"", time.Now().UTC().Format("20060102-150405")))
+	res.WriteHeader(http.StatusOK)
+
+	archive := zip.NewWriter(res)
+	manifest := exportManifest{Email: email, GeneratedAt: time.Now().UTC(), TicketCount: len(ticketIDs)}
+	for _, ticketID := range ticketIDs {
+		entry, err := h.writeTicketExport(ctx, archive, ticketID, includeAttachments)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.WarnContext(ctx, "Data export aborted by client", "error", err)
+				return nil
+			}
+			logger.ErrorContext(ctx, "Failed to export ticket", "ticketUUID", ticketID, "error", err)
+			manifest.Errors = append(manifest.Errors, fmt.Sprintf("ticket %s: %v", ticketID, err))
+			continue
+		}
+		manifest.Tickets = append(manifest.Tickets, entry)
+	}
+	if err := writeJSONEntry(archive, "manifest.json", manifest); err != nil {
+		logger.ErrorContext(ctx, "Failed to write export manifest", "error", err)
+		return nil
+	}
+	if err := archive.Close(); err != nil {
+		logger.ErrorContext(ctx, "Failed to finish export archive", "error", err)
+		return nil
+	}
+
+	logger.InfoContext(ctx, "Data export streamed", "tickets", len(manifest.Tickets), "errors", len(manifest.Errors))
+	return nil
+}
+
+// --- Helpers ---
+
+// subjectHash identifies a data subject in the audit log without storing
+// their address: a hex HMAC-SHA256 of the normalized email, keyed with the
+// JWT secret so it cannot be reversed by hashing guessed addresses.
+func (h *Handler) subjectHash(email string) string {
+	mac := hmac.New(sha256.New, []byte(h.config.Auth.JWTSecret))
+	fmt.Fprintf(mac, "data-subject\n%s", email)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// subjectTicketIDs lists the tickets submitted with the email address or by
+// the user account registered with it, oldest first.
+func (h *Handler) subjectTicketIDs(ctx context.Context, email string) ([]string, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT t.id FROM tickets t
+        WHERE LOWER(t.end_user_email) = $1
+           OR t.submitter_id IN (SELECT id FROM users WHERE LOWER(email) = $1)
+        ORDER BY t.ticket_number`, email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// writeTicketExport writes ticket.json, comments.json and (optionally) the
+// attachment files for one ticket under tickets/<number>/.
+func (h *Handler) writeTicketExport(ctx context.Context, archive *zip.Writer, ticketID string, includeAttachments bool) (manifestTicket, error) {
+	var t exportTicket
+	err := h.db.Pool.QueryRow(ctx, `
+        SELECT t.ticket_number, t.subject, t.description, t.status, t.urgency, t.issue_type,
+               t.submitter_name, t.end_user_email, t.created_at, t.updated_at, t.closed_at, t.resolution_notes,
+               COALESCE(ARRAY(SELECT tg.name FROM ticket_tags tt JOIN tags tg ON tg.id = tt.tag_id
+                              WHERE tt.ticket_id = t.id ORDER BY tg.name), '{}')
+        FROM tickets t WHERE t.id = $1`, ticketID).Scan(
+		&t.TicketNumber, &t.Subject, &t.Description, &t.Status, &t.Urgency, &t.IssueType,
+		&t.SubmitterName, &t.Email, &t.CreatedAt, &t.UpdatedAt, &t.ClosedAt, &t.ResolutionNotes, &t.Tags)
+	if err != nil {
+		return manifestTicket{}, fmt.Errorf("failed to load ticket: %w", err)
+	}
+
+	dir := fmt.Sprintf("tickets/%d", t.TicketNumber)
+	entry := manifestTicket{TicketNumber: t.TicketNumber, Subject: t.Subject, Attachments: []exportAttachment{}}
+	if err := writeJSONEntry(archive, dir+"/ticket.json", t); err != nil {
+		return manifestTicket{}, err
+	}
+	entry.Files = append(entry.Files, dir+"/ticket.json")
+
+	comments, err := h.exportComments(ctx, ticketID)
+	if err != nil {
+		return manifestTicket{}, err
+	}
+	if err := writeJSONEntry(archive, dir+"/comments.json", comments); err != nil {
+		return manifestTicket{}, err
+	}
+	entry.Files = append(entry.Files, dir+"/comments.json")
+	entry.Comments = len(comments)
+
+	attachments, err := h.exportAttachmentRows(ctx, ticketID)
+	if err != nil {
+		return manifestTicket{}, err
+	}
+	for _, att := range attachments {
+		ea := exportAttachment{Filename: att.filename, Size: att.size}
+		switch {
+		case att.purged:
+			ea.Status = exportAttachmentPurged
+		case !includeAttachments:
+			ea.Status = exportAttachmentSkipped
+		default:
+			// The attachment ID keeps names unique within the ticket directory.
+			ea.Path = fmt.Sprintf("%s/attachments/%s_%s", dir, att.id, path.Base(att.filename))
+			if copyErr := h.copyAttachmentEntry(ctx, archive, ea.Path, att.storagePath); copyErr != nil {
+				if ctx.Err() != nil {
+					return manifestTicket{}, copyErr
+				}
+				ea.Path, ea.Status, ea.Error = "", exportAttachmentFailed, copyErr.Error()
+			} else {
+				ea.Status = exportAttachmentIncluded
+			}
+		}
+		entry.Attachments = append(entry.Attachments, ea)
+	}
+	return entry, nil
+}
+
+// exportComments loads a ticket's public, non-deleted comments, oldest first.
+// Staff are identified by name only.
+func (h *Handler) exportComments(ctx context.Context, ticketID string) ([]exportComment, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT COALESCE(u.name, CASE WHEN tu.from_submitter THEN 'Submitter' ELSE 'System' END),
+               tu.from_submitter, tu.comment, tu.created_at
+        FROM ticket_updates tu
+        LEFT JOIN users u ON u.id = tu.user_id
+        WHERE tu.ticket_id = $1 AND NOT tu.is_internal_note AND tu.deleted_at IS NULL
+        ORDER BY tu.created_at`, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := make([]exportComment, 0)
+	for rows.Next() {
+		var ec exportComment
+		if err := rows.Scan(&ec.Author, &ec.FromSubmitter, &ec.Comment, &ec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan comment: %w", err)
+		}
+		comments = append(comments, ec)
+	}
+	return comments, rows.Err()
+}
+
+// exportAttachmentRows loads a ticket's attachment metadata.
+func (h *Handler) exportAttachmentRows(ctx context.Context, ticketID string) ([]exportAttachmentRow, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT id, filename, storage_path, size, purged_at IS NOT NULL
+        FROM attachments WHERE ticket_id = $1 ORDER BY uploaded_at`, ticketID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachments: %w", err)
+	}
+	defer rows.Close()
+
+	attachments := make([]exportAttachmentRow, 0)
+	for rows.Next() {
+		var att exportAttachmentRow
+		if err := rows.Scan(&att.id, &att.filename, &att.storagePath, &att.size, &att.purged); err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, att)
+	}
+	return attachments, rows.Err()
+}
+
+// copyAttachmentEntry streams a stored file into the archive. Files are
+// stored rather than deflated: most attachments are already compressed.
+func (h *Handler) copyAttachmentEntry(ctx context.Context, archive *zip.Writer, name, storagePath string) error {
+	reader, err := h.fileService.GetObject(ctx, storagePath)
+	if err != nil {
+		return fmt.Errorf("failed to read stored file: %w", err)
+	}
+	defer reader.Close()
+
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, reader); err != nil {
+		return fmt.Errorf("failed to copy stored file: %w", err)
+	}
+	return nil
+}
+
+// writeJSONEntry writes v as an indented JSON file in the archive.
+func writeJSONEntry(archive *zip.Writer, name string, v interface{}) error {
+	w, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: time.Now()})
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
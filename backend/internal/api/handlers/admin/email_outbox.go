@@ -0,0 +1,105 @@
+// backend/internal/api/handlers/admin/email_outbox.go
+// ==========================================================================
+// Admin handler for inspecting the email outbox (queued/sent/failed emails).
+// ==========================================================================
+
+package admin
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// GetEmailOutbox lists outbox entries, newest first.
+//
+// Query Parameters:
+//   - status: Optional filter ("pending", "sent" or "failed").
+//   - page / limit: Pagination (default limit 50, max 200).
+//
+// Returns:
+//   - JSON PaginatedResponse containing EmailOutboxEntry objects or an error response.
+func (h *Handler) GetEmailOutbox(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetEmailOutbox")
+
+	// --- 1. Parse Filters ---
+	status := c.QueryParam("status")
+	switch status {
+	case "", email.OutboxStatusPending, email.OutboxStatusSent, email.OutboxStatusFailed:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid status filter. Use 'pending', 'sent' or 'failed'.")
+	}
+	limit := 50
+	if parsed, err := strconv.Atoi(c.QueryParam("limit")); err == nil && parsed > 0 && parsed <= 200 {
+		limit = parsed
+	}
+	page := 1
+	if parsed, err := strconv.Atoi(c.QueryParam("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+
+	whereClause := ""
+	args := []interface{}{}
+	if status != "" {
+		whereClause = " WHERE status = $1"
+		args = append(args, status)
+	}
+
+	// --- 2. Query Outbox ---
+	var total int
+	if err := h.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM email_outbox`+whereClause, args...).Scan(&total); err != nil {
+		logger.ErrorContext(ctx, "Failed to count outbox entries", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve email outbox.")
+	}
+
+	query := `
+        SELECT id, kind, recipient, status, attempts, last_error, next_attempt_at, created_at, sent_at
+        FROM email_outbox` + whereClause +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2)
+	rows, err := h.db.Pool.Query(ctx, query, append(args, limit, (page-1)*limit)...)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query outbox entries", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve email outbox.")
+	}
+	defer rows.Close()
+
+	entries := make([]models.EmailOutboxEntry, 0)
+	for rows.Next() {
+		var entry models.EmailOutboxEntry
+		if err := rows.Scan(
+			&entry.ID, &entry.Kind, &entry.Recipient, &entry.Status, &entry.Attempts,
+			&entry.LastError, &entry.NextAttemptAt, &entry.CreatedAt, &entry.SentAt,
+		); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan outbox entry", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process email outbox.")
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating outbox entries", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process email outbox.")
+	}
+
+	// --- 3. Return Paginated Response ---
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       entries,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
+	})
+}
@@ -0,0 +1,81 @@
+// backend/internal/api/handlers/admin/email_selftest.go
+// ==========================================================================
+// Admin handler for the email self-test: sends a test message through the
+// configured email provider and reports the provider's error, if any.
+// ==========================================================================
+
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// emailTestRequest is the body accepted by SendTestEmail.
+type emailTestRequest struct {
+	Recipient string `json:"recipient"`
+}
+
+// --- Handler Functions ---
+
+// SendTestEmail sends a test email to the supplied address using the real
+// email delivery path (the outbox queue is bypassed so the result is immediate).
+//
+// Request Body:
+//   - recipient: The email address to send the test message to.
+//
+// Returns:
+//   - JSON APIResponse on success, or 502 with the provider error on failure.
+func (h *Handler) SendTestEmail(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "SendTestEmail")
+
+	// --- 1. Bind & Validate ---
+	var req emailTestRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	req.Recipient = strings.TrimSpace(req.Recipient)
+	if _, err := mail.ParseAddress(req.Recipient); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "A valid recipient email address is required.")
+	}
+
+	// --- 2. Send ---
+	sendErr := h.emailService.SendTestEmail(req.Recipient)
+
+	metadata := map[string]interface{}{"recipient": req.Recipient, "success": sendErr == nil}
+	if sendErr != nil {
+		metadata["error"] = sendErr.Error()
+	}
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionEmailTest,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "email",
+		IPAddress:    c.RealIP(),
+		Metadata:     metadata,
+	})
+
+	// --- 3. Respond ---
+	if sendErr != nil {
+		logger.WarnContext(ctx, "Test email failed", "recipient", req.Recipient, "error", sendErr)
+		return c.JSON(http.StatusBadGateway, models.APIResponse{
+			Success:   false,
+			Message:   "Test email failed.",
+			Error:     sendErr.Error(),
+			ErrorCode: string(apierror.CodeEmailDelivery),
+		})
+	}
+	logger.InfoContext(ctx, "Test email sent", "recipient", req.Recipient)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Test email sent successfully.",
+	})
+}
@@ -0,0 +1,195 @@
+// backend/internal/api/handlers/admin/explain.go
+// ==========================================================================
+// Admin handler for query plans. Runs EXPLAIN (ANALYZE) for a fixed catalog
+// of the application's heavy queries so index usage can be checked in
+// production without direct database access. Only catalog queries can be
+// explained: the endpoint never accepts SQL, and each plan runs in a
+// read-only transaction with a short statement timeout.
+// ==========================================================================
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// explainStatementTimeout bounds each EXPLAIN ANALYZE run.
+const explainStatementTimeout = "10s"
+
+// explainSearchTerm is the sample text used by the search queries.
+const explainSearchTerm = "password"
+
+// explainQuery is one entry in the catalog. args builds the sample arguments;
+// it receives the requesting admin's user ID for per-user queries.
+type explainQuery struct {
+	description string
+	sql         string
+	args        func(userID string) []interface{}
+}
+
+// explainQueries mirrors the heaviest queries the handlers issue, with sample
+// arguments. Keep them in sync when those queries change.
+var explainQueries = map[string]explainQuery{
+	"tickets_list_open": {
+		description: "Ticket list filtered by status, newest first (GetAllTickets)",
+		sql: `
+            SELECT t.id, t.ticket_number, t.subject, t.status, t.urgency, t.created_at, a.name,
+                   (SELECT COALESCE(json_agg(tg.name), '[]') FROM ticket_tags tt JOIN tags tg ON tt.tag_id = tg.id WHERE tt.ticket_id = t.id)
+            FROM tickets t
+            LEFT JOIN users a ON t.assigned_to_user_id = a.id
+            WHERE t.quarantined_at IS NULL AND t.spam_at IS NULL AND t.status = $1
+            ORDER BY t.created_at DESC, t.id
+            LIMIT 15 OFFSET 0`,
+		args: func(string) []interface{} { return []interface{}{models.StatusOpen} },
+	},
+	"tickets_list_assigned": {
+		description: "Ticket list assigned to the current user (GetAllTickets assigned_to=me)",
+		sql: `
+            SELECT t.id, t.ticket_number, t.subject, t.status, t.updated_at
+            FROM tickets t
+            WHERE t.quarantined_at IS NULL AND t.spam_at IS NULL AND t.assigned_to_user_id = $1
+            ORDER BY t.updated_at DESC, t.id
+            LIMIT 15 OFFSET 0`,
+		args: func(userID string) []interface{} { return []interface{}{userID} },
+	},
+	"tickets_list_tag": {
+		description: "Ticket list filtered by tag (GetAllTickets tags=...)",
+		sql: `
+            SELECT t.id, t.ticket_number, t.subject, t.status, t.created_at
+            FROM tickets t
+            JOIN ticket_tags tt ON tt.ticket_id = t.id
+            JOIN tags tg ON tg.id = tt.tag_id
+            WHERE t.quarantined_at IS NULL AND t.spam_at IS NULL AND LOWER(tg.name) = LOWER($1)
+            ORDER BY t.created_at DESC, t.id
+            LIMIT 15 OFFSET 0`,
+		args: func(string) []interface{} { return []interface{}{"Network"} },
+	},
+	"tickets_list_overdue": {
+		description: "Overdue ticket list (GetAllTickets due=overdue)",
+		sql: `
+            SELECT t.id, t.ticket_number, t.subject, t.due_date
+            FROM tickets t
+            WHERE t.quarantined_at IS NULL AND t.spam_at IS NULL AND t.due_date < NOW() AND t.status <> $1
+            ORDER BY t.due_date ASC NULLS LAST, t.id
+            LIMIT 15 OFFSET 0`,
+		args: func(string) []interface{} { return []interface{}{models.StatusClosed} },
+	},
+	"tickets_count_filtered": {
+		description: "Total count behind a filtered ticket list page (GetAllTickets)",
+		sql: `
+            SELECT COUNT(*) FROM tickets t
+            WHERE t.quarantined_at IS NULL AND t.spam_at IS NULL AND t.status = $1`,
+		args: func(string) []interface{} { return []interface{}{models.StatusOpen} },
+	},
+	"tickets_count_by_status": {
+		description: "Ticket counts per status (GetTicketCounts)",
+		sql:         `SELECT status, COUNT(*) FROM tickets WHERE quarantined_at IS NULL AND spam_at IS NULL GROUP BY status`,
+		args:        func(string) []interface{} { return nil },
+	},
+	"tickets_search": {
+		description: "Ticket search by number, subject or description (SearchTickets)",
+		sql: `
+            SELECT id, ticket_number, subject, status, updated_at
+            FROM tickets
+            WHERE quarantined_at IS NULL AND spam_at IS NULL AND (ticket_number::text = $1 OR subject ILIKE '%' || $1 || '%' OR description ILIKE '%' || $1 || '%')
+            ORDER BY updated_at DESC
+            LIMIT 50`,
+		args: func(string) []interface{} { return []interface{}{explainSearchTerm} },
+	},
+	"global_search_faqs": {
+		description: "FAQ hits for the global search (Search)",
+		sql: `
+            SELECT id, question, answer
+            FROM faq_entries
+            WHERE question ILIKE '%' || $1 || '%' OR answer ILIKE '%' || $1 || '%'
+            LIMIT 10`,
+		args: func(string) []interface{} { return []interface{}{explainSearchTerm} },
+	},
+}
+
+// --- Handler Functions ---
+
+// ExplainQuery returns the EXPLAIN (ANALYZE, BUFFERS) plan of one catalog
+// query, or the catalog itself when no query is named.
+//
+// Query Parameters:
+//   - query_id: A catalog query ID (omit to list the catalog).
+//
+// Returns:
+//   - JSON APIResponse containing a QueryPlan, or the ExplainableQuery list,
+//     404 for an unknown query_id, or another error response.
+func (h *Handler) ExplainQuery(c echo.Context) error {
+	ctx := c.Request().Context()
+	queryID := c.QueryParam("query_id")
+	logger := slog.With("handler", "ExplainQuery", "queryID", queryID)
+
+	if queryID == "" {
+		catalog := make([]models.ExplainableQuery, 0, len(explainQueries))
+		for id, q := range explainQueries {
+			catalog = append(catalog, models.ExplainableQuery{ID: id, Description: q.description})
+		}
+		sort.Slice(catalog, func(i, j int) bool { return catalog[i].ID < catalog[j].ID })
+		return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: catalog})
+	}
+
+	query, ok := explainQueries[queryID]
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotFound, "Unknown query_id. Omit it to list the available queries.")
+	}
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	plan, err := h.explain(ctx, query, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to explain query", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to explain query.")
+	}
+
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionQueryExplain,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "query_plan",
+		ResourceID:   queryID,
+		IPAddress:    c.RealIP(),
+	})
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: models.QueryPlan{
+		ID:          queryID,
+		Description: query.description,
+		SQL:         query.sql,
+		Plan:        plan,
+	}})
+}
+
+// --- Helper Functions ---
+
+// explain runs EXPLAIN ANALYZE for a catalog query in a read-only
+// transaction that is always rolled back.
+func (h *Handler) explain(ctx context.Context, query explainQuery, userID string) (json.RawMessage, error) {
+	tx, err := h.db.Pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin read-only transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT set_config('statement_timeout', $1, true)`, explainStatementTimeout); err != nil {
+		return nil, fmt.Errorf("failed to set statement timeout: %w", err)
+	}
+	var plan []byte
+	if err := tx.QueryRow(ctx, "EXPLAIN (ANALYZE, BUFFERS, FORMAT JSON) "+query.sql, query.args(userID)...).Scan(&plan); err != nil {
+		return nil, fmt.Errorf("failed to run EXPLAIN: %w", err)
+	}
+	return json.RawMessage(plan), nil
+}
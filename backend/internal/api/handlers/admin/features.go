@@ -0,0 +1,80 @@
+// backend/internal/api/handlers/admin/features.go
+// ==========================================================================
+// Feature flag handlers: list the current flags for clients, and let Admins
+// turn a flag on or off on the serving instance (see middleware/features).
+// ==========================================================================
+
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// featureNamePattern limits flag names to what FEATURE_FLAGS can express.
+var featureNamePattern = regexp.MustCompile(`^[a-z0-9_.-]{1,64}$`)
+
+// featureRequest is the body accepted by SetFeature.
+type featureRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// --- Handler Functions ---
+
+// GetFeatures lists the feature flags and whether each is on, so clients can
+// hide features that are switched off. Flags not listed are off. Public.
+//
+// Returns:
+//   - JSON APIResponse containing a map of flag name to enabled.
+func (h *Handler) GetFeatures(c echo.Context) error {
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: h.features.All()})
+}
+
+// SetFeature turns a feature flag on or off, overriding its configured value
+// until the instance restarts.
+//
+// Path Parameters:
+//   - name: The flag name (lower-case letters, digits, "_", "." or "-").
+//
+// Request Body:
+//   - enabled: Whether the flag should be on.
+//
+// Returns:
+//   - JSON APIResponse containing the updated flag map, or 400 if the name or body is invalid.
+func (h *Handler) SetFeature(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "SetFeature")
+
+	name := strings.ToLower(c.Param("name"))
+	if !featureNamePattern.MatchString(name) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid feature flag name.")
+	}
+	var req featureRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	if req.Enabled == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "enabled is required.")
+	}
+
+	previous := h.features.Enabled(name)
+	h.features.Set(name, *req.Enabled)
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionFeatureToggle,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "feature",
+		ResourceID:   name,
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"enabled": *req.Enabled, "previous": previous},
+	})
+
+	logger.InfoContext(ctx, "Feature flag changed", "flag", name, "enabled", *req.Enabled, "userID", auth.OptionalUserID(c))
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: "Feature flag updated.", Data: h.features.All()})
+}
@@ -0,0 +1,69 @@
+// backend/internal/api/handlers/admin/maintenance.go
+// ==========================================================================
+// Admin handlers for read-only maintenance mode: report the current state and
+// turn it on or off on the serving instance (see middleware/maintenance).
+// ==========================================================================
+
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// maintenanceRequest is the body accepted by SetMaintenanceMode.
+type maintenanceRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// --- Handler Functions ---
+
+// GetMaintenanceMode reports whether maintenance mode is on.
+//
+// Returns:
+//   - JSON APIResponse containing a maintenance.Status.
+func (h *Handler) GetMaintenanceMode(c echo.Context) error {
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: h.maintenance.Status()})
+}
+
+// SetMaintenanceMode turns maintenance mode on or off. This route is exempt
+// from maintenance mode itself, so it can always be switched back off.
+//
+// Request Body:
+//   - enabled: Whether maintenance mode should be on.
+//
+// Returns:
+//   - JSON APIResponse containing the new maintenance.Status, or 400 if enabled is missing.
+func (h *Handler) SetMaintenanceMode(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "SetMaintenanceMode")
+
+	var req maintenanceRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	if req.Enabled == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "enabled is required.")
+	}
+
+	h.maintenance.Set(*req.Enabled)
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionMaintenanceToggle,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "maintenance",
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"enabled": *req.Enabled},
+	})
+
+	message := "Maintenance mode disabled."
+	if *req.Enabled {
+		message = "Maintenance mode enabled; write requests are now rejected."
+	}
+	logger.WarnContext(ctx, "Maintenance mode changed", "enabled", *req.Enabled, "userID", auth.OptionalUserID(c))
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: message, Data: h.maintenance.Status()})
+}
@@ -0,0 +1,118 @@
+// backend/internal/api/handlers/admin/webhooks.go
+// ==========================================================================
+// Admin handlers for webhook dead letters: deliveries that exhausted their
+// retries can be listed and manually re-driven.
+// ==========================================================================
+
+package admin
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// GetWebhookDeadLetters lists webhook dead letters, newest first.
+//
+// Query Parameters:
+//   - status: Optional filter ("dead" or "redriven").
+//   - page / limit: Pagination (default limit 50, max 200).
+//
+// Returns:
+//   - JSON PaginatedResponse containing WebhookDeadLetter objects or an error response.
+func (h *Handler) GetWebhookDeadLetters(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetWebhookDeadLetters")
+
+	// --- 1. Parse Filters ---
+	status := c.QueryParam("status")
+	switch status {
+	case "", webhook.DeadLetterStatusDead, webhook.DeadLetterStatusRedriven:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid status filter. Use 'dead' or 'redriven'.")
+	}
+	limit := 50
+	if parsed, err := strconv.Atoi(c.QueryParam("limit")); err == nil && parsed > 0 && parsed <= 200 {
+		limit = parsed
+	}
+	page := 1
+	if parsed, err := strconv.Atoi(c.QueryParam("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+
+	// --- 2. Query Dead Letters ---
+	letters, total, err := h.webhookService.ListDeadLetters(ctx, status, limit, (page-1)*limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to list webhook dead letters", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve webhook dead letters.")
+	}
+
+	// --- 3. Return Paginated Response ---
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       letters,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
+	})
+}
+
+// RedriveWebhookDeadLetter queues a dead letter for delivery again with a
+// fresh retry budget. Each dead letter can be re-driven once; if the new
+// delivery also fails it produces a new dead letter.
+//
+// Path Parameters:
+//   - id: The dead letter UUID.
+//
+// Returns:
+//   - JSON APIResponse on success, 404 if not found, 409 if already re-driven.
+func (h *Handler) RedriveWebhookDeadLetter(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "RedriveWebhookDeadLetter")
+
+	id := c.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid dead letter ID.")
+	}
+
+	if err := h.webhookService.Redrive(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, webhook.ErrDeadLetterNotFound):
+			return echo.NewHTTPError(http.StatusNotFound, "Dead letter not found.")
+		case errors.Is(err, webhook.ErrAlreadyRedriven):
+			return echo.NewHTTPError(http.StatusConflict, "Dead letter has already been re-driven.")
+		}
+		logger.ErrorContext(ctx, "Failed to re-drive webhook dead letter", "deadLetterID", id, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to re-drive webhook delivery.")
+	}
+
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionWebhookRedrive,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "webhook_dead_letter",
+		ResourceID:   id,
+		IPAddress:    c.RealIP(),
+	})
+
+	logger.InfoContext(ctx, "Webhook dead letter re-driven", "deadLetterID", id)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Webhook delivery re-queued.",
+	})
+}
@@ -0,0 +1,198 @@
+// backend/internal/api/handlers/dashboard/dashboard.go
+// ==========================================================================
+// Handler for the "my work" dashboard endpoint. Aggregates the authenticated
+// user's assigned-ticket counts (including overdue ones), unread
+// notifications and recent activity in a single call, running the
+// sub-queries concurrently.
+// Note: the tree has no task entity yet, so no task section is returned.
+// ==========================================================================
+
+package dashboard
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/errgroup"
+)
+
+// recentActivityLimit is the number of recent updates included in the dashboard.
+const recentActivityLimit = 10
+
+// --- Handler Struct ---
+
+// Handler holds dependencies for the dashboard endpoint.
+type Handler struct {
+	db *db.DB // Database connection pool
+}
+
+// --- Constructor ---
+
+// NewHandler creates a new instance of the dashboard Handler.
+//
+// Parameters:
+//   - db: The database connection pool (*db.DB).
+//
+// Returns:
+//   - *Handler: A pointer to the newly created Handler.
+func NewHandler(db *db.DB) *Handler {
+	return &Handler{
+		db: db,
+	}
+}
+
+// --- Route Registration ---
+
+// RegisterRoutes registers the dashboard routes. The caller is responsible for
+// applying JWT middleware to the group.
+//
+// Parameters:
+//   - g: The echo group (e.g., /api/me) to register routes onto (*echo.Group).
+//   - h: The dashboard Handler instance (*Handler).
+func RegisterRoutes(g *echo.Group, h *Handler) {
+	g.GET("/dashboard", h.GetMyDashboard) // GET /api/me/dashboard
+}
+
+// --- Handler Functions ---
+
+// GetMyDashboard returns the authenticated user's aggregated dashboard. Every
+// section is scoped to the requesting user.
+//
+// Returns:
+//   - JSON APIResponse containing a MyDashboard object or an error response.
+func (h *Handler) GetMyDashboard(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetMyDashboard")
+
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// --- Run Sub-Queries Concurrently ---
+	dashboard := models.MyDashboard{RecentActivity: []models.DashboardActivity{}}
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var queryErr error
+		dashboard.AssignedByStatus, dashboard.AssignedOpenTotal, queryErr = h.assignedStatusCounts(gctx, userID)
+		return queryErr
+	})
+	g.Go(func() error {
+		return h.db.Pool.QueryRow(gctx, `
+            SELECT COUNT(*) FROM tickets
+            WHERE assigned_to_user_id = $1 AND assigned_at IS NOT NULL AND accepted_at IS NULL AND status <> $2 AND spam_at IS NULL`,
+			userID, models.StatusClosed).Scan(&dashboard.PendingAcceptance)
+	})
+	g.Go(func() error {
+		return h.db.Pool.QueryRow(gctx, `
+            SELECT COUNT(*) FROM tickets
+            WHERE assigned_to_user_id = $1 AND due_date < NOW() AND status <> $2 AND spam_at IS NULL`,
+			userID, models.StatusClosed).Scan(&dashboard.OverdueAssigned)
+	})
+	g.Go(func() error {
+		return h.db.Pool.QueryRow(gctx, `
+            SELECT COUNT(*) FROM tickets t
+            JOIN team_members tm ON tm.team_id = t.assigned_to_team_id AND tm.user_id = $1
+            WHERE t.assigned_to_user_id IS NULL AND t.status <> $2 AND t.quarantined_at IS NULL AND t.spam_at IS NULL`,
+			userID, models.StatusClosed).Scan(&dashboard.TeamUnclaimed)
+	})
+	g.Go(func() error {
+		return h.db.Pool.QueryRow(gctx,
+			`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND is_read = FALSE`, userID,
+		).Scan(&dashboard.UnreadNotifications)
+	})
+	g.Go(func() error {
+		var queryErr error
+		dashboard.RecentActivity, queryErr = h.recentActivity(gctx, userID)
+		return queryErr
+	})
+	if err := g.Wait(); err != nil {
+		logger.ErrorContext(ctx, "Failed to build dashboard", "userID", userID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to load dashboard.")
+	}
+
+	logger.DebugContext(ctx, "Dashboard built", "userID", userID, "openAssigned", dashboard.AssignedOpenTotal)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    dashboard,
+	})
+}
+
+// --- Sub-Queries ---
+
+// assignedStatusCounts counts the user's assigned tickets per status, leaving
+// out spam. Every known status is present in the map so the frontend can
+// render fixed tiles.
+func (h *Handler) assignedStatusCounts(ctx context.Context, userID string) (map[models.TicketStatus]int, int, error) {
+	counts := map[models.TicketStatus]int{
+		models.StatusOpen:              0,
+		models.StatusInProgress:        0,
+		models.StatusWaitingOnCustomer: 0,
+		models.StatusClosed:            0,
+	}
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT status, COUNT(*) FROM tickets
+        WHERE assigned_to_user_id = $1 AND spam_at IS NULL
+        GROUP BY status`, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	openTotal := 0
+	for rows.Next() {
+		var status models.TicketStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, 0, err
+		}
+		counts[status] = count
+		if status != models.StatusClosed {
+			openTotal += count
+		}
+	}
+	return counts, openTotal, rows.Err()
+}
+
+// recentActivity lists the latest comments and history entries on tickets
+// assigned to the user, excluding the user's own activity.
+func (h *Handler) recentActivity(ctx context.Context, userID string) ([]models.DashboardActivity, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT t.id, t.ticket_number, t.subject, a.comment, u.name, a.is_system, a.created_at
+        FROM (
+            SELECT tu.ticket_id, tu.user_id, tu.comment, tu.is_system_update AS is_system, tu.created_at
+            FROM ticket_updates tu
+            WHERE tu.deleted_at IS NULL
+            UNION ALL
+            SELECT th.ticket_id, th.actor_user_id, th.summary, TRUE, th.created_at
+            FROM ticket_history th
+        ) a
+        JOIN tickets t ON t.id = a.ticket_id
+        LEFT JOIN users u ON u.id = a.user_id
+        WHERE t.assigned_to_user_id = $1
+          AND (a.user_id IS NULL OR a.user_id <> $1)
+        ORDER BY a.created_at DESC
+        LIMIT $2`, userID, recentActivityLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	activity := make([]models.DashboardActivity, 0, recentActivityLimit)
+	for rows.Next() {
+		var item models.DashboardActivity
+		if err := rows.Scan(
+			&item.TicketID, &item.TicketNumber, &item.Subject, &item.Comment,
+			&item.AuthorName, &item.IsSystem, &item.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		activity = append(activity, item)
+	}
+	return activity, rows.Err()
+}
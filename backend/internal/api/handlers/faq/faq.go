@@ -1,23 +1,35 @@
 // backend/internal/api/handlers/faq/faq.go
 // ==========================================================================
 // Handler functions for managing Frequently Asked Questions (FAQ) entries.
-// Provides endpoints for CRUD operations on FAQs.
+// Provides endpoints for CRUD operations on FAQs. Within a category, pinned
+// entries are listed first, then by the admin-defined display_order, then by
+// creation time.
 // ==========================================================================
 
 package faq
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/etag"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models"
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
 
+// faqColumns is the column list scanned by scanFAQ.
+const faqColumns = `id, question, answer, category, display_order, pinned, created_at, updated_at`
+
+// faqOrderBy is the listing order of FAQ entries.
+const faqOrderBy = ` ORDER BY category, pinned DESC, display_order, created_at`
+
 // --- Handler Struct ---
 
 // Handler holds dependencies for FAQ-related request handlers.
@@ -54,76 +66,45 @@ func RegisterRoutes(g *echo.Group, h *Handler, adminMiddleware echo.MiddlewareFu
 	slog.Debug("Registering FAQ routes")
 
 	// Public routes (Read operations)
-	g.GET("", h.GetAllFAQs)     // GET /api/faq
-	g.GET("/:id", h.GetFAQByID) // GET /api/faq/{id}
+	g.GET("", h.GetAllFAQs)                  // GET /api/faq
+	g.GET("/categories", h.GetFAQCategories) // GET /api/faq/categories
+	g.GET("/:id", h.GetFAQByID)              // GET /api/faq/{id}
 
 	// Admin-protected routes (Write operations)
-	g.POST("", h.CreateFAQ, adminMiddleware)       // POST /api/faq
-	g.PUT("/:id", h.UpdateFAQ, adminMiddleware)    // PUT /api/faq/{id}
-	g.DELETE("/:id", h.DeleteFAQ, adminMiddleware) // DELETE /api/faq/{id}
+	g.POST("", h.CreateFAQ, adminMiddleware)                              // POST /api/faq
+	g.POST("/bulk-recategorize", h.BulkRecategorizeFAQs, adminMiddleware) // POST /api/faq/bulk-recategorize
+	g.PUT("/order", h.ReorderFAQs, adminMiddleware)                       // PUT /api/faq/order
+	g.PUT("/:id", h.UpdateFAQ, adminMiddleware)                           // PUT /api/faq/{id}
+	g.DELETE("/:id", h.DeleteFAQ, adminMiddleware)                        // DELETE /api/faq/{id}
 
 	slog.Debug("Finished registering FAQ routes")
 }
 
 // --- Handler Functions ---
 
-// GetAllFAQs retrieves all FAQ entries, optionally filtered by category.
+// GetAllFAQs retrieves all FAQ entries, optionally filtered by category,
+// ordered by category, then pinned first, then display order.
 //
 // Query Parameters:
 //   - category (optional): Filters FAQs by the specified category name.
 //
 // Returns:
-//   - JSON response containing an array of FAQEntry objects or an error response.
+//   - JSON response containing an array of FAQEntry objects (304 if If-None-Match is current) or an error response.
 func (h *Handler) GetAllFAQs(c echo.Context) error {
 	ctx := c.Request().Context()
 	category := c.QueryParam("category")
 	logger := slog.With("handler", "GetAllFAQs", "categoryFilter", category)
 
-	// --- Build Query ---
-	query := `
-        SELECT id, question, answer, category, created_at, updated_at
-        FROM faq_entries
-    `
-	args := []interface{}{}
-	if category != "" {
-		query += " WHERE category = $1"
-		args = append(args, category)
-	}
-	query += " ORDER BY category, created_at" // Order for consistent results
-
-	logger.DebugContext(ctx, "Executing GetAllFAQs query", "query", query, "args", args)
-
 	// --- Execute Query ---
-	rows, err := h.db.Pool.Query(ctx, query, args...)
+	faqs, err := h.fetchFAQs(ctx, category)
 	if err != nil {
-		logger.ErrorContext(ctx, "Database query failed", "error", err)
+		logger.ErrorContext(ctx, "Failed to retrieve FAQs", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve FAQs.")
 	}
-	defer rows.Close()
-
-	// --- Scan Results ---
-	faqs := make([]models.FAQEntry, 0)
-	for rows.Next() {
-		var faq models.FAQEntry
-		if err := rows.Scan(
-			&faq.ID, &faq.Question, &faq.Answer, &faq.Category,
-			&faq.CreatedAt, &faq.UpdatedAt,
-		); err != nil {
-			logger.ErrorContext(ctx, "Failed to scan FAQ row", "error", err)
-			// Return error immediately if scanning fails for one row
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process FAQ data.")
-		}
-		faqs = append(faqs, faq)
-	}
-
-	if err = rows.Err(); err != nil {
-		logger.ErrorContext(ctx, "Error iterating FAQ rows", "error", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process FAQ results.")
-	}
 
 	// --- Return Response ---
 	logger.InfoContext(ctx, "Retrieved FAQs successfully", "count", len(faqs))
-	return c.JSON(http.StatusOK, models.APIResponse{
+	return etag.JSON(c, http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    faqs,
 	})
@@ -135,7 +116,7 @@ func (h *Handler) GetAllFAQs(c echo.Context) error {
 //   - id: The UUID of the FAQ entry to retrieve.
 //
 // Returns:
-//   - JSON response containing the FAQEntry object or an error response (404 if not found).
+//   - JSON response containing the FAQEntry object (304 if If-None-Match is current) or an error response (404 if not found).
 func (h *Handler) GetFAQByID(c echo.Context) error {
 	ctx := c.Request().Context()
 	faqID := c.Param("id")
@@ -147,15 +128,11 @@ func (h *Handler) GetFAQByID(c echo.Context) error {
 	}
 
 	// --- Fetch FAQ from Database ---
-	var faq models.FAQEntry
-	err := h.db.Pool.QueryRow(ctx, `
-        SELECT id, question, answer, category, created_at, updated_at
+	faq, err := scanFAQ(h.db.Pool.QueryRow(ctx, `
+        SELECT `+faqColumns+`
         FROM faq_entries
         WHERE id = $1
-    `, faqID).Scan(
-		&faq.ID, &faq.Question, &faq.Answer, &faq.Category,
-		&faq.CreatedAt, &faq.UpdatedAt,
-	)
+    `, faqID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			logger.WarnContext(ctx, "FAQ not found")
@@ -167,7 +144,7 @@ func (h *Handler) GetFAQByID(c echo.Context) error {
 
 	// --- Return Response ---
 	logger.InfoContext(ctx, "Retrieved FAQ by ID successfully")
-	return c.JSON(http.StatusOK, models.APIResponse{
+	return etag.JSON(c, http.StatusOK, models.APIResponse{
 		Success: true,
 		Data:    faq,
 	})
@@ -176,7 +153,7 @@ func (h *Handler) GetFAQByID(c echo.Context) error {
 // CreateFAQ creates a new FAQ entry. (Admin Only)
 //
 // Request Body:
-//   - Expects JSON matching models.FAQCreate (question, answer, category).
+//   - Expects JSON matching models.FAQCreate (question, answer, category, optional pinned).
 //
 // Returns:
 //   - JSON response containing the newly created FAQEntry object or an error response.
@@ -195,18 +172,18 @@ func (h *Handler) CreateFAQ(c echo.Context) error {
 	logger.DebugContext(ctx, "Create FAQ request received", "category", faqCreate.Category)
 
 	// --- Insert FAQ into Database ---
-	var createdFAQ models.FAQEntry
-	err := h.db.Pool.QueryRow(ctx, `
-        INSERT INTO faq_entries (question, answer, category, created_at, updated_at)
-        VALUES ($1, $2, $3, $4, $5)
-        RETURNING id, question, answer, category, created_at, updated_at
+	// New entries go to the end of their category's order.
+	pinned := faqCreate.Pinned != nil && *faqCreate.Pinned
+	createdFAQ, err := scanFAQ(h.db.Pool.QueryRow(ctx, `
+        INSERT INTO faq_entries (question, answer, category, display_order, pinned, created_at, updated_at)
+        VALUES ($1, $2, $3,
+                (SELECT COALESCE(MAX(display_order) + 1, 0) FROM faq_entries WHERE category = $3),
+                $4, $5, $6)
+        RETURNING `+faqColumns+`
     `,
-		faqCreate.Question, faqCreate.Answer, faqCreate.Category,
+		faqCreate.Question, faqCreate.Answer, faqCreate.Category, pinned,
 		time.Now(), time.Now(), // Set created_at and updated_at
-	).Scan(
-		&createdFAQ.ID, &createdFAQ.Question, &createdFAQ.Answer, &createdFAQ.Category,
-		&createdFAQ.CreatedAt, &createdFAQ.UpdatedAt,
-	)
+	))
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to insert FAQ into database", "error", err)
 		// TODO: Check for specific DB errors (e.g., constraints)
@@ -228,7 +205,8 @@ func (h *Handler) CreateFAQ(c echo.Context) error {
 //   - id: The UUID of the FAQ entry to update.
 //
 // Request Body:
-//   - Expects JSON matching models.FAQCreate (question, answer, category).
+//   - Expects JSON matching models.FAQCreate (question, answer, category);
+//     pinned is left unchanged when omitted.
 //
 // Returns:
 //   - JSON response containing the updated FAQEntry object or an error response.
@@ -253,20 +231,16 @@ func (h *Handler) UpdateFAQ(c echo.Context) error {
 	logger.DebugContext(ctx, "Update FAQ request received", "category", faqUpdate.Category)
 
 	// --- Update FAQ in Database ---
-	var updatedFAQ models.FAQEntry
-	err := h.db.Pool.QueryRow(ctx, `
+	updatedFAQ, err := scanFAQ(h.db.Pool.QueryRow(ctx, `
         UPDATE faq_entries
-        SET question = $1, answer = $2, category = $3, updated_at = $4
-        WHERE id = $5
-        RETURNING id, question, answer, category, created_at, updated_at
+        SET question = $1, answer = $2, category = $3, pinned = COALESCE($4, pinned), updated_at = $5
+        WHERE id = $6
+        RETURNING `+faqColumns+`
     `,
-		faqUpdate.Question, faqUpdate.Answer, faqUpdate.Category,
+		faqUpdate.Question, faqUpdate.Answer, faqUpdate.Category, faqUpdate.Pinned,
 		time.Now(), // Update updated_at timestamp
 		faqID,
-	).Scan(
-		&updatedFAQ.ID, &updatedFAQ.Question, &updatedFAQ.Answer, &updatedFAQ.Category,
-		&updatedFAQ.CreatedAt, &updatedFAQ.UpdatedAt,
-	)
+	))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			logger.WarnContext(ctx, "FAQ not found for update")
@@ -323,3 +297,192 @@ func (h *Handler) DeleteFAQ(c echo.Context) error {
 		Message: "FAQ entry deleted successfully.",
 	})
 }
+
+// GetFAQCategories lists the distinct FAQ categories with their entry counts,
+// ordered by name.
+//
+// Returns:
+//   - JSON APIResponse containing FAQCategory objects or an error response.
+func (h *Handler) GetFAQCategories(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetFAQCategories")
+
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT category, COUNT(*)::int FROM faq_entries GROUP BY category ORDER BY category`)
+	if err != nil {
+		logger.ErrorContext(ctx, "Database query failed", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve FAQ categories.")
+	}
+	defer rows.Close()
+
+	categories := make([]models.FAQCategory, 0)
+	for rows.Next() {
+		var category models.FAQCategory
+		if err := rows.Scan(&category.Name, &category.Count); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan FAQ category row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process FAQ categories.")
+		}
+		categories = append(categories, category)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating FAQ category rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process FAQ categories.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: categories})
+}
+
+// BulkRecategorizeFAQs moves every FAQ entry in one category to another in a
+// single UPDATE. Moving to a category that has no entries yet renames the
+// category. (Admin Only)
+//
+// Request Body:
+//   - Expects JSON matching models.FAQRecategorize.
+//
+// Returns:
+//   - JSON APIResponse containing a FAQRecategorizeResult or an error response.
+func (h *Handler) BulkRecategorizeFAQs(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "BulkRecategorizeFAQs")
+
+	var req models.FAQRecategorize
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	req.From = strings.TrimSpace(req.From)
+	req.To = strings.TrimSpace(req.To)
+	if req.From == "" || req.To == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "from and to are required.")
+	}
+	if req.From == req.To {
+		return echo.NewHTTPError(http.StatusBadRequest, "from and to must differ.")
+	}
+
+	commandTag, err := h.db.Pool.Exec(ctx, `
+        UPDATE faq_entries SET category = $2, updated_at = NOW() WHERE category = $1`, req.From, req.To)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to recategorize FAQ entries", "from", req.From, "to", req.To, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to recategorize FAQ entries.")
+	}
+
+	updated := commandTag.RowsAffected()
+	logger.InfoContext(ctx, "FAQ entries recategorized", "from", req.From, "to", req.To, "updated", updated)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "FAQ entries recategorized.",
+		Data:    models.FAQRecategorizeResult{From: req.From, To: req.To, Updated: updated},
+	})
+}
+
+// ReorderFAQs sets the display order of the entries in one category. Pinned
+// entries still come first; the order applies among pinned and among unpinned
+// entries. (Admin Only)
+//
+// Request Body:
+//   - Expects JSON matching models.FAQOrder: the category and all of its entry
+//     IDs, each once, in display order.
+//
+// Returns:
+//   - JSON APIResponse containing the category's reordered []FAQEntry, 400 if
+//     the list does not match the category's entries, or another error response.
+func (h *Handler) ReorderFAQs(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "ReorderFAQs")
+
+	var req models.FAQOrder
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	req.Category = strings.TrimSpace(req.Category)
+	if req.Category == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "category is required.")
+	}
+	logger = logger.With("category", req.Category)
+
+	// --- Validate Against the Category's Entries ---
+	current, err := h.fetchFAQs(ctx, req.Category)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load FAQ entries", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve FAQs.")
+	}
+	if len(current) == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "FAQ category not found.")
+	}
+	remaining := make(map[string]bool, len(current))
+	for _, faq := range current {
+		remaining[faq.ID] = true
+	}
+	for _, id := range req.FAQIDs {
+		if !remaining[id] {
+			return echo.NewHTTPError(http.StatusBadRequest, "faq_ids must list each entry of the category exactly once.")
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "faq_ids must list each entry of the category exactly once.")
+	}
+
+	// --- Store the Order ---
+	if _, err := h.db.Pool.Exec(ctx, `
+        UPDATE faq_entries f SET display_order = o.position - 1
+        FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+        WHERE f.id = o.id AND f.category = $1`, req.Category, req.FAQIDs); err != nil {
+		logger.ErrorContext(ctx, "Failed to store FAQ order", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to reorder FAQ entries.")
+	}
+
+	faqs, err := h.fetchFAQs(ctx, req.Category)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to reload FAQ entries", "error", err)
+		return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: "FAQ entries reordered."})
+	}
+	logger.InfoContext(ctx, "FAQ entries reordered", "count", len(faqs))
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "FAQ entries reordered.",
+		Data:    faqs,
+	})
+}
+
+// --- Helper Functions ---
+
+// fetchFAQs loads the FAQ entries in listing order, optionally limited to one
+// category ("" for all).
+func (h *Handler) fetchFAQs(ctx context.Context, category string) ([]models.FAQEntry, error) {
+	query := `SELECT ` + faqColumns + ` FROM faq_entries`
+	args := []interface{}{}
+	if category != "" {
+		query += " WHERE category = $1"
+		args = append(args, category)
+	}
+	query += faqOrderBy
+
+	rows, err := h.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query FAQs: %w", err)
+	}
+	defer rows.Close()
+
+	faqs := make([]models.FAQEntry, 0)
+	for rows.Next() {
+		faq, err := scanFAQ(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan FAQ row: %w", err)
+		}
+		faqs = append(faqs, faq)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read FAQ rows: %w", err)
+	}
+	return faqs, nil
+}
+
+// scanFAQ scans a row selected with faqColumns.
+func scanFAQ(row pgx.Row) (models.FAQEntry, error) {
+	var faq models.FAQEntry
+	err := row.Scan(
+		&faq.ID, &faq.Question, &faq.Answer, &faq.Category,
+		&faq.DisplayOrder, &faq.Pinned, &faq.CreatedAt, &faq.UpdatedAt,
+	)
+	return faq, err
+}
@@ -0,0 +1,225 @@
+// backend/internal/api/handlers/search/search.go
+// ==========================================================================
+// Handler for the global search endpoint. Searches tickets and FAQs in
+// parallel and merges the hits into a single relevance-ordered list.
+// Note: the tree has no task entity yet, so only tickets and FAQs are searched.
+// ==========================================================================
+
+package search
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/pagination"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultPerTypeLimit = 10  // Hits returned per entity type unless overridden
+	maxPerTypeLimit     = 25  // Upper bound for the per_type parameter
+	defaultTotalLimit   = 20  // Hits returned overall unless overridden
+	maxTotalLimit       = 50  // Upper bound for the limit parameter
+	snippetLength       = 160 // Characters of body text included in each hit
+)
+
+// --- Handler Struct ---
+
+// Handler holds dependencies for the global search endpoint.
+type Handler struct {
+	db     *db.DB         // Database connection pool
+	config *config.Config // Application configuration (page size cap)
+}
+
+// --- Constructor ---
+
+// NewHandler creates a new instance of the search Handler.
+//
+// Parameters:
+//   - db: The database connection pool (*db.DB).
+//   - cfg: The application configuration (*config.Config).
+//
+// Returns:
+//   - *Handler: A pointer to the newly created Handler.
+func NewHandler(db *db.DB, cfg *config.Config) *Handler {
+	return &Handler{
+		db:     db,
+		config: cfg,
+	}
+}
+
+// --- Handler Functions ---
+
+// Search runs the ticket and FAQ searches concurrently and returns a merged,
+// typed result list ordered by relevance score.
+// Ticket hits honor the shared ticket access rule: Admins see every ticket, other
+// users see only tickets matching db.TicketAccessCondition.
+//
+// Query Parameters:
+//   - q: The search text (required).
+//   - per_type: Maximum hits per entity type (default 10, max 25).
+//   - limit: Maximum hits overall (default 20, max 50 or API_MAX_PAGE_SIZE if lower).
+//
+// Returns:
+//   - JSON response containing an array of SearchResult objects or an error response.
+func (h *Handler) Search(c echo.Context) error {
+	ctx := c.Request().Context()
+	query := strings.TrimSpace(c.QueryParam("q"))
+	logger := slog.With("handler", "GlobalSearch", "query", query)
+
+	// --- 1. Input Validation ---
+	if query == "" {
+		logger.WarnContext(ctx, "Missing search query parameter")
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing search query parameter 'q'.")
+	}
+	perType := pagination.Limit(c.QueryParam("per_type"), defaultPerTypeLimit, min(maxPerTypeLimit, h.config.Server.MaxPageSize))
+	total := pagination.Limit(c.QueryParam("limit"), defaultTotalLimit, min(maxTotalLimit, h.config.Server.MaxPageSize))
+
+	// --- 2. Requesting User Context ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// --- 3. Run Searches Concurrently ---
+	var ticketHits, faqHits []models.SearchResult
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var searchErr error
+		ticketHits, searchErr = h.searchTickets(gctx, query, userID, userRole == models.RoleAdmin, perType)
+		return searchErr
+	})
+	g.Go(func() error {
+		var searchErr error
+		faqHits, searchErr = h.searchFAQs(gctx, query, perType)
+		return searchErr
+	})
+	if err := g.Wait(); err != nil {
+		logger.ErrorContext(ctx, "Global search failed", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to perform search.")
+	}
+
+	// --- 4. Merge, Rank and Cap ---
+	results := make([]models.SearchResult, 0, len(ticketHits)+len(faqHits))
+	results = append(results, ticketHits...)
+	results = append(results, faqHits...)
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].UpdatedAt.After(results[j].UpdatedAt)
+	})
+	if len(results) > total {
+		results = results[:total]
+	}
+
+	logger.InfoContext(ctx, "Global search successful", "tickets", len(ticketHits), "faqs", len(faqHits), "returned", len(results))
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    results,
+	})
+}
+
+// --- Entity Searches ---
+
+// searchTickets finds tickets visible to the requesting user (the same scope as
+// the ticket list). Matches on the ticket number rank highest, then subject,
+// description and submitter fields.
+func (h *Handler) searchTickets(ctx context.Context, query, userID string, isAdmin bool, limit int) ([]models.SearchResult, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT id, ticket_number, subject, description, status, updated_at,
+               GREATEST(
+                   CASE WHEN CAST(ticket_number AS TEXT) = $1 THEN 1.0 ELSE 0 END,
+                   CASE WHEN subject ILIKE '%' || $1 || '%' THEN 0.8 ELSE 0 END,
+                   CASE WHEN description ILIKE '%' || $1 || '%' THEN 0.5 ELSE 0 END,
+                   CASE WHEN submitter_name ILIKE '%' || $1 || '%' OR end_user_email ILIKE '%' || $1 || '%' THEN 0.4 ELSE 0 END
+               )::float8 AS score
+        FROM tickets
+        WHERE (subject ILIKE '%' || $1 || '%'
+               OR description ILIKE '%' || $1 || '%'
+               OR submitter_name ILIKE '%' || $1 || '%'
+               OR end_user_email ILIKE '%' || $1 || '%'
+               OR CAST(ticket_number AS TEXT) = $1)
+          AND ($2 OR `+db.TicketAccessCondition("tickets", "$3")+`)
+          AND quarantined_at IS NULL AND spam_at IS NULL
+        ORDER BY score DESC, updated_at DESC
+        LIMIT $4`, query, isAdmin, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := make([]models.SearchResult, 0)
+	for rows.Next() {
+		var hit models.SearchResult
+		var ticketNumber int32
+		var subject, description string
+		if err := rows.Scan(&hit.ID, &ticketNumber, &subject, &description, &hit.Status, &hit.UpdatedAt, &hit.Score); err != nil {
+			return nil, err
+		}
+		hit.Type = models.SearchTypeTicket
+		hit.Title = "#" + strconv.Itoa(int(ticketNumber)) + " " + subject
+		hit.Snippet = snippet(description)
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// searchFAQs finds FAQ entries. FAQs are public, so no visibility filter applies.
+func (h *Handler) searchFAQs(ctx context.Context, query string, limit int) ([]models.SearchResult, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT id, question, answer, updated_at,
+               GREATEST(
+                   CASE WHEN question ILIKE '%' || $1 || '%' THEN 0.8 ELSE 0 END,
+                   CASE WHEN answer ILIKE '%' || $1 || '%' THEN 0.5 ELSE 0 END,
+                   CASE WHEN category ILIKE '%' || $1 || '%' THEN 0.3 ELSE 0 END
+               )::float8 AS score
+        FROM faq_entries
+        WHERE question ILIKE '%' || $1 || '%'
+           OR answer ILIKE '%' || $1 || '%'
+           OR category ILIKE '%' || $1 || '%'
+        ORDER BY score DESC, updated_at DESC
+        LIMIT $2`, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	hits := make([]models.SearchResult, 0)
+	for rows.Next() {
+		var hit models.SearchResult
+		var answer string
+		if err := rows.Scan(&hit.ID, &hit.Title, &answer, &hit.UpdatedAt, &hit.Score); err != nil {
+			return nil, err
+		}
+		hit.Type = models.SearchTypeFAQ
+		hit.Snippet = snippet(answer)
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// --- Helpers ---
+
+// snippet trims body text to a short preview suitable for search results.
+func snippet(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= snippetLength {
+		return text
+	}
+	return string(runes[:snippetLength]) + "..."
+}
@@ -0,0 +1,361 @@
+// backend/internal/api/handlers/solution/solution.go
+// ==========================================================================
+// Handler functions for the solution knowledge base: admin management of
+// known fixes and the public keyword search used before submitting a ticket.
+// Matching itself lives in the solutions package, which ticket creation also
+// uses to suggest solutions.
+// ==========================================================================
+
+package solution
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/solutions"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// maxSearchResults is the number of matches returned by SearchSolutions.
+const maxSearchResults = 5
+
+// maxSearchTextLength bounds the text accepted by SearchSolutions.
+const maxSearchTextLength = 10000
+
+// solutionColumns is the column list scanned by scanSolution.
+const solutionColumns = `id, title, body, issue_type, keywords, created_at, updated_at`
+
+// --- Handler Struct ---
+
+// Handler holds dependencies for solution knowledge base request handlers.
+type Handler struct {
+	db *db.DB // Database connection pool
+}
+
+// --- Constructor ---
+
+// NewHandler creates a new instance of the solution Handler.
+//
+// Parameters:
+//   - db: The database connection pool (*db.DB).
+//
+// Returns:
+//   - *Handler: A pointer to the newly created Handler.
+func NewHandler(db *db.DB) *Handler {
+	return &Handler{
+		db: db,
+	}
+}
+
+// --- Handler Functions ---
+
+// GetAllSolutions lists the knowledge base, optionally filtered by issue type.
+//
+// Query Parameters:
+//   - issue_type (optional): Only solutions for this issue type (and those for every type).
+//
+// Returns:
+//   - JSON APIResponse containing Solution objects or an error response.
+func (h *Handler) GetAllSolutions(c echo.Context) error {
+	ctx := c.Request().Context()
+	issueType := strings.TrimSpace(c.QueryParam("issue_type"))
+	logger := slog.With("handler", "GetAllSolutions", "issueTypeFilter", issueType)
+
+	query := `SELECT ` + solutionColumns + ` FROM solutions`
+	args := []interface{}{}
+	if issueType != "" {
+		query += ` WHERE issue_type IS NULL OR LOWER(issue_type) = LOWER($1)`
+		args = append(args, issueType)
+	}
+	query += ` ORDER BY issue_type NULLS FIRST, title`
+
+	rows, err := h.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		logger.ErrorContext(ctx, "Database query failed", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve solutions.")
+	}
+	defer rows.Close()
+
+	list := make([]models.Solution, 0)
+	for rows.Next() {
+		s, err := scanSolution(rows)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to scan solution row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process solution data.")
+		}
+		list = append(list, s)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating solution rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process solution results.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: list})
+}
+
+// GetSolutionByID retrieves a single solution.
+//
+// Path Parameters:
+//   - id: The UUID of the solution.
+//
+// Returns:
+//   - JSON APIResponse containing the Solution or an error response (404 if not found).
+func (h *Handler) GetSolutionByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	solutionID := c.Param("id")
+	logger := slog.With("handler", "GetSolutionByID", "solutionID", solutionID)
+
+	s, err := scanSolution(h.db.Pool.QueryRow(ctx, `SELECT `+solutionColumns+` FROM solutions WHERE id = $1`, solutionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Solution not found.")
+		}
+		logger.ErrorContext(ctx, "Database query failed", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve solution.")
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: s})
+}
+
+// CreateSolution adds a solution to the knowledge base. (Admin Only)
+//
+// Request Body:
+//   - Expects JSON matching models.SolutionCreate.
+//
+// Returns:
+//   - JSON APIResponse containing the created Solution or an error response.
+func (h *Handler) CreateSolution(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "CreateSolution")
+
+	req, err := bindSolution(c)
+	if err != nil {
+		return err
+	}
+
+	s, err := scanSolution(h.db.Pool.QueryRow(ctx, `
+        INSERT INTO solutions (title, body, issue_type, keywords, created_by_user_id)
+        VALUES ($1, $2, NULLIF($3, ''), $4, NULLIF($5, '')::uuid)
+        RETURNING `+solutionColumns,
+		req.Title, req.Body, req.IssueType, req.Keywords, auth.OptionalUserID(c)))
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to insert solution", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create solution.")
+	}
+
+	logger.InfoContext(ctx, "Solution created", "solutionID", s.ID)
+	return c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Solution created successfully.",
+		Data:    s,
+	})
+}
+
+// UpdateSolution replaces a solution's content. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the solution.
+//
+// Request Body:
+//   - Expects JSON matching models.SolutionCreate.
+//
+// Returns:
+//   - JSON APIResponse containing the updated Solution or an error response.
+func (h *Handler) UpdateSolution(c echo.Context) error {
+	ctx := c.Request().Context()
+	solutionID := c.Param("id")
+	logger := slog.With("handler", "UpdateSolution", "solutionID", solutionID)
+
+	req, err := bindSolution(c)
+	if err != nil {
+		return err
+	}
+
+	s, err := scanSolution(h.db.Pool.QueryRow(ctx, `
+        UPDATE solutions
+        SET title = $2, body = $3, issue_type = NULLIF($4, ''), keywords = $5, updated_at = NOW()
+        WHERE id = $1
+        RETURNING `+solutionColumns,
+		solutionID, req.Title, req.Body, req.IssueType, req.Keywords))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Solution not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to update solution", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to update solution.")
+	}
+
+	logger.InfoContext(ctx, "Solution updated")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Solution updated successfully.",
+		Data:    s,
+	})
+}
+
+// DeleteSolution removes a solution and its effectiveness history. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the solution.
+//
+// Returns:
+//   - JSON success message or an error response.
+func (h *Handler) DeleteSolution(c echo.Context) error {
+	ctx := c.Request().Context()
+	solutionID := c.Param("id")
+	logger := slog.With("handler", "DeleteSolution", "solutionID", solutionID)
+
+	tag, err := h.db.Pool.Exec(ctx, `DELETE FROM solutions WHERE id = $1`, solutionID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to delete solution", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to delete solution.")
+	}
+	if tag.RowsAffected() == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "Solution not found.")
+	}
+
+	logger.InfoContext(ctx, "Solution deleted")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Solution deleted successfully.",
+	})
+}
+
+// SearchSolutions matches free text against the knowledge base by issue type
+// and keyword overlap. Public, so submitters can check for a known fix before
+// opening a ticket.
+//
+// Request Body:
+//   - Expects JSON matching models.SolutionSearchRequest.
+//
+// Returns:
+//   - JSON APIResponse containing SolutionMatch objects, best first.
+func (h *Handler) SearchSolutions(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "SearchSolutions")
+
+	var req models.SolutionSearchRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	if strings.TrimSpace(req.Text) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "text is required.")
+	}
+	if len(req.Text) > maxSearchTextLength {
+		return echo.NewHTTPError(http.StatusBadRequest, "text is too long.")
+	}
+
+	matches, err := solutions.Match(ctx, h.db.Pool, req.IssueType, req.Text, req.Email, maxSearchResults)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to search solutions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search solutions.")
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: matches})
+}
+
+// GetSolutionReport ranks solutions by how often the tickets they were
+// suggested for went on to be closed. (Admin Only)
+//
+// Query Parameters:
+//   - limit (optional): Number of solutions to return (default 20, max 100).
+//
+// Returns:
+//   - JSON APIResponse containing SolutionReportEntry objects, most closures first.
+func (h *Handler) GetSolutionReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetSolutionReport")
+
+	limit := 20
+	if parsed, err := strconv.Atoi(c.QueryParam("limit")); err == nil && parsed > 0 && parsed <= 100 {
+		limit = parsed
+	}
+
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT s.id, s.title, s.issue_type,
+               COUNT(ss.id)::int AS times_suggested,
+               COUNT(ss.id) FILTER (WHERE t.status = 'Closed' AND t.closed_at >= ss.shown_at)::int AS closed_after,
+               COUNT(ss.emailed_at)::int,
+               COUNT(ss.id) FILTER (WHERE ss.emailed_at IS NOT NULL AND t.status = 'Closed' AND t.closed_at >= ss.emailed_at)::int,
+               COALESCE((SELECT SUM(helpful_count) FROM solution_effectiveness se WHERE se.solution_id = s.id), 0)::int,
+               COALESCE((SELECT SUM(not_helpful_count) FROM solution_effectiveness se WHERE se.solution_id = s.id), 0)::int,
+               MAX(ss.shown_at)
+        FROM solutions s
+        JOIN solution_suggestions ss ON ss.solution_id = s.id
+        JOIN tickets t ON t.id = ss.ticket_id
+        WHERE t.spam_at IS NULL
+        GROUP BY s.id
+        ORDER BY closed_after DESC, times_suggested DESC, s.title
+        LIMIT $1`, limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query solution report", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build solution report.")
+	}
+	defer rows.Close()
+
+	report := make([]models.SolutionReportEntry, 0)
+	for rows.Next() {
+		var entry models.SolutionReportEntry
+		if err := rows.Scan(
+			&entry.SolutionID, &entry.Title, &entry.IssueType, &entry.TimesSuggested, &entry.ClosedAfter,
+			&entry.TimesEmailed, &entry.ClosedAfterEmail,
+			&entry.HelpfulCount, &entry.NotHelpfulCount, &entry.LastSuggestedAt,
+		); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan solution report row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build solution report.")
+		}
+		if entry.TimesSuggested > 0 {
+			entry.ClosureRate = float64(entry.ClosedAfter) / float64(entry.TimesSuggested)
+		}
+		report = append(report, entry)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating solution report rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build solution report.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: report})
+}
+
+// --- Helper Functions ---
+
+// bindSolution binds and validates a SolutionCreate body, normalizing its
+// keywords (defaulting to the title's words).
+func bindSolution(c echo.Context) (models.SolutionCreate, error) {
+	var req models.SolutionCreate
+	if err := c.Bind(&req); err != nil {
+		return req, echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	req.Body = strings.TrimSpace(req.Body)
+	req.IssueType = strings.TrimSpace(req.IssueType)
+	if req.Title == "" || req.Body == "" {
+		return req, echo.NewHTTPError(http.StatusBadRequest, "title and body are required.")
+	}
+	if len(req.Title) > 255 {
+		return req, echo.NewHTTPError(http.StatusBadRequest, "title must be at most 255 characters.")
+	}
+	if len(req.IssueType) > 100 {
+		return req, echo.NewHTTPError(http.StatusBadRequest, "issue_type must be at most 100 characters.")
+	}
+	req.Keywords = solutions.NormalizeKeywords(req.Keywords)
+	if len(req.Keywords) == 0 {
+		req.Keywords = solutions.Keywords(req.Title)
+	}
+	if len(req.Keywords) == 0 {
+		return req, echo.NewHTTPError(http.StatusBadRequest, "At least one keyword is required.")
+	}
+	return req, nil
+}
+
+// scanSolution scans a row selected with solutionColumns.
+func scanSolution(row pgx.Row) (models.Solution, error) {
+	var s models.Solution
+	err := row.Scan(&s.ID, &s.Title, &s.Body, &s.IssueType, &s.Keywords, &s.CreatedAt, &s.UpdatedAt)
+	return s, err
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/db"
@@ -129,6 +130,7 @@ func (h *Handler) CreateTag(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 	// TODO: Add explicit validation if not handled by middleware
+	tagCreate.Name = strings.Join(strings.Fields(tagCreate.Name), " ") // Trim and collapse whitespace; case is kept for display
 	if tagCreate.Name == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "Tag name cannot be empty.")
 	}
@@ -137,7 +139,7 @@ func (h *Handler) CreateTag(c echo.Context) error {
 
 	// --- Check for Existing Tag ---
 	var exists bool
-	err := h.db.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM tags WHERE name = $1)`, tagCreate.Name).Scan(&exists)
+	err := h.db.Pool.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM tags WHERE LOWER(name) = LOWER($1))`, tagCreate.Name).Scan(&exists)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to check for existing tag", "tagName", tagCreate.Name, "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Database error checking tag.")
@@ -149,13 +151,18 @@ func (h *Handler) CreateTag(c echo.Context) error {
 
 	// --- Insert Tag into Database ---
 	var newTag models.Tag
+	// ON CONFLICT covers a concurrent create of the same name after the check above.
 	err = h.db.Pool.QueryRow(ctx, `
         INSERT INTO tags (name, created_at) VALUES ($1, $2)
+        ON CONFLICT ((LOWER(name))) DO NOTHING
         RETURNING id, name, created_at
     `, tagCreate.Name, time.Now()).Scan(&newTag.ID, &newTag.Name, &newTag.CreatedAt)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WarnContext(ctx, "Attempted to create duplicate tag", "tagName", tagCreate.Name)
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Tag '%s' already exists.", tagCreate.Name))
+		}
 		logger.ErrorContext(ctx, "Failed to insert tag into database", "tagName", tagCreate.Name, "error", err)
-		// TODO: Check for specific DB errors (e.g., unique constraint race condition)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create tag.")
 	}
 
@@ -232,3 +239,82 @@ func (h *Handler) DeleteTag(c echo.Context) error {
 		Message: "Tag deleted successfully.",
 	})
 }
+
+// MergeDuplicateTags merges tags whose names differ only by case or
+// whitespace (e.g., "VPN", "vpn" and " Vpn") into the oldest of them, moving
+// ticket links to the kept tag and normalizing remaining names. It is the
+// one-time cleanup for databases created before tag names were unique
+// case-insensitively, and is safe to run again. (Admin Only)
+//
+// Returns:
+//   - JSON response with the number of merged tags and moved ticket links, or an error response.
+func (h *Handler) MergeDuplicateTags(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "MergeDuplicateTags")
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to start transaction.")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
+			}
+		}
+	}()
+
+	// Block concurrent tag creation so the duplicate set cannot change mid-merge.
+	if _, err = tx.Exec(ctx, `LOCK TABLE tags IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		logger.ErrorContext(ctx, "Failed to lock tags table", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to merge tags.")
+	}
+	if _, err = tx.Exec(ctx, `
+        CREATE TEMP TABLE tag_merges ON COMMIT DROP AS
+        SELECT id, keep_id FROM (
+            SELECT id, FIRST_VALUE(id) OVER (
+                PARTITION BY LOWER(regexp_replace(btrim(name), '\s+', ' ', 'g'))
+                ORDER BY created_at, id) AS keep_id
+            FROM tags
+        ) ranked
+        WHERE id <> keep_id`); err != nil {
+		logger.ErrorContext(ctx, "Failed to find duplicate tags", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to merge tags.")
+	}
+
+	moved, err := tx.Exec(ctx, `
+        INSERT INTO ticket_tags (ticket_id, tag_id)
+        SELECT tt.ticket_id, m.keep_id FROM ticket_tags tt JOIN tag_merges m ON m.id = tt.tag_id
+        ON CONFLICT DO NOTHING`)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to move ticket links to kept tags", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to merge tags.")
+	}
+	merged, err := tx.Exec(ctx, `DELETE FROM tags WHERE id IN (SELECT id FROM tag_merges)`) // Cascades to their ticket_tags rows
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to delete duplicate tags", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to merge tags.")
+	}
+	if _, err = tx.Exec(ctx, `
+        UPDATE tags SET name = regexp_replace(btrim(name), '\s+', ' ', 'g')
+        WHERE name <> regexp_replace(btrim(name), '\s+', ' ', 'g')`); err != nil {
+		logger.ErrorContext(ctx, "Failed to normalize tag names", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to merge tags.")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit tag merge", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to merge tags.")
+	}
+
+	logger.InfoContext(ctx, "Merged duplicate tags", "mergedTags", merged.RowsAffected(), "movedLinks", moved.RowsAffected())
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Merged %d duplicate tags.", merged.RowsAffected()),
+		Data: map[string]int64{
+			"merged_tags": merged.RowsAffected(),
+			"moved_links": moved.RowsAffected(),
+		},
+	})
+}
@@ -0,0 +1,429 @@
+// backend/internal/api/handlers/team/team.go
+// ==========================================================================
+// Handler functions for support teams. Staff can list teams and their
+// members (for assignment pickers); creating, renaming and deleting teams and
+// changing membership is Admin only. Team assignment of tickets and the
+// member queues are handled by the ticket package.
+// ==========================================================================
+
+package team
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// maxTeamNameLength bounds team names (teams.name is VARCHAR(100)).
+const maxTeamNameLength = 100
+
+// teamColumns is the column list scanned by scanTeam.
+const teamColumns = `id, name, created_at, updated_at`
+
+// --- Handler Struct ---
+
+// Handler holds dependencies for team request handlers.
+type Handler struct {
+	db           *db.DB        // Database connection pool
+	auditService audit.Service // Records membership changes, which change ticket access
+}
+
+// --- Constructor ---
+
+// NewHandler creates a new instance of the team Handler.
+//
+// Parameters:
+//   - db: The database connection pool (*db.DB).
+//   - auditService: The audit log service.
+//
+// Returns:
+//   - *Handler: A pointer to the newly created Handler.
+func NewHandler(db *db.DB, auditService audit.Service) *Handler {
+	return &Handler{
+		db:           db,
+		auditService: auditService,
+	}
+}
+
+// --- Route Registration ---
+
+// RegisterRoutes registers the team routes. Reads are open to all staff;
+// writes require adminMiddleware.
+//
+// Parameters:
+//   - g: The echo group (e.g., /api/teams) to register routes onto (*echo.Group).
+//   - h: The team Handler instance (*Handler).
+//   - adminMiddleware: The middleware function to restrict access to Admins only.
+func RegisterRoutes(g *echo.Group, h *Handler, adminMiddleware echo.MiddlewareFunc) {
+	slog.Debug("Registering team routes")
+
+	g.GET("", h.GetAllTeams)     // GET /api/teams
+	g.GET("/:id", h.GetTeamByID) // GET /api/teams/{id}
+
+	g.POST("", h.CreateTeam, adminMiddleware)                             // POST /api/teams
+	g.PUT("/:id", h.UpdateTeam, adminMiddleware)                          // PUT /api/teams/{id}
+	g.DELETE("/:id", h.DeleteTeam, adminMiddleware)                       // DELETE /api/teams/{id}
+	g.PUT("/:id/members/:userId", h.AddTeamMember, adminMiddleware)       // PUT /api/teams/{id}/members/{userId}
+	g.DELETE("/:id/members/:userId", h.RemoveTeamMember, adminMiddleware) // DELETE /api/teams/{id}/members/{userId}
+
+	slog.Debug("Finished registering team routes")
+}
+
+// --- Handler Functions ---
+
+// GetAllTeams lists the teams with their members, ordered by name.
+//
+// Returns:
+//   - JSON APIResponse containing Team objects or an error response.
+func (h *Handler) GetAllTeams(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetAllTeams")
+
+	rows, err := h.db.Pool.Query(ctx, `SELECT `+teamColumns+` FROM teams ORDER BY LOWER(name)`)
+	if err != nil {
+		logger.ErrorContext(ctx, "Database query failed", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve teams.")
+	}
+	defer rows.Close()
+
+	teams := make([]models.Team, 0)
+	index := make(map[string]int)
+	for rows.Next() {
+		t, err := scanTeam(rows)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to scan team row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process team data.")
+		}
+		t.Members = []models.UserSummary{}
+		index[t.ID] = len(teams)
+		teams = append(teams, t)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating team rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process team results.")
+	}
+
+	memberRows, err := h.db.Pool.Query(ctx, `
+        SELECT tm.team_id, u.id, u.name, u.role
+        FROM team_members tm
+        JOIN users u ON u.id = tm.user_id
+        ORDER BY u.name`)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query team members", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve teams.")
+	}
+	defer memberRows.Close()
+	for memberRows.Next() {
+		var teamID string
+		var member models.UserSummary
+		if err := memberRows.Scan(&teamID, &member.ID, &member.Name, &member.Role); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan team member row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process team data.")
+		}
+		if i, ok := index[teamID]; ok {
+			teams[i].Members = append(teams[i].Members, member)
+		}
+	}
+	if err := memberRows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating team member rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process team results.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: teams})
+}
+
+// GetTeamByID retrieves a single team with its members.
+//
+// Path Parameters:
+//   - id: The UUID of the team.
+//
+// Returns:
+//   - JSON APIResponse containing the Team or an error response (404 if not found).
+func (h *Handler) GetTeamByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	teamID := c.Param("id")
+	logger := slog.With("handler", "GetTeamByID", "teamID", teamID)
+
+	t, err := h.loadTeam(c, teamID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Team not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load team", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve team.")
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: t})
+}
+
+// CreateTeam creates a team with no members. (Admin Only)
+//
+// Request Body:
+//   - Expects JSON matching models.TeamCreate.
+//
+// Returns:
+//   - JSON APIResponse containing the created Team or an error response (409 if the name is taken).
+func (h *Handler) CreateTeam(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "CreateTeam")
+
+	name, err := bindTeamName(c)
+	if err != nil {
+		return err
+	}
+
+	t, err := scanTeam(h.db.Pool.QueryRow(ctx, `
+        INSERT INTO teams (name) VALUES ($1)
+        RETURNING `+teamColumns, name))
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return echo.NewHTTPError(http.StatusConflict, "A team with this name already exists.")
+		}
+		logger.ErrorContext(ctx, "Failed to insert team", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create team.")
+	}
+	t.Members = []models.UserSummary{}
+
+	logger.InfoContext(ctx, "Team created", "teamID", t.ID, "name", t.Name)
+	return c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Team created successfully.",
+		Data:    t,
+	})
+}
+
+// UpdateTeam renames a team. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the team.
+//
+// Request Body:
+//   - Expects JSON matching models.TeamCreate.
+//
+// Returns:
+//   - JSON APIResponse containing the updated Team or an error response.
+func (h *Handler) UpdateTeam(c echo.Context) error {
+	ctx := c.Request().Context()
+	teamID := c.Param("id")
+	logger := slog.With("handler", "UpdateTeam", "teamID", teamID)
+
+	name, err := bindTeamName(c)
+	if err != nil {
+		return err
+	}
+
+	tag, err := h.db.Pool.Exec(ctx, `UPDATE teams SET name = $2, updated_at = NOW() WHERE id = $1`, teamID, name)
+	if err != nil {
+		if db.IsUniqueViolation(err) {
+			return echo.NewHTTPError(http.StatusConflict, "A team with this name already exists.")
+		}
+		logger.ErrorContext(ctx, "Failed to update team", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to update team.")
+	}
+	if tag.RowsAffected() == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "Team not found.")
+	}
+
+	t, err := h.loadTeam(c, teamID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to reload team", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Team updated, but failed to retrieve it.")
+	}
+
+	logger.InfoContext(ctx, "Team renamed", "name", name)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Team updated successfully.",
+		Data:    t,
+	})
+}
+
+// DeleteTeam removes a team. Its tickets keep their individual assignee (if
+// any) and lose the team assignment. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the team.
+//
+// Returns:
+//   - JSON success message or an error response.
+func (h *Handler) DeleteTeam(c echo.Context) error {
+	ctx := c.Request().Context()
+	teamID := c.Param("id")
+	logger := slog.With("handler", "DeleteTeam", "teamID", teamID)
+
+	tag, err := h.db.Pool.Exec(ctx, `DELETE FROM teams WHERE id = $1`, teamID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to delete team", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to delete team.")
+	}
+	if tag.RowsAffected() == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "Team not found.")
+	}
+
+	logger.InfoContext(ctx, "Team deleted")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Team deleted successfully.",
+	})
+}
+
+// AddTeamMember adds a staff member or admin to a team. Adding an existing
+// member is a no-op. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the team.
+//   - userId: The UUID of the user.
+//
+// Returns:
+//   - JSON APIResponse containing the updated Team or an error response.
+func (h *Handler) AddTeamMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	teamID := c.Param("id")
+	userID := c.Param("userId")
+	logger := slog.With("handler", "AddTeamMember", "teamID", teamID, "userID", userID)
+
+	var role models.UserRole
+	if err := h.db.Pool.QueryRow(ctx, `SELECT role FROM users WHERE id = $1`, userID).Scan(&role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "User not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load user", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add team member.")
+	}
+	if role != models.RoleStaff && role != models.RoleAdmin {
+		return echo.NewHTTPError(http.StatusBadRequest, "Only staff and admins can be team members.")
+	}
+
+	tag, err := h.db.Pool.Exec(ctx, `
+        INSERT INTO team_members (team_id, user_id) VALUES ($1, $2)
+        ON CONFLICT (team_id, user_id) DO NOTHING`, teamID, userID)
+	if err != nil {
+		if db.IsForeignKeyViolation(err) {
+			return echo.NewHTTPError(http.StatusNotFound, "Team not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to add team member", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to add team member.")
+	}
+	if tag.RowsAffected() > 0 {
+		h.auditService.RecordAsync(audit.Event{
+			Action:       audit.ActionTeamMemberAdd,
+			ActorUserID:  auth.OptionalUserID(c),
+			ResourceType: "team",
+			ResourceID:   teamID,
+			IPAddress:    c.RealIP(),
+			Metadata:     map[string]interface{}{"user_id": userID},
+		})
+		logger.InfoContext(ctx, "Team member added")
+	}
+
+	return h.respondWithTeam(c, teamID, "Team member added.")
+}
+
+// RemoveTeamMember removes a user from a team. Tickets the user already
+// claimed stay assigned to them. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the team.
+//   - userId: The UUID of the user.
+//
+// Returns:
+//   - JSON APIResponse containing the updated Team or an error response (404 if not a member).
+func (h *Handler) RemoveTeamMember(c echo.Context) error {
+	ctx := c.Request().Context()
+	teamID := c.Param("id")
+	userID := c.Param("userId")
+	logger := slog.With("handler", "RemoveTeamMember", "teamID", teamID, "userID", userID)
+
+	tag, err := h.db.Pool.Exec(ctx, `DELETE FROM team_members WHERE team_id = $1 AND user_id = $2`, teamID, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to remove team member", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to remove team member.")
+	}
+	if tag.RowsAffected() == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "User is not a member of this team.")
+	}
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionTeamMemberRemove,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "team",
+		ResourceID:   teamID,
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"user_id": userID},
+	})
+	logger.InfoContext(ctx, "Team member removed")
+
+	return h.respondWithTeam(c, teamID, "Team member removed.")
+}
+
+// --- Helper Functions ---
+
+// respondWithTeam returns the team with its current members after a membership change.
+func (h *Handler) respondWithTeam(c echo.Context, teamID, message string) error {
+	t, err := h.loadTeam(c, teamID)
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "Failed to reload team", "teamID", teamID, "error", err)
+		return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: message})
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: message, Data: t})
+}
+
+// loadTeam loads a team and its members. Returns pgx.ErrNoRows if the team
+// does not exist.
+func (h *Handler) loadTeam(c echo.Context, teamID string) (models.Team, error) {
+	ctx := c.Request().Context()
+	t, err := scanTeam(h.db.Pool.QueryRow(ctx, `SELECT `+teamColumns+` FROM teams WHERE id = $1`, teamID))
+	if err != nil {
+		return t, err
+	}
+
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT u.id, u.name, u.role
+        FROM team_members tm
+        JOIN users u ON u.id = tm.user_id
+        WHERE tm.team_id = $1
+        ORDER BY u.name`, teamID)
+	if err != nil {
+		return t, err
+	}
+	defer rows.Close()
+	t.Members = []models.UserSummary{}
+	for rows.Next() {
+		var member models.UserSummary
+		if err := rows.Scan(&member.ID, &member.Name, &member.Role); err != nil {
+			return t, err
+		}
+		t.Members = append(t.Members, member)
+	}
+	return t, rows.Err()
+}
+
+// bindTeamName binds a TeamCreate body and returns the trimmed, whitespace-
+// collapsed name.
+func bindTeamName(c echo.Context) (string, error) {
+	var req models.TeamCreate
+	if err := c.Bind(&req); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	name := strings.Join(strings.Fields(req.Name), " ")
+	if name == "" {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Team name cannot be empty.")
+	}
+	if len([]rune(name)) > maxTeamNameLength {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Team name is too long.")
+	}
+	return name, nil
+}
+
+// scanTeam scans the teamColumns of a row.
+func scanTeam(row pgx.Row) (models.Team, error) {
+	var t models.Team
+	err := row.Scan(&t.ID, &t.Name, &t.CreatedAt, &t.UpdatedAt)
+	return t, err
+}
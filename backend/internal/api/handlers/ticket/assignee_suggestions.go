@@ -0,0 +1,121 @@
+// backend/internal/api/handlers/ticket/assignee_suggestions.go
+// ==========================================================================
+// Assignee suggestions for a ticket: staff ranked by how many similar tickets
+// (same issue type or a shared tag) they have resolved, then by their current
+// workload. Powers the "assign to best match" hint in the assignment UI.
+// ==========================================================================
+
+package ticket
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// maxAssigneeSuggestions caps the number of suggestions returned.
+const maxAssigneeSuggestions = 5
+
+// assigneeSuggestionsQuery ranks Staff/Admin users for ticket $1 in a single
+// aggregation. A closed ticket counts as similar when it has the same
+// issue_type or shares at least one tag; the workload is the number of
+// non-closed, non-quarantined tickets currently assigned to the user.
+const assigneeSuggestionsQuery = `
+    WITH target AS (
+        SELECT id, issue_type FROM tickets WHERE id = $1
+    ),
+    similar AS (
+        SELECT t.assigned_to_user_id AS user_id, COUNT(*) AS resolved
+        FROM tickets t
+        CROSS JOIN target
+        WHERE t.status = 'Closed'
+          AND t.assigned_to_user_id IS NOT NULL
+          AND t.id <> target.id
+          AND ((target.issue_type IS NOT NULL AND t.issue_type = target.issue_type)
+               OR EXISTS (
+                   SELECT 1
+                   FROM ticket_tags tt
+                   JOIN ticket_tags target_tags ON target_tags.tag_id = tt.tag_id AND target_tags.ticket_id = target.id
+                   WHERE tt.ticket_id = t.id))
+        GROUP BY t.assigned_to_user_id
+    ),
+    workload AS (
+        SELECT assigned_to_user_id AS user_id, COUNT(*) AS open_tickets
+        FROM tickets
+        WHERE status <> 'Closed' AND assigned_to_user_id IS NOT NULL AND quarantined_at IS NULL AND spam_at IS NULL
+        GROUP BY assigned_to_user_id
+    )
+    SELECT u.id, u.name, u.email, u.role, COALESCE(s.resolved, 0), COALESCE(w.open_tickets, 0)
+    FROM users u
+    LEFT JOIN similar s ON s.user_id = u.id
+    LEFT JOIN workload w ON w.user_id = u.id
+    WHERE u.role IN ('Staff', 'Admin')
+    ORDER BY COALESCE(s.resolved, 0) DESC, COALESCE(w.open_tickets, 0) ASC, u.name ASC
+    LIMIT $2`
+
+// --- Handler Functions ---
+
+// GetAssigneeSuggestions returns the best-matching assignees for a ticket,
+// best match first. (Staff/Admin only)
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Returns:
+//   - JSON APIResponse containing up to maxAssigneeSuggestions models.AssigneeSuggestion, or an error response.
+func (h *Handler) GetAssigneeSuggestions(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "GetAssigneeSuggestions", "ticketUUID", ticketID)
+
+	// --- 1. Authorization Check ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	if !auth.IsStaffOrAdmin(userRole) {
+		return echo.NewHTTPError(http.StatusForbidden, "Not authorized to assign this ticket.")
+	}
+	if _, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin); err != nil {
+		switch err.Error() {
+		case "ticket not found":
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		case "not authorized to access this ticket":
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to assign this ticket.")
+		}
+		logger.ErrorContext(ctx, "Failed to verify ticket access", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+
+	// --- 2. Rank Candidates ---
+	rows, err := h.db.Pool.Query(ctx, assigneeSuggestionsQuery, ticketID, maxAssigneeSuggestions)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query assignee suggestions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assignee suggestions.")
+	}
+	defer rows.Close()
+
+	suggestions := make([]models.AssigneeSuggestion, 0, maxAssigneeSuggestions)
+	for rows.Next() {
+		var s models.AssigneeSuggestion
+		if err := rows.Scan(&s.UserID, &s.Name, &s.Email, &s.Role, &s.SimilarResolved, &s.OpenTickets); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan assignee suggestion row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process assignee suggestions.")
+		}
+		suggestions = append(suggestions, s)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating assignee suggestions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process assignee suggestions.")
+	}
+
+	// --- 3. Return Response ---
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: suggestions})
+}
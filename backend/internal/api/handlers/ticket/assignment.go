@@ -0,0 +1,341 @@
+// backend/internal/api/handlers/ticket/assignment.go
+// ==========================================================================
+// Assignment acceptance workflow. A newly assigned ticket stays pending until
+// the assignee accepts it (POST /tickets/:id/accept), which moves it to
+// "In Progress". Every step is recorded in ticket_assignment_history.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// AcceptAssignment lets the current assignee take ownership of a ticket.
+// The acceptance is stamped (accepted_at), an Open ticket moves to "In Progress",
+// and the event is written to the assignment history.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Returns:
+//   - JSON response with the updated ticket or an error response.
+func (h *Handler) AcceptAssignment(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "AcceptAssignment", "ticketUUID", ticketID)
+
+	// --- 1. Get Requesting User ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// --- 2. Lock and Validate Ticket State ---
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error.")
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	var status models.TicketStatus
+	var assigneeID *string
+	var acceptedAt *time.Time
+	var endUserEmail, subject, locale string
+	err = tx.QueryRow(ctx, `
+        SELECT t.status, t.assigned_to_user_id, t.accepted_at, t.end_user_email, t.subject,
+               COALESCE(t.locale, s.locale, '')
+        FROM tickets t
+        LEFT JOIN users s ON s.email = t.end_user_email
+        WHERE t.id = $1
+        FOR UPDATE OF t`, ticketID,
+	).Scan(&status, &assigneeID, &acceptedAt, &endUserEmail, &subject, &locale)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load ticket for acceptance", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to load ticket.")
+	}
+	if assigneeID == nil || *assigneeID != userID {
+		logger.WarnContext(ctx, "Non-assignee attempted to accept ticket", "userID", userID)
+		return echo.NewHTTPError(http.StatusForbidden, "Only the assigned user can accept this ticket.")
+	}
+	if status == models.StatusClosed {
+		return echo.NewHTTPError(http.StatusBadRequest, "Closed tickets cannot be accepted.")
+	}
+	if acceptedAt != nil {
+		return echo.NewHTTPError(http.StatusConflict, "Ticket assignment has already been accepted.")
+	}
+
+	// --- 3. Record Acceptance ---
+	newStatus := status
+	if status == models.StatusOpen {
+		newStatus = models.StatusInProgress
+	}
+	if _, err := tx.Exec(ctx, `
+        UPDATE tickets
+        SET accepted_at = NOW(), assigned_at = COALESCE(assigned_at, NOW()), status = $2, updated_at = NOW()
+        WHERE id = $1`, ticketID, newStatus); err != nil {
+		logger.ErrorContext(ctx, "Failed to record acceptance", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to accept ticket.")
+	}
+	if err := recordAssignmentEvent(ctx, tx, ticketID, models.AssignmentEventAccepted, &userID, userID); err != nil {
+		logger.ErrorContext(ctx, "Failed to record assignment history", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to accept ticket.")
+	}
+
+	assigneeName, nameErr := h.getUserName(ctx, userID)
+	if nameErr != nil {
+		assigneeName = userID
+	}
+	comment := fmt.Sprintf("Assignment accepted by %s.", assigneeName)
+	var changes []models.FieldChange
+	if newStatus != status {
+		changes = append(changes, statusChange(status, newStatus))
+		comment += " " + describeChanges(changes)
+	}
+	if err := h.addHistoryEntry(ctx, tx, ticketID, userID, comment, changes); err != nil {
+		logger.ErrorContext(ctx, "Failed to add system comment", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to accept ticket.")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit acceptance", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to save acceptance.")
+	}
+
+	// --- 4. Notify Submitter (AFTER COMMIT) ---
+	if newStatus == models.StatusInProgress && status != models.StatusInProgress {
+		go func(recipient, tID, subj, assignee, locale string) {
+			bgCtx := context.Background()
+			emailLogger := slog.With("operation", "SendTicketInProgress", "ticketID", tID)
+			if emailErr := h.emailService.SendTicketInProgress(recipient, tID, subj, assignee, locale); emailErr != nil {
+				emailLogger.ErrorContext(bgCtx, "Failed to send 'In Progress' email", "recipient", recipient, "error", emailErr)
+			} else {
+				emailLogger.InfoContext(bgCtx, "Sent 'In Progress' email", "recipient", recipient)
+			}
+		}(endUserEmail, ticketID, subject, h.submitterFacingStaffName(assigneeName, locale), locale)
+	}
+
+	// --- 5. Return Updated Ticket ---
+	updatedTicket, err := h.getTicketDetailsByID(ctx, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to fetch ticket after acceptance", "error", err)
+		return c.JSON(http.StatusOK, map[string]string{"message": "Ticket accepted, but failed to retrieve full details."})
+	}
+	logger.InfoContext(ctx, "Ticket assignment accepted", "userID", userID, "status", newStatus)
+	return c.JSON(http.StatusOK, updatedTicket)
+}
+
+// GetAssignmentHistory returns the assignment lifecycle events for a ticket, oldest first.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Returns:
+//   - JSON APIResponse containing AssignmentHistoryEntry objects or an error response.
+func (h *Handler) GetAssignmentHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "GetAssignmentHistory", "ticketUUID", ticketID)
+
+	// --- 1. Authorization Check ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin); err != nil {
+		if err.Error() == "ticket not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		if err.Error() == "not authorized to access this ticket" {
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to view this ticket.")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+
+	// --- 2. Query History ---
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT h.id, h.ticket_id, h.event, h.assignee_user_id, a.name, h.actor_user_id, u.name, h.created_at
+        FROM ticket_assignment_history h
+        LEFT JOIN users a ON a.id = h.assignee_user_id
+        LEFT JOIN users u ON u.id = h.actor_user_id
+        WHERE h.ticket_id = $1
+        ORDER BY h.created_at ASC`, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query assignment history", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve assignment history.")
+	}
+	defer rows.Close()
+
+	history := make([]models.AssignmentHistoryEntry, 0)
+	for rows.Next() {
+		var entry models.AssignmentHistoryEntry
+		if err := rows.Scan(
+			&entry.ID, &entry.TicketID, &entry.Event, &entry.AssigneeUserID, &entry.AssigneeName,
+			&entry.ActorUserID, &entry.ActorName, &entry.CreatedAt,
+		); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan assignment history row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process assignment history.")
+		}
+		history = append(history, entry)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating assignment history", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process assignment history.")
+	}
+
+	// --- 3. Return Response ---
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: history})
+}
+
+// --- Helper Functions ---
+
+// assigneeChanging reports whether the update would change the ticket's assignee.
+func assigneeChanging(currentState *models.TicketState, update *models.TicketStatusUpdate) bool {
+	if update.AssignedToUserID == nil {
+		return false
+	}
+	newAssigneeID := *update.AssignedToUserID
+	if newAssigneeID == "" {
+		return currentState.AssignedToUserID != nil
+	}
+	return currentState.AssignedToUserID == nil || *currentState.AssignedToUserID != newAssigneeID
+}
+
+// applyAssignmentChange resets the acceptance state after the assignee changes and
+// records the change in the assignment history. Self-assignments are accepted
+// immediately; any other assignment waits for the assignee to accept it.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - tx: The transaction the ticket update runs in.
+//   - ticketID: The ticket being updated.
+//   - previousAssigneeID: The assignee before the update (nil if unassigned).
+//   - newAssigneeID: The new assignee ("" when unassigning).
+//   - actorUserID: The user performing the update.
+//
+// Returns:
+//   - error: If any statement fails.
+func applyAssignmentChange(ctx context.Context, tx pgx.Tx, ticketID string, previousAssigneeID *string, newAssigneeID, actorUserID string) error {
+	if newAssigneeID == "" {
+		if _, err := tx.Exec(ctx, `
+            UPDATE tickets SET assigned_at = NULL, accepted_at = NULL, acceptance_escalated_at = NULL
+            WHERE id = $1`, ticketID); err != nil {
+			return fmt.Errorf("failed to clear assignment state: %w", err)
+		}
+		return recordAssignmentEvent(ctx, tx, ticketID, models.AssignmentEventUnassigned, previousAssigneeID, actorUserID)
+	}
+
+	selfAssigned := newAssigneeID == actorUserID
+	if _, err := tx.Exec(ctx, `
+        UPDATE tickets
+        SET assigned_at = NOW(), accepted_at = CASE WHEN $2 THEN NOW() END, acceptance_escalated_at = NULL
+        WHERE id = $1`, ticketID, selfAssigned); err != nil {
+		return fmt.Errorf("failed to reset assignment state: %w", err)
+	}
+	if err := recordAssignmentEvent(ctx, tx, ticketID, models.AssignmentEventAssigned, &newAssigneeID, actorUserID); err != nil {
+		return err
+	}
+	if selfAssigned {
+		return recordAssignmentEvent(ctx, tx, ticketID, models.AssignmentEventAccepted, &newAssigneeID, actorUserID)
+	}
+	return nil
+}
+
+// recordAssignmentEvent inserts a row into ticket_assignment_history.
+// An empty actorUserID records a system event.
+func recordAssignmentEvent(ctx context.Context, tx pgx.Tx, ticketID, event string, assigneeUserID *string, actorUserID string) error {
+	var actorArg interface{}
+	if actorUserID != "" {
+		actorArg = actorUserID
+	}
+	_, err := tx.Exec(ctx, `
+        INSERT INTO ticket_assignment_history (ticket_id, event, assignee_user_id, actor_user_id)
+        VALUES ($1, $2, $3, $4)`, ticketID, event, assigneeUserID, actorArg)
+	if err != nil {
+		return fmt.Errorf("failed to record assignment event: %w", err)
+	}
+	return nil
+}
+
+// routedAssignment describes an auto-assignment made by a routing rule.
+type routedAssignment struct {
+	AssigneeUserID string
+	AssigneeName   string
+	AssigneeEmail  string
+	Domain         string // The matching rule's domain
+}
+
+// applyRoutingRules auto-assigns a new ticket using the first enabled
+// assignment rule (in position order) whose domain matches the submitter's
+// email domain, case-insensitively. A "*.example.com" rule also matches
+// subdomains of example.com. The assignment is recorded in the assignment
+// history and explained in a system comment.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - tx: The ticket creation transaction.
+//   - ticketID: The newly created ticket.
+//   - submitterEmail: The ticket's end_user_email.
+//
+// Returns:
+//   - *routedAssignment: The assignment made, or nil if no rule matched.
+//   - error: If any statement fails.
+func (h *Handler) applyRoutingRules(ctx context.Context, tx pgx.Tx, ticketID, submitterEmail string) (*routedAssignment, error) {
+	at := strings.LastIndex(submitterEmail, "@")
+	if at < 0 || at == len(submitterEmail)-1 {
+		return nil, nil
+	}
+	domain := strings.ToLower(strings.TrimSpace(submitterEmail[at+1:]))
+
+	var routed routedAssignment
+	err := tx.QueryRow(ctx, `
+        SELECT r.assignee_user_id, u.name, u.email, r.domain
+        FROM assignment_rules r
+        JOIN users u ON u.id = r.assignee_user_id
+        WHERE r.enabled
+          AND u.role IN ('Staff', 'Admin')
+          AND (r.domain = $1 OR (r.domain LIKE '*.%' AND ($1 = substr(r.domain, 3) OR $1 LIKE '%' || substr(r.domain, 2))))
+        ORDER BY r.position, r.created_at
+        LIMIT 1`, domain,
+	).Scan(&routed.AssigneeUserID, &routed.AssigneeName, &routed.AssigneeEmail, &routed.Domain)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to evaluate assignment rules: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE tickets SET assigned_to_user_id = $2 WHERE id = $1`, ticketID, routed.AssigneeUserID); err != nil {
+		return nil, fmt.Errorf("failed to auto-assign ticket: %w", err)
+	}
+	if err := applyAssignmentChange(ctx, tx, ticketID, nil, routed.AssigneeUserID, ""); err != nil {
+		return nil, err
+	}
+	comment := fmt.Sprintf("Automatically assigned to %s by the routing rule for domain '%s' (submitter domain '%s').",
+		routed.AssigneeName, routed.Domain, domain)
+	if err := h.addHistoryNote(ctx, tx, ticketID, "", comment); err != nil {
+		return nil, err
+	}
+	return &routed, nil
+}
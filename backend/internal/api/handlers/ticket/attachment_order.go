@@ -0,0 +1,108 @@
+// backend/internal/api/handlers/ticket/attachment_order.go
+// ==========================================================================
+// Handler for staff-defined attachment ordering. Attachments are listed by
+// display_order, with never-ordered attachments (e.g., new uploads) after
+// them by upload time; see fetchTicketAttachments.
+// ==========================================================================
+
+package ticket
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// attachmentOrderRequest is the body of ReorderAttachments.
+type attachmentOrderRequest struct {
+	AttachmentIDs []string `json:"attachment_ids"` // Every attachment of the ticket, in the desired order
+}
+
+// ReorderAttachments sets the display order of a ticket's attachments.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Request Body:
+//   - attachment_ids: All of the ticket's attachment IDs, each once, in display order.
+//
+// Returns:
+//   - JSON APIResponse containing the reordered []Attachment, 400 if the list
+//     does not match the ticket's attachments, or another error response.
+func (h *Handler) ReorderAttachments(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "ReorderAttachments", "ticketUUID", ticketID)
+
+	var body attachmentOrderRequest
+	if err := c.Bind(&body); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+
+	// --- Authorization ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	if userRole != models.RoleAdmin && userRole != models.RoleStaff {
+		return echo.NewHTTPError(http.StatusForbidden, "Not authorized to manage this ticket's attachments.")
+	}
+	if _, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin); err != nil {
+		switch err.Error() {
+		case "ticket not found":
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		case "not authorized to access this ticket":
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to manage this ticket's attachments.")
+		}
+		logger.ErrorContext(ctx, "Failed to verify ticket access", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+
+	// --- Validate Against the Ticket's Attachments ---
+	current, err := h.fetchTicketAttachments(ctx, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load attachments", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve attachments.")
+	}
+	remaining := make(map[string]bool, len(current))
+	for _, att := range current {
+		remaining[att.ID] = true
+	}
+	for _, id := range body.AttachmentIDs {
+		if !remaining[id] {
+			return echo.NewHTTPError(http.StatusBadRequest, "attachment_ids must list each of the ticket's attachments exactly once.")
+		}
+		delete(remaining, id)
+	}
+	if len(remaining) > 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "attachment_ids must list each of the ticket's attachments exactly once.")
+	}
+
+	// --- Store the Order ---
+	if _, err := h.db.Pool.Exec(ctx, `
+        UPDATE attachments a SET display_order = o.position - 1
+        FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, position)
+        WHERE a.id = o.id AND a.ticket_id = $1`, ticketID, body.AttachmentIDs); err != nil {
+		logger.ErrorContext(ctx, "Failed to store attachment order", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to reorder attachments.")
+	}
+
+	attachments, err := h.fetchTicketAttachments(ctx, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to reload attachments", "error", err)
+		return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: "Attachments reordered."})
+	}
+	logger.InfoContext(ctx, "Attachments reordered", "count", len(attachments), "userID", userID)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Attachments reordered.",
+		Data:    attachments,
+	})
+}
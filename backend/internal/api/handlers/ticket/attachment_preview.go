@@ -0,0 +1,167 @@
+// backend/internal/api/handlers/ticket/attachment_preview.go
+// ==========================================================================
+// PDF previews for office document attachments. After an upload commits,
+// each supported document is converted in the background; the rendered PDF
+// is stored next to the original and served inline from the preview route.
+// Conversion is best-effort: a failure only marks the preview as failed and
+// never affects the attachment itself.
+// ==========================================================================
+
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preview"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// previewStoreTimeout bounds the storage and database work around a conversion
+// (the conversion itself is bounded by the preview service's timeout).
+const previewStoreTimeout = 2 * time.Minute
+
+// --- Handler Functions ---
+
+// PreviewAttachment streams an attachment's rendered PDF preview inline.
+// Registered alongside the public download route.
+//
+// Path Parameters:
+//   - attachmentId: The UUID of the attachment.
+//
+// Returns:
+//   - The PDF content as a stream, or 404 if no preview is ready.
+func (h *Handler) PreviewAttachment(c echo.Context) error {
+	ctx := c.Request().Context()
+	attachmentID := c.Param("attachmentId")
+	logger := slog.With("handler", "PreviewAttachment", "attachmentID", attachmentID)
+
+	var previewPath *string
+	var status *string
+	var purgedAt *time.Time
+	err := h.db.Pool.QueryRow(ctx, `
+        SELECT preview_storage_path, preview_status, purged_at FROM attachments WHERE id = $1`,
+		attachmentID).Scan(&previewPath, &status, &purgedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Attachment not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to get attachment preview metadata", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve attachment information.")
+	}
+	if purgedAt != nil {
+		return echo.NewHTTPError(http.StatusGone, "This attachment was removed under the retention policy.")
+	}
+	if previewPath == nil || status == nil || *status != preview.StatusReady {
+		return echo.NewHTTPError(http.StatusNotFound, "No preview is available for this attachment.")
+	}
+
+	reader, err := h.fileService.GetObject(ctx, *previewPath)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to get preview from storage", "storagePath", *previewPath, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve preview from storage.")
+	}
+	defer reader.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, "inline")
+	return c.Stream(http.StatusOK, "application/pdf", reader)
+}
+
+// --- Helpers ---
+
+// previewURL returns the preview route for an attachment whose preview is ready.
+func previewURL(attachmentID string, status *string) string {
+	if status == nil || *status != preview.StatusReady {
+		return ""
+	}
+	return fmt.Sprintf("/api/attachments/preview/%s", attachmentID)
+}
+
+// schedulePreviews starts background conversion for every office document in
+// attachments and marks those entries as pending in the returned metadata.
+func (h *Handler) schedulePreviews(attachments []models.Attachment) {
+	for i := range attachments {
+		if !h.previews.Supports(attachments[i].Filename) {
+			continue
+		}
+		attachments[i].PreviewStatus = preview.StatusPending
+		go h.generatePreview(attachments[i])
+	}
+}
+
+// generatePreview converts one attachment and records the outcome.
+func (h *Handler) generatePreview(attachment models.Attachment) {
+	ctx, cancel := context.WithTimeout(context.Background(), previewStoreTimeout)
+	defer cancel()
+	logger := slog.With("helper", "generatePreview", "attachmentID", attachment.ID)
+
+	if _, err := h.db.Pool.Exec(ctx, `UPDATE attachments SET preview_status = $2 WHERE id = $1`,
+		attachment.ID, preview.StatusPending); err != nil {
+		logger.ErrorContext(ctx, "Failed to mark preview pending", "error", err)
+		return
+	}
+
+	previewPath, err := h.renderPreview(ctx, attachment)
+	if err != nil {
+		logger.WarnContext(ctx, "Attachment preview conversion failed", "filename", attachment.Filename, "error", err)
+		if _, dbErr := h.db.Pool.Exec(ctx, `UPDATE attachments SET preview_status = $2 WHERE id = $1`,
+			attachment.ID, preview.StatusFailed); dbErr != nil {
+			logger.ErrorContext(ctx, "Failed to mark preview failed", "error", dbErr)
+		}
+		return
+	}
+
+	tag, err := h.db.Pool.Exec(ctx, `
+        UPDATE attachments SET preview_status = $2, preview_storage_path = $3
+        WHERE id = $1 AND purged_at IS NULL`, attachment.ID, preview.StatusReady, previewPath)
+	if err != nil || tag.RowsAffected() == 0 {
+		// The attachment was deleted or purged meanwhile (or the update failed);
+		// don't leave an orphaned preview behind.
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to record attachment preview", "error", err)
+		}
+		if delErr := h.fileService.DeleteFile(ctx, previewPath); delErr != nil {
+			logger.ErrorContext(ctx, "Failed to clean up orphaned preview", "storagePath", previewPath, "error", delErr)
+		}
+		return
+	}
+	logger.InfoContext(ctx, "Attachment preview ready", "filename", attachment.Filename, "storagePath", previewPath)
+}
+
+// renderPreview reads the original from storage, converts it and stores the PDF.
+func (h *Handler) renderPreview(ctx context.Context, attachment models.Attachment) (string, error) {
+	original, err := h.fileService.GetObject(ctx, attachment.StoragePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read original: %w", err)
+	}
+	defer original.Close()
+
+	pdf, err := h.previews.Convert(ctx, attachment.Filename, original)
+	if err != nil {
+		return "", err
+	}
+
+	previewPath := fmt.Sprintf("tickets/%s/previews/%s.pdf", attachment.TicketID, attachment.ID)
+	previewPath, err = h.fileService.UploadFile(ctx, previewPath, bytes.NewReader(pdf), int64(len(pdf)), "application/pdf")
+	if err != nil {
+		return "", fmt.Errorf("failed to store preview: %w", err)
+	}
+	return previewPath, nil
+}
+
+// deletePreview removes a stored preview file, logging failures.
+func (h *Handler) deletePreview(ctx context.Context, previewPath *string) {
+	if previewPath == nil || *previewPath == "" {
+		return
+	}
+	if err := h.fileService.DeleteFile(ctx, *previewPath); err != nil {
+		slog.ErrorContext(ctx, "Failed to delete attachment preview", "storagePath", *previewPath, "error", err)
+	}
+}
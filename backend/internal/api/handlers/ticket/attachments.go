@@ -16,15 +16,20 @@ import (
 	"log/slog"
 	"mime/multipart"
 	"net/http"
-	"path/filepath"
+	"strings"
 	"time"
 	"database/sql" // Import for sql.NullString
 
 	"github.com/google/uuid" // Import UUID package
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/file"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Data models
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
+	"golang.org/x/sync/errgroup"
 )
 
 const (
@@ -67,6 +72,23 @@ func (h *Handler) UploadAttachment(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
 	}
 
+	return h.storeUploadedAttachments(c, logger, ticketID, nil)
+}
+
+// storeUploadedAttachments validates the files sent under the "attachments"
+// form field, stores them and records their metadata in one transaction.
+//
+// Parameters:
+//   - c: The echo context of the upload request.
+//   - logger: The calling handler's logger.
+//   - ticketID: The ticket the files are attached to (already verified to exist).
+//   - updateID: The comment the files are attached to, or nil for the ticket itself.
+//
+// Returns:
+//   - The 201 JSON response with the created Attachment metadata, or an error response.
+func (h *Handler) storeUploadedAttachments(c echo.Context, logger *slog.Logger, ticketID string, updateID *string) error {
+	ctx := c.Request().Context()
+
 	// --- 2. Get Files from Request ---
 	// Use MultipartForm() to handle multiple files under the same key
 	form, err := c.MultipartForm()
@@ -86,116 +108,89 @@ func (h *Handler) UploadAttachment(c echo.Context) error {
 	uploadedByUserID, _ := auth.GetUserIDFromContext(c) // Ignore error for now, default to ""
 	uploadedByRole, _ := auth.GetUserRoleFromContext(c) // Ignore error for now, default to ""
 
-	// --- 3. Process Each File ---
+	// --- 3. Validate All Files (before any upload starts) ---
+	if err := h.validateAttachmentBatch(files); err != nil {
+		logger.WarnContext(ctx, "Attachment batch rejected", "fileCount", len(files), "error", err)
+		return apierror.New(http.StatusBadRequest, apierror.CodeAttachmentInvalid, err.Error())
+	}
+	for _, fileHeader := range files {
+		if err := h.validateAttachment(fileHeader); err != nil {
+			logger.WarnContext(ctx, "Attachment validation failed", "filename", fileHeader.Filename, "error", err)
+			return apierror.New(http.StatusBadRequest, apierror.CodeAttachmentInvalid, err.Error())
+		}
+	}
+
+	filenames, err := h.attachmentFilenames(ctx, ticketID, files)
+	if err != nil {
+		return err
+	}
+
+	// --- 4. Upload Files to Storage Concurrently ---
+	// Uploads run before the transaction starts so no DB connection is held
+	// while waiting on storage.
+	uploads, uploadErr := h.uploadFilesConcurrently(ctx, ticketID, files, filenames)
+	if uploadErr != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to store attachments: "+uploadErr.Error())
+	}
+
+	// --- 5. Store Metadata in Database (single transaction) ---
 	attachmentsMetadata := make([]models.Attachment, 0, len(files))
-	var processingError error // To capture the first error encountered
 
 	// Use a transaction for database operations
 	tx, txErr := h.db.Pool.Begin(ctx)
 	if txErr != nil {
 		logger.ErrorContext(ctx, "Failed to begin database transaction", "error", txErr)
+		h.deleteStoredUploads(uploads)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to start transaction.")
 	}
-	// Defer rollback logic
+	// Unless the metadata is committed, roll back and remove the stored files.
+	committed := false
 	defer func() {
-		if p := recover(); p != nil { // Catch panics
-			_ = tx.Rollback(ctx)
-			panic(p) // Re-throw panic after Rollback
-		} else if processingError != nil { // Rollback on explicit error
-			logger.WarnContext(ctx, "Rolling back transaction due to processing error", "error", processingError)
-			if rbErr := tx.Rollback(ctx); rbErr != nil {
-				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
-			}
-		} else { // Commit if no error
-			commitErr := tx.Commit(ctx)
-			if commitErr != nil {
-				logger.ErrorContext(ctx, "Failed to commit transaction", "error", commitErr)
-				// Set processingError so the final response indicates failure
-				processingError = fmt.Errorf("database error: failed to save attachments: %w", commitErr)
-			}
-		}
-	}()
-
-	for _, fileHeader := range files {
-		logger.DebugContext(ctx, "Processing file", "filename", fileHeader.Filename, "size", fileHeader.Size)
-
-		// --- 3a. Validate File ---
-		if err := h.validateAttachment(fileHeader); err != nil {
-			logger.WarnContext(ctx, "Attachment validation failed", "filename", fileHeader.Filename, "error", err)
-			processingError = echo.NewHTTPError(http.StatusBadRequest, err.Error()) // Return specific validation error
-			return processingError // Stop processing further files on validation error
+		if committed {
+			return
 		}
-
-		// --- 3b. Open File ---
-		file, openErr := fileHeader.Open()
-		if openErr != nil {
-			logger.ErrorContext(ctx, "Failed to open uploaded file", "filename", fileHeader.Filename, "error", openErr)
-			processingError = echo.NewHTTPError(http.StatusInternalServerError, "Failed to process uploaded file: "+fileHeader.Filename)
-			return processingError // Stop processing
-		}
-
-		// --- 3c. Upload File to Storage Service ---
-		contentType := fileHeader.Header.Get("Content-Type")
-		if contentType == "" { contentType = "application/octet-stream" }
-		safeFilename := filepath.Base(fileHeader.Filename) // Sanitize filename
-		// Generate a unique ID for the storage path part to avoid collisions even with same names/timestamps
-		uniqueID := uuid.New().String()
-		storagePath := fmt.Sprintf("tickets/%s/%s_%s", ticketID, uniqueID, safeFilename)
-
-		storagePath, uploadErr := h.fileService.UploadFile(ctx, storagePath, file, fileHeader.Size, contentType)
-		file.Close() // Close the file *after* uploading
-		if uploadErr != nil {
-			logger.ErrorContext(ctx, "Failed to upload attachment via file service", "filename", safeFilename, "error", uploadErr)
-			processingError = echo.NewHTTPError(http.StatusInternalServerError, "Failed to store attachment: "+safeFilename)
-			return processingError // Stop processing
+		if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
 		}
-		logger.DebugContext(ctx, "File uploaded to storage", "storagePath", storagePath)
-
-		// --- 3d. Store Metadata in Database (within transaction) ---
-		var attachment models.Attachment
-		var uploadedByUserIDNullable sql.NullString
-		var uploadedByRoleNullable sql.NullString
+		h.deleteStoredUploads(uploads)
+	}()
 
-		if uploadedByUserID != "" { uploadedByUserIDNullable = sql.NullString{String: uploadedByUserID, Valid: true} }
-		if string(uploadedByRole) != "" { uploadedByRoleNullable = sql.NullString{String: string(uploadedByRole), Valid: true} }
+	var uploadedByUserIDNullable sql.NullString
+	var uploadedByRoleNullable sql.NullString
+	if uploadedByUserID != "" { uploadedByUserIDNullable = sql.NullString{String: uploadedByUserID, Valid: true} }
+	if string(uploadedByRole) != "" { uploadedByRoleNullable = sql.NullString{String: string(uploadedByRole), Valid: true} }
 
+	for _, upload := range uploads {
 		// Insert metadata into the database using the transaction (tx)
+		var attachment models.Attachment
 		dbErr := tx.QueryRow(ctx, `
-            INSERT INTO attachments (ticket_id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role)
-            VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-            RETURNING id, ticket_id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role
-        `, ticketID, safeFilename, storagePath, contentType, fileHeader.Size, time.Now(), uploadedByUserIDNullable, uploadedByRoleNullable).Scan(
+            INSERT INTO attachments (ticket_id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role, update_id)
+            VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            RETURNING id, ticket_id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role, update_id
+        `, ticketID, upload.Filename, upload.StoragePath, upload.ContentType, upload.Header.Size, time.Now(), uploadedByUserIDNullable, uploadedByRoleNullable, updateID).Scan(
 			&attachment.ID, &attachment.TicketID, &attachment.Filename,
 			&attachment.StoragePath, &attachment.MimeType, &attachment.Size, &attachment.UploadedAt,
 			&attachment.UploadedByUserID, &attachment.UploadedByRole, // Scan directly now
+			&attachment.UpdateID,
 		)
 		if dbErr != nil {
-			logger.ErrorContext(ctx, "Failed to store attachment metadata in database", "filename", safeFilename, "storagePath", storagePath, "error", dbErr)
-			// Attempt to clean up the file uploaded just before the DB error
-			logger.WarnContext(ctx, "Attempting to clean up orphaned file from storage due to DB error", "storagePath", storagePath)
-			if cleanupErr := h.fileService.DeleteFile(context.Background(), storagePath); cleanupErr != nil {
-				logger.ErrorContext(ctx, "Failed to clean up orphaned file", "storagePath", storagePath, "cleanupError", cleanupErr)
-			}
-			processingError = echo.NewHTTPError(http.StatusInternalServerError, "Failed to save attachment metadata for: "+safeFilename)
-			return processingError // Stop processing
+			logger.ErrorContext(ctx, "Failed to store attachment metadata in database", "filename", upload.Filename, "storagePath", upload.StoragePath, "error", dbErr)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save attachment metadata for: "+upload.Filename)
 		}
 
 		attachment.URL = fmt.Sprintf("/api/attachments/download/%s", attachment.ID) // Add download URL
 		attachmentsMetadata = append(attachmentsMetadata, attachment)
 		logger.DebugContext(ctx, "Attachment metadata stored", "attachmentID", attachment.ID)
-	} // End of file processing loop
-
-	// If loop finished but an error occurred during commit (checked by defer), return error
-	if processingError != nil {
-		// The defer function already tried to rollback
-		// Return the appropriate error status code based on the error type
-		if httpErr, ok := processingError.(*echo.HTTPError); ok {
-			return httpErr
-		}
-		return echo.NewHTTPError(http.StatusInternalServerError, processingError.Error())
+	} // End of metadata loop
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to save attachments.")
 	}
+	committed = true
+	h.schedulePreviews(attachmentsMetadata) // Best-effort, in the background
 
-	// --- 4. Return Success Response ---
+	// --- 6. Return Success Response ---
 	logger.InfoContext(ctx, "Attachments uploaded and metadata stored successfully", "count", len(attachmentsMetadata))
 	return c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
@@ -231,15 +226,16 @@ func (h *Handler) GetAttachment(c echo.Context) error {
 	var uploadedByUserIDNullable sql.NullString
 	var uploadedByRoleNullable sql.NullString
 	var urlNullable sql.NullString
+	var previewStatus *string
 
 	err := h.db.Pool.QueryRow(ctx, `
-        SELECT id, ticket_id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role, url
+        SELECT id, ticket_id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role, url, preview_status
         FROM attachments
         WHERE id = $1 AND ticket_id = $2 -- Ensure attachment belongs to the ticket
     `, attachmentID, ticketID).Scan(
 		&attachment.ID, &attachment.TicketID, &attachment.Filename,
 		&attachment.StoragePath, &attachment.MimeType, &attachment.Size, &attachment.UploadedAt,
-		&uploadedByUserIDNullable, &uploadedByRoleNullable, &urlNullable,
+		&uploadedByUserIDNullable, &uploadedByRoleNullable, &urlNullable, &previewStatus,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -254,6 +250,8 @@ func (h *Handler) GetAttachment(c echo.Context) error {
 	if uploadedByUserIDNullable.Valid { attachment.UploadedByUserID = uploadedByUserIDNullable.String }
 	if uploadedByRoleNullable.Valid { attachment.UploadedByRole = uploadedByRoleNullable.String }
 	if urlNullable.Valid { attachment.URL = urlNullable.String }
+	if previewStatus != nil { attachment.PreviewStatus = *previewStatus }
+	attachment.PreviewURL = previewURL(attachment.ID, previewStatus)
 
 
 	// --- 3. Add Download URL & Return Response ---
@@ -270,6 +268,9 @@ func (h *Handler) GetAttachment(c echo.Context) error {
 
 // DownloadAttachment streams the content of an attachment file to the client.
 // Assumes a separate route like /api/attachments/download/:attachmentId is registered.
+// Every served download is recorded in the audit log (asynchronously, so auditing
+// can never delay or fail the download). The route is public; the actor is the
+// authenticated user when a valid token accompanies the request, otherwise "public".
 //
 // Path Parameters:
 //   - attachmentId: The UUID of the attachment to download.
@@ -288,11 +289,12 @@ func (h *Handler) DownloadAttachment(c echo.Context) error {
 	}
 
 	// --- 2. Get Attachment Metadata from DB ---
-	// Fetch only necessary fields (storage path, filename, MIME type)
-	var storagePath, filename, mimeType string
+	// Fetch only necessary fields (storage path, filename, MIME type, owning ticket)
+	var storagePath, filename, mimeType, ticketID string
+	var purgedAt *time.Time
 	err := h.db.Pool.QueryRow(ctx, `
-        SELECT storage_path, filename, mime_type FROM attachments WHERE id = $1
-    `, attachmentID).Scan(&storagePath, &filename, &mimeType)
+        SELECT storage_path, filename, mime_type, ticket_id, purged_at FROM attachments WHERE id = $1
+    `, attachmentID).Scan(&storagePath, &filename, &mimeType, &ticketID, &purgedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			logger.WarnContext(ctx, "Attachment metadata not found for download")
@@ -301,6 +303,10 @@ func (h *Handler) DownloadAttachment(c echo.Context) error {
 		logger.ErrorContext(ctx, "Failed to get attachment metadata for download", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve attachment information.")
 	}
+	if purgedAt != nil {
+		logger.InfoContext(ctx, "Download requested for attachment removed by retention policy", "purgedAt", purgedAt)
+		return echo.NewHTTPError(http.StatusGone, "This attachment was removed under the retention policy.")
+	}
 
 	// --- 3. Get File Stream from Storage Service ---
 	fileReader, err := h.fileService.GetObject(ctx, storagePath)
@@ -318,11 +324,27 @@ func (h *Handler) DownloadAttachment(c echo.Context) error {
 		}
 	}()
 
-	// --- 4. Stream File to Client ---
+	// --- 4. Record Download Audit Event (non-blocking) ---
+	if h.auditService != nil {
+		h.auditService.RecordAsync(audit.Event{
+			Action:       audit.ActionAttachmentDownload,
+			ActorUserID:  auth.OptionalUserID(c),
+			ResourceType: "attachment",
+			ResourceID:   attachmentID,
+			IPAddress:    c.RealIP(),
+			Metadata: map[string]interface{}{
+				"ticket_id": ticketID,
+				"filename":  filename,
+			},
+		})
+	}
+
+	// --- 5. Stream File to Client ---
 	// Set headers for file download
 	c.Response().Header().Set(echo.HeaderContentType, mimeType)
-	// Content-Disposition forces browser download dialog
-	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"%s\"", filename))
+	// Content-Disposition forces browser download dialog; the filename is
+	// encoded so quotes, accents and control characters survive (RFC 5987)
+	c.Response().Header().Set(echo.HeaderContentDisposition, file.ContentDisposition("attachment", filename))
 	// Optional: Set Content-Length if size is known and reliable
 	// c.Response().Header().Set(echo.HeaderContentLength, fmt.Sprintf("%d", size))
 
@@ -381,7 +403,8 @@ func (h *Handler) DeleteAttachment(c echo.Context) error {
 
 	// --- 3. Get Attachment Storage Path ---
 	var storagePath, filename string
-	err = h.db.Pool.QueryRow(ctx, `SELECT storage_path, filename FROM attachments WHERE id = $1 AND ticket_id = $2`, attachmentID, ticketID).Scan(&storagePath, &filename)
+	var previewPath *string
+	err = h.db.Pool.QueryRow(ctx, `SELECT storage_path, filename, preview_storage_path FROM attachments WHERE id = $1 AND ticket_id = $2`, attachmentID, ticketID).Scan(&storagePath, &filename, &previewPath)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			logger.WarnContext(ctx, "Attachment not found for deletion")
@@ -402,6 +425,7 @@ func (h *Handler) DeleteAttachment(c echo.Context) error {
 	} else {
 		logger.InfoContext(ctx, "Successfully deleted file from storage", "storagePath", storagePath)
 	}
+	h.deletePreview(ctx, previewPath)
 
 
 	// --- 5. Delete Metadata from Database ---
@@ -438,6 +462,24 @@ func (h *Handler) checkTicketExists(ctx context.Context, ticketID string) (bool,
 	return exists, nil
 }
 
+// validateAttachmentBatch enforces the per-request limits (file count and
+// combined size) before any file is uploaded.
+func (h *Handler) validateAttachmentBatch(files []*multipart.FileHeader) error {
+	maxFiles := h.config.Storage.MaxFilesPerRequest
+	if len(files) > maxFiles {
+		return fmt.Errorf("too many attachments: %d files sent, at most %d allowed per request", len(files), maxFiles)
+	}
+	var totalSize int64
+	for _, fileHeader := range files {
+		totalSize += fileHeader.Size
+	}
+	if maxTotal := h.config.Storage.MaxTotalUploadSize; totalSize > maxTotal {
+		return fmt.Errorf("attachments total %.1f MB, exceeding the %.1f MB limit per request",
+			float64(totalSize)/(1024*1024), float64(maxTotal)/(1024*1024))
+	}
+	return nil
+}
+
 // validateAttachment checks if the uploaded file meets size and potentially type constraints.
 func (h *Handler) validateAttachment(fileHeader *multipart.FileHeader) error {
 	// Check file size
@@ -461,3 +503,145 @@ func (h *Handler) validateAttachment(fileHeader *multipart.FileHeader) error {
 
 	return nil // Validation passed
 }
+
+// storedUpload describes a file that has been uploaded to storage but whose
+// metadata row may not be committed yet.
+type storedUpload struct {
+	Header      *multipart.FileHeader
+	Filename    string // Sanitized filename
+	StoragePath string
+	ContentType string
+}
+
+// uploadFilesConcurrently uploads files to storage under tickets/<ticketID>/
+// using a bounded worker pool (Storage.UploadConcurrency). Results keep the
+// order of files. If any upload fails, the remaining uploads are cancelled and
+// every file already stored is deleted before the error is returned.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - ticketID: The ticket the files belong to.
+//   - files: The (already validated) uploaded files.
+//   - filenames: The stored name of each file, from attachmentFilenames.
+//
+// Returns:
+//   - []storedUpload: One entry per file, in input order.
+//   - error: The first upload error, naming the failing file.
+func (h *Handler) uploadFilesConcurrently(ctx context.Context, ticketID string, files []*multipart.FileHeader, filenames []string) ([]storedUpload, error) {
+	logger := slog.With("helper", "uploadFilesConcurrently", "ticketUUID", ticketID)
+	results := make([]storedUpload, len(files))
+	uploaded := make([]bool, len(files))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(h.config.Storage.UploadConcurrency)
+	for i, fileHeader := range files {
+		g.Go(func() error {
+			src, err := fileHeader.Open()
+			if err != nil {
+				return fmt.Errorf("failed to open file '%s': %w", fileHeader.Filename, err)
+			}
+			defer src.Close()
+
+			contentType := fileHeader.Header.Get("Content-Type")
+			if contentType == "" {
+				contentType = "application/octet-stream"
+			}
+			safeFilename := filenames[i]
+			// A UUID keeps paths unique even for identical names uploaded in parallel.
+			storagePath := file.AttachmentStoragePath(ticketID, uuid.New().String(), safeFilename)
+
+			storagePath, err = h.fileService.UploadFile(gctx, storagePath, src, fileHeader.Size, contentType)
+			if err != nil {
+				return fmt.Errorf("failed to upload file '%s': %w", safeFilename, err)
+			}
+			results[i] = storedUpload{Header: fileHeader, Filename: safeFilename, StoragePath: storagePath, ContentType: contentType}
+			uploaded[i] = true
+			logger.DebugContext(gctx, "File uploaded to storage", "filename", safeFilename, "storagePath", storagePath)
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		logger.ErrorContext(ctx, "Attachment upload failed; cleaning up stored files", "error", err)
+		stored := make([]storedUpload, 0, len(files))
+		for i, ok := range uploaded {
+			if ok {
+				stored = append(stored, results[i])
+			}
+		}
+		h.deleteStoredUploads(stored)
+		return nil, err
+	}
+	return results, nil
+}
+
+// attachmentFilenames sanitizes the names of files about to be attached to a
+// ticket and applies the same-name policy (ATTACHMENT_DUPLICATE_FILENAMES)
+// against the ticket's existing attachments and the rest of the batch.
+// Names are compared case-insensitively.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - ticketID: The ticket the files belong to ("" for a ticket not yet created).
+//   - files: The uploaded files.
+//
+// Returns:
+//   - []string: The name to store for each file, in input order.
+//   - error: 409 when the policy is "reject" and a name is taken, or 500 on a database error.
+func (h *Handler) attachmentFilenames(ctx context.Context, ticketID string, files []*multipart.FileHeader) ([]string, error) {
+	maxLength := h.config.Storage.MaxFilenameLength
+	names := make([]string, len(files))
+	for i, fileHeader := range files {
+		names[i] = file.SanitizeFilename(fileHeader.Filename, maxLength)
+	}
+	policy := h.config.Storage.DuplicateFilenames
+	if policy == config.DuplicateFilenamesAllow {
+		return names, nil
+	}
+
+	taken := make(map[string]bool)
+	if ticketID != "" {
+		rows, err := h.db.Pool.Query(ctx, `SELECT LOWER(filename) FROM attachments WHERE ticket_id = $1`, ticketID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load existing attachment names", "ticketUUID", ticketID, "error", err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify attachment names.")
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var existing string
+			if err := rows.Scan(&existing); err != nil {
+				slog.ErrorContext(ctx, "Failed to scan attachment name", "ticketUUID", ticketID, "error", err)
+				return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify attachment names.")
+			}
+			taken[existing] = true
+		}
+		if err := rows.Err(); err != nil {
+			slog.ErrorContext(ctx, "Error iterating attachment names", "ticketUUID", ticketID, "error", err)
+			return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify attachment names.")
+		}
+	}
+
+	for i, name := range names {
+		if taken[strings.ToLower(name)] {
+			if policy == config.DuplicateFilenamesReject {
+				return nil, apierror.New(http.StatusConflict, apierror.CodeAttachmentNameTaken,
+					fmt.Sprintf("A file named '%s' is already attached to this ticket.", name))
+			}
+			for n := 2; taken[strings.ToLower(name)]; n++ {
+				name = file.NumberedFilename(names[i], n, maxLength)
+			}
+			names[i] = name
+		}
+		taken[strings.ToLower(name)] = true
+	}
+	return names, nil
+}
+
+// deleteStoredUploads removes uploaded files from storage, e.g. after the
+// transaction recording them failed. Errors are logged, not returned.
+func (h *Handler) deleteStoredUploads(uploads []storedUpload) {
+	for _, upload := range uploads {
+		if err := h.fileService.DeleteFile(context.Background(), upload.StoragePath); err != nil {
+			slog.Error("Failed to clean up orphaned file", "storagePath", upload.StoragePath, "cleanupError", err)
+		}
+	}
+}
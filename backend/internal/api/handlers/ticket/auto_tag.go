@@ -0,0 +1,104 @@
+// backend/internal/api/handlers/ticket/auto_tag.go
+// ==========================================================================
+// Rule-based auto-tagging for new tickets. When enabled, each configured rule
+// whose keywords appear in the subject or description adds its tag. Tags the
+// submitter already chose are not duplicated, and auto-applied tags are
+// listed in a system comment so staff know they were machine-added.
+// ==========================================================================
+
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+)
+
+// autoTagRule is a compiled auto-tag rule.
+type autoTagRule struct {
+	tag      string
+	keywords []string
+	patterns []*regexp.Regexp
+}
+
+// autoTagMatch is a tag added by a rule and the keyword that triggered it.
+type autoTagMatch struct {
+	tag     string
+	keyword string
+}
+
+// compileAutoTagRules builds the rule matchers from configuration. Keywords
+// match case-insensitively on word boundaries, like urgency keywords; blank
+// keywords (e.g. from a stray comma) are skipped so they cannot match everything.
+//
+// Parameters:
+//   - cfg: The auto-tag configuration.
+//
+// Returns:
+//   - []autoTagRule: The matchers, or nil when the feature is disabled.
+func compileAutoTagRules(cfg config.AutoTagConfig) []autoTagRule {
+	if !cfg.Enabled {
+		return nil
+	}
+	rules := make([]autoTagRule, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		compiled := autoTagRule{tag: normalizeTagName(rule.Tag)}
+		for _, keyword := range rule.Keywords {
+			keyword = strings.TrimSpace(keyword)
+			if keyword == "" {
+				continue
+			}
+			compiled.keywords = append(compiled.keywords, keyword)
+			compiled.patterns = append(compiled.patterns, regexp.MustCompile(`(?i)(^|\W)`+regexp.QuoteMeta(keyword)+`($|\W)`))
+		}
+		rules = append(rules, compiled)
+	}
+	return rules
+}
+
+// matchAutoTags returns the tags whose rules match the subject or description,
+// skipping tags already in existing (compared case-insensitively) and
+// duplicate rules for the same tag.
+//
+// Parameters:
+//   - subject: The ticket subject.
+//   - description: The ticket description.
+//   - existing: The tags the submitter provided.
+//
+// Returns:
+//   - []autoTagMatch: The tags to add, in rule order.
+func (h *Handler) matchAutoTags(subject, description string, existing []string) []autoTagMatch {
+	if len(h.autoTagRules) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, tag := range existing {
+		seen[strings.ToLower(strings.TrimSpace(tag))] = true
+	}
+	text := subject + "\n" + description
+	var matches []autoTagMatch
+	for _, rule := range h.autoTagRules {
+		if seen[strings.ToLower(rule.tag)] {
+			continue
+		}
+		for i, pattern := range rule.patterns {
+			if pattern.MatchString(text) {
+				matches = append(matches, autoTagMatch{tag: rule.tag, keyword: rule.keywords[i]})
+				seen[strings.ToLower(rule.tag)] = true
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// autoTagComment formats the system comment listing auto-applied tags.
+func autoTagComment(matches []autoTagMatch) string {
+	parts := make([]string, len(matches))
+	for i, m := range matches {
+		parts[i] = fmt.Sprintf("%s (mentions %q)", m.tag, m.keyword)
+	}
+	return "Tags added automatically from the ticket content: " + strings.Join(parts, ", ") + ". Staff may remove them if they do not apply."
+}
@@ -9,9 +9,14 @@ package ticket
 import (
 	"log/slog" // Use structured logging
 
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/captcha"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
 	"github.com/henrythedeveloper/it-ticket-system/internal/db"    // Corrected import path
 	"github.com/henrythedeveloper/it-ticket-system/internal/email" // Corrected import path
 	"github.com/henrythedeveloper/it-ticket-system/internal/file"  // Corrected import path
+	"github.com/henrythedeveloper/it-ticket-system/internal/preview"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
 	"github.com/labstack/echo/v4"
 )
 
@@ -19,9 +24,17 @@ import (
 
 // Handler holds dependencies for ticket-related request handlers.
 type Handler struct {
-	db           *db.DB        // Database connection pool
-	emailService email.Service // Service for sending emails
-	fileService  file.Service  // Service for file storage operations
+	db              *db.DB           // Database connection pool
+	emailService    email.Service    // Service for sending emails
+	fileService     file.Service     // Service for file storage operations
+	auditService    audit.Service    // Service for recording audit events
+	captcha         captcha.Service  // CAPTCHA verification for public submissions
+	previews        preview.Service  // Office document to PDF preview rendering
+	config          *config.Config   // Application configuration
+	urgencyKeywords []urgencyKeyword // Compiled keyword matchers for urgency suggestion
+	spamPatterns    []spamPattern    // Compiled spam filter matchers for public submissions
+	autoTagRules    []autoTagRule    // Compiled auto-tag rules for new tickets
+	webhooks        *webhook.Service // Outbound ticket event webhooks
 }
 
 // --- Constructor ---
@@ -33,14 +46,27 @@ type Handler struct {
 //   - db: The database connection pool (*db.DB).
 //   - emailService: The email sending service (email.Service).
 //   - fileService: The file storage service (file.Service).
+//   - auditService: The audit event service (audit.Service).
+//   - captchaService: The CAPTCHA verification service (captcha.Service).
+//   - previewService: The attachment preview renderer (preview.Service).
+//   - cfg: The application configuration (*config.Config).
+//   - webhookService: The outbound webhook dispatcher (*webhook.Service).
 //
 // Returns:
 //   - *Handler: A pointer to the newly created Handler.
-func NewHandler(db *db.DB, emailService email.Service, fileService file.Service) *Handler {
+func NewHandler(db *db.DB, emailService email.Service, fileService file.Service, auditService audit.Service, captchaService captcha.Service, previewService preview.Service, cfg *config.Config, webhookService *webhook.Service) *Handler {
 	return &Handler{
-		db:           db,
-		emailService: emailService,
-		fileService:  fileService,
+		db:              db,
+		emailService:    emailService,
+		fileService:     fileService,
+		auditService:    auditService,
+		captcha:         captchaService,
+		previews:        previewService,
+		config:          cfg,
+		urgencyKeywords: compileUrgencyKeywords(cfg.UrgencyKeywords),
+		spamPatterns:    compileSpamPatterns(cfg.SpamFilter),
+		autoTagRules:    compileAutoTagRules(cfg.AutoTag),
+		webhooks:        webhookService,
 	}
 }
 
@@ -72,9 +98,22 @@ func RegisterRoutes(g *echo.Group, h *Handler) {
 		{"GET", "/counts", h.GetTicketCounts},                      // GET /api/tickets/counts
 		{"GET", "/search", h.SearchTickets},                        // GET /api/tickets/search
 		{"GET", "/:id", h.GetTicketByID},                 // GET /api/tickets/{id} - Use optimized handler with attachments
+		{"GET", "/:id/pdf", h.ExportTicketPDF},                     // GET /api/tickets/{id}/pdf
 		{"PUT", "/:id", h.UpdateTicket},                           // PUT /api/tickets/{id} (Handles status/assignee updates)
+		{"POST", "/:id/accept", h.AcceptAssignment},               // POST /api/tickets/{id}/accept (Assignee takes ownership)
+		{"POST", "/:id/claim", h.ClaimTicket},                     // POST /api/tickets/{id}/claim (Team member takes ownership of a team ticket)
+		{"POST", "/:id/closure-preview", h.PreviewClosureEmail},   // POST /api/tickets/{id}/closure-preview (Renders the closure email for draft notes)
+		{"GET", "/:id/assignment-history", h.GetAssignmentHistory}, // GET /api/tickets/{id}/assignment-history
+		{"GET", "/:id/history", h.GetTicketHistory},               // GET /api/tickets/{id}/history (Field changes and system events)
+		{"GET", "/:id/assignee-suggestions", h.GetAssigneeSuggestions}, // GET /api/tickets/{id}/assignee-suggestions (Staff/Admin)
 		{"POST", "/:id/comments", h.AddTicketComment},             // POST /api/tickets/{id}/comments
+		{"PUT", "/:id/comments/:commentId", h.UpdateTicketComment},    // PUT /api/tickets/{id}/comments/{commentId} (Author within window, or Admin)
+		{"DELETE", "/:id/comments/:commentId", h.DeleteTicketComment}, // DELETE /api/tickets/{id}/comments/{commentId} (Tombstones the comment)
+		{"GET", "/:id/comments/:commentId/history", h.GetTicketCommentHistory}, // GET /api/tickets/{id}/comments/{commentId}/history
+		{"POST", "/:id/comments/:commentId/reactions", h.ToggleCommentReaction}, // POST /api/tickets/{id}/comments/{commentId}/reactions (Toggles the caller's reaction)
+		{"POST", "/:id/comments/:commentId/attachments", h.UploadCommentAttachment}, // POST /api/tickets/{id}/comments/{commentId}/attachments (Author within window, or Admin)
 		{"POST", "/:id/attachments", h.UploadAttachment},          // POST /api/tickets/{id}/attachments
+		{"PUT", "/:id/attachments/order", h.ReorderAttachments},   // PUT /api/tickets/{id}/attachments/order (Staff/Admin)
 		{"GET", "/:id/attachments/:attachmentId", h.GetAttachment}, // GET /api/tickets/{id}/attachments/{attachmentId} (Metadata)
 		{"DELETE", "/:id/attachments/:attachmentId", h.DeleteAttachment},
 		// Note: Download route is often separate or handled differently, e.g., /api/attachments/download/:attachmentId
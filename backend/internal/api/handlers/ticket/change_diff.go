@@ -0,0 +1,192 @@
+// backend/internal/api/handlers/ticket/change_diff.go
+// ==========================================================================
+// Field change diffing for ticket history. Every code path that changes a
+// ticket's fields builds its change set here, so the history summary
+// ("Status changed from 'Open' to 'Closed'.") and the structured changes
+// stored alongside it (ticket_history.changes) always agree.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+)
+
+// Field names used in structured change sets.
+const (
+	changeFieldStatus          = "status"
+	changeFieldAssignee        = "assignee"
+	changeFieldTeam            = "team"
+	changeFieldUrgency         = "urgency"
+	changeFieldIssueType       = "issue_type"
+	changeFieldResolutionNotes = "resolution_notes"
+	changeFieldDueDate         = "due_date"
+)
+
+// diffTicketUpdate computes the field changes an update makes to the current
+// state, including the implicit close when resolution notes are added (see
+// buildTicketUpdateQuery). Assignee and team display values are names.
+//
+// Parameters:
+//   - ctx: Request context (used for assignee and team name lookups).
+//   - currentState: The ticket before the update.
+//   - update: The requested update.
+//
+// Returns:
+//   - []models.FieldChange: The changes, in a fixed field order; empty if nothing changes.
+func (h *Handler) diffTicketUpdate(ctx context.Context, currentState *models.TicketState, update *models.TicketStatusUpdate) []models.FieldChange {
+	var changes []models.FieldChange
+
+	notesChanged := update.ResolutionNotes != nil && *update.ResolutionNotes != stringValue(currentState.ResolutionNotes)
+	if newStatus := effectiveStatus(currentState, update); newStatus != currentState.Status {
+		changes = append(changes, statusChange(currentState.Status, newStatus))
+	}
+
+	if assigneeChanging(currentState, update) {
+		var newAssigneeID *string
+		if *update.AssignedToUserID != "" {
+			newAssigneeID = update.AssignedToUserID
+		}
+		changes = append(changes, models.FieldChange{
+			Field:       changeFieldAssignee,
+			From:        currentState.AssignedToUserID,
+			To:          newAssigneeID,
+			FromDisplay: h.assigneeDisplayName(ctx, currentState.AssignedToUserID),
+			ToDisplay:   h.assigneeDisplayName(ctx, newAssigneeID),
+		})
+	}
+
+	if teamChanging(currentState, update) {
+		var newTeamID *string
+		if *update.AssignedToTeamID != "" {
+			newTeamID = update.AssignedToTeamID
+		}
+		changes = append(changes, models.FieldChange{
+			Field:       changeFieldTeam,
+			From:        currentState.AssignedToTeamID,
+			To:          newTeamID,
+			FromDisplay: h.teamDisplayName(ctx, currentState.AssignedToTeamID),
+			ToDisplay:   h.teamDisplayName(ctx, newTeamID),
+		})
+	}
+
+	if update.Urgency != nil && *update.Urgency != currentState.Urgency {
+		changes = append(changes, valueChange(changeFieldUrgency, optionalString(string(currentState.Urgency)), optionalString(string(*update.Urgency))))
+	}
+
+	if update.IssueType != nil && strings.TrimSpace(*update.IssueType) != stringValue(currentState.IssueType) {
+		changes = append(changes, valueChange(changeFieldIssueType, optionalString(stringValue(currentState.IssueType)), optionalString(strings.TrimSpace(*update.IssueType))))
+	}
+
+	if newDueDate, changed := dueDateChange(currentState, update); changed {
+		changes = append(changes, models.FieldChange{
+			Field:       changeFieldDueDate,
+			From:        dueDateValue(currentState.DueDate),
+			To:          dueDateValue(newDueDate),
+			FromDisplay: dueDateDisplay(currentState.DueDate),
+			ToDisplay:   dueDateDisplay(newDueDate),
+		})
+	}
+
+	if notesChanged {
+		changes = append(changes, models.FieldChange{
+			Field: changeFieldResolutionNotes,
+			From:  optionalString(stringValue(currentState.ResolutionNotes)),
+			To:    optionalString(*update.ResolutionNotes),
+		})
+	}
+	return changes
+}
+
+// statusChange builds the change entry for a status transition.
+func statusChange(from, to models.TicketStatus) models.FieldChange {
+	return valueChange(changeFieldStatus, optionalString(string(from)), optionalString(string(to)))
+}
+
+// valueChange builds a change entry whose display values are the raw values.
+func valueChange(field string, from, to *string) models.FieldChange {
+	return models.FieldChange{
+		Field:       field,
+		From:        from,
+		To:          to,
+		FromDisplay: stringValue(from),
+		ToDisplay:   stringValue(to),
+	}
+}
+
+// describeChanges renders a change set as comment text, one sentence per
+// field (e.g., "Status changed from 'Open' to 'Closed'. Urgency changed from
+// 'Medium' to 'High'.").
+func describeChanges(changes []models.FieldChange) string {
+	sentences := make([]string, 0, len(changes))
+	for _, change := range changes {
+		sentences = append(sentences, describeChange(change))
+	}
+	return strings.Join(sentences, " ")
+}
+
+// describeChange renders a single field change.
+func describeChange(change models.FieldChange) string {
+	switch change.Field {
+	case changeFieldResolutionNotes:
+		switch {
+		case change.From == nil:
+			return "Resolution notes added."
+		case change.To == nil:
+			return "Resolution notes cleared."
+		default:
+			return "Resolution notes updated."
+		}
+	case changeFieldAssignee:
+		if change.To == nil {
+			return fmt.Sprintf("Assignee removed (was %s).", change.FromDisplay)
+		}
+		return fmt.Sprintf("Assignee changed from '%s' to '%s'.", change.FromDisplay, change.ToDisplay)
+	}
+
+	label := strings.ReplaceAll(change.Field, "_", " ")
+	label = strings.ToUpper(label[:1]) + label[1:]
+	switch {
+	case change.From == nil:
+		return fmt.Sprintf("%s set to '%s'.", label, change.ToDisplay)
+	case change.To == nil:
+		return fmt.Sprintf("%s cleared (was '%s').", label, change.FromDisplay)
+	default:
+		return fmt.Sprintf("%s changed from '%s' to '%s'.", label, change.FromDisplay, change.ToDisplay)
+	}
+}
+
+// assigneeDisplayName returns the user's name for a change set, "Unassigned"
+// for nil, or the raw ID if the user cannot be loaded.
+func (h *Handler) assigneeDisplayName(ctx context.Context, userID *string) string {
+	if userID == nil {
+		return "Unassigned"
+	}
+	name, err := h.getUserName(ctx, *userID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not fetch assignee name for change description", "userID", *userID, "error", err)
+		return *userID
+	}
+	return name
+}
+
+// stringValue dereferences an optional string ("" for nil).
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// optionalString returns nil for "", otherwise a pointer to s.
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
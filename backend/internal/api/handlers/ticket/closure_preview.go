@@ -0,0 +1,90 @@
+// backend/internal/api/handlers/ticket/closure_preview.go
+// ==========================================================================
+// Closure email preview. Staff can render the email a submitter would get
+// for draft resolution notes before closing the ticket; nothing is saved or
+// sent.
+// ==========================================================================
+
+package ticket
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// PreviewClosureEmail renders the closure email for draft resolution notes,
+// in the submitter's language, without closing the ticket or sending it.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Request Body:
+//   - Expects JSON matching models.ClosurePreviewRequest.
+//
+// Returns:
+//   - JSON APIResponse containing a models.EmailPreview or an error response.
+func (h *Handler) PreviewClosureEmail(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "PreviewClosureEmail", "ticketUUID", ticketID)
+
+	// --- 1. Bind & Validate ---
+	var req models.ClosurePreviewRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	if strings.TrimSpace(req.ResolutionNotes) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "resolution_notes is required.")
+	}
+
+	// --- 2. Authorization Check ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	ticket, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin)
+	if err != nil {
+		if err.Error() == "ticket not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		if err.Error() == "not authorized to access this ticket" {
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to view this ticket.")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+
+	// --- 3. Render ---
+	ticketLocale := ""
+	if ticket.Locale != nil {
+		ticketLocale = *ticket.Locale
+	}
+	locale := h.submitterLocale(ctx, ticketLocale, ticket.EndUserEmail)
+	subject, html, err := email.RenderTicketClosure(h.config.Server.PortalBaseURL, ticketID, ticket.Subject, req.ResolutionNotes, locale)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to render closure email", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render closure email.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data: models.EmailPreview{
+			Recipient:  ticket.EndUserEmail,
+			Locale:     locale,
+			Subject:    subject,
+			HTML:       html,
+			Suppressed: ticket.IsInternal, // Internal tickets never email the submitter
+		},
+	})
+}
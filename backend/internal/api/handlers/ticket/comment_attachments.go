@@ -0,0 +1,94 @@
+// backend/internal/api/handlers/ticket/comment_attachments.go
+// ==========================================================================
+// Attachments on individual comments. Files uploaded to a comment are stored
+// like any ticket attachment (same validation, storage, download and delete
+// routes) with update_id linking them to the comment, and are nested in that
+// comment when the ticket is fetched.
+// ==========================================================================
+
+package ticket
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// UploadCommentAttachment attaches one or more files to a comment. The same
+// rules as editing apply: the author may attach files within the edit window,
+// admins at any time, and system comments, submitter replies and deleted
+// comments take no attachments.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//   - commentId: The UUID of the comment (ticket update).
+//
+// Form Data:
+//   - Expects a multipart/form-data request with file field(s) named "attachments".
+//
+// Returns:
+//   - JSON response with an array of created Attachment metadata objects or an error response.
+func (h *Handler) UploadCommentAttachment(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID, commentID := c.Param("id"), c.Param("commentId")
+	logger := slog.With("handler", "UploadCommentAttachment", "ticketUUID", ticketID, "commentID", commentID)
+
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// --- 1. Load and Authorize the Comment ---
+	var ec editableComment
+	var status models.TicketStatus
+	err = h.db.Pool.QueryRow(ctx, `
+        SELECT tu.comment, tu.user_id, tu.is_system_update, tu.from_submitter, tu.created_at, tu.deleted_at, t.status
+        FROM ticket_updates tu
+        JOIN tickets t ON t.id = tu.ticket_id
+        WHERE tu.id = $1 AND tu.ticket_id = $2`, commentID, ticketID).Scan(
+		&ec.comment, &ec.authorID, &ec.isSystemUpdate, &ec.fromSubmitter, &ec.createdAt, &ec.deletedAt, &status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Comment not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load comment", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve comment.")
+	}
+	if status == models.StatusClosed {
+		return echo.NewHTTPError(http.StatusBadRequest, "Cannot add attachments to a closed ticket.")
+	}
+	if authErr := h.authorizeCommentChange(ec, userID, userRole); authErr != nil {
+		logger.WarnContext(ctx, "Comment attachment rejected", "userID", userID, "role", userRole, "reason", authErr.Error())
+		return authErr
+	}
+
+	// --- 2. Store the Files ---
+	return h.storeUploadedAttachments(c, logger, ticketID, &commentID)
+}
+
+// --- Helper Functions ---
+
+// nestCommentAttachments copies each attachment linked to a comment into that
+// comment's Attachments, keeping the attachments' display order.
+func nestCommentAttachments(updates []models.TicketUpdate, attachments []models.Attachment) {
+	byUpdate := make(map[string][]models.Attachment)
+	for _, att := range attachments {
+		if att.UpdateID != nil {
+			byUpdate[*att.UpdateID] = append(byUpdate[*att.UpdateID], att)
+		}
+	}
+	for i := range updates {
+		updates[i].Attachments = byUpdate[updates[i].ID]
+	}
+}
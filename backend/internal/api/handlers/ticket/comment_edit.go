@@ -0,0 +1,294 @@
+// backend/internal/api/handlers/ticket/comment_edit.go
+// ==========================================================================
+// Handlers for editing and deleting ticket comments. Authors may change their
+// own comments within the configured edit window; admins may change any
+// non-system comment at any time. The previous text is always preserved in
+// ticket_update_revisions, and deleted comments become tombstones (text
+// cleared, deleted_at set) rather than being removed.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// Revision actions recorded in ticket_update_revisions.
+const (
+	revisionActionEdit   = "edit"
+	revisionActionDelete = "delete"
+)
+
+// editableComment is the state of a comment needed to authorize a change.
+type editableComment struct {
+	comment        string
+	authorID       *string
+	isSystemUpdate bool
+	fromSubmitter  bool
+	createdAt      time.Time
+	deletedAt      *time.Time
+}
+
+// --- Handler Functions ---
+
+// UpdateTicketComment edits a comment's text, keeping the previous version.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//   - commentId: The comment (ticket update) UUID.
+//
+// Request Body:
+//   - content: The new comment text.
+//
+// Returns:
+//   - JSON APIResponse with the updated TicketUpdate, or an error response.
+func (h *Handler) UpdateTicketComment(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID, commentID := c.Param("id"), c.Param("commentId")
+	logger := slog.With("handler", "UpdateTicketComment", "ticketUUID", ticketID, "commentID", commentID)
+
+	var req models.TicketUpdateCreate
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	newComment := strings.TrimSpace(req.Comment)
+	if newComment == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Comment content cannot be empty.")
+	}
+
+	err := h.changeComment(ctx, c, ticketID, commentID, revisionActionEdit, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `UPDATE ticket_updates SET comment = $2, edited_at = NOW() WHERE id = $1`, commentID, newComment)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	updated, fetchErr := h.getTicketUpdateByID(ctx, commentID)
+	if fetchErr != nil {
+		logger.ErrorContext(ctx, "Failed to fetch edited comment", "error", fetchErr)
+		return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: "Comment updated."})
+	}
+	logger.InfoContext(ctx, "Comment edited")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Comment updated.",
+		Data:    updated,
+	})
+}
+
+// DeleteTicketComment tombstones a comment: its text moves to the revision
+// history and the row stays in the thread marked as deleted.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//   - commentId: The comment (ticket update) UUID.
+//
+// Returns:
+//   - JSON APIResponse on success, or an error response.
+func (h *Handler) DeleteTicketComment(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID, commentID := c.Param("id"), c.Param("commentId")
+	logger := slog.With("handler", "DeleteTicketComment", "ticketUUID", ticketID, "commentID", commentID)
+
+	err := h.changeComment(ctx, c, ticketID, commentID, revisionActionDelete, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `UPDATE ticket_updates SET comment = '', deleted_at = NOW() WHERE id = $1`, commentID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "Comment deleted")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Comment deleted.",
+	})
+}
+
+// GetTicketCommentHistory lists the previous versions of a comment, oldest first.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//   - commentId: The comment (ticket update) UUID.
+//
+// Returns:
+//   - JSON APIResponse containing TicketUpdateRevision objects, or an error response.
+func (h *Handler) GetTicketCommentHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID, commentID := c.Param("id"), c.Param("commentId")
+	logger := slog.With("handler", "GetTicketCommentHistory", "ticketUUID", ticketID, "commentID", commentID)
+
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	if accessErr := h.checkCommentTicketAccess(ctx, ticketID, userID, userRole); accessErr != nil {
+		return accessErr
+	}
+
+	// Internal-note revisions are hidden like the note itself.
+	var isInternalNote bool
+	err = h.db.Pool.QueryRow(ctx, `SELECT is_internal_note FROM ticket_updates WHERE id = $1 AND ticket_id = $2`,
+		commentID, ticketID).Scan(&isInternalNote)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Comment not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to look up comment", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve comment history.")
+	}
+	if isInternalNote && !auth.IsStaffOrAdmin(userRole) {
+		return echo.NewHTTPError(http.StatusNotFound, "Comment not found.")
+	}
+
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT r.id, r.update_id, r.previous_comment, r.action, r.actor_user_id, u.name, r.created_at
+        FROM ticket_update_revisions r
+        LEFT JOIN users u ON u.id = r.actor_user_id
+        WHERE r.update_id = $1
+        ORDER BY r.created_at ASC`, commentID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query comment revisions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve comment history.")
+	}
+	defer rows.Close()
+
+	revisions := make([]models.TicketUpdateRevision, 0)
+	for rows.Next() {
+		var rev models.TicketUpdateRevision
+		if err := rows.Scan(&rev.ID, &rev.UpdateID, &rev.PreviousComment, &rev.Action, &rev.ActorUserID, &rev.ActorName, &rev.CreatedAt); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan comment revision", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process comment history.")
+		}
+		revisions = append(revisions, rev)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating comment revisions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process comment history.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    revisions,
+	})
+}
+
+// --- Helpers ---
+
+// checkCommentTicketAccess runs checkTicketAccess for a comment handler and
+// maps its errors to 404 (ticket not found) or 403 (no access to the ticket).
+func (h *Handler) checkCommentTicketAccess(ctx context.Context, ticketID, userID string, userRole models.UserRole) error {
+	if _, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin); err != nil {
+		if err.Error() == "ticket not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		if err.Error() == "not authorized to access this ticket" {
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to access this ticket.")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+	return nil
+}
+
+// changeComment checks ticket access, locks the comment, checks that the caller
+// may change it, records its current text as a revision and applies apply, in
+// one transaction.
+func (h *Handler) changeComment(ctx context.Context, c echo.Context, ticketID, commentID, action string, apply func(tx pgx.Tx) error) error {
+	logger := slog.With("helper", "changeComment", "commentID", commentID, "action", action)
+
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	if accessErr := h.checkCommentTicketAccess(ctx, ticketID, userID, userRole); accessErr != nil {
+		return accessErr
+	}
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to start transaction.")
+	}
+	defer tx.Rollback(ctx) // No-op after commit
+
+	var ec editableComment
+	err = tx.QueryRow(ctx, `
+        SELECT comment, user_id, is_system_update, from_submitter, created_at, deleted_at
+        FROM ticket_updates WHERE id = $1 AND ticket_id = $2
+        FOR UPDATE`, commentID, ticketID).Scan(
+		&ec.comment, &ec.authorID, &ec.isSystemUpdate, &ec.fromSubmitter, &ec.createdAt, &ec.deletedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Comment not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load comment", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve comment.")
+	}
+	if authErr := h.authorizeCommentChange(ec, userID, userRole); authErr != nil {
+		logger.WarnContext(ctx, "Comment change rejected", "userID", userID, "role", userRole, "reason", authErr.Error())
+		return authErr
+	}
+
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO ticket_update_revisions (update_id, previous_comment, action, actor_user_id)
+        VALUES ($1, $2, $3, $4)`, commentID, ec.comment, action, userID); err != nil {
+		logger.ErrorContext(ctx, "Failed to record comment revision", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save comment change.")
+	}
+	if err := apply(tx); err != nil {
+		logger.ErrorContext(ctx, "Failed to apply comment change", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save comment change.")
+	}
+	if _, err := tx.Exec(ctx, `UPDATE tickets SET updated_at = NOW() WHERE id = $1`, ticketID); err != nil {
+		logger.ErrorContext(ctx, "Failed to touch ticket updated_at", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save comment change.")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit comment change", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save comment change.")
+	}
+	return nil
+}
+
+// authorizeCommentChange applies the edit/delete rules: system comments and
+// submitter replies are never editable, deleted comments cannot change again,
+// admins may change any other comment, and authors only within the edit window.
+func (h *Handler) authorizeCommentChange(ec editableComment, userID string, userRole models.UserRole) error {
+	if ec.isSystemUpdate || ec.fromSubmitter || ec.authorID == nil {
+		return apierror.New(http.StatusForbidden, apierror.CodeCommentNotEditable, "System comments and submitter replies cannot be edited or deleted.")
+	}
+	if ec.deletedAt != nil {
+		return apierror.New(http.StatusConflict, apierror.CodeCommentDeleted, "Comment has already been deleted.")
+	}
+	if userRole == models.RoleAdmin {
+		return nil
+	}
+	if *ec.authorID != userID {
+		return apierror.New(http.StatusForbidden, apierror.CodeCommentNotEditable, "Only the comment author or an admin can change this comment.")
+	}
+	window := h.config.Tickets.CommentEditWindow
+	if window <= 0 || time.Since(ec.createdAt) > window {
+		return apierror.New(http.StatusForbidden, apierror.CodeEditWindowExpired, "The edit window for this comment has passed.")
+	}
+	return nil
+}
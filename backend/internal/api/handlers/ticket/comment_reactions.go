@@ -0,0 +1,190 @@
+// backend/internal/api/handlers/ticket/comment_reactions.go
+// ==========================================================================
+// Emoji reactions on ticket comments. Reactions are stored per user, so each
+// user reacts at most once per emoji; posting the same emoji again removes it.
+// System comments and deleted comments cannot be reacted to, and comments the
+// caller cannot see (no access to the ticket, or an internal note for a
+// non-staff user) are reported as not found.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// maxReactionRunes bounds a reaction so it holds a single (possibly
+// multi-codepoint) emoji rather than arbitrary text.
+const maxReactionRunes = 8
+
+// --- Handler Functions ---
+
+// ToggleCommentReaction adds the caller's reaction to a comment, or removes
+// it if the caller already reacted with the same emoji.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//   - commentId: The comment (ticket update) UUID.
+//
+// Request Body:
+//   - emoji: The emoji to toggle.
+//
+// Returns:
+//   - JSON APIResponse with the comment's aggregated reactions, or an error response.
+func (h *Handler) ToggleCommentReaction(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID, commentID := c.Param("id"), c.Param("commentId")
+	logger := slog.With("handler", "ToggleCommentReaction", "ticketUUID", ticketID, "commentID", commentID)
+
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	var req models.ReactionToggle
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	emoji, ok := normalizeReaction(req.Emoji)
+	if !ok {
+		return echo.NewHTTPError(http.StatusBadRequest, "Reaction must be a single emoji.")
+	}
+
+	// A ticket the caller cannot access is reported like a missing comment, so
+	// reacting does not confirm that a comment ID exists.
+	if _, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin); err != nil {
+		if err.Error() == "ticket not found" || err.Error() == "not authorized to access this ticket" {
+			return echo.NewHTTPError(http.StatusNotFound, "Comment not found.")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+
+	// The comment must belong to the ticket and be a live, non-system comment
+	// the caller can see.
+	var isSystemUpdate, fromSubmitter, deleted, isInternalNote bool
+	var authorID *string
+	err = h.db.Pool.QueryRow(ctx, `
+        SELECT is_system_update, from_submitter, user_id, deleted_at IS NOT NULL, is_internal_note
+        FROM ticket_updates WHERE id = $1 AND ticket_id = $2`,
+		commentID, ticketID).Scan(&isSystemUpdate, &fromSubmitter, &authorID, &deleted, &isInternalNote)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Comment not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load comment", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve comment.")
+	}
+	if isInternalNote && !auth.IsStaffOrAdmin(userRole) {
+		return echo.NewHTTPError(http.StatusNotFound, "Comment not found.")
+	}
+	if isSystemUpdate || (authorID == nil && !fromSubmitter) {
+		return apierror.New(http.StatusForbidden, apierror.CodeCommentNotEditable, "System comments cannot be reacted to.")
+	}
+	if deleted {
+		return apierror.New(http.StatusConflict, apierror.CodeCommentDeleted, "Comment has been deleted.")
+	}
+
+	tag, err := h.db.Pool.Exec(ctx, `
+        DELETE FROM ticket_update_reactions WHERE update_id = $1 AND user_id = $2 AND emoji = $3`,
+		commentID, userID, emoji)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to remove reaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update reaction.")
+	}
+	added := tag.RowsAffected() == 0
+	if added {
+		if _, err := h.db.Pool.Exec(ctx, `
+            INSERT INTO ticket_update_reactions (update_id, user_id, emoji)
+            VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			commentID, userID, emoji); err != nil {
+			logger.ErrorContext(ctx, "Failed to add reaction", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update reaction.")
+		}
+	}
+
+	reactions, err := h.fetchCommentReactions(ctx, `tu.id = $1`, commentID, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to fetch reactions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve reactions.")
+	}
+	logger.DebugContext(ctx, "Reaction toggled", "emoji", emoji, "added", added)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    nonNilReactions(reactions[commentID]),
+	})
+}
+
+// --- Helpers ---
+
+// fetchTicketReactions returns the aggregated reactions for every comment on a
+// ticket, keyed by comment ID.
+func (h *Handler) fetchTicketReactions(ctx context.Context, ticketID, userID string) (map[string][]models.ReactionCount, error) {
+	return h.fetchCommentReactions(ctx, `tu.ticket_id = $1`, ticketID, userID)
+}
+
+// fetchCommentReactions aggregates reactions for the comments matched by
+// where (which references $1), ordered by when each emoji was first used.
+// userID ($2) marks the emojis the requesting user has reacted with.
+func (h *Handler) fetchCommentReactions(ctx context.Context, where, arg, userID string) (map[string][]models.ReactionCount, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT r.update_id, r.emoji, COUNT(*), BOOL_OR(r.user_id::text = $2)
+        FROM ticket_update_reactions r
+        JOIN ticket_updates tu ON tu.id = r.update_id
+        WHERE `+where+`
+        GROUP BY r.update_id, r.emoji
+        ORDER BY r.update_id, MIN(r.created_at)`, arg, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactions := make(map[string][]models.ReactionCount)
+	for rows.Next() {
+		var updateID string
+		var rc models.ReactionCount
+		if err := rows.Scan(&updateID, &rc.Emoji, &rc.Count, &rc.ReactedByMe); err != nil {
+			return nil, err
+		}
+		reactions[updateID] = append(reactions[updateID], rc)
+	}
+	return reactions, rows.Err()
+}
+
+// normalizeReaction trims the reaction and checks it is a short run of
+// symbol characters (no letters, digits, spaces or control characters).
+func normalizeReaction(raw string) (string, bool) {
+	emoji := strings.TrimSpace(raw)
+	if emoji == "" || len(emoji) > 32 || utf8.RuneCountInString(emoji) > maxReactionRunes {
+		return "", false
+	}
+	for _, r := range emoji {
+		if r < utf8.RuneSelf || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r) || unicode.IsControl(r) {
+			return "", false
+		}
+	}
+	return emoji, true
+}
+
+// nonNilReactions returns an empty slice instead of nil so responses encode as [].
+func nonNilReactions(reactions []models.ReactionCount) []models.ReactionCount {
+	if reactions == nil {
+		return []models.ReactionCount{}
+	}
+	return reactions
+}
@@ -10,18 +10,18 @@
 package ticket
 
 import (
-	"bytes" // Import bytes for reading raw body
 	"context"
 	"errors"
 	"fmt"
-	"io" // Import io for ReadAll
 	"log/slog"
 	"net/http"
 	"strings" // Import strings package for TrimSpace
 	"time"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth" // Auth helpers
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"            // Structured error responses
 	"github.com/henrythedeveloper/it-ticket-system/internal/models"              // Data models
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
@@ -30,6 +30,13 @@ import (
 
 // AddTicketComment handles requests to add a new comment or update to a ticket.
 // It performs authorization checks and inserts the comment into the database.
+// With reply_to_submitter set, the comment is a public reply: it is also emailed
+// to the submitter, and the send time is recorded on the comment. With
+// TICKET_AUTO_ASSIGN_ON_COMMENT, a staff comment on an unassigned ticket also
+// assigns the ticket to its author (see autoAssignCommenter). Each user may add
+// at most TICKET_COMMENT_RATE_LIMIT comments per ticket within
+// TICKET_COMMENT_RATE_WINDOW (see checkCommentRate). A public reply on a
+// Waiting on Customer ticket restarts its reminder schedule.
 //
 // Path Parameters:
 //   - id: The UUID of the ticket to add the comment to.
@@ -38,7 +45,7 @@ import (
 //   - Expects JSON matching models.TicketUpdateCreate.
 //
 // Returns:
-//   - JSON response with the newly created TicketUpdate object or an error response.
+//   - JSON response with the newly created TicketUpdate object, 429 if the comment rate limit is reached, or an error response.
 func (h *Handler) AddTicketComment(c echo.Context) (err error) { // Use named return for defer rollback check
 	ctx := c.Request().Context()
 	ticketID := c.Param("id")
@@ -54,29 +61,15 @@ func (h *Handler) AddTicketComment(c echo.Context) (err error) { // Use named re
 		return echo.NewHTTPError(http.StatusBadRequest, "Missing ticket ID.")
 	}
 
-	// *** ADDED: Log raw request body BEFORE binding ***
-	var rawBodyBytes []byte
-	if c.Request().Body != nil {
-		rawBodyBytes, _ = io.ReadAll(c.Request().Body)
-		// Restore the body so it can be read again by Bind
-		c.Request().Body = io.NopCloser(bytes.NewBuffer(rawBodyBytes))
-		logger.DebugContext(ctx, "Raw request body received", "rawBody", string(rawBodyBytes))
-	} else {
-		logger.WarnContext(ctx, "Request body is nil before binding")
-	}
-	// *** END ADDED LOG ***
-
 	var commentCreate models.TicketUpdateCreate
 	// *** REVERTED: Use single argument for c.Bind ***
 	if err = c.Bind(&commentCreate); err != nil {
 		// Log the binding error specifically
 		logger.ErrorContext(ctx, "Failed to bind request body", "error", err)
-		// Log the raw body again in case of error
-		logger.DebugContext(ctx, "Raw request body on bind failure", "rawBody", string(rawBodyBytes))
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 	// Log the bound data *after* successful binding
-	logger.DebugContext(ctx, "Request body bound successfully", "commentContentLength", len(commentCreate.Comment), "commentContent", commentCreate.Comment, "isInternal", commentCreate.IsInternalNote)
+	logger.DebugContext(ctx, "Request body bound successfully", "commentContentLength", len(commentCreate.Comment), "isInternal", commentCreate.IsInternalNote)
 
 
 	// Validation: Check if comment content is empty AFTER binding
@@ -100,10 +93,10 @@ func (h *Handler) AddTicketComment(c echo.Context) (err error) { // Use named re
 	// --- 3. Authorization & Pre-checks ---
 	// Fetch ticket status and assignee ID to check permissions
 	var currentStatus models.TicketStatus
-	var assignedToUserID *string
+	var assignedToUserID, assignedToTeamID *string
 	err = h.db.Pool.QueryRow(ctx, `
-        SELECT status, assigned_to_user_id FROM tickets WHERE id = $1
-    `, ticketID).Scan(&currentStatus, &assignedToUserID)
+        SELECT status, assigned_to_user_id, assigned_to_team_id FROM tickets WHERE id = $1
+    `, ticketID).Scan(&currentStatus, &assignedToUserID, &assignedToTeamID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			logger.WarnContext(ctx, "Ticket not found")
@@ -121,11 +114,35 @@ func (h *Handler) AddTicketComment(c echo.Context) (err error) { // Use named re
 
 	// Authorization: Check if user is allowed to add this type of comment
 	// Check if non-staff/admin is trying to add an internal note
-	if commentCreate.IsInternalNote && userRole != models.RoleAdmin && userRole != models.RoleStaff {
+	if commentCreate.IsInternalNote && !auth.IsStaffOrAdmin(userRole) {
 		logger.WarnContext(ctx, "Unauthorized attempt to add internal note", "userID", userID, "userRole", userRole)
 		return echo.NewHTTPError(http.StatusForbidden, "You are not authorized to add internal notes.")
 	}
 
+	// Public replies are staff-only and cannot also be internal notes
+	var replyTarget *publicReplyTarget
+	if commentCreate.ReplyToSubmitter {
+		if commentCreate.IsInternalNote {
+			return echo.NewHTTPError(http.StatusBadRequest, "An internal note cannot be sent to the submitter.")
+		}
+		if !auth.IsStaffOrAdmin(userRole) {
+			logger.WarnContext(ctx, "Unauthorized attempt to reply to submitter", "userID", userID, "userRole", userRole)
+			return echo.NewHTTPError(http.StatusForbidden, "You are not authorized to reply to the submitter.")
+		}
+		replyTarget, err = h.getPublicReplyTarget(ctx, ticketID)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to load submitter details for public reply", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve ticket details.")
+		}
+		if replyTarget.isInternal {
+			return echo.NewHTTPError(http.StatusBadRequest, "Internal tickets have no submitter to reply to.")
+		}
+	}
+
+	if err = h.checkCommentRate(ctx, ticketID, userID, logger); err != nil {
+		return err
+	}
+
 	// --- 4. Database Insertion (within Transaction) ---
 	tx, err := h.db.Pool.Begin(ctx)
 	if err != nil {
@@ -145,10 +162,10 @@ func (h *Handler) AddTicketComment(c echo.Context) (err error) { // Use named re
 	// Insert the comment
 	var commentID string
 	err = tx.QueryRow(ctx, `
-        INSERT INTO ticket_updates (ticket_id, user_id, comment, is_internal_note, created_at)
-        VALUES ($1, $2, $3, $4, $5)
+        INSERT INTO ticket_updates (ticket_id, user_id, comment, is_internal_note, is_public_reply, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
         RETURNING id
-    `, ticketID, userID, commentCreate.Comment, commentCreate.IsInternalNote, time.Now()).Scan(&commentID)
+    `, ticketID, userID, commentCreate.Comment, commentCreate.IsInternalNote, commentCreate.ReplyToSubmitter, time.Now()).Scan(&commentID)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to insert ticket update", "error", err)
 		// Use the named return variable 'err' to trigger the deferred rollback
@@ -165,6 +182,27 @@ func (h *Handler) AddTicketComment(c echo.Context) (err error) { // Use named re
 		// err = fmt.Errorf("failed to update ticket timestamp: %w", err) // Uncomment to trigger rollback
 	}
 
+	// Auto-assign the ticket to the first staff member who comments on it
+	autoAssigned := false
+	if err == nil && assignedToUserID == nil && h.config.Tickets.AutoAssignOnComment && auth.IsStaffOrAdmin(userRole) {
+		autoAssigned, err = h.autoAssignCommenter(ctx, tx, ticketID, userID, assignedToTeamID)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to auto-assign ticket to commenter", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to add comment.")
+		}
+	}
+
+	// A public reply on a ticket already waiting on the submitter asks them
+	// again, so the reminder schedule starts over from this reply
+	if err == nil && replyTarget != nil && currentStatus == models.StatusWaitingOnCustomer {
+		if _, err = tx.Exec(ctx, `
+            UPDATE tickets SET waiting_since = NOW(), waiting_reminders_sent = 0, waiting_last_reminder_at = NULL
+            WHERE id = $1 AND status = $2`, ticketID, models.StatusWaitingOnCustomer); err != nil {
+			logger.ErrorContext(ctx, "Failed to restart waiting reminders", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to add comment.")
+		}
+	}
+
 	// Commit transaction (only if no critical error occurred)
 	if err == nil {
 		err = tx.Commit(ctx)
@@ -179,6 +217,13 @@ func (h *Handler) AddTicketComment(c echo.Context) (err error) { // Use named re
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to complete comment addition.")
 	}
 
+	if replyTarget != nil {
+		h.sendPublicReply(ctx, commentID, userID, commentCreate.Comment, replyTarget)
+	}
+	if autoAssigned {
+		h.notifyAutoAssignment(ctx, ticketID)
+	}
+
 	// --- 5. Fetch Created Comment with User Details ---
 	// Fetch the comment we just created to include user details in the response
 	createdComment, fetchErr := h.getTicketUpdateByID(ctx, commentID)
@@ -214,7 +259,8 @@ func (h *Handler) getTicketUpdateByID(ctx context.Context, updateID string) (*mo
 
 	err := h.db.Pool.QueryRow(ctx, `
         SELECT
-            tu.id, tu.ticket_id, tu.user_id, tu.comment, tu.is_internal_note, tu.created_at,
+            tu.id, tu.ticket_id, tu.user_id, tu.comment, tu.is_internal_note, tu.created_at, tu.from_submitter,
+            tu.is_system_update, tu.edited_at, tu.deleted_at, tu.is_public_reply, tu.emailed_at,
             -- User details (nullable)
             u.id, u.name, u.email, u.role, u.created_at, u.updated_at
         FROM ticket_updates tu
@@ -222,7 +268,8 @@ func (h *Handler) getTicketUpdateByID(ctx context.Context, updateID string) (*mo
         WHERE tu.id = $1
     `, updateID).Scan(
 		&update.ID, &update.TicketID, &updateUserID, &update.Comment,
-		&update.IsInternalNote, &update.CreatedAt,
+		&update.IsInternalNote, &update.CreatedAt, &update.FromSubmitter,
+		&update.IsSystemUpdate, &update.EditedAt, &update.DeletedAt, &update.IsPublicReply, &update.EmailedAt,
 		// User details (scan into nullable pointers)
 		&user.ID, // Scan directly into user.ID (string)
 		&userName, &userEmail, &userRole,
@@ -253,6 +300,9 @@ func (h *Handler) getTicketUpdateByID(ctx context.Context, updateID string) (*mo
 			update.User = &models.User{ID: *updateUserID, Name: "Unknown User"} // Provide fallback
 			logger.WarnContext(ctx, "User details not found for update author", "authorUserID", *updateUserID)
 		}
+	} else if update.FromSubmitter {
+		// Reply posted by the submitter through the public status endpoint
+		update.User = &models.User{Name: "Submitter"}
 	} else {
 		// Handle system comment where user_id might be NULL
 		update.User = &models.User{Name: "System"} // Indicate system action
@@ -260,3 +310,152 @@ func (h *Handler) getTicketUpdateByID(ctx context.Context, updateID string) (*mo
 
 	return &update, nil
 }
+
+// checkCommentRate rejects a comment when the user already added
+// TICKET_COMMENT_RATE_LIMIT comments to the ticket within
+// TICKET_COMMENT_RATE_WINDOW, protecting ticket_updates and the notification
+// fan-out from runaway clients.
+//
+// Returns:
+//   - error: 429 when the limit is reached, 500 if the count fails, nil otherwise.
+func (h *Handler) checkCommentRate(ctx context.Context, ticketID, userID string, logger *slog.Logger) error {
+	limit := h.config.Tickets.CommentRateLimit
+	if limit <= 0 {
+		return nil
+	}
+	var recent int
+	if err := h.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM ticket_updates
+        WHERE ticket_id = $1 AND user_id = $2 AND created_at > $3`,
+		ticketID, userID, time.Now().Add(-h.config.Tickets.CommentRateWindow)).Scan(&recent); err != nil {
+		logger.ErrorContext(ctx, "Failed to count recent comments", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to add comment.")
+	}
+	if recent >= limit {
+		logger.WarnContext(ctx, "Comment rejected: per-ticket comment rate limit reached", "userID", userID, "recent", recent, "limit", limit)
+		return apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited,
+			"You are commenting on this ticket too quickly. Please wait a moment before adding another comment.")
+	}
+	return nil
+}
+
+// autoAssignCommenter assigns an unassigned ticket to the staff member who
+// just commented on it, recording the assignment and a history entry. A
+// ticket that belongs to a team is only taken by one of its members, as with
+// ClaimTicket.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - tx: The comment's transaction.
+//   - ticketID: The ticket commented on.
+//   - userID: The commenter (Staff or Admin).
+//   - teamID: The ticket's team (nil if none).
+//
+// Returns:
+//   - bool: Whether the ticket was assigned (false if it was assigned concurrently).
+//   - error: If any statement fails.
+func (h *Handler) autoAssignCommenter(ctx context.Context, tx pgx.Tx, ticketID, userID string, teamID *string) (bool, error) {
+	if teamID != nil {
+		isMember, err := h.isTeamMember(ctx, teamID, userID)
+		if err != nil || !isMember {
+			return false, err
+		}
+	}
+	tag, err := tx.Exec(ctx, `
+        UPDATE tickets SET assigned_to_user_id = $2, updated_at = NOW()
+        WHERE id = $1 AND assigned_to_user_id IS NULL`, ticketID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to assign commenter: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if err := applyAssignmentChange(ctx, tx, ticketID, nil, userID, userID); err != nil {
+		return false, err
+	}
+	assigneeName := h.assigneeDisplayName(ctx, &userID)
+	changes := []models.FieldChange{{
+		Field:       changeFieldAssignee,
+		To:          &userID,
+		FromDisplay: h.assigneeDisplayName(ctx, nil),
+		ToDisplay:   assigneeName,
+	}}
+	summary := fmt.Sprintf("Ticket auto-assigned to %s on their first comment. %s", assigneeName, describeChanges(changes))
+	if err := h.addHistoryEntry(ctx, tx, ticketID, userID, summary, changes); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// notifyAutoAssignment sends the assignment email to a ticket's new assignee
+// and dispatches the ticket update, as UpdateTicket does for a manual
+// assignment. Failures are only logged.
+func (h *Handler) notifyAutoAssignment(ctx context.Context, ticketID string) {
+	logger := slog.With("helper", "notifyAutoAssignment", "ticketUUID", ticketID)
+	updatedTicket, err := h.getTicketDetailsByID(ctx, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to fetch ticket after auto-assignment", "error", err)
+		return
+	}
+	if updatedTicket.AssignedToUser != nil {
+		go func(recipient, tID, subj string) {
+			bgCtx := context.Background()
+			emailLogger := slog.With("operation", "SendTicketAssignment", "ticketID", tID)
+			if emailErr := h.emailService.SendTicketAssignment(recipient, tID, subj); emailErr != nil {
+				emailLogger.ErrorContext(bgCtx, "Failed to send assignment email", "recipient", recipient, "error", emailErr)
+			} else {
+				emailLogger.InfoContext(bgCtx, "Sent assignment email", "recipient", recipient)
+			}
+		}(updatedTicket.AssignedToUser.Email, ticketID, updatedTicket.Subject)
+	}
+	go h.webhooks.Dispatch(webhook.EventTicketUpdated, updatedTicket)
+}
+
+// publicReplyTarget is the submitter-side data needed to email a public reply.
+type publicReplyTarget struct {
+	ticketNumber int32
+	subject      string
+	endUserEmail string
+	locale       string
+	isInternal   bool
+}
+
+// getPublicReplyTarget loads the submitter details of a ticket for a public reply.
+func (h *Handler) getPublicReplyTarget(ctx context.Context, ticketID string) (*publicReplyTarget, error) {
+	var target publicReplyTarget
+	err := h.db.Pool.QueryRow(ctx, `
+        SELECT t.ticket_number, t.subject, t.end_user_email, COALESCE(t.locale, s.locale, ''), t.is_internal
+        FROM tickets t
+        LEFT JOIN users s ON s.email = t.end_user_email
+        WHERE t.id = $1`, ticketID,
+	).Scan(&target.ticketNumber, &target.subject, &target.endUserEmail, &target.locale, &target.isInternal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch submitter details: %w", err)
+	}
+	return &target, nil
+}
+
+// sendPublicReply emails a committed public reply to the submitter and, once
+// the mailer accepts it, records emailed_at on the comment. Runs
+// asynchronously; failures are only logged (emailed_at stays NULL).
+func (h *Handler) sendPublicReply(ctx context.Context, commentID, authorUserID, comment string, target *publicReplyTarget) {
+	authorName, err := h.getUserName(ctx, authorUserID)
+	if err != nil {
+		authorName = "IT Support"
+	}
+	authorName = h.submitterFacingStaffName(authorName, target.locale)
+	go func(authorName string) {
+		bgCtx := context.Background()
+		emailLogger := slog.With("operation", "SendTicketReply", "commentID", commentID, "ticketNumber", target.ticketNumber)
+		ticketNumStr := fmt.Sprintf("%d", target.ticketNumber)
+		if emailErr := h.emailService.SendTicketReply(target.endUserEmail, ticketNumStr, target.subject, authorName, comment, target.locale); emailErr != nil {
+			emailLogger.ErrorContext(bgCtx, "Failed to send public reply email", "recipient", target.endUserEmail, "error", emailErr)
+			return
+		}
+		if _, err := h.db.Pool.Exec(bgCtx, `UPDATE ticket_updates SET emailed_at = NOW() WHERE id = $1`, commentID); err != nil {
+			emailLogger.ErrorContext(bgCtx, "Failed to record public reply email time", "error", err)
+			return
+		}
+		emailLogger.InfoContext(bgCtx, "Sent public reply email", "recipient", target.endUserEmail)
+	}(authorName)
+}
@@ -5,6 +5,7 @@
 // **REVISED AGAIN**: Fixed import paths and removed duplicate function.
 // **REVISED AGAIN**: Moved validation check after extracting form values.
 // **REVISED AGAIN**: Added submitter_name handling.
+// **REVISED AGAIN**: Also accepts application/json bodies (no attachments).
 // ==========================================================================
 
 package ticket
@@ -17,62 +18,64 @@ import (
 	"log/slog"
 	"mime/multipart" // Import for multipart handling
 	"net/http"
-	"path/filepath"
 	"strconv"
 	"strings" // Import strings package
 	"time"
 
 	// Import uuid package
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/captcha"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/i18n"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Correct models import
+	"github.com/henrythedeveloper/it-ticket-system/internal/solutions"
+	"github.com/henrythedeveloper/it-ticket-system/internal/timezone"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
 	"github.com/jackc/pgx/v5"                                       // Correct pgx import
 	"github.com/labstack/echo/v4"                                   // Correct echo import
 	// Removed invalid/duplicate imports
 )
 
 // CreateTicket handles the HTTP request to create a new support ticket.
-// It now expects multipart/form-data, processes form fields for ticket data,
-// handles file uploads, and saves attachment metadata.
+// It accepts either multipart/form-data (form fields plus optional file uploads)
+// or application/json (a TicketCreate body, no attachments), branching on the
+// request Content-Type, and saves attachment metadata for multipart requests.
 func (h *Handler) CreateTicket(c echo.Context) (err error) { // Use named return for defer rollback check
 	ctx := c.Request().Context()
 	logger := slog.With("handler", "CreateTicket")
 
-	// --- 1. Parse Multipart Form ---
-	const maxMemory = 32 << 20 // 32MB
-	if err = c.Request().ParseMultipartForm(maxMemory); err != nil {
-		logger.ErrorContext(ctx, "Failed to parse multipart form", "error", err)
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid form data: "+err.Error())
-	}
-	form := c.Request().MultipartForm
-
-	// --- 2. Extract and Validate Form Fields ---
-	getFormValue := func(key string, defaultValue string) string {
-		if values, ok := form.Value[key]; ok && len(values) > 0 {
-			return values[0]
+	// --- 1. Parse Request Body (JSON or Multipart Form) ---
+	var ticketCreate models.TicketCreate
+	var files []*multipart.FileHeader
+	isJSON := strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON)
+	if isJSON {
+		// --- 2a. Bind JSON Body (attachments are not supported on this path) ---
+		if err = c.Bind(&ticketCreate); err != nil {
+			logger.WarnContext(ctx, "Failed to bind JSON ticket body", "error", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body: "+err.Error())
 		}
-		return defaultValue
-	}
-	getFormValueSlice := func(key string) []string {
-		if values, ok := form.Value[key]; ok {
-			return values
+		if ticketCreate.SubmitterName != nil && strings.TrimSpace(*ticketCreate.SubmitterName) == "" {
+			ticketCreate.SubmitterName = nil
+		}
+		ticketCreate.Locale = i18n.Normalize(ticketCreate.Locale)
+	} else {
+		// --- 2b. Extract Form Fields and Files ---
+		ticketCreate, files, err = parseMultipartTicket(c)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to parse multipart form", "error", err)
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid form data: "+err.Error())
 		}
-		return []string{}
 	}
-
-	// Extract submitterName
-	submitterName := getFormValue("submitterName", "")
-	var submitterNamePtr *string
-	if strings.TrimSpace(submitterName) != "" {
-		submitterNamePtr = &submitterName
+	// Without an explicit choice, use the browser's language if supported. If neither
+	// matches, the locale stays empty and the submitter's account preference applies.
+	if ticketCreate.Locale == "" {
+		ticketCreate.Locale = i18n.Normalize(c.Request().Header.Get("Accept-Language"))
 	}
-
-	ticketCreate := models.TicketCreate{
-		SubmitterName: submitterNamePtr, // <<< ADDED
-		EndUserEmail:  getFormValue("endUserEmail", ""),
-		IssueType:     getFormValue("issueType", ""),
-		Urgency:       models.TicketUrgency(getFormValue("urgency", string(models.UrgencyMedium))),
-		Subject:       getFormValue("subject", ""),
-		Description:   getFormValue("description", ""),
-		Tags:          getFormValueSlice("tags"),
+	// Unset urgency defaults to Medium, or is derived from the due date below.
+	urgencyUnset := ticketCreate.Urgency == ""
+	if urgencyUnset {
+		ticketCreate.Urgency = models.UrgencyMedium
 	}
 
 	// --- Validation ---
@@ -88,20 +91,130 @@ func (h *Handler) CreateTicket(c echo.Context) (err error) { // Use named return
 		logger.WarnContext(ctx, "Invalid urgency value", "urgency", ticketCreate.Urgency)
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid urgency value.")
 	}
+	if validationErr := h.validateAttachmentBatch(files); validationErr != nil {
+		logger.WarnContext(ctx, "Attachment batch rejected", "fileCount", len(files), "error", validationErr)
+		return apierror.New(http.StatusBadRequest, apierror.CodeAttachmentInvalid, validationErr.Error())
+	}
+	for _, fileHeader := range files {
+		if validationErr := h.validateAttachment(fileHeader); validationErr != nil {
+			logger.WarnContext(ctx, "Attachment validation failed", "filename", fileHeader.Filename, "error", validationErr)
+			return apierror.New(http.StatusBadRequest, apierror.CodeAttachmentInvalid, fmt.Sprintf("validation failed for file '%s': %v", fileHeader.Filename, validationErr))
+		}
+	}
+	filenames, err := h.attachmentFilenames(ctx, "", files) // New ticket: only same-name files within the submission can clash
+	if err != nil {
+		return err
+	}
+	// Internal tickets may only be opened by authenticated staff (optional JWT on this route).
+	if ticketCreate.IsInternal {
+		if role := auth.OptionalUserRole(c); !auth.IsStaffOrAdmin(role) {
+			logger.WarnContext(ctx, "Internal ticket creation rejected for non-staff caller", "role", role)
+			return echo.NewHTTPError(http.StatusForbidden, "Only staff can create internal tickets.")
+		}
+	}
+	dueDate, err := parseDueDate(ticketCreate.DueDate, timezone.RequestLocation(c, h.db))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid due_date: "+err.Error())
+	}
+	if dueDate != nil && dueDate.Before(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "due_date must be in the future.")
+	}
 	// --- End Validation ---
 
+	// --- CAPTCHA Verification (public form spam protection; staff-created internal tickets are exempt) ---
+	if h.captcha.Enabled() && !ticketCreate.IsInternal {
+		if captchaErr := h.captcha.Verify(ctx, ticketCreate.CaptchaToken, c.RealIP()); captchaErr != nil {
+			if errors.Is(captchaErr, captcha.ErrUnavailable) {
+				logger.ErrorContext(ctx, "CAPTCHA verification unavailable", "error", captchaErr)
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "CAPTCHA verification is temporarily unavailable. Please try again.")
+			}
+			logger.WarnContext(ctx, "CAPTCHA verification failed", "email", ticketCreate.EndUserEmail, "error", captchaErr)
+			return echo.NewHTTPError(http.StatusBadRequest, "CAPTCHA verification failed.")
+		}
+	}
+
+	// --- Open Ticket Cap (public submissions only; staff-created tickets are exempt) ---
+	if capErr := h.checkOpenTicketCap(c, ticketCreate.EndUserEmail); capErr != nil {
+		return capErr
+	}
+
+	// --- Spam Filter (public submissions only; matches are quarantined, not rejected) ---
+	var quarantineReason string
+	if role := auth.OptionalUserRole(c); !ticketCreate.IsInternal && role != models.RoleStaff && role != models.RoleAdmin {
+		if pattern, ok := h.matchSpam(ticketCreate.Subject, ticketCreate.Description); ok {
+			quarantineReason = pattern
+			logger.WarnContext(ctx, "Ticket quarantined by spam filter", "pattern", pattern, "email", ticketCreate.EndUserEmail, "ip", c.RealIP())
+		}
+	}
+	quarantined := quarantineReason != ""
+
+	// --- Urgency From Due Date (only when the submitter left urgency unset) ---
+	var dueUrgencyNote string
+	if urgencyUnset && dueDate != nil && h.config.Tickets.UrgencyFromDueDate {
+		days := daysUntilDue(*dueDate, time.Now(), timezone.RequestLocation(c, h.db))
+		ticketCreate.Urgency = urgencyFromDueDate(days)
+		dueUrgencyNote = urgencyFromDueDateComment(ticketCreate.Urgency, days)
+		logger.InfoContext(ctx, "Urgency derived from due date", "urgency", ticketCreate.Urgency, "daysUntilDue", days)
+	}
+
+	// --- Urgency Suggestion (advisory; only raises the default urgency) ---
+	var urgencyAdjustment string
+	if ticketCreate.Urgency == models.UrgencyMedium {
+		if suggested, keyword, ok := h.suggestUrgency(ticketCreate.Subject, ticketCreate.Description); ok {
+			urgencyAdjustment = urgencyAdjustedComment(ticketCreate.Urgency, suggested, keyword)
+			logger.InfoContext(ctx, "Urgency raised by keyword match", "from", ticketCreate.Urgency, "to", suggested, "keyword", keyword)
+			ticketCreate.Urgency = suggested
+		}
+	}
+
+	// --- Tag Normalization & Cap ---
+	ticketCreate.Tags = normalizeTagNames(ticketCreate.Tags)
+	maxTags := h.config.Tickets.MaxTagsPerTicket
+	if maxTags > 0 && len(ticketCreate.Tags) > maxTags {
+		logger.WarnContext(ctx, "Ticket rejected; too many tags", "count", len(ticketCreate.Tags), "max", maxTags)
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("A ticket can have at most %d tags.", maxTags))
+	}
+
+	// --- Auto-Tagging (adds rule tags the submitter did not already choose, within the cap) ---
+	autoTags := h.matchAutoTags(ticketCreate.Subject, ticketCreate.Description, ticketCreate.Tags)
+	if maxTags > 0 && len(ticketCreate.Tags)+len(autoTags) > maxTags {
+		autoTags = autoTags[:maxTags-len(ticketCreate.Tags)]
+	}
+	for _, m := range autoTags {
+		ticketCreate.Tags = append(ticketCreate.Tags, m.tag)
+	}
+	if len(autoTags) > 0 {
+		logger.InfoContext(ctx, "Tags added by auto-tag rules", "count", len(autoTags))
+	}
+
 	emailToSend := ticketCreate.EndUserEmail
 	nameToSend := "User" // Default name for email
 	if ticketCreate.SubmitterName != nil {
 		nameToSend = *ticketCreate.SubmitterName
 	}
 
-	logger.DebugContext(ctx, "Ticket creation request received (multipart)",
+	logger.DebugContext(ctx, "Ticket creation request received",
+		"json", isJSON,
 		"SubmitterName", nameToSend,
 		"EndUserEmail", emailToSend,
 		"subject", ticketCreate.Subject,
 		"tags", ticketCreate.Tags)
 
+	// Submitter token for the public status/reply link; only its hash is stored.
+	// Internal tickets have no public status page, so they get no token; a quarantined
+	// ticket gets one only when an admin approves it.
+	var submitterToken string
+	var submitterTokenHash *string
+	if !ticketCreate.IsInternal && !quarantined {
+		var tokenHash string
+		submitterToken, tokenHash, err = newSubmitterToken()
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to generate submitter token", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create ticket.")
+		}
+		submitterTokenHash = &tokenHash
+	}
+
 	// --- 3. Database Transaction ---
 	tx, err := h.db.Pool.Begin(ctx)
 	if err != nil {
@@ -130,14 +243,18 @@ func (h *Handler) CreateTicket(c echo.Context) (err error) { // Use named return
 	// No need to generate a UUID for the ticket ID; Postgres will handle it
 
 	// Remove id from INSERT and RETURNING clauses
+	// ticket_number is an identity column (GENERATED ALWAYS): the database
+	// assigns it and rejects explicit values, so concurrent creates never collide.
 	err = tx.QueryRow(ctx, `
         INSERT INTO tickets (
             submitter_name, end_user_email, issue_type, urgency, subject, description,
-            status, created_at, updated_at
-        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+            status, created_at, updated_at, locale, attachments_pending_since, submitter_token_hash, is_internal,
+            quarantined_at, quarantine_reason, due_date
+        ) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10, ''), CASE WHEN $11 THEN NOW() END, $12, $13,
+                  CASE WHEN $14 <> '' THEN NOW() END, NULLIF($14, ''), $15)
         RETURNING id, ticket_number, submitter_name, end_user_email, issue_type, urgency, subject, description,
                   status, assigned_to_user_id, created_at, updated_at, closed_at,
-                  resolution_notes
+                  resolution_notes, locale, is_internal, due_date
         `,
 		submitterNameToInsert,    // $1
 		emailToSend,              // $2
@@ -148,12 +265,19 @@ func (h *Handler) CreateTicket(c echo.Context) (err error) { // Use named return
 		models.StatusOpen,        // $7
 		time.Now(),               // $8
 		time.Now(),               // $9
+		ticketCreate.Locale,      // $10
+		len(files) > 0,           // $11: pending attachments until phase 2 finishes
+		submitterTokenHash,       // $12
+		ticketCreate.IsInternal,  // $13
+		quarantineReason,         // $14: spam filter match; the ticket is held for admin review
+		dueDate,                  // $15
 	).Scan(
 		&createdTicket.ID, &createdTicket.TicketNumber, &createdTicket.SubmitterName, // <<< Scan submitter_name
 		&createdTicket.EndUserEmail, &createdTicket.IssueType, &createdTicket.Urgency,
 		&createdTicket.Subject, &createdTicket.Description, &createdTicket.Status,
 		&createdTicket.AssignedToUserID, &createdTicket.CreatedAt, &createdTicket.UpdatedAt,
-		&createdTicket.ClosedAt, &createdTicket.ResolutionNotes,
+		&createdTicket.ClosedAt, &createdTicket.ResolutionNotes, &createdTicket.Locale,
+		&createdTicket.IsInternal, &createdTicket.DueDate,
 	)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to insert ticket into database", "error", err)
@@ -161,6 +285,19 @@ func (h *Handler) CreateTicket(c echo.Context) (err error) { // Use named return
 	}
 	logger.DebugContext(ctx, "Ticket record inserted", "ticketUUID", createdTicket.ID, "ticketNumber", createdTicket.TicketNumber)
 
+	if dueUrgencyNote != "" {
+		if err = h.addHistoryNote(ctx, tx, createdTicket.ID, "", dueUrgencyNote); err != nil {
+			logger.ErrorContext(ctx, "Failed to record urgency derivation", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create ticket record.")
+		}
+	}
+	if urgencyAdjustment != "" {
+		if err = h.addHistoryNote(ctx, tx, createdTicket.ID, "", urgencyAdjustment); err != nil {
+			logger.ErrorContext(ctx, "Failed to record urgency adjustment", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create ticket record.")
+		}
+	}
+
 	// --- 5. Process and Link Tags ---
 	// ... (Tag processing logic remains the same) ...
 	var tagIDs []string
@@ -173,129 +310,252 @@ func (h *Handler) CreateTicket(c echo.Context) (err error) { // Use named return
 		err = h.linkTagsToTicket(ctx, tx, createdTicket.ID, tagIDs)
 		if err != nil {
 			// Error logged in helper, trigger rollback
+			if db.IsForeignKeyViolation(err) { // A tag was deleted between lookup and linking
+				return echo.NewHTTPError(http.StatusConflict, "A tag was deleted while the ticket was being created. Please try again.")
+			}
 			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to link tags to ticket.")
 		}
 		logger.DebugContext(ctx, "Tags processed and linked", "tagIDs", tagIDs)
 	}
-
-	// --- 6. Process Attachments ---
-	// ... (Attachment processing logic remains the same) ...
-	attachmentsMetadata := make([]models.Attachment, 0)
-	files := form.File["attachments"] // "attachments" is the field name from the form
-	logger.DebugContext(ctx, "Processing attachments", "fileCount", len(files))
-
-	for _, fileHeader := range files {
-		logger.DebugContext(ctx, "Processing file", "filename", fileHeader.Filename, "size", fileHeader.Size)
-
-		// --- 6a. Validate File (using the one defined in attachments.go, assuming Handler has access) ---
-		if validationErr := h.validateAttachment(fileHeader); validationErr != nil {
-			logger.WarnContext(ctx, "Attachment validation failed", "filename", fileHeader.Filename, "error", validationErr)
-			err = fmt.Errorf("validation failed for file '%s': %w", fileHeader.Filename, validationErr)
-			// Return bad request instead of internal server error for validation issues
-			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
-		}
-
-		// --- 6b. Open File ---
-		file, openErr := fileHeader.Open()
-		if openErr != nil {
-			logger.ErrorContext(ctx, "Failed to open uploaded file", "filename", fileHeader.Filename, "error", openErr)
-			err = fmt.Errorf("failed to open file '%s': %w", fileHeader.Filename, openErr)
-			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process uploaded file.")
+	if len(autoTags) > 0 {
+		if err = h.addHistoryNote(ctx, tx, createdTicket.ID, "", autoTagComment(autoTags)); err != nil {
+			logger.ErrorContext(ctx, "Failed to record auto-applied tags", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to process tags.")
 		}
+	}
 
-		// Process file within a closure to ensure defer file.Close() runs per file
-		func(fh *multipart.FileHeader, f multipart.File) {
-			defer f.Close()
-
-			// --- 6c. Upload File to Storage ---
-			contentType := fh.Header.Get("Content-Type")
-			if contentType == "" {
-				contentType = "application/octet-stream"
-			}
-			// Ensure filename is sanitized
-			safeFilename := filepath.Base(fh.Filename)
-			storagePath := fmt.Sprintf("tickets/%s/%d_%s", createdTicket.ID, time.Now().UnixNano(), safeFilename)
-
-			storagePath, uploadErr := h.fileService.UploadFile(ctx, storagePath, f, fh.Size, contentType)
-			if uploadErr != nil {
-				logger.ErrorContext(ctx, "Failed to upload attachment via file service", "filename", safeFilename, "error", uploadErr)
-				err = fmt.Errorf("failed to upload file '%s': %w", safeFilename, uploadErr)
-				return // Exit closure, setting outer 'err'
-			}
-			logger.DebugContext(ctx, "File uploaded to storage", "filename", safeFilename, "storagePath", storagePath)
-
-			// --- 6d. Store Metadata in Database (within transaction) ---
-			var attachment models.Attachment
-			dbErr := tx.QueryRow(ctx, `
-                INSERT INTO attachments (ticket_id, filename, storage_path, mime_type, size, uploaded_at)
-                VALUES ($1, $2, $3, $4, $5, $6)
-                RETURNING id, ticket_id, filename, storage_path, mime_type, size, uploaded_at
-            `, createdTicket.ID, safeFilename, storagePath, contentType, fh.Size, time.Now()).Scan( // Use safeFilename
-				&attachment.ID, &attachment.TicketID, &attachment.Filename,
-				&attachment.StoragePath, &attachment.MimeType, &attachment.Size, &attachment.UploadedAt,
-			)
-			if dbErr != nil {
-				logger.ErrorContext(ctx, "Failed to store attachment metadata in database", "filename", safeFilename, "storagePath", storagePath, "error", dbErr)
-				logger.WarnContext(ctx, "Attempting to clean up orphaned file from storage due to DB error", "storagePath", storagePath)
-				if cleanupErr := h.fileService.DeleteFile(context.Background(), storagePath); cleanupErr != nil {
-					logger.ErrorContext(ctx, "Failed to clean up orphaned file", "storagePath", storagePath, "cleanupError", cleanupErr)
-				}
-				err = fmt.Errorf("failed to save metadata for file '%s': %w", safeFilename, dbErr)
-				return // Exit closure, setting outer 'err'
-			}
-			attachment.URL = fmt.Sprintf("/api/attachments/download/%s", attachment.ID) // Add download URL
-			attachmentsMetadata = append(attachmentsMetadata, attachment)
-			logger.DebugContext(ctx, "Attachment metadata stored", "attachmentID", attachment.ID)
-
-		}(fileHeader, file) // Pass fileHeader and file to the closure
-
-		// Check if an error occurred inside the closure
+	// --- 5b. Apply Domain Routing Rules (auto-assignment; deferred until a quarantined ticket is approved) ---
+	var routed *routedAssignment
+	if !quarantined {
+		routed, err = h.applyRoutingRules(ctx, tx, createdTicket.ID, emailToSend)
 		if err != nil {
-			// Rollback will be handled by the main defer
-			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+			logger.ErrorContext(ctx, "Failed to apply assignment routing rules", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to route ticket.")
 		}
-	} // End of attachment processing loop
+	}
+	if routed != nil {
+		createdTicket.AssignedToUserID = &routed.AssigneeUserID
+		logger.InfoContext(ctx, "Ticket auto-assigned by routing rule", "assigneeUserID", routed.AssigneeUserID, "ruleDomain", routed.Domain)
+	}
 
-	// --- 7. Commit Transaction ---
+	// --- 6. Commit Ticket (phase 1) ---
+	// The ticket and tags are committed before any attachment upload so a slow
+	// storage round-trip never holds locks or a pooled connection. A ticket with
+	// files stays marked "pending attachments" until phase 2 completes.
 	err = tx.Commit(ctx)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to commit transaction", "ticketUUID", createdTicket.ID, "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to save ticket.")
 	}
 
+	// --- 7. Process Attachments (phase 2) ---
+	attachmentsMetadata := make([]models.Attachment, 0, len(files))
+	message := "Ticket created successfully."
+	if len(files) > 0 {
+		logger.DebugContext(ctx, "Processing attachments", "fileCount", len(files))
+		var attachErr error
+		attachmentsMetadata, attachErr = h.attachFilesToNewTicket(ctx, createdTicket.ID, files, filenames)
+		if attachErr != nil {
+			// The ticket itself exists; report the partial failure rather than inviting a duplicate submission.
+			logger.ErrorContext(ctx, "Ticket created but attachments could not be saved", "ticketUUID", createdTicket.ID, "error", attachErr)
+			message = "Ticket created, but the attachments could not be saved. Please try uploading them again."
+			attachmentsMetadata = []models.Attachment{}
+		}
+	}
+
 	// --- 8. Post-Creation Actions (Email Notification) ---
 	logger.InfoContext(ctx, "Ticket created successfully with attachments",
 		"ticketUUID", createdTicket.ID,
 		"ticketNumber", createdTicket.TicketNumber,
 		"attachmentCount", len(attachmentsMetadata))
 
-	// Send confirmation email asynchronously
-	go func(recipientEmail, submitterName, ticketNumStr, ticketSubject string) { // <<< Added submitterName
-		bgCtx := context.Background()
-		emailLogger := slog.With("operation", "SendTicketConfirmation", "ticketNumber", ticketNumStr)
-		// Pass submitterName to the email service function
-		if emailErr := h.emailService.SendTicketConfirmation(recipientEmail, submitterName, ticketNumStr, ticketSubject); emailErr != nil { // <<< Pass nameToSend
-			emailLogger.ErrorContext(bgCtx, "Failed to send ticket confirmation email", "recipient", recipientEmail, "error", emailErr)
-		} else {
-			emailLogger.InfoContext(bgCtx, "Sent ticket confirmation email", "recipient", recipientEmail)
-		}
-	}(emailToSend, nameToSend, strconv.Itoa(int(createdTicket.TicketNumber)), createdTicket.Subject) // <<< Pass nameToSend
+	// Knowledge base suggestions are returned with the ticket and, up to
+	// TICKET_CONFIRMATION_SUGGESTIONS, listed in the confirmation email.
+	var suggested []models.SolutionMatch
+	if !createdTicket.IsInternal && !quarantined {
+		suggested = h.suggestSolutions(ctx, &createdTicket)
+	}
+
+	// Send confirmation email asynchronously (internal tickets never email the submitter;
+	// quarantined tickets are confirmed when an admin approves them)
+	if !createdTicket.IsInternal && !quarantined {
+		emailSuggestions, emailedIDs := h.confirmationSuggestions(suggested)
+		go func(recipientEmail, submitterName, ticketNumStr, ticketSubject, locale, statusLink string) { // <<< Added submitterName
+			bgCtx := context.Background()
+			emailLogger := slog.With("operation", "SendTicketConfirmation", "ticketNumber", ticketNumStr)
+			// Pass submitterName to the email service function
+			if emailErr := h.emailService.SendTicketConfirmation(recipientEmail, submitterName, ticketNumStr, ticketSubject, locale, statusLink, emailSuggestions); emailErr != nil { // <<< Pass nameToSend
+				emailLogger.ErrorContext(bgCtx, "Failed to send ticket confirmation email", "recipient", recipientEmail, "error", emailErr)
+				return
+			}
+			emailLogger.InfoContext(bgCtx, "Sent ticket confirmation email", "recipient", recipientEmail, "suggestionCount", len(emailSuggestions))
+			if err := solutions.RecordEmailed(bgCtx, h.db.Pool, createdTicket.ID, emailedIDs); err != nil {
+				emailLogger.ErrorContext(bgCtx, "Failed to record emailed solution suggestions", "error", err)
+			}
+		}(emailToSend, nameToSend, strconv.Itoa(int(createdTicket.TicketNumber)), createdTicket.Subject, h.submitterLocale(ctx, ticketCreate.Locale, emailToSend),
+			h.submitterStatusLink(createdTicket.TicketNumber, submitterToken)) // <<< Pass nameToSend
+	}
+
+	// Notify the routed assignee, mirroring manual assignment in UpdateTicket.
+	if routed != nil {
+		go func(recipient, tID, subj string) {
+			bgCtx := context.Background()
+			emailLogger := slog.With("operation", "SendTicketAssignment", "ticketID", tID)
+			if emailErr := h.emailService.SendTicketAssignment(recipient, tID, subj); emailErr != nil {
+				emailLogger.ErrorContext(bgCtx, "Failed to send assignment email", "recipient", recipient, "error", emailErr)
+			} else {
+				emailLogger.InfoContext(bgCtx, "Sent assignment email", "recipient", recipient)
+			}
+		}(routed.AssigneeEmail, createdTicket.ID, createdTicket.Subject)
+	}
 
 	// --- 9. Return Success Response ---
 	createdTicket.Attachments = attachmentsMetadata
+	webhookTicket := createdTicket // Copy before the submitter token is attached; receivers never see it
+	if !quarantined {
+		go h.webhooks.Dispatch(webhook.EventTicketCreated, webhookTicket)
+	}
+	createdTicket.SubmitterToken = submitterToken // Shown once so the submitter can track the ticket without the email
+	createdTicket.SuggestedSolutions = suggested
 	// Fetch Tag objects if needed for response (omitted for simplicity)
 	// createdTicket.Tags = ...
 
 	return c.JSON(http.StatusCreated, models.APIResponse{
 		Success: true,
-		Message: "Ticket created successfully.",
+		Message: message,
 		Data:    createdTicket,
 	})
 }
 
-// --- Helper Functions (findOrCreateTags, linkTagsToTicket) ---
+// --- Helper Functions (attachFilesToNewTicket, parseMultipartTicket, findOrCreateTags, linkTagsToTicket) ---
+
+// attachFilesToNewTicket is phase 2 of ticket creation: it uploads the files
+// concurrently, then inserts their metadata and clears the ticket's
+// attachments_pending_since marker in one short transaction. On failure the
+// stored files are removed, the marker is cleared and a system comment notes
+// that the attachments were not saved.
+//
+// Returns:
+//   - []models.Attachment: The saved attachment metadata.
+//   - error: If uploading or recording the attachments failed.
+func (h *Handler) attachFilesToNewTicket(ctx context.Context, ticketID string, files []*multipart.FileHeader, filenames []string) (attachments []models.Attachment, err error) {
+	logger := slog.With("helper", "attachFilesToNewTicket", "ticketUUID", ticketID)
+	defer func() {
+		if err == nil {
+			return
+		}
+		// Use a fresh context: the request may have been cancelled mid-upload.
+		bgCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if _, clearErr := h.db.Pool.Exec(bgCtx, `UPDATE tickets SET attachments_pending_since = NULL WHERE id = $1`, ticketID); clearErr != nil {
+			logger.ErrorContext(bgCtx, "Failed to clear pending-attachments marker", "error", clearErr)
+		}
+		if _, noteErr := h.db.Pool.Exec(bgCtx, `
+            INSERT INTO ticket_history (ticket_id, summary) VALUES ($1, $2)`,
+			ticketID, fmt.Sprintf("%d attachment(s) submitted with this ticket could not be saved.", len(files))); noteErr != nil {
+			logger.ErrorContext(bgCtx, "Failed to record attachment failure in history", "error", noteErr)
+		}
+	}()
+
+	uploads, err := h.uploadFilesConcurrently(ctx, ticketID, files, filenames)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		h.deleteStoredUploads(uploads)
+		return nil, fmt.Errorf("failed to begin attachment transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				logger.ErrorContext(ctx, "Failed to rollback attachment transaction", "rollbackError", rbErr)
+			}
+			h.deleteStoredUploads(uploads)
+		}
+	}()
+
+	attachments = make([]models.Attachment, 0, len(uploads))
+	for _, upload := range uploads {
+		var attachment models.Attachment
+		err = tx.QueryRow(ctx, `
+            INSERT INTO attachments (ticket_id, filename, storage_path, mime_type, size, uploaded_at)
+            VALUES ($1, $2, $3, $4, $5, $6)
+            RETURNING id, ticket_id, filename, storage_path, mime_type, size, uploaded_at
+        `, ticketID, upload.Filename, upload.StoragePath, upload.ContentType, upload.Header.Size, time.Now()).Scan(
+			&attachment.ID, &attachment.TicketID, &attachment.Filename,
+			&attachment.StoragePath, &attachment.MimeType, &attachment.Size, &attachment.UploadedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to save metadata for file '%s': %w", upload.Filename, err)
+		}
+		attachment.URL = fmt.Sprintf("/api/attachments/download/%s", attachment.ID) // Add download URL
+		attachments = append(attachments, attachment)
+		logger.DebugContext(ctx, "Attachment metadata stored", "attachmentID", attachment.ID)
+	}
+	if _, err = tx.Exec(ctx, `UPDATE tickets SET attachments_pending_since = NULL WHERE id = $1`, ticketID); err != nil {
+		return nil, fmt.Errorf("failed to clear pending-attachments marker: %w", err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("failed to commit attachments: %w", err)
+	}
+	h.schedulePreviews(attachments) // Best-effort, in the background
+	return attachments, nil
+}
+
+// parseMultipartTicket extracts ticket fields and uploaded files from a
+// multipart/form-data ticket submission.
+//
+// Returns:
+//   - models.TicketCreate: The ticket fields from the form.
+//   - []*multipart.FileHeader: Files sent in the "attachments" field.
+//   - error: If the form cannot be parsed.
+func parseMultipartTicket(c echo.Context) (models.TicketCreate, []*multipart.FileHeader, error) {
+	const maxMemory = 32 << 20 // 32MB
+	if err := c.Request().ParseMultipartForm(maxMemory); err != nil {
+		return models.TicketCreate{}, nil, err
+	}
+	form := c.Request().MultipartForm
+
+	getFormValue := func(key string, defaultValue string) string {
+		if values, ok := form.Value[key]; ok && len(values) > 0 {
+			return values[0]
+		}
+		return defaultValue
+	}
+	getFormValueSlice := func(key string) []string {
+		if values, ok := form.Value[key]; ok {
+			return values
+		}
+		return []string{}
+	}
+
+	// Extract submitterName
+	submitterName := getFormValue("submitterName", "")
+	var submitterNamePtr *string
+	if strings.TrimSpace(submitterName) != "" {
+		submitterNamePtr = &submitterName
+	}
+
+	ticketCreate := models.TicketCreate{
+		SubmitterName: submitterNamePtr,
+		EndUserEmail:  getFormValue("endUserEmail", ""),
+		IssueType:     getFormValue("issueType", ""),
+		Urgency:       models.TicketUrgency(getFormValue("urgency", "")),
+		Subject:       getFormValue("subject", ""),
+		Description:   getFormValue("description", ""),
+		Tags:          getFormValueSlice("tags"),
+		Locale:        i18n.Normalize(getFormValue("locale", "")),
+		CaptchaToken:  getFormValue("captchaToken", ""),
+		DueDate:       getFormValue("dueDate", ""),
+	}
+	ticketCreate.IsInternal, _ = strconv.ParseBool(getFormValue("isInternal", "false"))
+	return ticketCreate, form.File["attachments"], nil // "attachments" is the field name from the form
+}
 // ... (These helper functions remain the same) ...
 // findOrCreateTags finds existing tags or creates new ones within a transaction.
+// Names are matched case-insensitively; an existing tag keeps its display form.
 func (h *Handler) findOrCreateTags(ctx context.Context, tx pgx.Tx, tagNames []string) ([]string, error) {
 	logger := slog.With("helper", "findOrCreateTags")
 	tagIDs := make([]string, 0, len(tagNames))
@@ -303,16 +563,18 @@ func (h *Handler) findOrCreateTags(ctx context.Context, tx pgx.Tx, tagNames []st
 	for _, name := range tagNames {
 		var tagID string
 		// Use QueryRow for potentially non-existent tags
-		err := tx.QueryRow(ctx, `SELECT id FROM tags WHERE name = $1`, name).Scan(&tagID)
+		err := tx.QueryRow(ctx, `SELECT id FROM tags WHERE LOWER(name) = LOWER($1)`, name).Scan(&tagID)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				// Tag doesn't exist, create it
 				logger.DebugContext(ctx, "Tag not found, creating...", "tagName", name)
-				err = tx.QueryRow(ctx, `INSERT INTO tags (name, created_at) VALUES ($1, $2) RETURNING id`, name, time.Now()).Scan(&tagID)
+				// A concurrent request may create the same tag; the no-op update returns its ID.
+				err = tx.QueryRow(ctx, `
+                    INSERT INTO tags (name, created_at) VALUES ($1, $2)
+                    ON CONFLICT ((LOWER(name))) DO UPDATE SET name = tags.name
+                    RETURNING id`, name, time.Now()).Scan(&tagID)
 				if err != nil {
 					logger.ErrorContext(ctx, "Failed to create new tag", "tagName", name, "error", err)
-					// Consider potential race conditions if multiple requests try to create the same tag
-					// A unique constraint on tag name in the DB is essential.
 					return nil, fmt.Errorf("failed to create tag '%s': %w", name, err)
 				}
 				logger.DebugContext(ctx, "Created new tag", "tagName", name, "tagID", tagID)
@@ -338,29 +600,50 @@ func (h *Handler) linkTagsToTicket(ctx context.Context, tx pgx.Tx, ticketID stri
 		return nil
 	}
 
-	// Use pgx.CopyFrom for potentially better performance with many tags
-	// Prepare data for CopyFrom: [][]interface{}{ {ticketID, tagID1}, {ticketID, tagID2}, ... }
-	rowsToCopy := make([][]interface{}, len(tagIDs))
-	for i, tagID := range tagIDs {
-		rowsToCopy[i] = []interface{}{ticketID, tagID}
-	}
-
-	// Perform the bulk insert, ignoring conflicts
-	copyCount, err := tx.CopyFrom(
-		ctx,
-		pgx.Identifier{"ticket_tags"},
-		[]string{"ticket_id", "tag_id"},
-		pgx.CopyFromRows(rowsToCopy),
-	)
-
+	// A single INSERT ... SELECT over the ID array; links the ticket already
+	// has (or duplicate IDs in the list) are skipped instead of failing the
+	// whole operation, which CopyFrom cannot do.
+	result, err := tx.Exec(ctx, `
+        INSERT INTO ticket_tags (ticket_id, tag_id)
+        SELECT $1::uuid, tag_id FROM unnest($2::uuid[]) AS tag_id
+        ON CONFLICT (ticket_id, tag_id) DO NOTHING`, ticketID, tagIDs)
 	if err != nil {
-		logger.ErrorContext(ctx, "Failed to bulk link tags to ticket", "error", err)
-		// Check for specific constraint errors if necessary
+		logger.ErrorContext(ctx, "Failed to link tags to ticket", "error", err)
 		return fmt.Errorf("failed to link tags: %w", err)
 	}
 
-	logger.DebugContext(ctx, "Linked tags to ticket", "copyCount", copyCount, "expectedCount", len(tagIDs))
-	// Note: copyCount might be less than len(tagIDs) if some links already existed (due to ON CONFLICT DO NOTHING implicitly handled by CopyFrom with unique constraints)
+	logger.DebugContext(ctx, "Linked tags to ticket", "linkedCount", result.RowsAffected(), "requestedCount", len(tagIDs))
+	return nil
+}
 
+// checkOpenTicketCap rejects a public submission when the submitter already
+// has the configured maximum of non-closed tickets. Tickets created by
+// authenticated staff are never capped, and internal tickets are not counted.
+//
+// Returns:
+//   - error: 429 with guidance when the cap is reached, 500 if the count fails, nil otherwise.
+func (h *Handler) checkOpenTicketCap(c echo.Context, endUserEmail string) error {
+	limit := h.config.Tickets.MaxOpenPerSubmitter
+	if limit <= 0 {
+		return nil
+	}
+	if role := auth.OptionalUserRole(c); role == models.RoleStaff || role == models.RoleAdmin {
+		return nil
+	}
+
+	ctx := c.Request().Context()
+	var openCount int
+	if err := h.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM tickets
+        WHERE LOWER(end_user_email) = LOWER($1) AND status <> $2 AND NOT is_internal`,
+		strings.TrimSpace(endUserEmail), models.StatusClosed).Scan(&openCount); err != nil {
+		slog.ErrorContext(ctx, "Failed to count open tickets for submitter", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create ticket.")
+	}
+	if openCount >= limit {
+		slog.WarnContext(ctx, "Ticket rejected: submitter open ticket cap reached", "openCount", openCount, "limit", limit)
+		return apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited,
+			fmt.Sprintf("You already have %d open tickets. Please add to one of your existing tickets (using the link in its confirmation email) instead of opening a new one.", openCount))
+	}
 	return nil
 }
@@ -0,0 +1,134 @@
+// backend/internal/api/handlers/ticket/due_date.go
+// ==========================================================================
+// Ticket due dates: parsing client values and the due-bucket list filter.
+// A plain date (YYYY-MM-DD) means the end of that day in the requester's
+// timezone; RFC 3339 timestamps are used as given. Buckets split the future
+// at the end of today and seven days later; closed tickets are never overdue.
+// ==========================================================================
+
+package ticket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/timezone"
+)
+
+// parseDueDate parses a due date sent by a client.
+//
+// Parameters:
+//   - value: YYYY-MM-DD, an RFC 3339 timestamp, or "" for no due date.
+//   - loc: The requester's location, used for plain dates.
+//
+// Returns:
+//   - *time.Time: The due instant in UTC, or nil for "".
+//   - error: If the value matches neither format.
+func parseDueDate(value string, loc *time.Location) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.UTC()
+		return &t, nil
+	}
+	// ParseBoundary returns the start of the following day for upper bounds.
+	nextDay, err := timezone.ParseBoundary(value, loc, true)
+	if err != nil {
+		return nil, err
+	}
+	due := nextDay.Add(-time.Second)
+	return &due, nil
+}
+
+// dueDateDisplay formats a due date for change descriptions.
+func dueDateDisplay(due *time.Time) string {
+	if due == nil {
+		return ""
+	}
+	return due.UTC().Format("2006-01-02 15:04 UTC")
+}
+
+// dueDateValue renders a due date as the RFC 3339 value stored in change sets.
+func dueDateValue(due *time.Time) *string {
+	if due == nil {
+		return nil
+	}
+	value := due.UTC().Format(time.RFC3339)
+	return &value
+}
+
+// dueDateChange reports whether an update changes the due date, and to what.
+// update.DueDate has already been normalized to RFC 3339 (or "" to clear it)
+// by UpdateTicket.
+func dueDateChange(currentState *models.TicketState, update *models.TicketStatusUpdate) (*time.Time, bool) {
+	if update.DueDate == nil {
+		return nil, false
+	}
+	var newDueDate *time.Time
+	if *update.DueDate != "" {
+		due, err := time.Parse(time.RFC3339, *update.DueDate)
+		if err != nil {
+			return nil, false
+		}
+		newDueDate = &due
+	}
+	switch {
+	case newDueDate == nil && currentState.DueDate == nil:
+		return nil, false
+	case newDueDate != nil && currentState.DueDate != nil && newDueDate.Equal(*currentState.DueDate):
+		return nil, false
+	}
+	return newDueDate, true
+}
+
+// buildDueFilter builds the WHERE condition for the due list filter.
+//
+// Parameters:
+//   - param: Comma-separated buckets (overdue, today, week, later, none); a
+//     ticket matching any of them is included.
+//   - now: The current time.
+//   - loc: The requester's location, which decides where "today" ends.
+//   - argIdx: The next placeholder index.
+//
+// Returns:
+//   - string: The condition (in parentheses), or "" if param is empty.
+//   - []interface{}: The arguments for the condition's placeholders.
+//   - error: If a bucket name is unknown.
+func buildDueFilter(param string, now time.Time, loc *time.Location, argIdx int) (string, []interface{}, error) {
+	startOfTomorrow := timezone.StartOfDay(now, loc).AddDate(0, 0, 1)
+	endOfWeek := startOfTomorrow.AddDate(0, 0, 6)
+
+	var conditions []string
+	var args []interface{}
+	placeholder := func(value time.Time) string {
+		args = append(args, value.UTC())
+		argIdx++
+		return fmt.Sprintf("$%d", argIdx-1)
+	}
+	for _, bucket := range strings.Split(param, ",") {
+		switch strings.ToLower(strings.TrimSpace(bucket)) {
+		case "":
+			continue
+		case timezone.BucketOverdue:
+			conditions = append(conditions, fmt.Sprintf("(t.due_date < %s AND t.status <> '%s')", placeholder(now), models.StatusClosed))
+		case timezone.BucketToday:
+			conditions = append(conditions, fmt.Sprintf("(t.due_date >= %s AND t.due_date < %s)", placeholder(now), placeholder(startOfTomorrow)))
+		case timezone.BucketWeek:
+			conditions = append(conditions, fmt.Sprintf("(t.due_date >= %s AND t.due_date < %s)", placeholder(startOfTomorrow), placeholder(endOfWeek)))
+		case timezone.BucketLater:
+			conditions = append(conditions, fmt.Sprintf("t.due_date >= %s", placeholder(endOfWeek)))
+		case timezone.BucketNone:
+			conditions = append(conditions, "t.due_date IS NULL")
+		default:
+			return "", nil, fmt.Errorf("unknown due bucket %q: expected overdue, today, week, later or none", bucket)
+		}
+	}
+	if len(conditions) == 0 {
+		return "", nil, nil
+	}
+	return "(" + strings.Join(conditions, " OR ") + ")", args, nil
+}
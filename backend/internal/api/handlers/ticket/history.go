@@ -0,0 +1,115 @@
+// backend/internal/api/handlers/ticket/history.go
+// ==========================================================================
+// Ticket history: the structured timeline of field changes and system events
+// (status and assignee changes, acceptance, auto-tagging, ...). History lives
+// in ticket_history, separate from ticket_updates, so the comment thread holds
+// only human messages.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// GetTicketHistory returns a ticket's history entries, oldest first.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Returns:
+//   - JSON APIResponse containing models.TicketHistoryEntry objects or an error response.
+func (h *Handler) GetTicketHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "GetTicketHistory", "ticketUUID", ticketID)
+
+	// --- 1. Authorization Check ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	if _, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin); err != nil {
+		if err.Error() == "ticket not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		if err.Error() == "not authorized to access this ticket" {
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to view this ticket.")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+
+	// --- 2. Query History ---
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT th.id, th.ticket_id, th.actor_user_id, u.name, th.summary, th.changes, th.created_at
+        FROM ticket_history th
+        LEFT JOIN users u ON u.id = th.actor_user_id
+        WHERE th.ticket_id = $1
+        ORDER BY th.created_at ASC, th.id`, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query ticket history", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve ticket history.")
+	}
+	defer rows.Close()
+
+	history := make([]models.TicketHistoryEntry, 0)
+	for rows.Next() {
+		var entry models.TicketHistoryEntry
+		if err := rows.Scan(
+			&entry.ID, &entry.TicketID, &entry.ActorUserID, &entry.ActorName,
+			&entry.Summary, &entry.Changes, &entry.CreatedAt,
+		); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan ticket history row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process ticket history.")
+		}
+		history = append(history, entry)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating ticket history", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process ticket history.")
+	}
+
+	// --- 3. Return Response ---
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: history})
+}
+
+// --- Helper Functions ---
+
+// addHistoryNote records a system event without structured field changes.
+func (h *Handler) addHistoryNote(ctx context.Context, tx pgx.Tx, ticketID, actorUserID, summary string) error {
+	return h.addHistoryEntry(ctx, tx, ticketID, actorUserID, summary, nil)
+}
+
+// addHistoryEntry records a history entry together with the structured field
+// changes it describes (build both with diffTicketUpdate/describeChanges).
+// An empty actorUserID records a system or submitter event.
+func (h *Handler) addHistoryEntry(ctx context.Context, tx pgx.Tx, ticketID, actorUserID, summary string, changes []models.FieldChange) error {
+	var actorArg interface{}
+	if actorUserID != "" {
+		actorArg = actorUserID
+	}
+	var changesArg interface{}
+	if len(changes) > 0 {
+		changesArg = changes
+	}
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO ticket_history (ticket_id, actor_user_id, summary, changes)
+        VALUES ($1, $2, $3, $4)`, ticketID, actorArg, summary, changesArg); err != nil {
+		return fmt.Errorf("failed to record ticket history: %w", err)
+	}
+	return nil
+}
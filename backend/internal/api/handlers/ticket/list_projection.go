@@ -0,0 +1,174 @@
+// backend/internal/api/handlers/ticket/list_projection.go
+// ==========================================================================
+// Column projection for the ticket list. A client can pass
+// ?fields=id,ticket_number,subject,status,urgency to receive only those keys
+// per ticket; the assignee join and tag aggregation are only added to the
+// query when a requested field (or the sort order) needs them. Without the
+// parameter GetAllTickets returns the full models.Ticket shape.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// listField describes one selectable ticket list field.
+type listField struct {
+	expr          string     // SQL expression selecting the value
+	needsAssignee bool       // Requires the LEFT JOIN on the assignee
+	raw           bool       // Value is JSON built by the query
+	dest          func() any // Returns a fresh scan destination
+}
+
+// ticketListFields maps the accepted field names (the models.Ticket JSON keys)
+// to their projections.
+var ticketListFields = map[string]listField{
+	"id":                  {expr: "t.id", dest: func() any { return new(string) }},
+	"ticket_number":       {expr: "t.ticket_number", dest: func() any { return new(int32) }},
+	"subject":             {expr: "t.subject", dest: func() any { return new(string) }},
+	"description":         {expr: "t.description", dest: func() any { return new(string) }},
+	"status":              {expr: "t.status", dest: func() any { return new(string) }},
+	"urgency":             {expr: "t.urgency", dest: func() any { return new(string) }},
+	"created_at":          {expr: "t.created_at", dest: func() any { return new(time.Time) }},
+	"updated_at":          {expr: "t.updated_at", dest: func() any { return new(time.Time) }},
+	"closed_at":           {expr: "t.closed_at", dest: func() any { return new(*time.Time) }},
+	"due_date":            {expr: "t.due_date", dest: func() any { return new(*time.Time) }},
+	"submitter_name":      {expr: "t.submitter_name", dest: func() any { return new(*string) }},
+	"end_user_email":      {expr: "t.end_user_email", dest: func() any { return new(string) }},
+	"assigned_to_user_id": {expr: "t.assigned_to_user_id", dest: func() any { return new(*string) }},
+	"is_internal":         {expr: "t.is_internal", dest: func() any { return new(bool) }},
+	"assigned_to_team_id": {expr: "t.assigned_to_team_id", dest: func() any { return new(*string) }},
+	"assigned_to_team": {
+		expr: "(SELECT json_build_object('id', tm.id, 'name', tm.name) FROM teams tm WHERE tm.id = t.assigned_to_team_id)",
+		raw:  true,
+		dest: func() any { return new([]byte) },
+	},
+	"assigned_to_user": {
+		expr:          "CASE WHEN a.id IS NULL THEN NULL ELSE json_build_object('id', a.id, 'name', a.name) END",
+		needsAssignee: true,
+		raw:           true,
+		dest:          func() any { return new([]byte) },
+	},
+	"tags": {
+		expr: `COALESCE(
+				(SELECT json_agg(json_build_object('id', tg.id, 'name', tg.name, 'created_at', tg.created_at))
+				 FROM ticket_tags tt JOIN tags tg ON tt.tag_id = tg.id
+				 WHERE tt.ticket_id = t.id),
+				'[]'::json
+			)`,
+		raw:  true,
+		dest: func() any { return new([]byte) },
+	},
+}
+
+// listFieldAliases are shorter names accepted for convenience.
+var listFieldAliases = map[string]string{
+	"number":   "ticket_number",
+	"assignee": "assigned_to_user",
+	"team":     "assigned_to_team",
+}
+
+// parseListFields parses the fields query parameter. The ticket ID is always
+// included so clients can link rows to the detail view.
+//
+// Parameters:
+//   - param: Comma-separated field names; "" requests the full shape.
+//
+// Returns:
+//   - []string: The canonical field names in request order, or nil for the full shape.
+//   - error: If a field name is unknown.
+func parseListFields(param string) ([]string, error) {
+	if strings.TrimSpace(param) == "" {
+		return nil, nil
+	}
+	fields := []string{"id"}
+	seen := map[string]bool{"id": true}
+	for _, name := range strings.Split(param, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if canonical, ok := listFieldAliases[name]; ok {
+			name = canonical
+		}
+		if _, ok := ticketListFields[name]; !ok {
+			return nil, fmt.Errorf("unknown field '%s'", name)
+		}
+		if !seen[name] {
+			seen[name] = true
+			fields = append(fields, name)
+		}
+	}
+	return fields, nil
+}
+
+// queryProjectedTickets runs the list query for a field projection, sharing
+// the filters, ordering and pagination built by GetAllTickets.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - fields: The canonical field names from parseListFields.
+//   - filterJoins: Joins needed only for filtering (tags).
+//   - whereClause: The WHERE clause, with a leading space (or "").
+//   - orderByClause: The ORDER BY clause from buildTicketOrderBy.
+//   - args: The filter arguments followed by limit and offset.
+//   - limitIdx: The placeholder index of the limit argument.
+//
+// Returns:
+//   - []map[string]any: One map per ticket, keyed by field name.
+//   - error: If the query or a scan fails.
+func (h *Handler) queryProjectedTickets(ctx context.Context, fields []string, filterJoins, whereClause, orderByClause string, args []any, limitIdx int) ([]map[string]any, error) {
+	exprs := make([]string, len(fields))
+	needsAssignee := strings.Contains(orderByClause, ticketSortColumns["assignedTo"])
+	for i, name := range fields {
+		field := ticketListFields[name]
+		exprs[i] = field.expr
+		needsAssignee = needsAssignee || field.needsAssignee
+	}
+
+	from := " FROM tickets t "
+	groupBy := " GROUP BY t.id "
+	if needsAssignee {
+		from += " LEFT JOIN users a ON t.assigned_to_user_id = a.id "
+		groupBy += ", a.id "
+	}
+	query := "SELECT " + strings.Join(exprs, ", ") + from + filterJoins + whereClause +
+		groupBy + orderByClause + fmt.Sprintf(" LIMIT $%d OFFSET $%d", limitIdx, limitIdx+1)
+
+	rows, err := h.db.Pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := make([]map[string]any, 0)
+	for rows.Next() {
+		dests := make([]any, len(fields))
+		for i, name := range fields {
+			dests[i] = ticketListFields[name].dest()
+		}
+		if err := rows.Scan(dests...); err != nil {
+			return nil, fmt.Errorf("failed to scan ticket row: %w", err)
+		}
+		ticket := make(map[string]any, len(fields))
+		for i, name := range fields {
+			if ticketListFields[name].raw {
+				raw := *dests[i].(*[]byte)
+				if raw == nil {
+					ticket[name] = nil
+				} else {
+					ticket[name] = json.RawMessage(raw)
+				}
+				continue
+			}
+			ticket[name] = dests[i]
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, rows.Err()
+}
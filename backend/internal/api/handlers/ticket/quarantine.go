@@ -0,0 +1,324 @@
+// backend/internal/api/handlers/ticket/quarantine.go
+// ==========================================================================
+// Admin review of tickets quarantined by the spam filter. Quarantined tickets
+// are hidden from the normal ticket lists, counts and search; an Admin either
+// approves one (releasing it into the queues as if it had just been created),
+// marks it as spam (see spam.go) or discards it (deleting the ticket and its
+// stored attachments).
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// GetQuarantinedTickets lists tickets held by the spam filter, newest first.
+//
+// Query Parameters:
+//   - page / limit: Pagination (default limit 50, max 200).
+//
+// Returns:
+//   - JSON PaginatedResponse containing Ticket objects (with quarantine_reason) or an error response.
+func (h *Handler) GetQuarantinedTickets(c echo.Context) error {
+	return h.listHeldTickets(c, slog.With("handler", "GetQuarantinedTickets"),
+		"t.quarantined_at IS NOT NULL AND t.spam_at IS NULL", "t.quarantined_at DESC, t.id")
+}
+
+// listHeldTickets serves a paginated admin list of tickets kept out of the
+// queues (quarantined or spam).
+//
+// Parameters:
+//   - c: The echo context (page / limit query parameters).
+//   - logger: The calling handler's logger.
+//   - where: The SQL condition selecting the tickets (on alias t).
+//   - orderBy: The SQL ORDER BY list.
+//
+// Returns:
+//   - JSON PaginatedResponse containing Ticket objects or an error response.
+func (h *Handler) listHeldTickets(c echo.Context, logger *slog.Logger, where, orderBy string) error {
+	ctx := c.Request().Context()
+
+	limit := 50
+	if parsed, err := strconv.Atoi(c.QueryParam("limit")); err == nil && parsed > 0 && parsed <= 200 {
+		limit = parsed
+	}
+	page := 1
+	if parsed, err := strconv.Atoi(c.QueryParam("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+
+	var total int
+	if err := h.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM tickets t WHERE `+where).Scan(&total); err != nil {
+		logger.ErrorContext(ctx, "Failed to count tickets", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve tickets.")
+	}
+
+	rows, err := h.db.Pool.Query(ctx, ticketDetailSelect+ticketDetailFrom+`
+        WHERE `+where+`
+        ORDER BY `+orderBy+`
+        LIMIT $1 OFFSET $2`, limit, (page-1)*limit)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query tickets", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve tickets.")
+	}
+	defer rows.Close()
+
+	tickets := make([]models.Ticket, 0, limit)
+	for rows.Next() {
+		ticket, scanErr := scanTicketWithUsersAndSubmitter(rows)
+		if scanErr != nil {
+			logger.ErrorContext(ctx, "Failed to scan ticket", "error", scanErr)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve tickets.")
+		}
+		tickets = append(tickets, ticket)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating tickets", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve tickets.")
+	}
+
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	return c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       tickets,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
+	})
+}
+
+// ApproveQuarantinedTicket releases a quarantined ticket into the normal
+// queues. Routing rules run as they would have at creation, the submitter
+// receives the confirmation email (with a fresh status link, since the
+// original token was returned only once) and the ticket.created webhook fires.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//
+// Returns:
+//   - JSON APIResponse with the released ticket, 404 if the ticket is not quarantined.
+func (h *Handler) ApproveQuarantinedTicket(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "ApproveQuarantinedTicket", "ticketUUID", ticketID)
+	adminID := auth.OptionalUserID(c)
+
+	submitterToken, tokenHash, err := newSubmitterToken()
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to generate submitter token", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to approve ticket.")
+	}
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to approve ticket.")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
+			}
+		}
+	}()
+
+	var released struct {
+		number           int32
+		subject, email   string
+		submitterName    *string
+		locale           string
+		isInternal       bool
+		quarantineReason *string
+	}
+	err = tx.QueryRow(ctx, `
+        UPDATE tickets t SET quarantined_at = NULL, quarantine_reason = NULL, updated_at = NOW(),
+                             submitter_token_hash = CASE WHEN t.is_internal THEN NULL ELSE $2 END
+        FROM (SELECT id, quarantine_reason FROM tickets WHERE id = $1 AND quarantined_at IS NOT NULL AND spam_at IS NULL FOR UPDATE) old
+        WHERE t.id = old.id
+        RETURNING t.ticket_number, t.subject, t.end_user_email, t.submitter_name, COALESCE(t.locale, ''), t.is_internal, old.quarantine_reason`,
+		ticketID, tokenHash,
+	).Scan(&released.number, &released.subject, &released.email, &released.submitterName, &released.locale, &released.isInternal, &released.quarantineReason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Quarantined ticket not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to release quarantined ticket", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to approve ticket.")
+	}
+
+	if err = h.addHistoryNote(ctx, tx, ticketID, adminID, "Released from spam quarantine."); err != nil {
+		logger.ErrorContext(ctx, "Failed to record quarantine release", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to approve ticket.")
+	}
+	routed, err := h.applyRoutingRules(ctx, tx, ticketID, released.email)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to apply assignment routing rules", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to approve ticket.")
+	}
+	if err = h.auditService.RecordTx(ctx, tx, audit.Event{
+		Action:       audit.ActionTicketApprove,
+		ActorUserID:  adminID,
+		ResourceType: "ticket",
+		ResourceID:   ticketID,
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"ticket_number": released.number, "pattern": released.quarantineReason},
+	}); err != nil {
+		logger.ErrorContext(ctx, "Failed to audit quarantine approval", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to approve ticket.")
+	}
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit quarantine approval", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to approve ticket.")
+	}
+	logger.InfoContext(ctx, "Quarantined ticket approved", "ticketNumber", released.number, "pattern", released.quarantineReason)
+
+	// --- Post-Approval Actions (mirroring CreateTicket) ---
+	if !released.isInternal {
+		name := "User"
+		if released.submitterName != nil {
+			name = *released.submitterName
+		}
+		go func(recipient, submitterName, ticketNumStr, subject, locale, statusLink string) {
+			if emailErr := h.emailService.SendTicketConfirmation(recipient, submitterName, ticketNumStr, subject, locale, statusLink, nil); emailErr != nil {
+				slog.Error("Failed to send ticket confirmation email after quarantine approval", "ticketNumber", ticketNumStr, "error", emailErr)
+			}
+		}(released.email, name, strconv.Itoa(int(released.number)), released.subject,
+			h.submitterLocale(ctx, released.locale, released.email), h.submitterStatusLink(released.number, submitterToken))
+	}
+	if routed != nil {
+		go func(recipient, subject string) {
+			if emailErr := h.emailService.SendTicketAssignment(recipient, ticketID, subject); emailErr != nil {
+				slog.Error("Failed to send assignment email after quarantine approval", "ticketUUID", ticketID, "error", emailErr)
+			}
+		}(routed.AssigneeEmail, released.subject)
+	}
+
+	ticket, fetchErr := h.getTicketDetailsByID(ctx, ticketID)
+	if fetchErr != nil {
+		logger.ErrorContext(ctx, "Failed to fetch approved ticket", "error", fetchErr)
+		return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: "Ticket approved."})
+	}
+	go h.webhooks.Dispatch(webhook.EventTicketCreated, *ticket)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Ticket approved.",
+		Data:    ticket,
+	})
+}
+
+// DiscardQuarantinedTicket permanently deletes a quarantined ticket and its
+// stored attachments. The submitter is not notified.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//
+// Returns:
+//   - JSON APIResponse on success, 404 if the ticket is not quarantined.
+func (h *Handler) DiscardQuarantinedTicket(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "DiscardQuarantinedTicket", "ticketUUID", ticketID)
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discard ticket.")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
+			}
+		}
+	}()
+
+	// Storage paths are collected before the cascade removes the attachment rows.
+	var storagePaths []string
+	var previewPaths []*string
+	rows, err := tx.Query(ctx, `
+        SELECT a.storage_path, a.preview_storage_path
+        FROM attachments a JOIN tickets t ON t.id = a.ticket_id
+        WHERE t.id = $1 AND t.quarantined_at IS NOT NULL AND t.spam_at IS NULL`, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query quarantined ticket attachments", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discard ticket.")
+	}
+	for rows.Next() {
+		var storagePath string
+		var previewPath *string
+		if err = rows.Scan(&storagePath, &previewPath); err != nil {
+			rows.Close()
+			logger.ErrorContext(ctx, "Failed to scan attachment path", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discard ticket.")
+		}
+		storagePaths = append(storagePaths, storagePath)
+		previewPaths = append(previewPaths, previewPath)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating attachment paths", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discard ticket.")
+	}
+
+	var ticketNumber int32
+	var quarantineReason *string
+	err = tx.QueryRow(ctx, `
+        DELETE FROM tickets WHERE id = $1 AND quarantined_at IS NOT NULL AND spam_at IS NULL
+        RETURNING ticket_number, quarantine_reason`, ticketID).Scan(&ticketNumber, &quarantineReason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Quarantined ticket not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to delete quarantined ticket", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discard ticket.")
+	}
+	if err = h.auditService.RecordTx(ctx, tx, audit.Event{
+		Action:       audit.ActionTicketDiscard,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "ticket",
+		ResourceID:   ticketID,
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"ticket_number": ticketNumber, "pattern": quarantineReason},
+	}); err != nil {
+		logger.ErrorContext(ctx, "Failed to audit quarantine discard", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discard ticket.")
+	}
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit quarantine discard", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to discard ticket.")
+	}
+
+	// Stored files are removed after the commit; a failure only leaves orphaned objects.
+	bgCtx := context.Background()
+	for i, storagePath := range storagePaths {
+		if delErr := h.fileService.DeleteFile(bgCtx, storagePath); delErr != nil {
+			logger.ErrorContext(ctx, "Failed to delete discarded ticket attachment", "storagePath", storagePath, "error", delErr)
+		}
+		h.deletePreview(bgCtx, previewPaths[i])
+	}
+
+	logger.InfoContext(ctx, "Quarantined ticket discarded", "ticketNumber", ticketNumber, "pattern", quarantineReason, "attachments", len(storagePaths))
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Ticket discarded.",
+	})
+}
@@ -0,0 +1,171 @@
+// backend/internal/api/handlers/ticket/resend_notification.go
+// ==========================================================================
+// Admin replay of a ticket's submitter notification emails, for when the
+// original was lost or went to a wrong address. The email is rebuilt from the
+// ticket's current state through the same email.Service methods used by the
+// normal lifecycle, and every resend is written to the audit trail.
+// ==========================================================================
+
+package ticket
+
+import (
+	"log/slog"
+	"net/http"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// Notification types accepted by ResendNotification.
+const (
+	notificationConfirmation = "confirmation" // Ticket received (includes a fresh status link)
+	notificationInProgress   = "in_progress"  // Work has started
+	notificationClosure      = "closure"      // Ticket resolved (requires a Closed ticket)
+)
+
+// resendNotificationRequest is the body accepted by ResendNotification.
+type resendNotificationRequest struct {
+	Type      string `json:"type"`
+	Recipient string `json:"recipient,omitempty"` // Defaults to the ticket's submitter
+}
+
+// --- Handler Functions ---
+
+// ResendNotification re-sends one of a ticket's submitter notifications.
+// (Admin Only)
+//
+// Resending the confirmation issues a new submitter token so the email carries
+// a working status link; the link from the original confirmation stops working.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Request Body:
+//   - type: "confirmation", "in_progress" or "closure".
+//   - recipient: Optional address to send to instead of the submitter.
+//
+// Returns:
+//   - JSON APIResponse on success, 400/404 for invalid requests, or 502 with the delivery error on failure.
+func (h *Handler) ResendNotification(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "ResendNotification", "ticketUUID", ticketID)
+
+	// --- 1. Bind & Validate ---
+	var req resendNotificationRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	switch req.Type {
+	case notificationConfirmation, notificationInProgress, notificationClosure:
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "type must be one of: confirmation, in_progress, closure.")
+	}
+	req.Recipient = strings.TrimSpace(req.Recipient)
+	if req.Recipient != "" {
+		if _, err := mail.ParseAddress(req.Recipient); err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "recipient must be a valid email address.")
+		}
+	}
+
+	// --- 2. Load Ticket ---
+	ticket, err := h.getTicketDetailsByID(ctx, ticketID)
+	if err != nil {
+		if err.Error() == "ticket not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to load ticket.")
+	}
+	if ticket.IsInternal {
+		return echo.NewHTTPError(http.StatusBadRequest, "Internal tickets have no submitter notifications.")
+	}
+	if ticket.QuarantinedAt != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Quarantined tickets must be approved before notifications are sent.")
+	}
+	if req.Type == notificationClosure && ticket.Status != models.StatusClosed {
+		return echo.NewHTTPError(http.StatusBadRequest, "Only closed tickets have a closure notification.")
+	}
+
+	recipient := req.Recipient
+	if recipient == "" {
+		recipient = ticket.EndUserEmail
+	}
+	ticketLocale := ""
+	if ticket.Locale != nil {
+		ticketLocale = *ticket.Locale
+	}
+	locale := h.submitterLocale(ctx, ticketLocale, ticket.EndUserEmail)
+
+	// --- 3. Send ---
+	var sendErr error
+	switch req.Type {
+	case notificationConfirmation:
+		rawToken, tokenHash, tokenErr := newSubmitterToken()
+		if tokenErr != nil {
+			logger.ErrorContext(ctx, "Failed to generate submitter token", "error", tokenErr)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to resend notification.")
+		}
+		if _, err := h.db.Pool.Exec(ctx, `UPDATE tickets SET submitter_token_hash = $2 WHERE id = $1`, ticketID, tokenHash); err != nil {
+			logger.ErrorContext(ctx, "Failed to rotate submitter token", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to resend notification.")
+		}
+		name := "User"
+		if ticket.SubmitterName != nil {
+			name = *ticket.SubmitterName
+		}
+		sendErr = h.emailService.SendTicketConfirmation(recipient, name, strconv.Itoa(int(ticket.TicketNumber)), ticket.Subject,
+			locale, h.submitterStatusLink(ticket.TicketNumber, rawToken), nil)
+	case notificationInProgress:
+		assigneeName := "Unassigned"
+		if ticket.AssignedToUser != nil {
+			assigneeName = ticket.AssignedToUser.Name
+		}
+		sendErr = h.emailService.SendTicketInProgress(recipient, ticketID, ticket.Subject, h.submitterFacingStaffName(assigneeName, locale), locale)
+	case notificationClosure:
+		resolution := ""
+		if ticket.ResolutionNotes != nil {
+			resolution = *ticket.ResolutionNotes
+		}
+		sendErr = h.emailService.SendTicketClosure(recipient, ticketID, ticket.Subject, resolution, locale)
+	}
+
+	metadata := map[string]interface{}{
+		"ticket_number": ticket.TicketNumber,
+		"type":          req.Type,
+		"recipient":     recipient,
+		"success":       sendErr == nil,
+	}
+	if sendErr != nil {
+		metadata["error"] = sendErr.Error()
+	}
+	h.auditService.RecordAsync(audit.Event{
+		Action:       audit.ActionNotificationResend,
+		ActorUserID:  auth.OptionalUserID(c),
+		ResourceType: "ticket",
+		ResourceID:   ticketID,
+		IPAddress:    c.RealIP(),
+		Metadata:     metadata,
+	})
+
+	// --- 4. Respond ---
+	if sendErr != nil {
+		logger.WarnContext(ctx, "Notification resend failed", "type", req.Type, "recipient", recipient, "error", sendErr)
+		return c.JSON(http.StatusBadGateway, models.APIResponse{
+			Success:   false,
+			Message:   "Failed to resend notification.",
+			Error:     sendErr.Error(),
+			ErrorCode: string(apierror.CodeEmailDelivery),
+		})
+	}
+	logger.InfoContext(ctx, "Notification resent", "type", req.Type, "recipient", recipient)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Notification sent to " + recipient + ".",
+	})
+}
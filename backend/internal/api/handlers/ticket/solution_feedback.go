@@ -0,0 +1,115 @@
+// backend/internal/api/handlers/ticket/solution_feedback.go
+// ==========================================================================
+// Knowledge base suggestions for new tickets. Matching solutions are returned
+// with the created ticket and counted as shown to the submitter; the
+// submitter can then report, with their ticket token, whether a suggestion
+// helped, which ranks it first for their address next time. The first few are
+// also listed in the confirmation email (TICKET_CONFIRMATION_SUGGESTIONS).
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/solutions"
+	"github.com/labstack/echo/v4"
+)
+
+// maxSuggestedSolutions is the number of solutions returned with a new ticket.
+const maxSuggestedSolutions = 3
+
+// --- Handler Functions ---
+
+// SubmitSolutionFeedback records whether a solution suggested for the
+// submitter's ticket helped.
+//
+// Path Parameters:
+//   - number: The ticket number.
+//   - solutionId: The UUID of the suggested solution.
+//
+// Headers / Query Parameters:
+//   - X-Ticket-Token header or token query parameter: The submitter token.
+//
+// Request Body:
+//   - Expects JSON matching models.SolutionFeedback.
+//
+// Returns:
+//   - JSON success message, 404 if the solution was not suggested to this submitter, or an error response.
+func (h *Handler) SubmitSolutionFeedback(c echo.Context) error {
+	ctx := c.Request().Context()
+	solutionID := c.Param("solutionId")
+	logger := slog.With("handler", "SubmitSolutionFeedback", "ticketNumber", c.Param("number"), "solutionID", solutionID)
+
+	st, err := h.authenticateSubmitter(c)
+	if err != nil {
+		return err
+	}
+	var req models.SolutionFeedback
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+
+	var email string
+	if err := h.db.Pool.QueryRow(ctx, `SELECT end_user_email FROM tickets WHERE id = $1`, st.ID).Scan(&email); err != nil {
+		logger.ErrorContext(ctx, "Failed to load submitter email", "ticketUUID", st.ID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record feedback.")
+	}
+	recorded, err := solutions.RecordFeedback(ctx, h.db.Pool, solutionID, email, req.Helpful)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to record solution feedback", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record feedback.")
+	}
+	if !recorded {
+		return echo.NewHTTPError(http.StatusNotFound, "This solution was not suggested for your ticket.")
+	}
+
+	logger.InfoContext(ctx, "Solution feedback recorded", "helpful", req.Helpful)
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: "Thanks for your feedback."})
+}
+
+// --- Helper Functions ---
+
+// suggestSolutions matches a new ticket against the knowledge base and counts
+// the matches as shown to its submitter. Failures are only logged: a ticket is
+// never rejected because suggestions are unavailable.
+func (h *Handler) suggestSolutions(ctx context.Context, ticket *models.Ticket) []models.SolutionMatch {
+	logger := slog.With("helper", "suggestSolutions", "ticketUUID", ticket.ID)
+	matches, err := solutions.Match(ctx, h.db.Pool, ticket.IssueType, ticket.Subject+"\n"+ticket.Description,
+		ticket.EndUserEmail, maxSuggestedSolutions)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to match solutions", "error", err)
+		return nil
+	}
+	if err := solutions.RecordShown(ctx, h.db.Pool, ticket.ID, ticket.EndUserEmail, matches); err != nil {
+		logger.ErrorContext(ctx, "Failed to record suggested solutions", "error", err)
+	}
+	return matches
+}
+
+// confirmationSuggestions picks the suggestions listed in the confirmation
+// email, capped by TICKET_CONFIRMATION_SUGGESTIONS.
+//
+// Returns:
+//   - []email.SuggestedSolution: The suggestions for the email (nil when none).
+//   - []string: Their solution IDs, for solutions.RecordEmailed.
+func (h *Handler) confirmationSuggestions(matches []models.SolutionMatch) ([]email.SuggestedSolution, []string) {
+	limit := h.config.Tickets.ConfirmationSuggestions
+	if limit > len(matches) {
+		limit = len(matches)
+	}
+	if limit <= 0 {
+		return nil, nil
+	}
+	suggestions := make([]email.SuggestedSolution, limit)
+	ids := make([]string, limit)
+	for i, m := range matches[:limit] {
+		suggestions[i] = email.SuggestedSolution{Title: m.Title, Body: m.Body}
+		ids[i] = m.ID
+	}
+	return suggestions, ids
+}
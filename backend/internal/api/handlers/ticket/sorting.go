@@ -0,0 +1,81 @@
+// backend/internal/api/handlers/ticket/sorting.go
+// ==========================================================================
+// ORDER BY construction for ticket lists. Sort fields map to whitelisted SQL
+// expressions; urgency sorts by severity rather than alphabetically, NULL
+// placement is always explicit, and a secondary field breaks ties before the
+// final ticket ID tie-break that keeps pagination stable.
+// ==========================================================================
+
+package ticket
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+)
+
+// urgencySeverityExpr ranks urgency so that DESC yields Critical, High, Medium, Low.
+var urgencySeverityExpr = urgencySeverityCase("t.urgency")
+
+// ticketSortColumns maps the sortBy values accepted from clients to SQL expressions.
+var ticketSortColumns = map[string]string{
+	"createdAt":    "t.created_at",
+	"updatedAt":    "t.updated_at",
+	"closedAt":     "t.closed_at",
+	"dueDate":      "t.due_date",
+	"ticketNumber": "t.ticket_number",
+	"status":       "t.status",
+	"urgency":      urgencySeverityExpr,
+	"assignedTo":   "a.name",
+}
+
+// urgencySeverityCase returns a SQL expression ranking the given urgency
+// column by models.UrgencySeverityOrder (Low = 1 ... Critical = 4; unknown = 0).
+func urgencySeverityCase(column string) string {
+	var b strings.Builder
+	b.WriteString("CASE " + column)
+	for i, urgency := range models.UrgencySeverityOrder {
+		fmt.Fprintf(&b, " WHEN '%s' THEN %d", urgency, i+1)
+	}
+	b.WriteString(" ELSE 0 END")
+	return b.String()
+}
+
+// buildTicketOrderBy builds the ORDER BY clause for a ticket list query.
+// Unknown values are ignored, falling back to the defaults.
+//
+// Parameters:
+//   - sortBy: The primary sort field (a ticketSortColumns key); "" keeps the default (updatedAt DESC).
+//   - sortOrder: "asc" or "desc" (default "desc"); the secondary field uses the same direction.
+//   - nulls: "first" or "last" (default "last") for NULL closedAt/dueDate/assignedTo values.
+//   - thenBy: The secondary sort field; "" uses defaultThenBy.
+//   - defaultThenBy: The configured secondary sort field.
+//
+// Returns:
+//   - string: The ORDER BY clause, with a leading space.
+func buildTicketOrderBy(sortBy, sortOrder, nulls, thenBy, defaultThenBy string) string {
+	primary, ok := ticketSortColumns[sortBy]
+	if !ok {
+		sortBy, primary = "updatedAt", ticketSortColumns["updatedAt"]
+		sortOrder = "desc"
+	}
+	order := "DESC"
+	if strings.ToLower(sortOrder) == "asc" {
+		order = "ASC"
+	}
+	nullsClause := "NULLS LAST"
+	if strings.ToLower(nulls) == "first" {
+		nullsClause = "NULLS FIRST"
+	}
+
+	terms := []string{fmt.Sprintf("%s %s %s", primary, order, nullsClause)}
+	if thenBy == "" {
+		thenBy = defaultThenBy
+	}
+	if secondary, ok := ticketSortColumns[thenBy]; ok && thenBy != sortBy {
+		terms = append(terms, fmt.Sprintf("%s %s %s", secondary, order, nullsClause))
+	}
+	terms = append(terms, "t.id "+order) // Stable order for pagination
+	return " ORDER BY " + strings.Join(terms, ", ")
+}
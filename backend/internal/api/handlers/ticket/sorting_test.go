@@ -0,0 +1,113 @@
+// backend/internal/api/handlers/ticket/sorting_test.go
+// ==========================================================================
+// Tests for the ticket list ORDER BY builder: urgency ranks by severity, and
+// unknown sort parameters fall back to the defaults.
+// ==========================================================================
+
+package ticket
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+)
+
+// urgencyRanks parses the WHEN/ELSE ranks out of a urgencySeverityCase expression.
+func urgencyRanks(t *testing.T, expr string) (map[string]int, int) {
+	t.Helper()
+	ranks := map[string]int{}
+	for _, m := range regexp.MustCompile(`WHEN '([^']+)' THEN (\d+)`).FindAllStringSubmatch(expr, -1) {
+		rank, _ := strconv.Atoi(m[2])
+		ranks[m[1]] = rank
+	}
+	elseMatch := regexp.MustCompile(`ELSE (\d+) END`).FindStringSubmatch(expr)
+	if elseMatch == nil {
+		t.Fatalf("no ELSE branch in %q", expr)
+	}
+	fallback, _ := strconv.Atoi(elseMatch[1])
+	return ranks, fallback
+}
+
+func TestUrgencySeverityCaseRanksBySeverity(t *testing.T) {
+	ranks, fallback := urgencyRanks(t, urgencySeverityCase("t.urgency"))
+
+	ordered := []models.TicketUrgency{models.UrgencyCritical, models.UrgencyHigh, models.UrgencyMedium, models.UrgencyLow}
+	for i := 0; i < len(ordered)-1; i++ {
+		higher, lower := ordered[i], ordered[i+1]
+		if ranks[string(higher)] <= ranks[string(lower)] {
+			t.Errorf("rank(%s) = %d, want greater than rank(%s) = %d",
+				higher, ranks[string(higher)], lower, ranks[string(lower)])
+		}
+	}
+	for _, urgency := range ordered {
+		if ranks[string(urgency)] <= fallback {
+			t.Errorf("rank(%s) = %d, want greater than the fallback %d", urgency, ranks[string(urgency)], fallback)
+		}
+	}
+	if _, ok := ranks["Unknown"]; ok {
+		t.Errorf("unexpected rank for an unknown urgency")
+	}
+}
+
+func TestBuildTicketOrderBy(t *testing.T) {
+	tests := []struct {
+		name                                          string
+		sortBy, sortOrder, nulls, thenBy, defaultThen string
+		want                                          string
+	}{
+		{
+			name:   "urgency sorts by severity",
+			sortBy: "urgency", sortOrder: "desc",
+			want: " ORDER BY " + urgencySeverityExpr + " DESC NULLS LAST, t.id DESC",
+		},
+		{
+			name:   "unknown sortBy falls back to updatedAt desc",
+			sortBy: "urgency; DROP TABLE tickets", sortOrder: "asc",
+			want: " ORDER BY t.updated_at DESC NULLS LAST, t.id DESC",
+		},
+		{
+			name: "empty sortBy uses the default",
+			want: " ORDER BY t.updated_at DESC NULLS LAST, t.id DESC",
+		},
+		{
+			name:   "ascending with nulls first",
+			sortBy: "dueDate", sortOrder: "ASC", nulls: "first",
+			want: " ORDER BY t.due_date ASC NULLS FIRST, t.id ASC",
+		},
+		{
+			name:   "unknown sortOrder and nulls use the defaults",
+			sortBy: "createdAt", sortOrder: "sideways", nulls: "middle",
+			want: " ORDER BY t.created_at DESC NULLS LAST, t.id DESC",
+		},
+		{
+			name:   "secondary sort from thenBy",
+			sortBy: "status", sortOrder: "asc", thenBy: "urgency",
+			want: " ORDER BY t.status ASC NULLS LAST, " + urgencySeverityExpr + " ASC NULLS LAST, t.id ASC",
+		},
+		{
+			name:   "secondary sort from the configured default",
+			sortBy: "status", defaultThen: "createdAt",
+			want: " ORDER BY t.status DESC NULLS LAST, t.created_at DESC NULLS LAST, t.id DESC",
+		},
+		{
+			name:   "secondary equal to primary is skipped",
+			sortBy: "urgency", thenBy: "urgency",
+			want: " ORDER BY " + urgencySeverityExpr + " DESC NULLS LAST, t.id DESC",
+		},
+		{
+			name:   "unknown thenBy is ignored",
+			sortBy: "status", thenBy: "nope",
+			want: " ORDER BY t.status DESC NULLS LAST, t.id DESC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildTicketOrderBy(tt.sortBy, tt.sortOrder, tt.nulls, tt.thenBy, tt.defaultThen)
+			if got != tt.want {
+				t.Errorf("buildTicketOrderBy() =\n  %q\nwant\n  %q", got, tt.want)
+			}
+		})
+	}
+}
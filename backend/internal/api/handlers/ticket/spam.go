@@ -0,0 +1,314 @@
+// backend/internal/api/handlers/ticket/spam.go
+// ==========================================================================
+// Spam disposition. An Admin can mark any ticket (typically one held in the
+// quarantine queue) as spam: it is kept for the spam report but excluded from
+// the ticket lists, counts, search and reports. Unmarking reverses this; a
+// ticket the filter had quarantined goes back to the quarantine queue.
+// The spam report shows spam volume over time and how each filter pattern
+// performed, to help tune SPAM_FILTER_KEYWORDS / SPAM_FILTER_PATTERNS.
+// ==========================================================================
+
+package ticket
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// defaultSpamReportDays is the period the spam report covers by default.
+	defaultSpamReportDays = 30
+	// maxSpamReportDays is the longest period the spam report covers.
+	maxSpamReportDays = 365
+)
+
+// --- Handler Functions ---
+
+// GetSpamTickets lists tickets marked as spam, most recently marked first.
+//
+// Query Parameters:
+//   - page / limit: Pagination (default limit 50, max 200).
+//
+// Returns:
+//   - JSON PaginatedResponse containing Ticket objects (with spam_at) or an error response.
+func (h *Handler) GetSpamTickets(c echo.Context) error {
+	return h.listHeldTickets(c, slog.With("handler", "GetSpamTickets"),
+		"t.spam_at IS NOT NULL", "t.spam_at DESC, t.id")
+}
+
+// MarkTicketSpam marks a ticket as spam. The submitter is not notified.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//
+// Returns:
+//   - JSON APIResponse on success, 404 if the ticket does not exist, 409 if it is already spam.
+func (h *Handler) MarkTicketSpam(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "MarkTicketSpam", "ticketUUID", ticketID)
+	adminID := auth.OptionalUserID(c)
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark ticket as spam.")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
+			}
+		}
+	}()
+
+	var spamAt *time.Time
+	var number int32
+	var quarantineReason *string
+	err = tx.QueryRow(ctx, `
+        SELECT spam_at, ticket_number, quarantine_reason FROM tickets WHERE id = $1 FOR UPDATE`, ticketID,
+	).Scan(&spamAt, &number, &quarantineReason)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load ticket", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark ticket as spam.")
+	}
+	if spamAt != nil {
+		return echo.NewHTTPError(http.StatusConflict, "Ticket is already marked as spam.")
+	}
+
+	if _, err = tx.Exec(ctx, `
+        UPDATE tickets SET spam_at = NOW(), spam_marked_by = NULLIF($2, '')::uuid, updated_at = NOW()
+        WHERE id = $1`, ticketID, adminID); err != nil {
+		logger.ErrorContext(ctx, "Failed to mark ticket as spam", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark ticket as spam.")
+	}
+	if err = h.addHistoryNote(ctx, tx, ticketID, adminID, "Marked as spam."); err != nil {
+		logger.ErrorContext(ctx, "Failed to record spam mark", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark ticket as spam.")
+	}
+	source := "manual"
+	if quarantineReason != nil {
+		source = "filter"
+	}
+	if err = h.auditService.RecordTx(ctx, tx, audit.Event{
+		Action:       audit.ActionTicketSpamMark,
+		ActorUserID:  adminID,
+		ResourceType: "ticket",
+		ResourceID:   ticketID,
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"ticket_number": number, "source": source, "pattern": quarantineReason},
+	}); err != nil {
+		logger.ErrorContext(ctx, "Failed to audit spam mark", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark ticket as spam.")
+	}
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit spam mark", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to mark ticket as spam.")
+	}
+
+	logger.InfoContext(ctx, "Ticket marked as spam", "ticketNumber", number, "source", source)
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: "Ticket marked as spam."})
+}
+
+// UnmarkTicketSpam clears a ticket's spam mark. A ticket the spam filter had
+// quarantined returns to the quarantine queue; any other ticket returns to
+// the normal queues.
+//
+// Path Parameters:
+//   - id: The ticket UUID.
+//
+// Returns:
+//   - JSON APIResponse with the ticket, 404 if the ticket is not marked as spam.
+func (h *Handler) UnmarkTicketSpam(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "UnmarkTicketSpam", "ticketUUID", ticketID)
+	adminID := auth.OptionalUserID(c)
+
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmark ticket.")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
+			}
+		}
+	}()
+
+	var number int32
+	var quarantined bool
+	err = tx.QueryRow(ctx, `
+        UPDATE tickets SET spam_at = NULL, spam_marked_by = NULL, updated_at = NOW()
+        WHERE id = $1 AND spam_at IS NOT NULL
+        RETURNING ticket_number, quarantined_at IS NOT NULL`, ticketID,
+	).Scan(&number, &quarantined)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Spam ticket not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to unmark spam ticket", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmark ticket.")
+	}
+
+	summary := "Spam mark removed."
+	if quarantined {
+		summary = "Spam mark removed; returned to spam quarantine."
+	}
+	if err = h.addHistoryNote(ctx, tx, ticketID, adminID, summary); err != nil {
+		logger.ErrorContext(ctx, "Failed to record spam unmark", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmark ticket.")
+	}
+	if err = h.auditService.RecordTx(ctx, tx, audit.Event{
+		Action:       audit.ActionTicketSpamUnmark,
+		ActorUserID:  adminID,
+		ResourceType: "ticket",
+		ResourceID:   ticketID,
+		IPAddress:    c.RealIP(),
+		Metadata:     map[string]interface{}{"ticket_number": number, "quarantined": quarantined},
+	}); err != nil {
+		logger.ErrorContext(ctx, "Failed to audit spam unmark", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmark ticket.")
+	}
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit spam unmark", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to unmark ticket.")
+	}
+	logger.InfoContext(ctx, "Ticket spam mark removed", "ticketNumber", number, "quarantined", quarantined)
+
+	message := "Spam mark removed."
+	if quarantined {
+		message = "Spam mark removed; the ticket is back in quarantine."
+	}
+	ticket, fetchErr := h.getTicketDetailsByID(ctx, ticketID)
+	if fetchErr != nil {
+		logger.ErrorContext(ctx, "Failed to fetch unmarked ticket", "error", fetchErr)
+		return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: message})
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Message: message, Data: ticket})
+}
+
+// GetSpamReport reports spam volume per day or week, split into filter
+// catches and manual marks, along with per-pattern spam and false positive
+// (approved from quarantine) counts. Intervals are UTC.
+//
+// Query Parameters:
+//   - days (optional): The period covered (default 30, max 365).
+//   - interval (optional): "day" (default) or "week".
+//
+// Returns:
+//   - JSON APIResponse containing a models.SpamReport or an error response.
+func (h *Handler) GetSpamReport(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetSpamReport")
+
+	days := defaultSpamReportDays
+	if param := c.QueryParam("days"); param != "" {
+		parsed, err := strconv.Atoi(param)
+		if err != nil || parsed <= 0 || parsed > maxSpamReportDays {
+			return echo.NewHTTPError(http.StatusBadRequest, "days must be between 1 and 365.")
+		}
+		days = parsed
+	}
+	interval := "day"
+	switch param := c.QueryParam("interval"); param {
+	case "", "day":
+	case "week":
+		interval = "week"
+	default:
+		return echo.NewHTTPError(http.StatusBadRequest, "interval must be day or week.")
+	}
+
+	report := models.SpamReport{
+		Since:    time.Now().UTC().AddDate(0, 0, -days),
+		Interval: interval,
+		Buckets:  make([]models.SpamReportBucket, 0),
+		Patterns: make([]models.SpamPatternCount, 0),
+	}
+
+	// --- 1. Volume per Interval ---
+	rows, err := h.db.Pool.Query(ctx, `
+        WITH buckets AS (
+            SELECT generate_series(date_trunc($2::text, $1::timestamptz AT TIME ZONE 'UTC'),
+                                   date_trunc($2::text, NOW() AT TIME ZONE 'UTC'),
+                                   ('1 ' || $2::text)::interval) AS start
+        )
+        SELECT b.start AT TIME ZONE 'UTC',
+               COUNT(t.id)::int,
+               COUNT(t.id) FILTER (WHERE t.quarantine_reason IS NOT NULL)::int,
+               COUNT(t.id) FILTER (WHERE t.quarantine_reason IS NULL)::int
+        FROM buckets b
+        LEFT JOIN tickets t ON t.spam_at >= $1
+                           AND date_trunc($2::text, t.spam_at AT TIME ZONE 'UTC') = b.start
+        GROUP BY b.start
+        ORDER BY b.start`, report.Since, interval)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query spam volume", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build spam report.")
+	}
+	for rows.Next() {
+		var bucket models.SpamReportBucket
+		if err := rows.Scan(&bucket.Start, &bucket.Total, &bucket.Filter, &bucket.Manual); err != nil {
+			rows.Close()
+			logger.ErrorContext(ctx, "Failed to scan spam volume row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build spam report.")
+		}
+		report.Total += bucket.Total
+		report.Buckets = append(report.Buckets, bucket)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating spam volume rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build spam report.")
+	}
+
+	// --- 2. Filter Patterns ---
+	// Approvals are read from the audit log, which records the pattern that
+	// quarantined each released ticket.
+	rows, err = h.db.Pool.Query(ctx, `
+        SELECT pattern, SUM(spam)::int, SUM(approved)::int
+        FROM (
+            SELECT quarantine_reason AS pattern, 1 AS spam, 0 AS approved
+            FROM tickets
+            WHERE spam_at >= $1 AND quarantine_reason IS NOT NULL
+            UNION ALL
+            SELECT metadata->>'pattern', 0, 1
+            FROM audit_log
+            WHERE action = $2 AND created_at >= $1 AND metadata->>'pattern' IS NOT NULL
+        ) p
+        GROUP BY pattern
+        ORDER BY SUM(spam) DESC, SUM(approved) DESC, pattern`, report.Since, audit.ActionTicketApprove)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query spam patterns", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build spam report.")
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var pattern models.SpamPatternCount
+		if err := rows.Scan(&pattern.Pattern, &pattern.Spam, &pattern.Approved); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan spam pattern row", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build spam report.")
+		}
+		report.Patterns = append(report.Patterns, pattern)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating spam pattern rows", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build spam report.")
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: report})
+}
@@ -0,0 +1,72 @@
+// backend/internal/api/handlers/ticket/spam_filter.go
+// ==========================================================================
+// Content filter for public ticket submissions. When enabled, a submission
+// whose subject or description matches a configured keyword or regular
+// expression is quarantined for admin review instead of entering the queues.
+// ==========================================================================
+
+package ticket
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+)
+
+// spamPattern is a compiled spam keyword or pattern and its configured source.
+type spamPattern struct {
+	source  string
+	pattern *regexp.Regexp
+}
+
+// compileSpamPatterns builds the spam matchers from configuration. Keywords
+// match case-insensitively on word boundaries, like urgency keywords; patterns
+// are used as written (prefix "(?i)" for case-insensitive matching). Patterns
+// were validated when the configuration was loaded.
+//
+// Parameters:
+//   - cfg: The spam filter configuration.
+//
+// Returns:
+//   - []spamPattern: The matchers, or nil when the filter is disabled.
+func compileSpamPatterns(cfg config.SpamFilterConfig) []spamPattern {
+	if !cfg.Enabled {
+		return nil
+	}
+	var patterns []spamPattern
+	for _, keyword := range cfg.Keywords {
+		keyword = strings.TrimSpace(keyword)
+		if keyword == "" {
+			continue
+		}
+		patterns = append(patterns, spamPattern{
+			source:  keyword,
+			pattern: regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(keyword) + `($|\W)`),
+		})
+	}
+	for _, expr := range cfg.Patterns {
+		patterns = append(patterns, spamPattern{source: expr, pattern: regexp.MustCompile(expr)})
+	}
+	return patterns
+}
+
+// matchSpam returns the first configured keyword or pattern found in the
+// subject or description.
+//
+// Parameters:
+//   - subject: The ticket subject.
+//   - description: The ticket description.
+//
+// Returns:
+//   - string: The keyword or pattern that matched, as configured.
+//   - bool: False if nothing matched (or the filter is disabled).
+func (h *Handler) matchSpam(subject, description string) (string, bool) {
+	text := subject + "\n" + description
+	for _, sp := range h.spamPatterns {
+		if sp.pattern.MatchString(text) {
+			return sp.source, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,414 @@
+// backend/internal/api/handlers/ticket/submitter_access.go
+// ==========================================================================
+// Public, token-authenticated access for ticket submitters. Each ticket gets a
+// random submitter token at creation (only its SHA-256 hash is stored); the
+// raw token is emailed to the submitter as a status link. With it they can
+// view the ticket's public thread and reply. A reply to a closed ticket within
+// the configured reopen window reopens it and notifies the last assignee;
+// after the window the submitter is asked to open a new ticket instead.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/sla"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// SubmitterTokenHeader carries the submitter token (allowed by CORS); the "token" query parameter is also accepted.
+const SubmitterTokenHeader = "X-Ticket-Token"
+
+// submitterTicket is the ticket state needed to serve a submitter request.
+type submitterTicket struct {
+	ID               string
+	TicketNumber     int32
+	Subject          string
+	Status           models.TicketStatus
+	AssignedToUserID *string
+	ClosedAt         *time.Time
+	TokenHash        *string
+}
+
+// --- Handler Functions ---
+
+// GetPublicTicketStatus returns the submitter-facing view of a ticket: its
+// status, assignee, resolution and non-internal comment thread. Staff names
+// are shown only when TICKET_SHOW_ASSIGNEE_TO_SUBMITTER is on.
+//
+// Path Parameters:
+//   - number: The ticket number.
+//
+// Headers / Query Parameters:
+//   - X-Ticket-Token header or token query parameter: The submitter token.
+//
+// Returns:
+//   - JSON APIResponse containing a PublicTicketStatus or an error response.
+func (h *Handler) GetPublicTicketStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetPublicTicketStatus", "ticketNumber", c.Param("number"))
+
+	st, err := h.authenticateSubmitter(c)
+	if err != nil {
+		return err
+	}
+
+	status := models.PublicTicketStatus{
+		TicketNumber: st.TicketNumber,
+		Subject:      st.Subject,
+		Status:       st.Status,
+	}
+	var locale string
+	var assigneeName *string
+	err = h.db.Pool.QueryRow(ctx, `
+        SELECT t.urgency, t.created_at, t.updated_at, t.resolution_notes, COALESCE(t.locale, ''), u.name
+        FROM tickets t
+        LEFT JOIN users u ON u.id = t.assigned_to_user_id
+        WHERE t.id = $1`, st.ID,
+	).Scan(&status.Urgency, &status.CreatedAt, &status.UpdatedAt, &status.ResolutionNotes, &locale, &assigneeName)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load ticket status", "ticketUUID", st.ID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve ticket status.")
+	}
+	status.ClosedAt = st.ClosedAt
+	status.ReopenDeadline = h.reopenDeadline(st)
+	status.CanReply = st.Status != models.StatusClosed ||
+		(status.ReopenDeadline != nil && time.Now().Before(*status.ReopenDeadline))
+
+	status.Updates, err = h.fetchTicketThread(ctx, st.ID, false)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load public ticket thread", "ticketUUID", st.ID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve ticket status.")
+	}
+	if assigneeName != nil {
+		name := h.submitterFacingStaffName(*assigneeName, locale)
+		status.AssigneeName = &name
+	}
+	// Staff identities are not exposed publicly; only the author's display name
+	// is kept, and staff names are replaced when the assignee is hidden.
+	for i := range status.Updates {
+		update := &status.Updates[i]
+		update.UserID = nil
+		if update.User != nil && !update.FromSubmitter && !update.IsSystemUpdate {
+			update.User.Name = h.submitterFacingStaffName(update.User.Name, locale)
+		}
+	}
+
+	return c.JSON(http.StatusOK, models.APIResponse{Success: true, Data: status})
+}
+
+// AddSubmitterComment adds a reply from the ticket's submitter. Replying to a
+// closed ticket within the reopen window reopens it (In Progress when it has
+// an assignee, otherwise Open) and notifies the last assignee. Replying to a
+// ticket that is Waiting on Customer moves it back to In Progress.
+//
+// Path Parameters:
+//   - number: The ticket number.
+//
+// Headers / Query Parameters:
+//   - X-Ticket-Token header or token query parameter: The submitter token.
+//
+// Request Body:
+//   - SubmitterCommentCreate (content is required).
+//
+// Returns:
+//   - JSON APIResponse containing the created TicketUpdate, 409 if the reopen
+//     window has passed, or another error response.
+func (h *Handler) AddSubmitterComment(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "AddSubmitterComment", "ticketNumber", c.Param("number"))
+
+	st, err := h.authenticateSubmitter(c)
+	if err != nil {
+		return err
+	}
+
+	var body models.SubmitterCommentCreate
+	if err = c.Bind(&body); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	if strings.TrimSpace(body.Comment) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Comment content cannot be empty.")
+	}
+
+	// --- Reopen Window Check ---
+	reopen := st.Status == models.StatusClosed
+	if reopen {
+		deadline := h.reopenDeadline(st)
+		if deadline == nil || !time.Now().Before(*deadline) {
+			logger.InfoContext(ctx, "Reply to closed ticket rejected; reopen window has passed", "ticketUUID", st.ID)
+			return apierror.New(http.StatusConflict, apierror.CodeReopenWindowExpired, fmt.Sprintf(
+				"Ticket #%d was closed too long ago to be reopened. Please submit a new ticket and mention ticket #%d in the description.",
+				st.TicketNumber, st.TicketNumber))
+		}
+	}
+
+	// --- Database Transaction ---
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin database transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to start transaction.")
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil {
+				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
+			}
+		}
+	}()
+
+	var commentID string
+	err = tx.QueryRow(ctx, `
+        INSERT INTO ticket_updates (ticket_id, comment, is_internal_note, from_submitter, created_at)
+        VALUES ($1, $2, FALSE, TRUE, NOW())
+        RETURNING id`, st.ID, body.Comment).Scan(&commentID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to insert submitter comment", "ticketUUID", st.ID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to add comment.")
+	}
+
+	newStatus := st.Status
+	resumed := false
+	if reopen {
+		newStatus, err = h.reopenClosedTicket(ctx, tx, st)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to reopen ticket", "ticketUUID", st.ID, "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to reopen ticket.")
+		}
+	} else if st.Status == models.StatusWaitingOnCustomer {
+		resumed, err = h.resumeWaitingTicket(ctx, tx, st)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to resume waiting ticket", "ticketUUID", st.ID, "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to add comment.")
+		}
+	} else if _, err = tx.Exec(ctx, `UPDATE tickets SET updated_at = NOW() WHERE id = $1`, st.ID); err != nil {
+		logger.ErrorContext(ctx, "Failed to update ticket timestamp", "ticketUUID", st.ID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to add comment.")
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit submitter comment", "ticketUUID", st.ID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to save comment.")
+	}
+
+	message := "Comment added successfully."
+	if reopen {
+		message = "Ticket reopened. Our team has been notified of your reply."
+		logger.InfoContext(ctx, "Ticket reopened by submitter reply", "ticketUUID", st.ID, "status", newStatus)
+		h.notifyTicketReopened(st)
+	} else if resumed {
+		message = "Thank you for your reply. Our team will continue working on your ticket."
+		logger.InfoContext(ctx, "Waiting ticket resumed by submitter reply", "ticketUUID", st.ID)
+		h.notifyTicketResumed(st)
+	}
+
+	createdComment, fetchErr := h.getTicketUpdateByID(ctx, commentID)
+	if fetchErr != nil {
+		logger.ErrorContext(ctx, "Failed to fetch created submitter comment", "commentID", commentID, "error", fetchErr)
+		return c.JSON(http.StatusCreated, models.APIResponse{
+			Success: true,
+			Message: message,
+			Data:    map[string]string{"id": commentID},
+		})
+	}
+	createdComment.UserID = nil
+	return c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    createdComment,
+	})
+}
+
+// --- Helper Functions ---
+
+// newSubmitterToken generates a random submitter token and its stored hash.
+//
+// Returns:
+//   - string: The raw token (given to the submitter, never stored).
+//   - string: The hex SHA-256 hash to store on the ticket.
+//   - error: If the system random source fails.
+func newSubmitterToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate submitter token: %w", err)
+	}
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+	return raw, hashSubmitterToken(raw), nil
+}
+
+// hashSubmitterToken returns the hex SHA-256 hash of a raw submitter token.
+func hashSubmitterToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+// submitterStatusLink builds the portal URL of a ticket's public status page.
+func (h *Handler) submitterStatusLink(ticketNumber int32, rawToken string) string {
+	if h.config.Server.PortalBaseURL == "" || rawToken == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/ticket-status/%d?token=%s",
+		strings.TrimRight(h.config.Server.PortalBaseURL, "/"), ticketNumber, url.QueryEscape(rawToken))
+}
+
+// authenticateSubmitter loads the ticket named by the :number path parameter
+// and checks the request's submitter token against it. Unknown tickets,
+// internal, quarantined or spam tickets and wrong tokens all get the same 404
+// so ticket numbers cannot be probed.
+func (h *Handler) authenticateSubmitter(c echo.Context) (*submitterTicket, error) {
+	ctx := c.Request().Context()
+	notFound := echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+
+	number, err := strconv.ParseInt(c.Param("number"), 10, 32)
+	if err != nil || number <= 0 {
+		return nil, notFound
+	}
+	token := strings.TrimSpace(c.Request().Header.Get(SubmitterTokenHeader))
+	if token == "" {
+		token = strings.TrimSpace(c.QueryParam("token"))
+	}
+	if token == "" {
+		return nil, echo.NewHTTPError(http.StatusUnauthorized, "A ticket access token is required.")
+	}
+
+	var st submitterTicket
+	err = h.db.Pool.QueryRow(ctx, `
+        SELECT id, ticket_number, subject, status, assigned_to_user_id, closed_at, submitter_token_hash
+        FROM tickets WHERE ticket_number = $1 AND NOT is_internal AND quarantined_at IS NULL AND spam_at IS NULL`, int32(number),
+	).Scan(&st.ID, &st.TicketNumber, &st.Subject, &st.Status, &st.AssignedToUserID, &st.ClosedAt, &st.TokenHash)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, notFound
+		}
+		slog.ErrorContext(ctx, "Failed to load ticket for submitter access", "ticketNumber", number, "error", err)
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve ticket.")
+	}
+	if st.TokenHash == nil || subtle.ConstantTimeCompare([]byte(*st.TokenHash), []byte(hashSubmitterToken(token))) != 1 {
+		slog.WarnContext(ctx, "Submitter token mismatch", "ticketNumber", number, "ip", c.RealIP())
+		return nil, notFound
+	}
+	return &st, nil
+}
+
+// reopenDeadline returns when a closed ticket stops accepting reopening
+// replies, or nil if it is not closed or reopening is disabled.
+func (h *Handler) reopenDeadline(st *submitterTicket) *time.Time {
+	if st.Status != models.StatusClosed || st.ClosedAt == nil || h.config.Tickets.ReopenWindow <= 0 {
+		return nil
+	}
+	deadline := st.ClosedAt.Add(h.config.Tickets.ReopenWindow)
+	return &deadline
+}
+
+// reopenClosedTicket reopens a closed ticket within tx and records a system
+// comment. The ticket returns to In Progress if it still has an assignee.
+func (h *Handler) reopenClosedTicket(ctx context.Context, tx pgx.Tx, st *submitterTicket) (models.TicketStatus, error) {
+	newStatus := models.StatusOpen
+	if st.AssignedToUserID != nil {
+		newStatus = models.StatusInProgress
+	}
+	tag, err := tx.Exec(ctx, `
+        UPDATE tickets SET status = $2, closed_at = NULL, updated_at = NOW()
+        WHERE id = $1 AND status = $3`, st.ID, newStatus, models.StatusClosed)
+	if err != nil {
+		return "", fmt.Errorf("failed to reopen ticket: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		// Reopened concurrently (e.g., by staff); keep the comment and the current status.
+		return st.Status, nil
+	}
+	if err := sla.Sync(ctx, tx, st.ID); err != nil {
+		return "", err
+	}
+	changes := []models.FieldChange{statusChange(models.StatusClosed, newStatus)}
+	comment := "Ticket reopened by the submitter's reply. " + describeChanges(changes)
+	if err := h.addHistoryEntry(ctx, tx, st.ID, "", comment, changes); err != nil {
+		return "", err
+	}
+	return newStatus, nil
+}
+
+// resumeWaitingTicket moves a Waiting on Customer ticket back to In Progress
+// within tx and records a system comment. The reminder count is reset, since
+// the submitter answered.
+//
+// Returns:
+//   - bool: False if the ticket left Waiting on Customer concurrently (nothing changed).
+//   - error: If an update fails.
+func (h *Handler) resumeWaitingTicket(ctx context.Context, tx pgx.Tx, st *submitterTicket) (bool, error) {
+	tag, err := tx.Exec(ctx, `
+        UPDATE tickets SET status = $2, waiting_since = NULL, waiting_reminders_sent = 0,
+                           waiting_last_reminder_at = NULL, updated_at = NOW()
+        WHERE id = $1 AND status = $3`, st.ID, models.StatusInProgress, models.StatusWaitingOnCustomer)
+	if err != nil {
+		return false, fmt.Errorf("failed to resume ticket: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if err := sla.Sync(ctx, tx, st.ID); err != nil {
+		return false, err
+	}
+	changes := []models.FieldChange{statusChange(models.StatusWaitingOnCustomer, models.StatusInProgress)}
+	comment := "Submitter replied. " + describeChanges(changes)
+	if err := h.addHistoryEntry(ctx, tx, st.ID, "", comment, changes); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// notifyTicketResumed tells the assignee in-app that the submitter answered a
+// Waiting on Customer ticket. Runs asynchronously; failures are only logged.
+func (h *Handler) notifyTicketResumed(st *submitterTicket) {
+	if st.AssignedToUserID == nil {
+		return
+	}
+	go func(assigneeID, ticketID string, ticketNumber int32) {
+		message := fmt.Sprintf("The submitter replied to ticket #%d; it is back In Progress", ticketNumber)
+		if err := h.CreateNotification(assigneeID, "status_change", message, &ticketID); err != nil {
+			slog.Error("Failed to create submitter reply notification", "ticketID", ticketID, "assigneeUserID", assigneeID, "error", err)
+		}
+	}(*st.AssignedToUserID, st.ID, st.TicketNumber)
+}
+
+// notifyTicketReopened emails and notifies the ticket's last assignee that the
+// submitter reopened it. Runs asynchronously; failures are only logged.
+func (h *Handler) notifyTicketReopened(st *submitterTicket) {
+	if st.AssignedToUserID == nil {
+		return
+	}
+	go func(assigneeID, ticketID string, ticketNumber int32, subject string) {
+		bgCtx := context.Background()
+		notifyLogger := slog.With("operation", "NotifyTicketReopened", "ticketID", ticketID)
+
+		var assigneeEmail string
+		if err := h.db.Pool.QueryRow(bgCtx, `SELECT email FROM users WHERE id = $1`, assigneeID).Scan(&assigneeEmail); err != nil {
+			notifyLogger.ErrorContext(bgCtx, "Failed to look up assignee email", "assigneeUserID", assigneeID, "error", err)
+			return
+		}
+		if err := h.emailService.SendTicketReopened(assigneeEmail, strconv.Itoa(int(ticketNumber)), subject); err != nil {
+			notifyLogger.ErrorContext(bgCtx, "Failed to send ticket reopened email", "recipient", assigneeEmail, "error", err)
+		}
+		message := fmt.Sprintf("Ticket #%d was reopened by the submitter", ticketNumber)
+		if err := h.CreateNotification(assigneeID, "ticket_reopened", message, &ticketID); err != nil {
+			notifyLogger.ErrorContext(bgCtx, "Failed to create reopen notification", "assigneeUserID", assigneeID, "error", err)
+		}
+	}(*st.AssignedToUserID, st.ID, st.TicketNumber, st.Subject)
+}
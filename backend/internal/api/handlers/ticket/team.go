@@ -0,0 +1,295 @@
+// backend/internal/api/handlers/ticket/team.go
+// ==========================================================================
+// Team assignment of tickets. A ticket can be assigned to a team as well as,
+// or instead of, an individual. Until a member claims it (POST
+// /tickets/:id/claim makes them the individual assignee), a team ticket sits in every member's "assigned to
+// me" queue, and only team members (and admins) can open it.
+// ==========================================================================
+
+package ticket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// In-app notification types sent to team members.
+const (
+	notificationTypeTeamAssignment = "team_assignment" // A ticket was assigned to the team
+	notificationTypeTeamClaim      = "team_claim"      // A member claimed one of the team's tickets
+)
+
+// teamMemberSQL is a SQL condition that is true when the user in the %s
+// placeholder belongs to the team of the ticket aliased t. Use with fmt.Sprintf.
+const teamMemberSQL = `EXISTS (SELECT 1 FROM team_members tm
+            WHERE tm.team_id = t.assigned_to_team_id AND tm.user_id = %s)`
+
+// --- Handler Functions ---
+
+// ClaimTicket lets a member of the ticket's team take individual ownership.
+// The claimer becomes the assignee (accepted immediately, as with any
+// self-assignment), the ticket keeps its team, and the other members are
+// notified in-app.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Returns:
+//   - JSON response with the updated ticket, 409 if it already has an
+//     individual assignee, or an error response.
+func (h *Handler) ClaimTicket(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "ClaimTicket", "ticketUUID", ticketID)
+
+	// --- 1. Get Requesting User ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+
+	// --- 2. Lock and Validate Ticket State ---
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error.")
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	var status models.TicketStatus
+	var assigneeID, teamID *string
+	err = tx.QueryRow(ctx, `
+        SELECT status, assigned_to_user_id, assigned_to_team_id
+        FROM tickets
+        WHERE id = $1
+        FOR UPDATE`, ticketID,
+	).Scan(&status, &assigneeID, &teamID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load ticket for claim", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to load ticket.")
+	}
+	if teamID == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Ticket is not assigned to a team.")
+	}
+	if assigneeID != nil {
+		return echo.NewHTTPError(http.StatusConflict, "Ticket is already assigned to an individual.")
+	}
+	if status == models.StatusClosed {
+		return echo.NewHTTPError(http.StatusBadRequest, "Closed tickets cannot be claimed.")
+	}
+	isMember, err := h.isTeamMember(ctx, teamID, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to check team membership", "teamID", *teamID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to claim ticket.")
+	}
+	if !isMember {
+		logger.WarnContext(ctx, "Non-member attempted to claim team ticket", "userID", userID, "teamID", *teamID)
+		return echo.NewHTTPError(http.StatusForbidden, "Only members of the assigned team can claim this ticket.")
+	}
+
+	// --- 3. Record Claim ---
+	if _, err := tx.Exec(ctx, `
+        UPDATE tickets SET assigned_to_user_id = $2, updated_at = NOW() WHERE id = $1`, ticketID, userID); err != nil {
+		logger.ErrorContext(ctx, "Failed to assign claimer", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to claim ticket.")
+	}
+	if err := applyAssignmentChange(ctx, tx, ticketID, nil, userID, userID); err != nil {
+		logger.ErrorContext(ctx, "Failed to record assignment change", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to claim ticket.")
+	}
+
+	claimerName := h.assigneeDisplayName(ctx, &userID)
+	teamName := h.teamDisplayName(ctx, teamID)
+	changes := []models.FieldChange{{
+		Field:       changeFieldAssignee,
+		To:          &userID,
+		FromDisplay: h.assigneeDisplayName(ctx, nil),
+		ToDisplay:   claimerName,
+	}}
+	summary := fmt.Sprintf("Ticket claimed by %s for team %s. %s", claimerName, teamName, describeChanges(changes))
+	if err := h.addHistoryEntry(ctx, tx, ticketID, userID, summary, changes); err != nil {
+		logger.ErrorContext(ctx, "Failed to add history entry", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to claim ticket.")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit claim", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to save claim.")
+	}
+
+	// --- 4. Notify Team and Return Updated Ticket (AFTER COMMIT) ---
+	updatedTicket, err := h.getTicketDetailsByID(ctx, ticketID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to fetch ticket after claim", "error", err)
+		return c.JSON(http.StatusOK, map[string]string{"message": "Ticket claimed, but failed to retrieve full details."})
+	}
+	h.notifyTeamClaim(updatedTicket, *teamID, userID, claimerName)
+	go h.webhooks.Dispatch(webhook.EventTicketUpdated, updatedTicket)
+
+	logger.InfoContext(ctx, "Team ticket claimed", "userID", userID, "teamID", *teamID)
+	return c.JSON(http.StatusOK, updatedTicket)
+}
+
+// --- Helper Functions ---
+
+// teamChanging reports whether the update would change the ticket's team.
+func teamChanging(currentState *models.TicketState, update *models.TicketStatusUpdate) bool {
+	if update.AssignedToTeamID == nil {
+		return false
+	}
+	newTeamID := *update.AssignedToTeamID
+	if newTeamID == "" {
+		return currentState.AssignedToTeamID != nil
+	}
+	return currentState.AssignedToTeamID == nil || *currentState.AssignedToTeamID != newTeamID
+}
+
+// isTeamMember reports whether the user belongs to the team. A nil team has
+// no members.
+func (h *Handler) isTeamMember(ctx context.Context, teamID *string, userID string) (bool, error) {
+	if teamID == nil || userID == "" {
+		return false, nil
+	}
+	var member bool
+	err := h.db.Pool.QueryRow(ctx, `
+        SELECT EXISTS (SELECT 1 FROM team_members WHERE team_id = $1 AND user_id = $2)`,
+		*teamID, userID).Scan(&member)
+	if err != nil {
+		return false, fmt.Errorf("failed to check team membership: %w", err)
+	}
+	return member, nil
+}
+
+// getTeamName fetches a team's name by its ID.
+func (h *Handler) getTeamName(ctx context.Context, teamID string) (string, error) {
+	var name string
+	err := h.db.Pool.QueryRow(ctx, `SELECT name FROM teams WHERE id = $1`, teamID).Scan(&name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", errors.New("team not found")
+		}
+		return "", fmt.Errorf("failed to fetch team name: %w", err)
+	}
+	return name, nil
+}
+
+// teamDisplayName returns the team's name for a change set, or the raw ID if
+// the team cannot be loaded. Callers only use it for non-nil teams.
+func (h *Handler) teamDisplayName(ctx context.Context, teamID *string) string {
+	if teamID == nil {
+		return ""
+	}
+	name, err := h.getTeamName(ctx, *teamID)
+	if err != nil {
+		slog.WarnContext(ctx, "Could not fetch team name for change description", "teamID", *teamID, "error", err)
+		return *teamID
+	}
+	return name
+}
+
+// notifyTeamAssignment tells the members of the ticket's new team (in-app and
+// by email) that a ticket is waiting for one of them to claim it. Nothing is
+// sent if the ticket already has an individual assignee, and the member who
+// made the change is skipped. Recipients' notification preferences apply.
+// Runs asynchronously; failures are only logged.
+//
+// Parameters:
+//   - updatedTicket: The ticket after the update.
+//   - actorUserID: The user who assigned the team.
+func (h *Handler) notifyTeamAssignment(updatedTicket *models.Ticket, actorUserID string) {
+	if updatedTicket.AssignedToTeamID == nil || updatedTicket.AssignedToUserID != nil {
+		return
+	}
+	teamID := *updatedTicket.AssignedToTeamID
+	logger := slog.With("operation", "NotifyTeamAssignment", "ticketID", updatedTicket.ID, "teamID", teamID)
+
+	go func() {
+		members, err := h.teamMembersExcept(context.Background(), teamID, actorUserID)
+		if err != nil {
+			logger.Error("Failed to load team members", "error", err)
+			return
+		}
+
+		teamName := "your team"
+		if updatedTicket.AssignedToTeam != nil {
+			teamName = updatedTicket.AssignedToTeam.Name
+		}
+		msg := fmt.Sprintf("Ticket #%d was assigned to %s and is waiting to be claimed", updatedTicket.TicketNumber, teamName)
+		for _, m := range members {
+			if err := h.CreateNotification(m.id, notificationTypeTeamAssignment, msg, &updatedTicket.ID); err != nil {
+				logger.Error("Failed to create team assignment notification", "memberUserID", m.id, "error", err)
+			}
+			if err := h.emailService.SendTicketAssignment(m.email, updatedTicket.ID, updatedTicket.Subject); err != nil {
+				logger.Error("Failed to send team assignment email", "memberUserID", m.id, "error", err)
+			}
+		}
+		logger.Info("Notified team of assignment", "members", len(members))
+	}()
+}
+
+// notifyTeamClaim tells the other members of the team (in-app) that a member
+// claimed one of its tickets. Runs asynchronously; failures are only logged.
+//
+// Parameters:
+//   - updatedTicket: The ticket after the claim.
+//   - teamID: The ticket's team.
+//   - claimerUserID: The member who claimed the ticket.
+//   - claimerName: The claimer's display name.
+func (h *Handler) notifyTeamClaim(updatedTicket *models.Ticket, teamID, claimerUserID, claimerName string) {
+	logger := slog.With("operation", "NotifyTeamClaim", "ticketID", updatedTicket.ID, "teamID", teamID)
+	go func() {
+		members, err := h.teamMembersExcept(context.Background(), teamID, claimerUserID)
+		if err != nil {
+			logger.Error("Failed to load team members", "error", err)
+			return
+		}
+		msg := fmt.Sprintf("Ticket #%d was claimed by %s", updatedTicket.TicketNumber, claimerName)
+		for _, m := range members {
+			if err := h.CreateNotification(m.id, notificationTypeTeamClaim, msg, &updatedTicket.ID); err != nil {
+				logger.Error("Failed to create team claim notification", "memberUserID", m.id, "error", err)
+			}
+		}
+		logger.Info("Notified team of claim", "members", len(members))
+	}()
+}
+
+// teamMember is a team member notification recipient.
+type teamMember struct {
+	id    string
+	email string
+}
+
+// teamMembersExcept loads the members of a team, leaving out excludeUserID
+// (typically the user who triggered the notification; "" excludes no one).
+func (h *Handler) teamMembersExcept(ctx context.Context, teamID, excludeUserID string) ([]teamMember, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT u.id, u.email
+        FROM team_members tm
+        JOIN users u ON u.id = tm.user_id
+        WHERE tm.team_id = $1 AND u.id::text <> $2`, teamID, excludeUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query team members: %w", err)
+	}
+	defer rows.Close()
+	var members []teamMember
+	for rows.Next() {
+		var m teamMember
+		if err := rows.Scan(&m.id, &m.email); err != nil {
+			return nil, fmt.Errorf("failed to scan team member: %w", err)
+		}
+		members = append(members, m)
+	}
+	return members, rows.Err()
+}
@@ -0,0 +1,277 @@
+// backend/internal/api/handlers/ticket/ticket_export.go
+// ==========================================================================
+// Handler for exporting a single ticket as a printable PDF document.
+// Rendering uses the pure-Go fpdf library so no external binaries are needed.
+// ==========================================================================
+
+package ticket
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/file"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// pdfTimeLayout is the timestamp format used throughout the exported document.
+const pdfTimeLayout = "2006-01-02 15:04 MST"
+
+// --- Handler Function ---
+
+// ExportTicketPDF renders a ticket (metadata, description, comment thread and
+// resolution) into a PDF and returns it as a download.
+// Internal notes are omitted unless an Admin explicitly asks for them with
+// include_internal=true; the document is then marked as containing them and
+// each internal note is labelled.
+//
+// Path Parameters:
+//   - id: The UUID of the ticket.
+//
+// Query Parameters:
+//   - include_internal: Optional ("true") to include internal notes (Admin only; 403 otherwise).
+//
+// Returns:
+//   - The PDF document (application/pdf) or an error response.
+func (h *Handler) ExportTicketPDF(c echo.Context) error {
+	ctx := c.Request().Context()
+	ticketID := c.Param("id")
+	logger := slog.With("handler", "ExportTicketPDF", "ticketUUID", ticketID)
+
+	// --- 1. Authorization Check ---
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
+	if err != nil {
+		return err
+	}
+	ticket, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin)
+	if err != nil {
+		logger.WarnContext(ctx, "Authorization check failed for ticket export", "error", err)
+		if err.Error() == "ticket not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found.")
+		}
+		if err.Error() == "not authorized to access this ticket" {
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to export this ticket.")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify ticket access.")
+	}
+
+	// --- 2. Fetch Comment Thread ---
+	includeInternal := c.QueryParam("include_internal") == "true"
+	if includeInternal && userRole != models.RoleAdmin {
+		logger.WarnContext(ctx, "Non-admin requested internal notes in ticket export", "role", userRole)
+		return echo.NewHTTPError(http.StatusForbidden, "Only admins can export internal notes.")
+	}
+	updates, err := h.fetchTicketThread(ctx, ticketID, includeInternal)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to fetch comment thread for export", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to load ticket comments.")
+	}
+	ticket.Updates = updates
+
+	// --- 3. Render Document ---
+	var buf bytes.Buffer
+	if err := h.renderTicketPDF(&buf, &ticket); err != nil {
+		logger.ErrorContext(ctx, "Failed to render ticket PDF", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate PDF.")
+	}
+
+	// --- 4. Send Response ---
+	filename := fmt.Sprintf("ticket-%d.pdf", ticket.TicketNumber)
+	c.Response().Header().Set(echo.HeaderContentDisposition, file.ContentDisposition("attachment", filename))
+	logger.InfoContext(ctx, "Ticket exported to PDF", "ticketNumber", ticket.TicketNumber, "comments", len(updates), "bytes", buf.Len())
+	return c.Blob(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// --- Helper Functions ---
+
+// fetchTicketThread loads a ticket's comments in chronological order.
+// When includeInternal is false, internal notes (including system updates) are excluded.
+// Deleted (tombstoned) comments are always excluded.
+func (h *Handler) fetchTicketThread(ctx context.Context, ticketID string, includeInternal bool) ([]models.TicketUpdate, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT tu.id, tu.ticket_id, tu.user_id, tu.comment, tu.is_internal_note, tu.is_system_update, tu.from_submitter, tu.created_at,
+               u.name
+        FROM ticket_updates tu
+        LEFT JOIN users u ON tu.user_id = u.id
+        WHERE tu.ticket_id = $1 AND ($2 OR tu.is_internal_note = false) AND tu.deleted_at IS NULL
+        ORDER BY tu.created_at ASC`, ticketID, includeInternal)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := make([]models.TicketUpdate, 0)
+	for rows.Next() {
+		var update models.TicketUpdate
+		var authorName *string
+		if err := rows.Scan(
+			&update.ID, &update.TicketID, &update.UserID, &update.Comment,
+			&update.IsInternalNote, &update.IsSystemUpdate, &update.FromSubmitter, &update.CreatedAt,
+			&authorName,
+		); err != nil {
+			return nil, err
+		}
+		switch {
+		case authorName != nil:
+			update.User = &models.User{Name: *authorName}
+		case update.FromSubmitter:
+			update.User = &models.User{Name: "Submitter"}
+		case update.UserID == nil:
+			update.User = &models.User{Name: "System"}
+		default:
+			update.User = &models.User{Name: "Unknown User"}
+		}
+		updates = append(updates, update)
+	}
+	return updates, rows.Err()
+}
+
+// renderTicketPDF writes the PDF representation of a ticket to buf.
+func (h *Handler) renderTicketPDF(buf *bytes.Buffer, ticket *models.Ticket) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	// Core fonts are cp1252; translate UTF-8 input so accented characters survive.
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	orgName := "IT Helpdesk"
+	logoPath := ""
+	if h.config != nil {
+		if h.config.Branding.OrgName != "" {
+			orgName = h.config.Branding.OrgName
+		}
+		logoPath = h.config.Branding.LogoPath
+	}
+
+	pdf.SetTitle(tr(fmt.Sprintf("Ticket #%d - %s", ticket.TicketNumber, ticket.Subject)), false)
+	pdf.SetAuthor(tr(orgName), false)
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-15)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.SetTextColor(128, 128, 128)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Ticket #%d - Page %d", ticket.TicketNumber, pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	// --- Header (Logo + Organization Name) ---
+	headerX := 10.0
+	if logoPath != "" {
+		if _, err := os.Stat(logoPath); err == nil {
+			pdf.ImageOptions(logoPath, 10, 10, 0, 14, false, fpdf.ImageOptions{ReadDpi: true}, 0, "")
+			headerX = 30
+		} else {
+			slog.Warn("Configured logo not readable, rendering PDF without it", "logoPath", logoPath, "error", err)
+		}
+	}
+	pdf.SetXY(headerX, 12)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, tr(orgName), "", 1, "L", false, 0, "")
+	pdf.SetY(28)
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.Line(10, pdf.GetY(), 200, pdf.GetY())
+	pdf.Ln(4)
+
+	// --- Title ---
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.MultiCell(0, 7, tr(fmt.Sprintf("Ticket #%d: %s", ticket.TicketNumber, ticket.Subject)), "", "L", false)
+	pdf.Ln(2)
+
+	// --- Metadata ---
+	submitter := ticket.EndUserEmail
+	if ticket.SubmitterName != nil && *ticket.SubmitterName != "" {
+		submitter = fmt.Sprintf("%s <%s>", *ticket.SubmitterName, ticket.EndUserEmail)
+	}
+	issueType := ticket.IssueType
+	if issueType == "" {
+		issueType = "-"
+	}
+	assignee := "Unassigned"
+	if ticket.AssignedToUser != nil {
+		assignee = ticket.AssignedToUser.Name
+	}
+	metadata := [][2]string{
+		{"Status", string(ticket.Status)},
+		{"Urgency", string(ticket.Urgency)},
+		{"Issue Type", issueType},
+		{"Submitter", submitter},
+		{"Assigned To", assignee},
+		{"Created", ticket.CreatedAt.Format(pdfTimeLayout)},
+		{"Last Updated", ticket.UpdatedAt.Format(pdfTimeLayout)},
+	}
+	if ticket.ClosedAt != nil {
+		metadata = append(metadata, [2]string{"Closed", ticket.ClosedAt.Format(pdfTimeLayout)})
+	}
+	for _, row := range metadata {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(35, 6, tr(row[0]+":"), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 6, tr(row[1]), "", "L", false)
+	}
+	pdf.Ln(4)
+
+	// --- Description ---
+	writePDFSection(pdf, tr, "Description", ticket.Description)
+
+	// --- Comment Thread ---
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, "Comments", "", 1, "L", false, 0, "")
+	for _, update := range ticket.Updates {
+		if update.IsInternalNote {
+			pdf.SetFont("Helvetica", "B", 10)
+			pdf.SetTextColor(180, 0, 0)
+			pdf.MultiCell(0, 5, "This export contains internal staff notes. Do not share it with the submitter.", "1", "L", false)
+			pdf.SetTextColor(0, 0, 0)
+			pdf.Ln(3)
+			break
+		}
+	}
+	if len(ticket.Updates) == 0 {
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, 6, "No comments.", "", 1, "L", false, 0, "")
+	}
+	for _, update := range ticket.Updates {
+		heading := fmt.Sprintf("%s - %s", update.User.Name, update.CreatedAt.Format(pdfTimeLayout))
+		if update.IsInternalNote {
+			heading = "INTERNAL NOTE - " + heading
+			pdf.SetTextColor(180, 0, 0)
+		}
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(0, 6, tr(heading), "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, 5, tr(strings.TrimSpace(update.Comment)), "", "L", false)
+		pdf.Ln(3)
+	}
+	pdf.Ln(2)
+
+	// --- Resolution ---
+	if ticket.ResolutionNotes != nil && strings.TrimSpace(*ticket.ResolutionNotes) != "" {
+		writePDFSection(pdf, tr, "Resolution", *ticket.ResolutionNotes)
+	}
+
+	// --- Generation Footer ---
+	pdf.SetFont("Helvetica", "I", 8)
+	pdf.SetTextColor(128, 128, 128)
+	pdf.CellFormat(0, 6, "Generated "+time.Now().Format(pdfTimeLayout), "", 1, "R", false, 0, "")
+
+	return pdf.Output(buf)
+}
+
+// writePDFSection renders a bold section heading followed by wrapped body text.
+func writePDFSection(pdf *fpdf.Fpdf, tr func(string) string, title, body string) {
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 8, tr(title), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.MultiCell(0, 5, tr(strings.TrimSpace(body)), "", "L", false)
+	pdf.Ln(4)
+}
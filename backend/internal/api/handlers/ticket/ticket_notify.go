@@ -8,16 +8,23 @@ package ticket
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"strconv"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preferences"
 )
 
 // CreateNotification inserts a notification for a user (used by ticket updates).
+// Nothing is inserted if the user turned off in-app notifications for the
+// category the type belongs to.
 func (h *Handler) CreateNotification(userID, notifType, message string, relatedTicketID *string) error {
 	_, err := h.db.Pool.Exec(
 		context.Background(),
-		`INSERT INTO notifications (user_id, type, message, related_ticket_id) VALUES ($1, $2, $3, $4)`,
-		userID, notifType, message, relatedTicketID,
+		`INSERT INTO notifications (user_id, type, message, related_ticket_id)
+         SELECT $1, $2, $3, $4
+         WHERE NOT `+fmt.Sprintf(preferences.InAppOptedOutSQL, "$1::uuid", "$5"),
+		userID, notifType, message, relatedTicketID, preferences.CategoryForNotificationType(notifType),
 	)
 	return err
 }
@@ -30,3 +37,54 @@ func (h *Handler) triggerUpdateNotifications(currentState *models.TicketState, u
 	}
 	// Add more notification logic as needed
 }
+
+// notificationTypeUrgencyChange is the in-app notification type for urgency changes.
+const notificationTypeUrgencyChange = "urgency_change"
+
+// notifyUrgencyChange tells the submitter (by email, unless the ticket is
+// internal) and the assignee (in-app and by email, unless they made the
+// change) that the ticket's urgency changed. TICKET_URGENCY_CHANGE_NOTIFY
+// selects whether only increases or every change is reported. Recipients'
+// notification preferences apply. Runs asynchronously; failures are only logged.
+//
+// Parameters:
+//   - currentState: The ticket before the update.
+//   - updatedTicket: The ticket after the update.
+//   - actorUserID: The user who made the change.
+func (h *Handler) notifyUrgencyChange(currentState *models.TicketState, updatedTicket *models.Ticket, actorUserID string) {
+	from, to := currentState.Urgency, updatedTicket.Urgency
+	if from == to {
+		return
+	}
+	switch h.config.Tickets.UrgencyChangeNotify {
+	case "off":
+		return
+	case "increase":
+		if urgencySeverity(to) <= urgencySeverity(from) {
+			return
+		}
+	}
+
+	ticketNumber := strconv.Itoa(int(updatedTicket.TicketNumber))
+	logger := slog.With("operation", "NotifyUrgencyChange", "ticketID", updatedTicket.ID, "from", from, "to", to)
+
+	if !currentState.IsInternal {
+		go func(recipient, locale string) {
+			if err := h.emailService.SendTicketUrgencyChanged(recipient, ticketNumber, updatedTicket.Subject, string(from), string(to), locale); err != nil {
+				logger.Error("Failed to send urgency change email to submitter", "error", err)
+			}
+		}(currentState.EndUserEmail, currentState.Locale)
+	}
+
+	if assignee := updatedTicket.AssignedToUser; assignee != nil && assignee.ID != actorUserID {
+		go func(assigneeID, assigneeEmail string) {
+			msg := fmt.Sprintf("Ticket #%s urgency changed from %s to %s", ticketNumber, from, to)
+			if err := h.CreateNotification(assigneeID, notificationTypeUrgencyChange, msg, &updatedTicket.ID); err != nil {
+				logger.Error("Failed to create urgency change notification", "assigneeUserID", assigneeID, "error", err)
+			}
+			if err := h.emailService.SendTicketUrgencyChanged(assigneeEmail, ticketNumber, updatedTicket.Subject, string(from), string(to), ""); err != nil {
+				logger.Error("Failed to send urgency change email to assignee", "assigneeUserID", assigneeID, "error", err)
+			}
+		}(assignee.ID, assignee.Email)
+	}
+}
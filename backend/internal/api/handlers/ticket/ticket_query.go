@@ -10,15 +10,21 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json" // Added for JSON unmarshalling
-	"errors"
 	"fmt"
 	"log/slog" // Use slog
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/etag"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Correct models import
+	"github.com/henrythedeveloper/it-ticket-system/internal/pagination"
+	"github.com/henrythedeveloper/it-ticket-system/internal/sla"
+	"github.com/henrythedeveloper/it-ticket-system/internal/timezone"
 	"github.com/jackc/pgx/v5"                                       // Correct pgx import
 	"github.com/labstack/echo/v4"                                   // Correct echo import
 	// Helper function import assumed from utils.go in the same package
@@ -27,27 +33,38 @@ import (
 // --- QUERY OPERATIONS ---
 
 // GetAllTickets retrieves a list of tickets based on query parameters for filtering and pagination.
+// limit defaults to 15 and is clamped to API_MAX_PAGE_SIZE. Non-admins only see the tickets
+// checkTicketAccess would let them open.
 // *** REVISED: Now fetches assignee details and tags for the list view. ***
 func (h *Handler) GetAllTickets(c echo.Context) error {
-	ctx := context.Background() // Use context.Background() if no request-specific context needed for db calls
+	ctx := c.Request().Context()
 	logger := slog.With("handler", "GetAllTickets")
 
 	// --- Parameter Parsing (remains the same) ---
 	status := c.QueryParam("status")
 	assignedTo := c.QueryParam("assigned_to")
+	team := c.QueryParam("team")
 	submitterID := c.QueryParam("submitter_id")
 	limitStr := c.QueryParam("limit")
 	pageStr := c.QueryParam("page")
 	tagParam := c.QueryParam("tags")
 	sortBy := c.QueryParam("sortBy")
+	if sortBy == "" {
+		sortBy = c.QueryParam("sort_by") // snake_case alias, matching the other list parameters
+	}
 	sortOrder := c.QueryParam("sortOrder")
-
-	limit := 15
-	if limitStr != "" {
-		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 100 {
-			limit = parsedLimit
-		}
+	if sortOrder == "" {
+		sortOrder = c.QueryParam("sort_order")
+	}
+	fromDate := c.QueryParam("from_date")
+	toDate := c.QueryParam("to_date")
+	due := c.QueryParam("due")
+	fields, fieldsErr := parseListFields(c.QueryParam("fields")) // Optional compact projection
+	if fieldsErr != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid fields: "+fieldsErr.Error())
 	}
+
+	limit := pagination.Limit(limitStr, 15, h.config.Server.MaxPageSize)
 	page := 1
 	if pageStr != "" {
 		if parsedPage, err := strconv.Atoi(pageStr); err == nil && parsedPage > 0 {
@@ -62,7 +79,8 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 	selectClause := `
 		SELECT
 			t.id, t.ticket_number, t.subject, t.description, t.status, t.urgency, t.created_at, t.updated_at,
-			t.submitter_name, t.end_user_email, t.assigned_to_user_id,
+			t.submitter_name, t.end_user_email, t.assigned_to_user_id, t.is_internal, t.due_date,
+			t.assigned_to_team_id, (SELECT name FROM teams WHERE id = t.assigned_to_team_id) AS assigned_team_name,
 			-- Assignee details (use COALESCE for NULL safety if needed, though LEFT JOIN handles it)
 			a.id AS assigned_user_id_val,
 			a.name AS assigned_user_name,
@@ -84,10 +102,21 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 
 	// --- Filtering Logic ---
 	args := []interface{}{}
-	whereClauses := []string{}
+	whereClauses := []string{"t.quarantined_at IS NULL", "t.spam_at IS NULL"} // Quarantined and spam tickets are only listed for admin review
 	joinClausesForFilter := "" // To add joins needed ONLY for filtering (tags)
 	argIdx := 1
 
+	// Access Scope (admins see every ticket)
+	if role, _ := auth.GetUserRoleFromContext(c); role != models.RoleAdmin {
+		userID, userErr := auth.GetUserIDFromContext(c)
+		if userErr != nil {
+			return userErr
+		}
+		whereClauses = append(whereClauses, db.TicketAccessCondition("t", fmt.Sprintf("$%d", argIdx)))
+		args = append(args, userID)
+		argIdx++
+	}
+
 	// Status Filter
 	if status != "" {
 		if strings.ToLower(status) == "unassigned" {
@@ -110,21 +139,94 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 	}
 	// AssignedTo Filter
 	if assignedTo != "" {
-		if strings.ToLower(assignedTo) == "unassigned" {
+		switch strings.ToLower(assignedTo) {
+		case "unassigned": // Explicit triage view of the unassigned pool
 			whereClauses = append(whereClauses, "t.assigned_to_user_id IS NULL")
-		} else {
-			// Note: Handle "me" logic if needed, usually involves getting user ID from context
+		case "me": // Personal view, plus unclaimed tickets of the user's teams; the unassigned pool is included for admins and, if configured, staff
+			userID, userErr := auth.GetUserIDFromContext(c)
+			if userErr != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Authentication required for assigned_to=me.")
+			}
+			role, _ := auth.GetUserRoleFromContext(c)
+			teamQueue := "(t.assigned_to_user_id IS NULL AND " + fmt.Sprintf(teamMemberSQL, fmt.Sprintf("$%d", argIdx)) + ")"
+			switch {
+			case role == models.RoleAdmin:
+				whereClauses = append(whereClauses, fmt.Sprintf("(t.assigned_to_user_id = $%d OR t.assigned_to_user_id IS NULL)", argIdx))
+			case h.config.Tickets.MyScopeIncludesUnassigned: // Other teams' tickets are not in the staff pool
+				whereClauses = append(whereClauses, fmt.Sprintf("(t.assigned_to_user_id = $%d OR %s OR (t.assigned_to_user_id IS NULL AND t.assigned_to_team_id IS NULL))", argIdx, teamQueue))
+			default:
+				whereClauses = append(whereClauses, fmt.Sprintf("(t.assigned_to_user_id = $%d OR %s)", argIdx, teamQueue))
+			}
+			args = append(args, userID)
+			argIdx++
+		default:
 			whereClauses = append(whereClauses, fmt.Sprintf("t.assigned_to_user_id = $%d", argIdx))
 			args = append(args, assignedTo)
 			argIdx++
 		}
 	}
+	// Team Filter (a team ID, "mine" for the requester's teams, or "none")
+	if team != "" {
+		switch strings.ToLower(team) {
+		case "none":
+			whereClauses = append(whereClauses, "t.assigned_to_team_id IS NULL")
+		case "mine":
+			userID, userErr := auth.GetUserIDFromContext(c)
+			if userErr != nil {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Authentication required for team=mine.")
+			}
+			whereClauses = append(whereClauses, fmt.Sprintf(teamMemberSQL, fmt.Sprintf("$%d", argIdx)))
+			args = append(args, userID)
+			argIdx++
+		default:
+			whereClauses = append(whereClauses, fmt.Sprintf("t.assigned_to_team_id = $%d", argIdx))
+			args = append(args, team)
+			argIdx++
+		}
+	}
 	// SubmitterID Filter
 	if submitterID != "" {
 		whereClauses = append(whereClauses, fmt.Sprintf("t.submitter_id = $%d", argIdx))
 		args = append(args, submitterID)
 		argIdx++
 	}
+	// Created Date Range Filter (boundaries are calendar days in the requester's timezone)
+	if fromDate != "" || toDate != "" {
+		loc := timezone.RequestLocation(c, h.db)
+		if fromDate != "" {
+			from, parseErr := timezone.ParseBoundary(fromDate, loc, false)
+			if parseErr != nil {
+				logger.WarnContext(ctx, "Invalid from_date parameter", "from_date", fromDate, "error", parseErr)
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid from_date: " + parseErr.Error())
+			}
+			whereClauses = append(whereClauses, fmt.Sprintf("t.created_at >= $%d", argIdx))
+			args = append(args, from)
+			argIdx++
+		}
+		if toDate != "" {
+			to, parseErr := timezone.ParseBoundary(toDate, loc, true)
+			if parseErr != nil {
+				logger.WarnContext(ctx, "Invalid to_date parameter", "to_date", toDate, "error", parseErr)
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid to_date: " + parseErr.Error())
+			}
+			whereClauses = append(whereClauses, fmt.Sprintf("t.created_at < $%d", argIdx))
+			args = append(args, to)
+			argIdx++
+		}
+		logger.DebugContext(ctx, "Applied created date range filter", "from_date", fromDate, "to_date", toDate, "timezone", loc.String())
+	}
+	// Due Date Bucket Filter (overdue, today, week, later, none; "today" ends at midnight in the requester's timezone)
+	if due != "" {
+		dueClause, dueArgs, dueErr := buildDueFilter(due, time.Now(), timezone.RequestLocation(c, h.db), argIdx)
+		if dueErr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid due: "+dueErr.Error())
+		}
+		if dueClause != "" {
+			whereClauses = append(whereClauses, dueClause)
+			args = append(args, dueArgs...)
+			argIdx += len(dueArgs)
+		}
+	}
 	// Tag Filter (Add JOIN only if filtering by tags)
 	if tagParam != "" {
 		tags := strings.Split(tagParam, ",")
@@ -134,7 +236,7 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 			trimmedTag := strings.TrimSpace(tag)
 			if trimmedTag != "" {
 				tagPlaceholders = append(tagPlaceholders, fmt.Sprintf("$%d", argIdx))
-				args = append(args, trimmedTag)
+				args = append(args, strings.ToLower(trimmedTag))
 				argIdx++
 				validTags = append(validTags, trimmedTag)
 			}
@@ -142,7 +244,7 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 		if len(tagPlaceholders) > 0 {
 			// Add JOIN to main query's from clause *and* the count query's from clause
 			joinClausesForFilter = ` JOIN ticket_tags tt_filter ON t.id = tt_filter.ticket_id JOIN tags tg_filter ON tt_filter.tag_id = tg_filter.id `
-			whereClauses = append(whereClauses, fmt.Sprintf("tg_filter.name IN (%s)", strings.Join(tagPlaceholders, ", ")))
+			whereClauses = append(whereClauses, fmt.Sprintf("LOWER(tg_filter.name) IN (%s)", strings.Join(tagPlaceholders, ", ")))
 			countFromClause += joinClausesForFilter // Add join to count query as well
 		}
 	}
@@ -160,19 +262,33 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 	err := h.db.Pool.QueryRow(ctx, totalQuery, args...).Scan(&totalCount)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to fetch ticket count", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch ticket count"})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch ticket count")
 	}
 	logger.DebugContext(ctx, "Total tickets count", "count", totalCount)
 
+	// Count-only mode: the total for the filters without fetching any rows
+	if c.QueryParam("count_only") == "true" {
+		logger.InfoContext(ctx, "Counted tickets", "total", totalCount)
+		return c.JSON(http.StatusOK, models.PaginatedResponse{Success: true, Data: []models.Ticket{}, Total: totalCount, Page: page, Limit: limit})
+	}
+
 	// Sorting Logic
-	orderByClause := " ORDER BY t.updated_at DESC" // Default sort
-	validSortColumns := map[string]string{"createdAt": "t.created_at", "updatedAt": "t.updated_at", "ticketNumber": "t.ticket_number", "status": "t.status", "urgency": "t.urgency"} // Map frontend name to DB column
-	if col, ok := validSortColumns[sortBy]; ok {
-		order := "DESC"
-		if strings.ToLower(sortOrder) == "asc" {
-			order = "ASC"
+	orderByClause := buildTicketOrderBy(sortBy, sortOrder, c.QueryParam("nulls"), c.QueryParam("thenBy"), h.config.Tickets.SecondarySort)
+	totalPages := 0
+	if limit > 0 {
+		totalPages = (totalCount + limit - 1) / limit
+	}
+	hasMore := page < totalPages
+
+	// Compact projection: only the requested columns, joins and aggregations
+	if fields != nil {
+		projected, err := h.queryProjectedTickets(ctx, fields, joinClausesForFilter, whereClause, orderByClause, append(args, limit, offset), argIdx)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to fetch projected tickets", "fields", fields, "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch tickets")
 		}
-		orderByClause = fmt.Sprintf(" ORDER BY %s %s, t.id %s", col, order, order) // Add t.id for stable sort
+		logger.InfoContext(ctx, "Fetched tickets successfully", "count", len(projected), "total", totalCount, "page", page, "fields", fields)
+		return c.JSON(http.StatusOK, models.PaginatedResponse{Success: true, Data: projected, Total: totalCount, Page: page, Limit: limit, TotalPages: totalPages, HasMore: hasMore})
 	}
 
 	// Data Query (Add GROUP BY clause for tag aggregation)
@@ -188,7 +304,7 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 	rows, err := h.db.Pool.Query(ctx, dataQuery, dataArgs...)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to fetch tickets", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch tickets"})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch tickets")
 	}
 	defer rows.Close()
 
@@ -199,6 +315,7 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 		var tagsJSON []byte                // Variable to scan tags JSON
 		var assignedUserIDVal *string      // Pointer for assignee ID
 		var assignedUserNameVal *string    // Pointer for assignee name
+		var assignedTeamNameVal *string    // Pointer for team name
 		var submitterNameNullable sql.NullString // Use sql.NullString for submitter name
 
 		// *** REVISED: Add scan destinations for new fields ***
@@ -208,6 +325,10 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 			&submitterNameNullable, // Scan into sql.NullString
 			&ticket.EndUserEmail,
 			&ticket.AssignedToUserID, // Scan FK ID directly
+			&ticket.IsInternal,
+			&ticket.DueDate,
+			&ticket.AssignedToTeamID,
+			&assignedTeamNameVal,
 			&assignedUserIDVal,       // Scan assignee ID from JOIN
 			&assignedUserNameVal,     // Scan assignee Name from JOIN
 			&tagsJSON,                // Scan aggregated tags JSON
@@ -217,7 +338,7 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 		if err != nil {
 			logger.ErrorContext(ctx, "Failed to scan ticket row", "error", err)
 			// Continue scanning other rows? Or return error? Returning error.
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to parse ticket data"})
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to parse ticket data")
 		}
 
 		// Populate SubmitterName from nullable type
@@ -236,6 +357,10 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 			ticket.AssignedToUser = nil // Explicitly set to nil if no assignee
 		}
 
+		if ticket.AssignedToTeamID != nil && assignedTeamNameVal != nil {
+			ticket.AssignedToTeam = &models.Team{ID: *ticket.AssignedToTeamID, Name: *assignedTeamNameVal}
+		}
+
 		// *** REVISED: Unmarshal Tags JSON ***
 		if err := json.Unmarshal(tagsJSON, &ticket.Tags); err != nil {
 			logger.ErrorContext(ctx, "Failed to unmarshal tags JSON", "ticketID", ticket.ID, "error", err)
@@ -246,215 +371,124 @@ func (h *Handler) GetAllTickets(c echo.Context) error {
 	}
 	if err := rows.Err(); err != nil {
 		logger.ErrorContext(ctx, "Error iterating ticket rows", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Error processing ticket results"})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Error processing ticket results")
 	}
 
 	// --- Return Response ---
-	totalPages := 0
-	if limit > 0 {
-		totalPages = (totalCount + limit - 1) / limit
-	}
-	hasMore := page < totalPages
 	response := models.PaginatedResponse{Success: true, Data: tickets, Total: totalCount, Page: page, Limit: limit, TotalPages: totalPages, HasMore: hasMore}
 	logger.InfoContext(ctx, "Fetched tickets successfully", "count", len(tickets), "total", totalCount, "page", page)
 	return c.JSON(http.StatusOK, response)
 }
 
 // GetTicketByID retrieves details for a single ticket, including related data like updates, tags, and attachments.
+// The response carries an ETag; a request whose If-None-Match names it gets 304 Not Modified.
+// Non-admins get 403 for tickets outside checkTicketAccess's scope.
 func (h *Handler) GetTicketByID(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	ticketID := c.Param("id")
 	logger := slog.With("handler", "GetTicketByID", "ticketID", ticketID)
 
-	// --- 1. Fetch Core Ticket Data + User Joins ---
-	ticketQuery := `
-        SELECT
-            t.id, t.ticket_number, t.submitter_name, t.end_user_email, t.issue_type, t.urgency, t.subject,
-            t.description, t.status, t.assigned_to_user_id, t.created_at, t.updated_at,
-            t.closed_at, t.resolution_notes,
-            -- Assigned user details (nullable)
-            a.id as assigned_user_id, a.name as assigned_user_name, a.email as assigned_user_email,
-            a.role as assigned_user_role, a.created_at as assigned_user_created_at, a.updated_at as assigned_user_updated_at,
-            -- Submitter details (nullable)
-            s.id as submitter_user_id, s.name as submitter_user_name, s.email as submitter_user_email,
-            s.role as submitter_user_role, s.created_at as submitter_user_created_at, s.updated_at as submitter_user_updated_at
-        FROM tickets t
-        LEFT JOIN users a ON t.assigned_to_user_id = a.id
-        LEFT JOIN users s ON t.end_user_email = s.email -- Join submitter based on email
-        WHERE t.id = $1`
-	row := h.db.Pool.QueryRow(ctx, ticketQuery, ticketID)
-
-	// Use the scanner helper from utils.go
-	ticket, err := scanTicketWithUsersAndSubmitter(row) // Ensure scanner in utils.go is correct
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	userRole, err := auth.GetUserRoleFromContext(c)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			logger.WarnContext(ctx, "Ticket not found")
-			return c.JSON(http.StatusNotFound, map[string]string{"error": "Ticket not found"})
+		return err
+	}
+
+	// --- 1. Fetch Core Ticket Data + User Joins (with the access check) ---
+	ticket, err := h.checkTicketAccess(ctx, ticketID, userID, userRole == models.RoleAdmin)
+	if err != nil {
+		if err.Error() == "ticket not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "Ticket not found")
+		}
+		if err.Error() == "not authorized to access this ticket" {
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to view this ticket.")
 		}
 		logger.ErrorContext(ctx, "Failed to fetch core ticket details", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch ticket details"})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch ticket details")
 	}
 
 	// --- 2. Fetch Tags ---
-	tagsQuery := `
-        SELECT tg.id, tg.name, tg.created_at
-        FROM tags tg
-        JOIN ticket_tags tt ON tg.id = tt.tag_id
-        WHERE tt.ticket_id = $1
-        ORDER BY tg.name ASC`
-	tagsRows, tagsErr := h.db.Pool.Query(ctx, tagsQuery, ticketID)
+	tags, tagsErr := h.fetchTicketTags(ctx, ticketID)
 	// Handle tags error (log but continue)
 	if tagsErr != nil {
 		logger.ErrorContext(ctx, "Failed to query tags for ticket", "error", tagsErr)
 		ticket.Tags = []models.Tag{}
 	} else {
-		defer tagsRows.Close()
-		tags := make([]models.Tag, 0)
-		for tagsRows.Next() {
-			var tag models.Tag
-			if scanErr := tagsRows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt); scanErr != nil {
-				logger.ErrorContext(ctx, "Failed to scan tag row", "error", scanErr)
-				continue
-			}
-			tags = append(tags, tag)
-		}
-		if rowsErr := tagsRows.Err(); rowsErr != nil {
-			logger.ErrorContext(ctx, "Error iterating tag rows", "error", rowsErr)
-		}
 		ticket.Tags = tags
 		logger.DebugContext(ctx, "Fetched associated tags", "count", len(ticket.Tags))
 	}
 
 	// --- 3. Fetch Attachments ---
-	attachmentsQuery := `
-        SELECT id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role, url
-        FROM attachments
-        WHERE ticket_id = $1
-        ORDER BY uploaded_at ASC`
-	attachRows, attachErr := h.db.Pool.Query(ctx, attachmentsQuery, ticketID)
+	attachments, attachErr := h.fetchTicketAttachments(ctx, ticketID)
 	// Handle attachments error (log but continue)
 	if attachErr != nil {
 		logger.ErrorContext(ctx, "Failed to query attachments for ticket", "error", attachErr)
 		ticket.Attachments = []models.Attachment{}
 	} else {
-		defer attachRows.Close()
-		attachments := make([]models.Attachment, 0)
-		for attachRows.Next() {
-			var att models.Attachment
-			// Use pointers/nullable types for potentially NULL columns
-			var uploadedByUserID sql.NullString // Use sql.NullString
-			var uploadedByRole sql.NullString   // Use sql.NullString
-			var url sql.NullString             // Use sql.NullString
-
-			if scanErr := attachRows.Scan(
-				&att.ID, &att.Filename, &att.StoragePath, &att.MimeType, &att.Size,
-				&att.UploadedAt,
-				&uploadedByUserID, // Scan into nullable type
-				&uploadedByRole,   // Scan into nullable type
-				&url,              // Scan into nullable type
-			); scanErr != nil {
-				logger.ErrorContext(ctx, "Failed to scan attachment row", "error", scanErr)
-				continue // Skip this attachment if scanning fails
-			}
-
-			// Assign values from nullable types if valid
-			if uploadedByUserID.Valid {
-				att.UploadedByUserID = uploadedByUserID.String
-			}
-			if uploadedByRole.Valid {
-				att.UploadedByRole = uploadedByRole.String
-			}
-			if url.Valid {
-				att.URL = url.String
-			}
-
-			// Generate download URL if not present in DB (optional fallback)
-			if att.URL == "" {
-				att.URL = fmt.Sprintf("/api/attachments/download/%s", att.ID)
-			}
-			attachments = append(attachments, att)
-		}
-		if rowsErr := attachRows.Err(); rowsErr != nil {
-			logger.ErrorContext(ctx, "Error iterating attachment rows", "error", rowsErr)
-		}
 		ticket.Attachments = attachments
 		logger.DebugContext(ctx, "Fetched associated attachments", "count", len(ticket.Attachments))
 	}
 
 	// --- 4. Fetch Updates (Comments) ---
-	updatesQuery := `
-        SELECT
-            tu.id, tu.ticket_id, tu.user_id, tu.comment, tu.is_internal_note, tu.created_at, tu.is_system_update,
-            u.id, u.name, u.email, u.role, u.created_at, u.updated_at
-        FROM ticket_updates tu
-        LEFT JOIN users u ON tu.user_id = u.id
-        WHERE tu.ticket_id = $1
-        ORDER BY tu.created_at DESC
-    `
-	updatesRows, updatesErr := h.db.Pool.Query(ctx, updatesQuery, ticketID)
+	updates, updatesErr := h.fetchTicketUpdates(ctx, ticketID)
 	// Handle updates error (log but continue)
 	if updatesErr != nil {
 		logger.ErrorContext(ctx, "Failed to query updates for ticket", "error", updatesErr)
 		ticket.Updates = []models.TicketUpdate{}
 	} else {
-		defer updatesRows.Close()
-		updates := make([]models.TicketUpdate, 0)
-		for updatesRows.Next() {
-			var update models.TicketUpdate
-			var user models.User
-			var updateUserID *string
-			var userName, userEmail, userRole *string
-			var userCreatedAt, userUpdatedAt *time.Time
-
-			scanErr := updatesRows.Scan(
-				&update.ID, &update.TicketID, &updateUserID, &update.Comment,
-				&update.IsInternalNote, &update.CreatedAt, &update.IsSystemUpdate,
-				&user.ID, &userName, &userEmail, &userRole,
-				&userCreatedAt, &userUpdatedAt,
-			)
-			if scanErr != nil {
-				logger.ErrorContext(ctx, "Failed to scan ticket update row", "error", scanErr)
-				continue
-			}
-			if updateUserID != nil {
-				update.UserID = updateUserID
-				if userName != nil {
-					user.Name = *userName
-					user.Email = *userEmail
-					user.Role = models.UserRole(*userRole)
-					user.CreatedAt = *userCreatedAt
-					user.UpdatedAt = *userUpdatedAt
-					update.User = &user
-				} else {
-					update.User = &models.User{ID: *updateUserID, Name: "Unknown User"}
-					logger.WarnContext(ctx, "User details not found for update author", "authorUserID", *updateUserID)
-				}
-			} else {
-				update.User = &models.User{Name: "System"}
-			}
-			updates = append(updates, update)
-		}
-		if rowsErr := updatesRows.Err(); rowsErr != nil {
-			logger.ErrorContext(ctx, "Error iterating update rows", "error", rowsErr)
-		}
 		ticket.Updates = updates
 		logger.DebugContext(ctx, "Fetched associated updates", "count", len(ticket.Updates))
 	}
+	nestCommentAttachments(ticket.Updates, ticket.Attachments)
+
+	// --- 5. Fetch Comment Reactions ---
+	if len(ticket.Updates) > 0 {
+		currentUserID, _ := auth.GetUserIDFromContext(c)
+		reactions, reactionsErr := h.fetchTicketReactions(ctx, ticketID, currentUserID)
+		if reactionsErr != nil {
+			logger.ErrorContext(ctx, "Failed to query comment reactions", "error", reactionsErr)
+		} else {
+			for i := range ticket.Updates {
+				ticket.Updates[i].Reactions = reactions[ticket.Updates[i].ID]
+			}
+		}
+	}
 
-	// --- 5. Return Combined Result ---
+	// --- 6. Compute SLA Status ---
+	slaStatus, slaErr := sla.Compute(ctx, h.db.Pool, h.config.SLA, &ticket, time.Now())
+	if slaErr != nil {
+		logger.ErrorContext(ctx, "Failed to compute SLA status", "error", slaErr)
+	} else {
+		ticket.SLA = slaStatus
+	}
+
+	// --- 7. Return Combined Result ---
 	logger.InfoContext(ctx, "Fetched ticket details successfully", "ticketID", ticket.ID)
-	return c.JSON(http.StatusOK, ticket)
+	return etag.JSON(c, http.StatusOK, ticket) // 304 when the client's copy is current
 }
 
-// GetTicketCounts retrieves counts of tickets grouped by status.
+// GetTicketCounts retrieves counts of tickets grouped by status, over the tickets
+// the caller may open.
 func (h *Handler) GetTicketCounts(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	logger := slog.With("handler", "GetTicketCounts")
-	query := `SELECT status, COUNT(*) FROM tickets GROUP BY status`
-	rows, err := h.db.Pool.Query(ctx, query)
+	query := `SELECT t.status, COUNT(*) FROM tickets t WHERE t.quarantined_at IS NULL AND t.spam_at IS NULL`
+	args := []interface{}{}
+	if role, _ := auth.GetUserRoleFromContext(c); role != models.RoleAdmin {
+		userID, userErr := auth.GetUserIDFromContext(c)
+		if userErr != nil {
+			return userErr
+		}
+		query += " AND " + db.TicketAccessCondition("t", "$1")
+		args = append(args, userID)
+	}
+	rows, err := h.db.Pool.Query(ctx, query+" GROUP BY t.status", args...)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to fetch ticket counts", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to fetch ticket counts"})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch ticket counts")
 	}
 	defer rows.Close()
 
@@ -464,45 +498,114 @@ func (h *Handler) GetTicketCounts(c echo.Context) error {
 		var count int
 		if err := rows.Scan(&status, &count); err != nil {
 			logger.ErrorContext(ctx, "Failed to parse ticket counts", "error", err)
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to parse ticket counts"})
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to parse ticket counts")
 		}
 		counts[status] = count
 	}
 	if err := rows.Err(); err != nil {
 		logger.ErrorContext(ctx, "Error iterating ticket count rows", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Error processing ticket count results"})
+		return echo.NewHTTPError(http.StatusInternalServerError, "Error processing ticket count results")
 	}
 	logger.InfoContext(ctx, "Retrieved ticket counts", "counts", counts)
 	return c.JSON(http.StatusOK, counts)
 }
 
-// SearchTickets performs a basic search across multiple ticket fields.
+// SearchTickets performs a search across multiple ticket fields.
+// Exact (ILIKE) matches are always returned; when fuzzy search is enabled and the
+// query is long enough, pg_trgm word similarity also catches misspellings
+// (e.g., "pasword reset"). Results are ordered by match score, then recency.
 func (h *Handler) SearchTickets(c echo.Context) error {
-	ctx := context.Background()
+	ctx := c.Request().Context()
 	queryParam := c.QueryParam("query")
 	logger := slog.With("handler", "SearchTickets", "query", queryParam)
 
 	if queryParam == "" {
 		logger.WarnContext(ctx, "Missing search query parameter")
-		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Missing search query parameter."})
+		return echo.NewHTTPError(http.StatusBadRequest, "Missing search query parameter.")
+	}
+
+	// Short queries produce near-random trigram matches, so only exact matching applies to them.
+	useFuzzy := h.config != nil && h.config.Search.FuzzyEnabled &&
+		utf8.RuneCountInString(strings.TrimSpace(queryParam)) >= h.config.Search.FuzzyMinLength
+
+	// Non-admins only find the tickets they may open.
+	scopeUserID := ""
+	if role, _ := auth.GetUserRoleFromContext(c); role != models.RoleAdmin {
+		userID, userErr := auth.GetUserIDFromContext(c)
+		if userErr != nil {
+			return userErr
+		}
+		scopeUserID = userID
 	}
 
-	// Include necessary fields for display, fetch tags/assignee separately if needed for search results page
-	query := `
-		SELECT id, ticket_number, subject, description, status, assigned_to_user_id, created_at, updated_at, submitter_name, end_user_email, urgency
-		FROM tickets
-		WHERE subject ILIKE '%' || $1 || '%'
+	tickets, err := h.searchTicketRows(ctx, queryParam, useFuzzy, scopeUserID)
+	if err != nil && useFuzzy {
+		// Most likely the pg_trgm extension is missing; degrade to exact matching.
+		logger.WarnContext(ctx, "Fuzzy ticket search failed, falling back to exact matching", "error", err)
+		tickets, err = h.searchTicketRows(ctx, queryParam, false, scopeUserID)
+	}
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to search tickets", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to search tickets")
+	}
+
+	logger.InfoContext(ctx, "Ticket search successful", "resultCount", len(tickets), "fuzzy", useFuzzy)
+	return c.JSON(http.StatusOK, tickets)
+}
+
+// searchTicketRows executes the ticket search query. With fuzzy enabled, the query runs in a
+// read-only transaction so the word-similarity threshold can be set locally; this lets the
+// <% operator use the trigram indexes instead of scoring every row. A non-empty scopeUserID
+// limits the results to the tickets that user may open (see db.TicketAccessCondition).
+func (h *Handler) searchTicketRows(ctx context.Context, queryParam string, fuzzy bool, scopeUserID string) ([]models.Ticket, error) {
+	const selectColumns = `
+		SELECT id, ticket_number, subject, description, status, assigned_to_user_id, created_at, updated_at, submitter_name, end_user_email, urgency`
+	const exactMatch = `
+		   subject ILIKE '%' || $1 || '%'
 		   OR description ILIKE '%' || $1 || '%'
 		   OR submitter_name ILIKE '%' || $1 || '%'
 		   OR end_user_email ILIKE '%' || $1 || '%'
-		   OR CAST(ticket_number AS TEXT) ILIKE '%' || $1 || '%'
+		   OR CAST(ticket_number AS TEXT) ILIKE '%' || $1 || '%'`
+
+	scope := ""
+	args := []interface{}{queryParam}
+	if scopeUserID != "" {
+		scope = " AND " + db.TicketAccessCondition("t", "$2")
+		args = append(args, scopeUserID)
+	}
+
+	var rows pgx.Rows
+	var err error
+	if fuzzy {
+		tx, txErr := h.db.Pool.BeginTx(ctx, pgx.TxOptions{AccessMode: pgx.ReadOnly})
+		if txErr != nil {
+			return nil, txErr
+		}
+		defer tx.Rollback(ctx) // Read-only; rollback simply ends the transaction
+
+		threshold := strconv.FormatFloat(h.config.Search.FuzzyThreshold, 'f', -1, 64)
+		if _, err := tx.Exec(ctx, `SELECT set_config('pg_trgm.word_similarity_threshold', $1, true)`, threshold); err != nil {
+			return nil, err
+		}
+		rows, err = tx.Query(ctx, selectColumns+`
+		FROM tickets t
+		WHERE quarantined_at IS NULL AND spam_at IS NULL`+scope+` AND (`+exactMatch+`
+		   OR $1 <% subject
+		   OR $1 <% description)
+		ORDER BY
+			(CASE WHEN`+exactMatch+` THEN 1 ELSE 0 END)
+				+ GREATEST(word_similarity($1, subject), word_similarity($1, description)) DESC,
+			updated_at DESC
+		LIMIT 50`, args...)
+	} else {
+		rows, err = h.db.Pool.Query(ctx, selectColumns+`
+		FROM tickets t
+		WHERE quarantined_at IS NULL AND spam_at IS NULL`+scope+` AND (`+exactMatch+`)
 		ORDER BY updated_at DESC
-		LIMIT 50
-	`
-	rows, err := h.db.Pool.Query(ctx, query, queryParam)
+		LIMIT 50`, args...)
+	}
 	if err != nil {
-		logger.ErrorContext(ctx, "Failed to search tickets", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to search tickets"})
+		return nil, err
 	}
 	defer rows.Close()
 
@@ -516,8 +619,7 @@ func (h *Handler) SearchTickets(c echo.Context) error {
 			&submitterNameNullable, &ticket.EndUserEmail, &ticket.Urgency,
 		)
 		if err != nil {
-			logger.ErrorContext(ctx, "Failed to parse searched ticket data", "error", err)
-			return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to parse ticket data"})
+			return nil, fmt.Errorf("failed to parse searched ticket data: %w", err)
 		}
 		if submitterNameNullable.Valid {
 			ticket.SubmitterName = &submitterNameNullable.String
@@ -525,10 +627,7 @@ func (h *Handler) SearchTickets(c echo.Context) error {
 		tickets = append(tickets, ticket)
 	}
 	if err := rows.Err(); err != nil {
-		logger.ErrorContext(ctx, "Error iterating search ticket rows", "error", err)
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Error processing search ticket results"})
+		return nil, fmt.Errorf("error processing search ticket results: %w", err)
 	}
-
-	logger.InfoContext(ctx, "Ticket search successful", "resultCount", len(tickets))
-	return c.JSON(http.StatusOK, tickets)
-}
\ No newline at end of file
+	return tickets, nil
+}
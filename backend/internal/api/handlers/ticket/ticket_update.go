@@ -4,7 +4,6 @@ package ticket
 
 import (
 	"context"
-	"encoding/json" // Needed for JSON unmarshalling in helper
 	"errors"
 	"fmt"
 	"log/slog"
@@ -13,12 +12,17 @@ import (
 	"time"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth" // Auth helpers
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models"              // Data models
+	"github.com/henrythedeveloper/it-ticket-system/internal/sla"
+	"github.com/henrythedeveloper/it-ticket-system/internal/timezone"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
 	"github.com/labstack/echo/v4"
 	"github.com/jackc/pgx/v5"
+	"golang.org/x/sync/errgroup"
 )
 
-// UpdateTicket handles requests to modify a ticket's status, assignee, or resolution notes.
+// UpdateTicket handles requests to modify a ticket's status, assignee, team, urgency, issue type, or resolution notes.
 func (h *Handler) UpdateTicket(c echo.Context) error {
 	ctx := c.Request().Context()
 	ticketID := c.Param("id")
@@ -32,6 +36,33 @@ func (h *Handler) UpdateTicket(c echo.Context) error {
 		logger.WarnContext(ctx, "Failed to bind request body", "error", err)
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
+	if update.Status != "" && !isKnownStatus(update.Status) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid status value.")
+	}
+	if update.Urgency != nil {
+		switch *update.Urgency {
+		case models.UrgencyLow, models.UrgencyMedium, models.UrgencyHigh, models.UrgencyCritical:
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid urgency value.")
+		}
+	}
+	if update.DueDate != nil {
+		due, dueErr := parseDueDate(*update.DueDate, timezone.RequestLocation(c, h.db))
+		if dueErr != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid due_date: "+dueErr.Error())
+		}
+		normalized := ""
+		if due != nil { normalized = due.Format(time.RFC3339) }
+		update.DueDate = &normalized
+	}
+
+	if update.AssignedToTeamID != nil && *update.AssignedToTeamID != "" {
+		if _, teamErr := h.getTeamName(ctx, *update.AssignedToTeamID); teamErr != nil {
+			if teamErr.Error() == "team not found" { return echo.NewHTTPError(http.StatusBadRequest, "Team not found.") }
+			logger.ErrorContext(ctx, "Failed to look up team", "teamID", *update.AssignedToTeamID, "error", teamErr)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify team.")
+		}
+	}
 
 	// --- 2. Get Requesting User Context ---
 	updaterUserID, err := auth.GetUserIDFromContext(c)
@@ -51,6 +82,30 @@ func (h *Handler) UpdateTicket(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to fetch current ticket state: "+err.Error())
 	}
 
+	// Enforce the configured status transitions, then the close rule
+	if transitionErr := h.checkStatusTransition(currentState, &update); transitionErr != nil {
+		logger.WarnContext(ctx, "Ticket status change rejected", "from", currentState.Status, "error", transitionErr)
+		return transitionErr
+	}
+
+	// Closed tickets keep their assignee, team and urgency unless reopened
+	if lockErr := h.checkClosedLock(currentState, &update); lockErr != nil {
+		logger.WarnContext(ctx, "Ticket change rejected; ticket is closed", "error", lockErr)
+		return lockErr
+	}
+
+	// Moving to In Progress requires the configured fields
+	if requiredErr := h.checkInProgressRequiredFields(currentState, &update); requiredErr != nil {
+		logger.WarnContext(ctx, "Ticket start rejected; required fields missing", "error", requiredErr)
+		return requiredErr
+	}
+
+	// Enforce the configured close rule (who may close, and whether an owner is required)
+	if closeErr := h.checkCloseRule(c, currentState, &update, updaterUserID); closeErr != nil {
+		logger.WarnContext(ctx, "Ticket close rejected by close rule", "rule", h.config.Tickets.CloseRule, "error", closeErr)
+		return closeErr
+	}
+
 	// --- 5. Build Dynamic Update Query ---
 	query, args, buildErr := h.buildTicketUpdateQuery(ctx, ticketID, &update, currentState)
 	if buildErr != nil {
@@ -87,15 +142,31 @@ func (h *Handler) UpdateTicket(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to update ticket.")
 	}
 
+	// Pause or resume the SLA clock if the status changed
+	if slaErr := sla.Sync(ctx, tx, ticketID); slaErr != nil {
+		logger.ErrorContext(ctx, "Failed to update SLA clock", "error", slaErr)
+		funcErr = fmt.Errorf("sla sync failed: %w", slaErr)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to update ticket.")
+	}
+
+	// Reset acceptance state and record assignment history when the assignee changes
+	if assigneeChanging(currentState, &update) {
+		if assignErr := applyAssignmentChange(ctx, tx, ticketID, currentState.AssignedToUserID, *update.AssignedToUserID, updaterUserID); assignErr != nil {
+			logger.ErrorContext(ctx, "Failed to record assignment change", "error", assignErr)
+			funcErr = fmt.Errorf("assignment change failed: %w", assignErr)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record assignment change.")
+		}
+	}
+
 	// Add system comment
 	updaterName := "System"
 	if updaterUserID != "" {
 		fetchedName, nameErr := h.getUserName(ctx, updaterUserID)
 		if nameErr == nil { updaterName = fetchedName } else { logger.WarnContext(ctx, "Could not fetch updater name", "userID", updaterUserID, "error", nameErr) }
 	}
-	changeDescription := h.generateChangeDescription(ctx, currentState, &update, updaterName)
-	if changeDescription != fmt.Sprintf("Ticket touched by %s (no field changes detected).", updaterName) {
-		if commentErr := h.addSystemComment(ctx, tx, ticketID, updaterUserID, changeDescription); commentErr != nil {
+	if changes := h.diffTicketUpdate(ctx, currentState, &update); len(changes) > 0 {
+		changeDescription := fmt.Sprintf("Ticket updated by %s: %s", updaterName, describeChanges(changes))
+		if commentErr := h.addHistoryEntry(ctx, tx, ticketID, updaterUserID, changeDescription, changes); commentErr != nil {
 			logger.ErrorContext(ctx, "Failed to add system comment", "error", commentErr)
 			funcErr = fmt.Errorf("system comment failed: %w", commentErr)
 			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to record ticket update.")
@@ -120,36 +191,56 @@ func (h *Handler) UpdateTicket(c echo.Context) error {
 	// Determine if status changed and if assignee changed
 	statusChangedToClosed := updatedTicket.Status == models.StatusClosed && currentState.Status != models.StatusClosed
 	statusChangedToInProgress := updatedTicket.Status == models.StatusInProgress && currentState.Status != models.StatusInProgress
+	statusChangedToWaiting := updatedTicket.Status == models.StatusWaitingOnCustomer && currentState.Status != models.StatusWaitingOnCustomer
 	assigneeChanged := (currentState.AssignedToUserID == nil && updatedTicket.AssignedToUserID != nil) ||
 		(currentState.AssignedToUserID != nil && updatedTicket.AssignedToUserID != nil && *currentState.AssignedToUserID != *updatedTicket.AssignedToUserID) ||
 		(currentState.AssignedToUserID != nil && updatedTicket.AssignedToUserID == nil) // Also check for unassignment
 
+	// Internal tickets never email the submitter; staff notifications are unaffected.
+	notifySubmitter := !currentState.IsInternal
+	if !notifySubmitter && (statusChangedToClosed || statusChangedToInProgress || statusChangedToWaiting) {
+		logger.InfoContext(ctx, "Submitter email suppressed for internal ticket", "ticketID", ticketID)
+	}
+
 	// Send Closure Email (to submitter)
-	if statusChangedToClosed {
+	if statusChangedToClosed && notifySubmitter {
 		logger.InfoContext(ctx, "Triggering closure email.", "ticketID", ticketID, "recipient", currentState.EndUserEmail)
 		resolution := ""
 		if updatedTicket.ResolutionNotes != nil { resolution = *updatedTicket.ResolutionNotes }
-		go func(recipient, tID, subj, res string) {
+		go func(recipient, tID, subj, res, locale string) {
 			bgCtx := context.Background()
 			emailLogger := slog.With("operation", "SendTicketClosure", "ticketID", tID)
-			if emailErr := h.emailService.SendTicketClosure(recipient, tID, subj, res); emailErr != nil {
+			if emailErr := h.emailService.SendTicketClosure(recipient, tID, subj, res, locale); emailErr != nil {
 				emailLogger.ErrorContext(bgCtx, "Failed to send ticket closure email", "recipient", recipient, "error", emailErr)
 			} else { emailLogger.InfoContext(bgCtx, "Sent ticket closure email", "recipient", recipient) }
-		}(currentState.EndUserEmail, ticketID, updatedTicket.Subject, resolution)
+		}(currentState.EndUserEmail, ticketID, updatedTicket.Subject, resolution, currentState.Locale)
 	}
 
 	// Send In Progress Email (to submitter)
-	if statusChangedToInProgress {
+	if statusChangedToInProgress && notifySubmitter {
 		logger.InfoContext(ctx, "Triggering 'In Progress' email.", "ticketID", ticketID, "recipient", currentState.EndUserEmail)
 		assigneeName := "Unassigned"
 		if updatedTicket.AssignedToUser != nil { assigneeName = updatedTicket.AssignedToUser.Name }
-		go func(recipient, tID, subj, assignee string) {
+		assigneeName = h.submitterFacingStaffName(assigneeName, currentState.Locale)
+		go func(recipient, tID, subj, assignee, locale string) {
 			bgCtx := context.Background()
 			emailLogger := slog.With("operation", "SendTicketInProgress", "ticketID", tID)
-			if emailErr := h.emailService.SendTicketInProgress(recipient, tID, subj, assignee); emailErr != nil {
+			if emailErr := h.emailService.SendTicketInProgress(recipient, tID, subj, assignee, locale); emailErr != nil {
 				emailLogger.ErrorContext(bgCtx, "Failed to send 'In Progress' email", "recipient", recipient, "error", emailErr)
 			} else { emailLogger.InfoContext(bgCtx, "Sent 'In Progress' email", "recipient", recipient) }
-		}(currentState.EndUserEmail, ticketID, updatedTicket.Subject, assigneeName)
+		}(currentState.EndUserEmail, ticketID, updatedTicket.Subject, assigneeName, currentState.Locale)
+	}
+
+	// Send Information Request Email (to submitter)
+	if statusChangedToWaiting && notifySubmitter {
+		logger.InfoContext(ctx, "Triggering information request email.", "ticketID", ticketID, "recipient", currentState.EndUserEmail)
+		go func(recipient, tID, subj, locale string) {
+			bgCtx := context.Background()
+			emailLogger := slog.With("operation", "SendTicketWaitingOnCustomer", "ticketID", tID)
+			if emailErr := h.emailService.SendTicketWaitingOnCustomer(recipient, tID, subj, locale, 0); emailErr != nil {
+				emailLogger.ErrorContext(bgCtx, "Failed to send information request email", "recipient", recipient, "error", emailErr)
+			} else { emailLogger.InfoContext(bgCtx, "Sent information request email", "recipient", recipient) }
+		}(currentState.EndUserEmail, ticketID, updatedTicket.Subject, currentState.Locale)
 	}
 
 	// Send Assignment Email (to NEW assignee)
@@ -163,6 +254,12 @@ func (h *Handler) UpdateTicket(c echo.Context) error {
 			} else { emailLogger.InfoContext(bgCtx, "Sent assignment email", "recipient", recipient) }
 		}(updatedTicket.AssignedToUser.Email, ticketID, updatedTicket.Subject)
 	}
+	// Notify the new team's members (only while no individual is assigned)
+	if teamChanging(currentState, &update) {
+		h.notifyTeamAssignment(updatedTicket, updaterUserID)
+	}
+	h.notifyUrgencyChange(currentState, updatedTicket, updaterUserID)
+	go h.webhooks.Dispatch(webhook.EventTicketUpdated, updatedTicket)
 
 	// --- 10. Return Success Response ---
 	logger.InfoContext(ctx, "Ticket updated successfully", "ticketID", ticketID)
@@ -174,13 +271,20 @@ func (h *Handler) UpdateTicket(c echo.Context) error {
 
 // getCurrentTicketStateForUpdate fetches essential current ticket data before an update.
 func (h *Handler) getCurrentTicketStateForUpdate(ctx context.Context, ticketID string) (*models.TicketState, error) {
-	query := `SELECT status, assigned_to_user_id, end_user_email, subject, ticket_number, resolution_notes FROM tickets WHERE id = $1`
+	query := `
+        SELECT t.status, t.assigned_to_user_id, t.end_user_email, t.subject, t.ticket_number, t.resolution_notes,
+               COALESCE(t.locale, s.locale, ''), t.is_internal, t.urgency, t.issue_type, t.due_date,
+               t.assigned_to_team_id
+        FROM tickets t
+        LEFT JOIN users s ON s.email = t.end_user_email
+        WHERE t.id = $1`
 	row := h.db.Pool.QueryRow(ctx, query, ticketID)
 
 	var state models.TicketState
 	err := row.Scan(
 		&state.Status, &state.AssignedToUserID, &state.EndUserEmail,
-		&state.Subject, &state.TicketNumber, &state.ResolutionNotes,
+		&state.Subject, &state.TicketNumber, &state.ResolutionNotes, &state.Locale, &state.IsInternal,
+		&state.Urgency, &state.IssueType, &state.DueDate, &state.AssignedToTeamID,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) { return nil, errors.New("ticket not found") }
@@ -207,12 +311,14 @@ func (h *Handler) buildTicketUpdateQuery(ctx context.Context, ticketID string, u
 	var setClauses []string
 	var args []interface{}
 	argIndex := 1
+	newStatus := currentState.Status
 
 	// Status Change
 	if update.Status != "" && update.Status != currentState.Status {
 		setClauses = append(setClauses, fmt.Sprintf("status = $%d", argIndex))
 		args = append(args, update.Status)
 		argIndex++
+		newStatus = update.Status
 	}
 
 	// Assignee Change
@@ -232,15 +338,49 @@ func (h *Handler) buildTicketUpdateQuery(ctx context.Context, ticketID string, u
 		currentNotes := ""; if currentState.ResolutionNotes != nil { currentNotes = *currentState.ResolutionNotes }
 		if *update.ResolutionNotes != currentNotes {
 			setClauses = append(setClauses, fmt.Sprintf("resolution_notes = $%d", argIndex)); args = append(args, *update.ResolutionNotes); argIndex++
-            if update.Status != models.StatusClosed { // Auto-close if resolution notes added and not already closing
+            if update.Status == "" && currentState.Status != models.StatusClosed { // Auto-close an open ticket unless the update sets a status itself
                  setClauses = append(setClauses, fmt.Sprintf("status = $%d", argIndex)); args = append(args, models.StatusClosed); argIndex++
                  setClauses = append(setClauses, fmt.Sprintf("closed_at = $%d", argIndex)); args = append(args, time.Now()); argIndex++
+                 newStatus = models.StatusClosed
             }
 		}
 	}
 
+	// Urgency
+	if update.Urgency != nil && *update.Urgency != currentState.Urgency {
+		setClauses = append(setClauses, fmt.Sprintf("urgency = $%d", argIndex)); args = append(args, *update.Urgency); argIndex++
+	}
+
+	// Issue Type ("" clears it)
+	if update.IssueType != nil {
+		newIssueType := strings.TrimSpace(*update.IssueType)
+		currentIssueType := ""; if currentState.IssueType != nil { currentIssueType = *currentState.IssueType }
+		if newIssueType != currentIssueType {
+			setClauses = append(setClauses, fmt.Sprintf("issue_type = NULLIF($%d, '')", argIndex)); args = append(args, newIssueType); argIndex++
+		}
+	}
+
+	// Due Date ("" clears it)
+	if newDueDate, changed := dueDateChange(currentState, update); changed {
+		setClauses = append(setClauses, fmt.Sprintf("due_date = $%d", argIndex)); args = append(args, newDueDate); argIndex++
+	}
+
+	// Team ("" removes it)
+	if teamChanging(currentState, update) {
+		setClauses = append(setClauses, fmt.Sprintf("assigned_to_team_id = NULLIF($%d, '')::uuid", argIndex)); args = append(args, *update.AssignedToTeamID); argIndex++
+	}
+
 	if len(setClauses) == 0 { return "", nil, errors.New("no fields to update") }
 
+	// Waiting on Customer bookkeeping: entering restarts the reminder cycle, leaving clears it
+	if newStatus != currentState.Status {
+		if newStatus == models.StatusWaitingOnCustomer {
+			setClauses = append(setClauses, "waiting_since = NOW()", "waiting_reminders_sent = 0", "waiting_last_reminder_at = NULL")
+		} else if currentState.Status == models.StatusWaitingOnCustomer {
+			setClauses = append(setClauses, "waiting_since = NULL")
+		}
+	}
+
 	// Always update updated_at
 	setClauses = append(setClauses, fmt.Sprintf("updated_at = $%d", argIndex)); args = append(args, time.Now()); argIndex++
 
@@ -257,115 +397,235 @@ func (h *Handler) buildTicketUpdateQuery(ctx context.Context, ticketID string, u
 	return query, args, nil
 }
 
-// generateChangeDescription creates a human-readable string describing the changes made.
-func (h *Handler) generateChangeDescription(ctx context.Context, currentState *models.TicketState, update *models.TicketStatusUpdate, updaterName string) string {
-	var description strings.Builder
-	description.WriteString(fmt.Sprintf("Ticket updated by %s: ", updaterName))
-	changed := false
+// getTicketDetailsByID fetches a single ticket with its related data (tags,
+// attachments and updates), matching the GetTicketByID response.
+func (h *Handler) getTicketDetailsByID(ctx context.Context, ticketID string) (*models.Ticket, error) {
+    logger := slog.With("helper", "getTicketDetailsByID", "ticketID", ticketID)
+    row := h.db.Pool.QueryRow(ctx, ticketDetailSelect+ticketDetailFrom+`
+        WHERE t.id = $1`, ticketID)
+    ticket, scanErr := scanTicketWithUsersAndSubmitter(row)
+    if scanErr != nil {
+        if errors.Is(scanErr, pgx.ErrNoRows) { logger.WarnContext(ctx, "Ticket not found"); return nil, errors.New("ticket not found") }
+        logger.ErrorContext(ctx, "Database query failed", "error", scanErr)
+        return nil, fmt.Errorf("failed to fetch ticket details: %w", scanErr)
+    }
+    // Related data is fetched concurrently; each failure is logged and leaves an empty list.
+    var g errgroup.Group
+    g.Go(func() error {
+        tags, tagsErr := h.fetchTicketTags(ctx, ticketID)
+        if tagsErr != nil {
+            logger.ErrorContext(ctx, "Failed to fetch ticket tags", "error", tagsErr); tags = []models.Tag{}
+        }
+        ticket.Tags = tags
+        return nil
+    })
+    g.Go(func() error {
+        attachments, attachErr := h.fetchTicketAttachments(ctx, ticketID)
+        if attachErr != nil {
+            logger.ErrorContext(ctx, "Failed to fetch ticket attachments", "error", attachErr); attachments = []models.Attachment{}
+        }
+        ticket.Attachments = attachments
+        return nil
+    })
+    g.Go(func() error {
+        updates, updatesErr := h.fetchTicketUpdates(ctx, ticketID)
+        if updatesErr != nil {
+            logger.ErrorContext(ctx, "Failed to fetch ticket updates", "error", updatesErr); updates = []models.TicketUpdate{}
+        }
+        ticket.Updates = updates
+        return nil
+    })
+    _ = g.Wait()
+    nestCommentAttachments(ticket.Updates, ticket.Attachments)
+    slaStatus, slaErr := sla.Compute(ctx, h.db.Pool, h.config.SLA, &ticket, time.Now())
+    if slaErr != nil {
+         logger.ErrorContext(ctx, "Failed to compute SLA status", "error", slaErr)
+    }
+    ticket.SLA = slaStatus
+    return &ticket, nil
+}
 
-	if update.Status != "" && update.Status != currentState.Status {
-		description.WriteString(fmt.Sprintf("Status changed from '%s' to '%s'. ", currentState.Status, update.Status)); changed = true
+// Ticket close rules (TICKET_CLOSE_RULE).
+const (
+	closeRuleAny      = "any"      // Anyone who can update the ticket may close it
+	closeRuleAssigned = "assigned" // The ticket must have an assignee when it is closed
+	closeRuleAssignee = "assignee" // Only the assignee (or an Admin) may close it
+)
+
+// closesTicket reports whether the update moves an open ticket to Closed,
+// either explicitly or by adding resolution notes without naming a status
+// (which auto-closes).
+func closesTicket(currentState *models.TicketState, update *models.TicketStatusUpdate) bool {
+	if currentState.Status == models.StatusClosed {
+		return false
 	}
-	if update.AssignedToUserID != nil {
-		newAssigneeID := *update.AssignedToUserID
-		assigneeChanged := false
-		currentAssigneeDisplay := "Unassigned"; newAssigneeDisplay := "Unassigned"
-		if currentState.AssignedToUserID != nil {
-			currentName, err := h.getUserName(ctx, *currentState.AssignedToUserID)
-			if err == nil { currentAssigneeDisplay = currentName } else { currentAssigneeDisplay = *currentState.AssignedToUserID; slog.WarnContext(ctx,"Could not fetch current assignee name", "userID", *currentState.AssignedToUserID, "error", err) }
+	if update.Status != "" {
+		return update.Status == models.StatusClosed
+	}
+	if update.ResolutionNotes != nil {
+		currentNotes := ""
+		if currentState.ResolutionNotes != nil {
+			currentNotes = *currentState.ResolutionNotes
 		}
-		if newAssigneeID != "" {
-            newName, err := h.getUserName(ctx, newAssigneeID)
-            if err == nil { newAssigneeDisplay = newName } else { newAssigneeDisplay = newAssigneeID; slog.WarnContext(ctx,"Could not fetch new assignee name", "userID", newAssigneeID, "error", err) }
+		return *update.ResolutionNotes != currentNotes
+	}
+	return false
+}
+
+// checkCloseRule applies the configured close rule to an update that closes
+// the ticket. The assignee considered is the one the ticket will have after
+// the update, so assigning and closing in one request is allowed.
+//
+// Returns:
+//   - error: 400 if the ticket would be closed without an assignee, 403 if the
+//     updater is not allowed to close it; nil otherwise.
+func (h *Handler) checkCloseRule(c echo.Context, currentState *models.TicketState, update *models.TicketStatusUpdate, updaterUserID string) error {
+	rule := h.config.Tickets.CloseRule
+	if rule == "" || rule == closeRuleAny || !closesTicket(currentState, update) {
+		return nil
+	}
+
+	assigneeID := ""
+	if currentState.AssignedToUserID != nil {
+		assigneeID = *currentState.AssignedToUserID
+	}
+	if update.AssignedToUserID != nil {
+		assigneeID = *update.AssignedToUserID
+	}
+	if assigneeID == "" {
+		return apierror.New(http.StatusBadRequest, apierror.CodeAssigneeRequired, "Assign the ticket before closing it.")
+	}
+
+	if rule == closeRuleAssignee && assigneeID != updaterUserID {
+		role, err := auth.GetUserRoleFromContext(c)
+		if err != nil {
+			return err
 		}
-		if newAssigneeID == "" && currentState.AssignedToUserID != nil {
-			assigneeChanged = true; description.WriteString(fmt.Sprintf("Assignee removed (was %s). ", currentAssigneeDisplay))
-		} else if newAssigneeID != "" && (currentState.AssignedToUserID == nil || *currentState.AssignedToUserID != newAssigneeID) {
-			assigneeChanged = true; description.WriteString(fmt.Sprintf("Assignee changed from '%s' to '%s'. ", currentAssigneeDisplay, newAssigneeDisplay))
+		if role != models.RoleAdmin {
+			return apierror.New(http.StatusForbidden, apierror.CodeNotAssignee, "Only the assignee or an admin can close this ticket.")
 		}
-		if assigneeChanged { changed = true }
 	}
-	if update.ResolutionNotes != nil {
-		currentNotes := ""; if currentState.ResolutionNotes != nil { currentNotes = *currentState.ResolutionNotes }
-		if *update.ResolutionNotes != currentNotes { description.WriteString("Resolution notes updated. "); changed = true }
+	return nil
+}
+
+// effectiveStatus returns the status the ticket will have after the update,
+// including the implicit close when resolution notes are added (see
+// buildTicketUpdateQuery).
+func effectiveStatus(currentState *models.TicketState, update *models.TicketStatusUpdate) models.TicketStatus {
+	if closesTicket(currentState, update) {
+		return models.StatusClosed
+	}
+	if update.Status != "" {
+		return update.Status
 	}
+	return currentState.Status
+}
 
-	if !changed { return fmt.Sprintf("Ticket touched by %s (no field changes detected).", updaterName) }
-	return strings.TrimSpace(description.String())
+// checkStatusTransition rejects updates whose status change is not allowed by
+// TICKET_STATUS_TRANSITIONS. A nil map allows every transition.
+//
+// Returns:
+//   - error: 422 if the transition is not allowed; nil otherwise.
+func (h *Handler) checkStatusTransition(currentState *models.TicketState, update *models.TicketStatusUpdate) error {
+	transitions := h.config.Tickets.StatusTransitions
+	newStatus := effectiveStatus(currentState, update)
+	if transitions == nil || newStatus == currentState.Status {
+		return nil
+	}
+	for _, allowed := range transitions[string(currentState.Status)] {
+		if allowed == string(newStatus) {
+			return nil
+		}
+	}
+	if newStatus == models.StatusClosed && update.Status != models.StatusClosed {
+		return apierror.New(http.StatusUnprocessableEntity, apierror.CodeInvalidTransition, fmt.Sprintf(
+			"Adding resolution notes closes the ticket, which is not allowed from '%s'.", currentState.Status))
+	}
+	return apierror.New(http.StatusUnprocessableEntity, apierror.CodeInvalidTransition, fmt.Sprintf(
+		"Cannot change ticket status from '%s' to '%s'.", currentState.Status, newStatus))
 }
 
-// addSystemComment inserts a system-generated comment into the ticket_updates table.
-func (h *Handler) addSystemComment(ctx context.Context, tx pgx.Tx, ticketID, userID, comment string) error {
-	query := `INSERT INTO ticket_updates (ticket_id, user_id, comment, is_internal_note, is_system_update, created_at) VALUES ($1, $2, $3, $4, $5, NOW())`
-	var userIDArg interface{}; if userID != "" { userIDArg = userID } else { userIDArg = nil }
-	_, err := tx.Exec(ctx, query, ticketID, userIDArg, comment, true, true)
-	if err != nil { return fmt.Errorf("failed to add system comment: %w", err) }
-	return nil
+// checkClosedLock rejects assignee, team and urgency changes on a Closed
+// ticket when TICKET_CLOSED_LOCK is on. An update that also reopens the
+// ticket may change them, so reopening and reassigning in one request works.
+//
+// Returns:
+//   - error: 409 naming the locked fields; nil otherwise.
+func (h *Handler) checkClosedLock(currentState *models.TicketState, update *models.TicketStatusUpdate) error {
+	if !h.config.Tickets.ClosedLock || currentState.Status != models.StatusClosed ||
+		effectiveStatus(currentState, update) != models.StatusClosed {
+		return nil
+	}
+
+	var locked []string
+	if update.AssignedToUserID != nil && *update.AssignedToUserID != stringValue(currentState.AssignedToUserID) {
+		locked = append(locked, "assignee")
+	}
+	if update.AssignedToTeamID != nil && *update.AssignedToTeamID != stringValue(currentState.AssignedToTeamID) {
+		locked = append(locked, "team")
+	}
+	if update.Urgency != nil && *update.Urgency != currentState.Urgency {
+		locked = append(locked, "urgency")
+	}
+	if len(locked) == 0 {
+		return nil
+	}
+	return apierror.New(http.StatusConflict, apierror.CodeTicketClosed, fmt.Sprintf(
+		"This ticket is closed; reopen it before changing its %s.", strings.Join(locked, ", ")))
 }
 
-// getTicketDetailsByID fetches a single ticket with its related data.
-func (h *Handler) getTicketDetailsByID(ctx context.Context, ticketID string) (*models.Ticket, error) {
-    logger := slog.With("helper", "getTicketDetailsByID", "ticketID", ticketID)
-    query := `
-        SELECT
-            t.id, t.ticket_number, t.submitter_name, t.end_user_email, t.issue_type, t.urgency, t.subject,
-            t.description, t.status, t.assigned_to_user_id, t.created_at, t.updated_at,
-            t.closed_at, t.resolution_notes,
-            a.id as assigned_user_id_val, a.name as assigned_user_name, a.email as assigned_user_email,
-            a.role as assigned_user_role, a.created_at as assigned_user_created_at, a.updated_at as assigned_user_updated_at,
-            s.id as submitter_user_id_val, s.name as submitter_user_name, s.email as submitter_user_email,
-            s.role as submitter_user_role, s.created_at as submitter_user_created_at, s.updated_at as submitter_user_updated_at,
-             COALESCE(
-                (SELECT json_agg(json_build_object('id', tg.id, 'name', tg.name, 'created_at', tg.created_at))
-                 FROM ticket_tags tt JOIN tags tg ON tt.tag_id = tg.id
-                 WHERE tt.ticket_id = t.id),
-                '[]'::json
-            ) as tags
-        FROM tickets t
-        LEFT JOIN users a ON t.assigned_to_user_id = a.id
-        LEFT JOIN users s ON t.end_user_email = s.email
-        WHERE t.id = $1
-        GROUP BY t.id, a.id, s.id
-    `
-    row := h.db.Pool.QueryRow(ctx, query, ticketID)
-    var ticket models.Ticket
-    var tagsJSON []byte
-    var assignedUserIDVal, assignedUserName, assignedUserEmail, assignedUserRole *string
-    var assignedUserCreatedAt, assignedUserUpdatedAt *time.Time
-    var submitterUserIDVal, submitterUserName, submitterUserEmail, submitterUserRole *string
-    var submitterUserCreatedAt, submitterUserUpdatedAt *time.Time
-
-    scanErr := row.Scan(
-        &ticket.ID, &ticket.TicketNumber, &ticket.SubmitterName, &ticket.EndUserEmail, &ticket.IssueType, &ticket.Urgency, &ticket.Subject,
-        &ticket.Description, &ticket.Status, &ticket.AssignedToUserID,
-        &ticket.CreatedAt, &ticket.UpdatedAt, &ticket.ClosedAt, &ticket.ResolutionNotes,
-        &assignedUserIDVal, &assignedUserName, &assignedUserEmail, &assignedUserRole,
-        &assignedUserCreatedAt, &assignedUserUpdatedAt,
-        &submitterUserIDVal, &submitterUserName, &submitterUserEmail, &submitterUserRole,
-        &submitterUserCreatedAt, &submitterUserUpdatedAt,
-        &tagsJSON,
-    )
-    if scanErr != nil {
-        if errors.Is(scanErr, pgx.ErrNoRows) { logger.WarnContext(ctx, "Ticket not found"); return nil, errors.New("ticket not found") }
-        logger.ErrorContext(ctx, "Database query failed", "error", scanErr)
-        return nil, fmt.Errorf("failed to fetch ticket details: %w", scanErr)
-    }
-    if assignedUserIDVal != nil {
-        ticket.AssignedToUser = &models.User{
-            ID: *assignedUserIDVal, Name: *assignedUserName, Email: *assignedUserEmail,
-            Role: models.UserRole(*assignedUserRole), CreatedAt: *assignedUserCreatedAt, UpdatedAt: *assignedUserUpdatedAt,
-        }
-    } else { ticket.AssignedToUser = nil }
-    if submitterUserIDVal != nil {
-        ticket.Submitter = &models.User{
-            ID: *submitterUserIDVal, Name: *submitterUserName, Email: *submitterUserEmail,
-            Role: models.UserRole(*submitterUserRole), CreatedAt: *submitterUserCreatedAt, UpdatedAt: *submitterUserUpdatedAt,
-        }
-    } else { ticket.Submitter = nil }
-    if err := json.Unmarshal(tagsJSON, &ticket.Tags); err != nil {
-         logger.ErrorContext(ctx, "Failed to unmarshal tags JSON", "error", err); ticket.Tags = []models.Tag{}
-    }
-    // Fetch attachments and updates separately
-    return &ticket, nil
+// Fields that TICKET_IN_PROGRESS_REQUIRED_FIELDS may require.
+const (
+	requiredFieldAssignee  = "assignee"
+	requiredFieldIssueType = "issue_type"
+)
+
+// checkInProgressRequiredFields rejects an update that moves the ticket to In
+// Progress while a configured required field is missing. Values set by the
+// same update count, so assigning and starting in one request is allowed.
+//
+// Returns:
+//   - error: 422 with per-field messages (keyed by request field name); nil otherwise.
+func (h *Handler) checkInProgressRequiredFields(currentState *models.TicketState, update *models.TicketStatusUpdate) error {
+	required := h.config.Tickets.InProgressRequiredFields
+	if len(required) == 0 || currentState.Status == models.StatusInProgress || effectiveStatus(currentState, update) != models.StatusInProgress {
+		return nil
+	}
+
+	fields := map[string]string{}
+	for _, field := range required {
+		switch field {
+		case requiredFieldAssignee:
+			assigneeID := stringValue(currentState.AssignedToUserID)
+			if update.AssignedToUserID != nil {
+				assigneeID = *update.AssignedToUserID
+			}
+			if assigneeID == "" {
+				fields["assignedToId"] = "An assignee is required before work starts."
+			}
+		case requiredFieldIssueType:
+			issueType := stringValue(currentState.IssueType)
+			if update.IssueType != nil {
+				issueType = strings.TrimSpace(*update.IssueType)
+			}
+			if issueType == "" {
+				fields["issue_type"] = "An issue type is required before work starts."
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return apierror.New(http.StatusUnprocessableEntity, apierror.CodeValidationFailed,
+		"Required fields are missing for moving the ticket to In Progress.").WithFields(fields)
 }
 
+// isKnownStatus reports whether status is one of models.TicketStatuses.
+func isKnownStatus(status models.TicketStatus) bool {
+	for _, known := range models.TicketStatuses {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+// backend/internal/api/handlers/ticket/urgency.go
+// ==========================================================================
+// Automatic urgency for new tickets. A ticket submitted without an urgency
+// but with a due date takes its urgency from the days left until it is due.
+// When keyword suggestion is enabled, a ticket at the default urgency (Medium)
+// whose subject or description mentions a configured keyword is raised to
+// High or Critical. Suggestions only ever raise urgency; Low, High and
+// Critical choices are left alone.
+// ==========================================================================
+
+package ticket
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+)
+
+// urgencyKeyword is a compiled keyword and the urgency it suggests.
+type urgencyKeyword struct {
+	keyword string
+	urgency models.TicketUrgency
+	pattern *regexp.Regexp
+}
+
+// compileUrgencyKeywords builds the keyword matchers from configuration,
+// Critical keywords first so the strongest match wins. Keywords match
+// case-insensitively on word boundaries ("down" does not match "download").
+//
+// Parameters:
+//   - cfg: The urgency keyword configuration.
+//
+// Returns:
+//   - []urgencyKeyword: The matchers, or nil when the feature is disabled.
+func compileUrgencyKeywords(cfg config.UrgencyKeywordsConfig) []urgencyKeyword {
+	if !cfg.Enabled {
+		return nil
+	}
+	var keywords []urgencyKeyword
+	add := func(list []string, urgency models.TicketUrgency) {
+		for _, keyword := range list {
+			keyword = strings.TrimSpace(keyword)
+			if keyword == "" {
+				continue
+			}
+			keywords = append(keywords, urgencyKeyword{
+				keyword: keyword,
+				urgency: urgency,
+				pattern: regexp.MustCompile(`(?i)(^|\W)` + regexp.QuoteMeta(keyword) + `($|\W)`),
+			})
+		}
+	}
+	add(cfg.Critical, models.UrgencyCritical)
+	add(cfg.High, models.UrgencyHigh)
+	return keywords
+}
+
+// suggestUrgency returns the urgency suggested by the first matching keyword
+// in the subject or description.
+//
+// Parameters:
+//   - subject: The ticket subject.
+//   - description: The ticket description.
+//
+// Returns:
+//   - models.TicketUrgency: The suggested urgency.
+//   - string: The keyword that matched.
+//   - bool: False if no keyword matched (or the feature is disabled).
+func (h *Handler) suggestUrgency(subject, description string) (models.TicketUrgency, string, bool) {
+	text := subject + "\n" + description
+	for _, kw := range h.urgencyKeywords {
+		if kw.pattern.MatchString(text) {
+			return kw.urgency, kw.keyword, true
+		}
+	}
+	return "", "", false
+}
+
+// urgencyAdjustedComment formats the system comment recorded when urgency is auto-adjusted.
+func urgencyAdjustedComment(from, to models.TicketUrgency, keyword string) string {
+	return fmt.Sprintf("Urgency automatically raised from %s to %s because the ticket mentions %q. Staff may change it if this is not accurate.", from, to, keyword)
+}
+
+// urgencyFromDueDate maps the calendar days left until a ticket is due to an
+// urgency: Critical within 1 day, High within 3, Medium within 7, else Low.
+func urgencyFromDueDate(days int) models.TicketUrgency {
+	switch {
+	case days <= 1:
+		return models.UrgencyCritical
+	case days <= 3:
+		return models.UrgencyHigh
+	case days <= 7:
+		return models.UrgencyMedium
+	default:
+		return models.UrgencyLow
+	}
+}
+
+// daysUntilDue counts the calendar days from now to the due date in loc, so
+// a ticket due later today is 0 days away and one due tomorrow is 1.
+func daysUntilDue(due, now time.Time, loc *time.Location) int {
+	dy, dm, dd := due.In(loc).Date()
+	ny, nm, nd := now.In(loc).Date()
+	return int(time.Date(dy, dm, dd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ny, nm, nd, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
+
+// urgencyFromDueDateComment formats the system comment recorded when urgency is derived from the due date.
+func urgencyFromDueDateComment(urgency models.TicketUrgency, days int) string {
+	return fmt.Sprintf("Urgency set to %s because the ticket is due in %d day(s) and no urgency was chosen. Staff may change it if this is not accurate.", urgency, days)
+}
+
+// urgencySeverity ranks an urgency by models.UrgencySeverityOrder
+// (Low = 1 ... Critical = 4; unknown = 0), matching urgencySeverityCase.
+func urgencySeverity(urgency models.TicketUrgency) int {
+	for i, u := range models.UrgencySeverityOrder {
+		if u == urgency {
+			return i + 1
+		}
+	}
+	return 0
+}
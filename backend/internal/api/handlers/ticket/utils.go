@@ -17,12 +17,42 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/henrythedeveloper/it-ticket-system/internal/i18n"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Data models
 	"github.com/jackc/pgx/v5"
 )
 
+// --- Shared Query Fragments ---
+
+// ticketDetailSelect lists the columns expected by scanTicketWithUsersAndSubmitter, in order.
+// Pair it with ticketDetailFrom so the assignee (a), team and submitter (s) joins are present.
+const ticketDetailSelect = `
+        SELECT
+            t.id, t.ticket_number, t.submitter_name, t.end_user_email, t.issue_type, t.urgency, t.subject,
+            t.description, t.status, t.assigned_to_user_id, t.created_at, t.updated_at,
+            t.closed_at, t.resolution_notes, t.due_date,
+            t.locale, t.assigned_at, t.accepted_at, t.attachments_pending_since, t.is_internal,
+            t.quarantined_at, t.quarantine_reason, t.spam_at, t.assigned_to_team_id,
+            t.waiting_since, t.waiting_reminders_sent, t.waiting_last_reminder_at,
+            -- Assigned team details (nullable)
+            team.name as assigned_team_name, team.created_at as assigned_team_created_at, team.updated_at as assigned_team_updated_at,
+            -- Assigned user details (nullable)
+            a.id as assigned_user_id, a.name as assigned_user_name, a.email as assigned_user_email,
+            a.role as assigned_user_role, a.created_at as assigned_user_created_at, a.updated_at as assigned_user_updated_at,
+            -- Submitter details (nullable)
+            s.id as submitter_user_id, s.name as submitter_user_name, s.email as submitter_user_email,
+            s.role as submitter_user_role, s.created_at as submitter_user_created_at, s.updated_at as submitter_user_updated_at`
+
+// ticketDetailFrom joins the assignee, team and submitter (matched by email) onto tickets.
+const ticketDetailFrom = `
+        FROM tickets t
+        LEFT JOIN users a ON t.assigned_to_user_id = a.id
+        LEFT JOIN teams team ON t.assigned_to_team_id = team.id
+        LEFT JOIN users s ON t.end_user_email = s.email`
+
 // --- Row Scanning Helper ---
 
 // scanTicketWithUsersAndSubmitter scans a ticket row along with potentially joined assigned user and submitter data.
@@ -44,12 +74,20 @@ func scanTicketWithUsersAndSubmitter(rowScanner interface { Scan(...interface{})
 	var assignedUserCreatedAt, assignedUserUpdatedAt *time.Time
 	var submitterUserID, submitterUserName, submitterUserEmail, submitterUserRole *string
 	var submitterUserCreatedAt, submitterUserUpdatedAt *time.Time
+	var attachmentsPendingSince *time.Time
+	var assignedTeamName *string
+	var assignedTeamCreatedAt, assignedTeamUpdatedAt *time.Time
 
 	// Define scan targets *without* total_count
 	scanTargets := []interface{}{
 		&ticket.ID, &ticket.TicketNumber, &ticket.SubmitterName, &ticket.EndUserEmail, &ticket.IssueType, &ticket.Urgency,
 		&ticket.Subject, &ticket.Description, &ticket.Status, &ticket.AssignedToUserID, // Scan the FK ID directly into the ticket struct field
-		&ticket.CreatedAt, &ticket.UpdatedAt, &ticket.ClosedAt, &ticket.ResolutionNotes,
+		&ticket.CreatedAt, &ticket.UpdatedAt, &ticket.ClosedAt, &ticket.ResolutionNotes, &ticket.DueDate,
+		&ticket.Locale, &ticket.AssignedAt, &ticket.AcceptedAt, &attachmentsPendingSince, &ticket.IsInternal,
+		&ticket.QuarantinedAt, &ticket.QuarantineReason, &ticket.SpamAt, &ticket.AssignedToTeamID,
+		&ticket.WaitingSince, &ticket.WaitingReminders, &ticket.WaitingRemindedAt,
+		// Assigned team fields (scan into temporary pointers)
+		&assignedTeamName, &assignedTeamCreatedAt, &assignedTeamUpdatedAt,
 		// Assigned user fields (scan into temporary pointers)
 		&assignedUserID, &assignedUserName, &assignedUserEmail, &assignedUserRole,
 		&assignedUserCreatedAt, &assignedUserUpdatedAt,
@@ -75,6 +113,12 @@ func scanTicketWithUsersAndSubmitter(rowScanner interface { Scan(...interface{})
 	)
 
 
+	// A ticket awaits acceptance once it has been assigned (with a recorded assignment time) but not yet accepted.
+	ticket.PendingAcceptance = ticket.AssignedToUserID != nil && ticket.AssignedAt != nil &&
+		ticket.AcceptedAt == nil && ticket.Status != models.StatusClosed
+
+	ticket.AttachmentsPending = attachmentsPendingSince != nil
+
 	// --- Populate AssignedToUser ---
 	// *** SIMPLIFIED LOGIC: Populate only if the joined user ID was successfully scanned ***
 	if assignedUserID != nil {
@@ -97,6 +141,16 @@ func scanTicketWithUsersAndSubmitter(rowScanner interface { Scan(...interface{})
 	}
 
 
+	// --- Populate AssignedToTeam ---
+	if ticket.AssignedToTeamID != nil && assignedTeamName != nil {
+		ticket.AssignedToTeam = &models.Team{
+			ID:        *ticket.AssignedToTeamID,
+			Name:      *assignedTeamName,
+			CreatedAt: *assignedTeamCreatedAt,
+			UpdatedAt: *assignedTeamUpdatedAt,
+		}
+	}
+
 	// --- Populate Submitter ---
 	// Check if the LEFT JOIN found a corresponding user based on email
 	if submitterUserID != nil {
@@ -144,22 +198,8 @@ func (h *Handler) checkTicketAccess(
 
 	// Fetch the ticket along with assignee ID for the check
 	// Use the same query as GetTicketByID to ensure consistency
-	row := h.db.Pool.QueryRow(ctx, `
-        SELECT
-            t.id, t.ticket_number, t.submitter_name, t.end_user_email, t.issue_type, t.urgency, t.subject,
-            t.description, t.status, t.assigned_to_user_id, t.created_at, t.updated_at,
-            t.closed_at, t.resolution_notes,
-            -- Assigned user details (nullable)
-            a.id as assigned_user_id, a.name as assigned_user_name, a.email as assigned_user_email,
-            a.role as assigned_user_role, a.created_at as assigned_user_created_at, a.updated_at as assigned_user_updated_at,
-            -- Submitter details (nullable)
-            s.id as submitter_user_id, s.name as submitter_user_name, s.email as submitter_user_email,
-            s.role as submitter_user_role, s.created_at as submitter_user_created_at, s.updated_at as submitter_user_updated_at
-        FROM tickets t
-        LEFT JOIN users a ON t.assigned_to_user_id = a.id
-        LEFT JOIN users s ON t.end_user_email = s.email
-        WHERE t.id = $1
-    `, ticketID)
+	row := h.db.Pool.QueryRow(ctx, ticketDetailSelect+ticketDetailFrom+`
+        WHERE t.id = $1`, ticketID)
 
 	// Use the simplified scanning helper
 	ticket, err := scanTicketWithUsersAndSubmitter(row)
@@ -179,16 +219,205 @@ func (h *Handler) checkTicketAccess(
 		return ticket, nil
 	}
 
-	// Staff users can access tickets assigned to them or unassigned tickets.
+	// Staff users can access tickets assigned to them, tickets of their teams and
+	// tickets assigned to neither a user nor a team (db.TicketAccessCondition is
+	// the SQL form used by the list and search queries).
 	isAssignedToUser := ticket.AssignedToUserID != nil && *ticket.AssignedToUserID == userID
-	isUnassigned := ticket.AssignedToUserID == nil
+	isUnassigned := ticket.AssignedToUserID == nil && ticket.AssignedToTeamID == nil
 
 	if isAssignedToUser || isUnassigned {
 		logger.DebugContext(ctx, "Access granted (Assigned or Unassigned)")
 		return ticket, nil
 	}
 
+	isTeamMember, err := h.isTeamMember(ctx, ticket.AssignedToTeamID, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Database error during team access check", "error", err)
+		return ticket, fmt.Errorf("database error checking access: %w", err)
+	}
+	if isTeamMember {
+		logger.DebugContext(ctx, "Access granted (Team member)", "teamID", *ticket.AssignedToTeamID)
+		return ticket, nil
+	}
+
 	// If none of the above conditions match, the user is not authorized.
-	logger.WarnContext(ctx, "Access denied", "assignedUserID", ticket.AssignedToUserID)
+	logger.WarnContext(ctx, "Access denied", "assignedUserID", ticket.AssignedToUserID, "assignedTeamID", ticket.AssignedToTeamID)
 	return ticket, errors.New("not authorized to access this ticket") // Specific error type might be better
 }
+
+
+// --- Related Data Helpers ---
+
+// fetchTicketTags loads the tags linked to a ticket, ordered by name.
+func (h *Handler) fetchTicketTags(ctx context.Context, ticketID string) ([]models.Tag, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT tg.id, tg.name, tg.created_at
+        FROM tags tg
+        JOIN ticket_tags tt ON tg.id = tt.tag_id
+        WHERE tt.ticket_id = $1
+        ORDER BY tg.name ASC`, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := make([]models.Tag, 0)
+	for rows.Next() {
+		var tag models.Tag
+		if err := rows.Scan(&tag.ID, &tag.Name, &tag.CreatedAt); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// normalizeTagName returns a tag name's display form: trimmed, with inner
+// whitespace collapsed to single spaces. Case is preserved for display; tags
+// are matched case-insensitively (see findOrCreateTags).
+func normalizeTagName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// normalizeTagNames normalizes tag names, dropping empty names and
+// case-insensitive duplicates (the first spelling wins).
+func normalizeTagNames(names []string) []string {
+	normalized := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = normalizeTagName(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// fetchTicketAttachments retrieves a ticket's attachments in display order:
+// staff-ordered attachments first (by display_order), then the rest by upload time.
+func (h *Handler) fetchTicketAttachments(ctx context.Context, ticketID string) ([]models.Attachment, error) {
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT id, ticket_id, filename, storage_path, mime_type, size, uploaded_at, uploaded_by_user_id, uploaded_by_role, url, purged_at, preview_status, display_order, update_id
+        FROM attachments
+        WHERE ticket_id = $1
+        ORDER BY display_order ASC NULLS LAST, uploaded_at ASC`, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := make([]models.Attachment, 0)
+	for rows.Next() {
+		var att models.Attachment
+		var uploadedByUserID, uploadedByRole, url, previewStatus *string
+		if err := rows.Scan(
+			&att.ID, &att.TicketID, &att.Filename, &att.StoragePath, &att.MimeType, &att.Size, &att.UploadedAt,
+			&uploadedByUserID, &uploadedByRole, &url, &att.PurgedAt, &previewStatus, &att.DisplayOrder, &att.UpdateID,
+		); err != nil {
+			return nil, err
+		}
+		att.UploadedByUserID = stringValue(uploadedByUserID)
+		att.UploadedByRole = stringValue(uploadedByRole)
+		att.URL = stringValue(url)
+		att.PreviewStatus = stringValue(previewStatus)
+		att.PreviewURL = previewURL(att.ID, previewStatus)
+		// Generate download URL if not present in DB
+		if att.URL == "" {
+			att.URL = fmt.Sprintf("/api/attachments/download/%s", att.ID)
+		}
+		attachments = append(attachments, att)
+	}
+	return attachments, rows.Err()
+}
+
+// fetchTicketUpdates retrieves a ticket's comment thread, newest first,
+// including internal notes and tombstoned comments. Rows that fail to scan
+// are logged and skipped.
+func (h *Handler) fetchTicketUpdates(ctx context.Context, ticketID string) ([]models.TicketUpdate, error) {
+	logger := slog.With("helper", "fetchTicketUpdates", "ticketID", ticketID)
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT
+            tu.id, tu.ticket_id, tu.user_id, tu.comment, tu.is_internal_note, tu.created_at, tu.is_system_update, tu.from_submitter,
+            tu.edited_at, tu.deleted_at, tu.changes, tu.is_public_reply, tu.emailed_at,
+            u.id, u.name, u.email, u.role, u.created_at, u.updated_at
+        FROM ticket_updates tu
+        LEFT JOIN users u ON tu.user_id = u.id
+        WHERE tu.ticket_id = $1
+        ORDER BY tu.created_at DESC`, ticketID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	updates := make([]models.TicketUpdate, 0)
+	for rows.Next() {
+		var update models.TicketUpdate
+		var user models.User
+		var updateUserID, userID *string
+		var userName, userEmail, userRole *string
+		var userCreatedAt, userUpdatedAt *time.Time
+
+		if err := rows.Scan(
+			&update.ID, &update.TicketID, &updateUserID, &update.Comment,
+			&update.IsInternalNote, &update.CreatedAt, &update.IsSystemUpdate, &update.FromSubmitter,
+			&update.EditedAt, &update.DeletedAt, &update.Changes, &update.IsPublicReply, &update.EmailedAt,
+			&userID, &userName, &userEmail, &userRole,
+			&userCreatedAt, &userUpdatedAt,
+		); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan ticket update row", "error", err)
+			continue
+		}
+		if updateUserID != nil {
+			update.UserID = updateUserID
+			if userName != nil {
+				user.ID = *userID
+				user.Name = *userName
+				user.Email = *userEmail
+				user.Role = models.UserRole(*userRole)
+				user.CreatedAt = *userCreatedAt
+				user.UpdatedAt = *userUpdatedAt
+				update.User = &user
+			} else {
+				update.User = &models.User{ID: *updateUserID, Name: "Unknown User"}
+				logger.WarnContext(ctx, "User details not found for update author", "authorUserID", *updateUserID)
+			}
+		} else if update.FromSubmitter {
+			update.User = &models.User{Name: "Submitter"}
+		} else {
+			update.User = &models.User{Name: "System"}
+		}
+		updates = append(updates, update)
+	}
+	return updates, rows.Err()
+}
+
+// --- Locale Helper ---
+
+// submitterLocale resolves the language for submitter-facing messages: the
+// ticket's own locale if set, otherwise the matching user account's preference.
+// An empty result means the default locale.
+func (h *Handler) submitterLocale(ctx context.Context, ticketLocale, email string) string {
+	if ticketLocale != "" {
+		return ticketLocale
+	}
+	var preference *string
+	if err := h.db.Pool.QueryRow(ctx, `SELECT locale FROM users WHERE email = $1`, email).Scan(&preference); err != nil || preference == nil {
+		return ""
+	}
+	return *preference
+}
+
+// --- Submitter-Facing Staff Names ---
+
+// submitterFacingStaffName returns the staff name to show a submitter: the
+// name itself, or the localized "our support team" label when
+// TICKET_SHOW_ASSIGNEE_TO_SUBMITTER is off.
+func (h *Handler) submitterFacingStaffName(name, locale string) string {
+	if h.config.Tickets.ShowAssigneeToSubmitter {
+		return name
+	}
+	return i18n.T(locale, "email.support_team")
+}
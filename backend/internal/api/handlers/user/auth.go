@@ -64,9 +64,15 @@ func (h *Handler) Login(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusUnauthorized, "Invalid email or password.")
 	}
 
-	// --- 4. Generate JWT Token ---
+	// --- 4. Start a Login Session and Generate JWT Token ---
+	// The session records the device and address shown in GET /api/users/me/sessions
+	sessionID, err := h.sessions.Create(ctx, user.ID, c.Request().UserAgent(), c.RealIP(), time.Now().Add(h.config.Auth.JWTExpires))
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to create login session", "userID", user.ID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process login.")
+	}
 	// Use the injected authService to generate the token
-	token, err := h.authService.GenerateToken(user)
+	token, err := h.authService.GenerateToken(user, sessionID)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to generate JWT token", "userID", user.ID, "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process login.")
@@ -15,6 +15,8 @@ import (
 	"github.com/henrythedeveloper/it-ticket-system/internal/config" // Import config
 	"github.com/henrythedeveloper/it-ticket-system/internal/db"   // Corrected import path
 	"github.com/henrythedeveloper/it-ticket-system/internal/email" // Import email service
+	"github.com/henrythedeveloper/it-ticket-system/internal/preferences"
+	"github.com/henrythedeveloper/it-ticket-system/internal/sessions"
 	"github.com/labstack/echo/v4"
 )
 
@@ -22,10 +24,12 @@ import (
 
 // Handler holds dependencies for user-related request handlers.
 type Handler struct {
-	db           *db.DB        // Database connection pool
-	authService  auth.Service  // Service for authentication logic (hashing, tokens)
-	emailService email.Service // Service for sending emails (needed for registration/reset)
-	config       *config.Config // Access to config (e.g., for PortalBaseURL)
+	db           *db.DB               // Database connection pool
+	authService  auth.Service         // Service for authentication logic (hashing, tokens)
+	emailService email.Service        // Service for sending emails (needed for registration/reset)
+	config       *config.Config       // Access to config (e.g., for PortalBaseURL)
+	preferences  *preferences.Service // Notification preferences (GET/PUT /me/notification-preferences)
+	sessions     *sessions.Service    // Login sessions (created at login, listed/revoked under /me/sessions)
 }
 
 // --- Constructor ---
@@ -47,6 +51,8 @@ func NewHandler(db *db.DB, authService auth.Service, emailService email.Service,
 		authService:  authService,
 		emailService: emailService, // Add email service
 		config:       cfg,          // Add config
+		preferences:  preferences.NewService(db),
+		sessions:     sessions.NewService(db, cfg.Auth.SessionIdleTimeout),
 	}
 }
 
@@ -64,6 +70,7 @@ func RegisterAuthRoutes(g *echo.Group, h *Handler) {
 	g.POST("/register", h.RegisterUser)         // POST /api/auth/register
 	g.POST("/forgot-password", h.RequestPasswordReset) // POST /api/auth/forgot-password
 	g.POST("/reset-password", h.ResetPassword)   // POST /api/auth/reset-password
+	g.POST("/accept-invite", h.AcceptInvite)     // POST /api/auth/accept-invite
 	slog.Debug("Finished registering public authentication routes")
 }
 
@@ -80,6 +87,14 @@ func RegisterUserManagementRoutes(g *echo.Group, h *Handler, adminMiddleware ech
 	// Get current user's profile (already authenticated via group middleware)
 	g.GET("/me", h.GetCurrentUser) // GET /api/users/me
 
+	// Current user's notification preferences
+	g.GET("/me/notification-preferences", h.GetNotificationPreferences)    // GET /api/users/me/notification-preferences
+	g.PUT("/me/notification-preferences", h.UpdateNotificationPreferences) // PUT /api/users/me/notification-preferences
+
+	// Current user's login sessions
+	g.GET("/me/sessions", h.GetMySessions)                 // GET /api/users/me/sessions
+	g.DELETE("/me/sessions/:sessionId", h.RevokeMySession) // DELETE /api/users/me/sessions/{sessionId}
+
 	// Get all users (Admin only)
 	g.GET("", h.GetAllUsers, adminMiddleware) // GET /api/users
 
@@ -95,6 +110,9 @@ func RegisterUserManagementRoutes(g *echo.Group, h *Handler, adminMiddleware ech
 	// Delete a user (Admin only)
 	g.DELETE("/:id", h.DeleteUser, adminMiddleware) // DELETE /api/users/{id}
 
+	// Revoke all of a user's sessions (Admin only)
+	g.DELETE("/:id/sessions", h.RevokeUserSessions, adminMiddleware) // DELETE /api/users/{id}/sessions
+
 	slog.Debug("Finished registering user management routes")
 }
 
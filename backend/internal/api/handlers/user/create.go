@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Data models
 	"github.com/labstack/echo/v4"
 )
@@ -86,8 +87,11 @@ func (h *Handler) CreateUser(c echo.Context) error {
 		&createdUser.Role, &createdUser.CreatedAt, &createdUser.UpdatedAt,
 	)
 	if err != nil {
+		if db.IsUniqueViolation(err) { // Lost a race with a concurrent create for the same email
+			logger.WarnContext(ctx, "Attempted to create user with existing email", "email", userCreate.Email)
+			return echo.NewHTTPError(http.StatusConflict, "Email address is already in use.")
+		}
 		logger.ErrorContext(ctx, "Failed to insert user into database", "email", userCreate.Email, "error", err)
-		// TODO: Check for specific DB errors if needed
 		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create user.")
 	}
 
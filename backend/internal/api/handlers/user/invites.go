@@ -0,0 +1,301 @@
+// backend/internal/api/handlers/user/invites.go
+// ==========================================================================
+// Registration invites. Admins issue invite tokens (optionally scoped to one
+// email address, which is then emailed the signup link) that fix the new
+// account's role. The token is consumed by AcceptInvite or by RegisterUser;
+// in invite-only mode (AUTH_REGISTRATION_MODE=invite) a token is required to
+// register at all. Accepting an invite works in every mode, including
+// "disabled", since the admin chose to let that person in.
+// ==========================================================================
+
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// CreateInvite issues a registration invite. (Admin Only)
+// When the invite is scoped to an email, the signup link is emailed there.
+//
+// Request Body:
+//   - Expects JSON matching models.InviteCreate (role, optional email).
+//
+// Returns:
+//   - JSON APIResponse (201) with the models.Invite, including the raw token
+//     and signup link (shown only once), or an error response.
+func (h *Handler) CreateInvite(c echo.Context) error {
+	ctx := c.Request().Context()
+	adminID, _ := auth.GetUserIDFromContext(c)
+	logger := slog.With("handler", "CreateInvite", "adminID", adminID)
+
+	var req models.InviteCreate
+	if err := c.Bind(&req); err != nil {
+		logger.WarnContext(ctx, "Failed to bind request body", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+	if req.Role != models.RoleAdmin && req.Role != models.RoleStaff {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid user role specified. Must be 'Admin' or 'Staff'.")
+	}
+	var email *string
+	if trimmed := strings.TrimSpace(req.Email); trimmed != "" {
+		exists, err := emailExists(ctx, h.db, trimmed)
+		if err != nil {
+			logger.ErrorContext(ctx, "Failed to check email existence for invite", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create invite.")
+		}
+		if exists {
+			return echo.NewHTTPError(http.StatusConflict, "Email address is already registered.")
+		}
+		email = &trimmed
+	}
+
+	rawToken, err := h.authService.GenerateSecureRandomToken(32)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to generate invite token", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create invite.")
+	}
+
+	invite := models.Invite{Email: email, Role: req.Role, CreatedByUserID: &adminID}
+	if err := h.db.Pool.QueryRow(ctx, `
+        INSERT INTO user_invites (token_hash, email, role, created_by_user_id, expires_at)
+        VALUES ($1, $2, $3, NULLIF($4, '')::uuid, $5)
+        RETURNING id, created_at, expires_at`,
+		hashToken(rawToken), email, req.Role, adminID, time.Now().Add(h.config.Auth.InviteTTL),
+	).Scan(&invite.ID, &invite.CreatedAt, &invite.ExpiresAt); err != nil {
+		logger.ErrorContext(ctx, "Failed to store invite", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to create invite.")
+	}
+	invite.Token = rawToken
+	invite.RegistrationURL = h.inviteURL(rawToken)
+
+	message := "Invite created. The token is shown only once."
+	if email != nil {
+		if err := h.emailService.SendInvite(*email, string(invite.Role), invite.RegistrationURL, invite.ExpiresAt.UTC().Format(time.RFC1123)); err != nil {
+			// The invite is still valid; the admin can share the link manually
+			logger.ErrorContext(ctx, "Failed to send invite email", "inviteID", invite.ID, "error", err)
+			message = "Invite created, but the invite email could not be sent. Share the link manually; it is shown only once."
+		} else {
+			message = "Invite created and emailed. The token is shown only once."
+		}
+	}
+
+	logger.InfoContext(ctx, "Invite created", "inviteID", invite.ID, "role", invite.Role, "scopedToEmail", email != nil)
+	return c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    invite,
+	})
+}
+
+// GetPendingInvites lists invites that are neither used nor expired, newest
+// first. (Admin Only)
+//
+// Returns:
+//   - JSON APIResponse containing []models.Invite (without tokens), or an error response.
+func (h *Handler) GetPendingInvites(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "GetPendingInvites")
+
+	rows, err := h.db.Pool.Query(ctx, `
+        SELECT id, email, role, created_by_user_id, created_at, expires_at
+        FROM user_invites
+        WHERE used_at IS NULL AND expires_at > NOW()
+        ORDER BY created_at DESC`)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to query invites", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve invites.")
+	}
+	defer rows.Close()
+
+	invites := make([]models.Invite, 0)
+	for rows.Next() {
+		var invite models.Invite
+		if err := rows.Scan(&invite.ID, &invite.Email, &invite.Role, &invite.CreatedByUserID, &invite.CreatedAt, &invite.ExpiresAt); err != nil {
+			logger.ErrorContext(ctx, "Failed to scan invite", "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve invites.")
+		}
+		invites = append(invites, invite)
+	}
+	if err := rows.Err(); err != nil {
+		logger.ErrorContext(ctx, "Error iterating invites", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve invites.")
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    invites,
+	})
+}
+
+// RevokeInvite deletes an unused invite so its link stops working. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the invite.
+//
+// Returns:
+//   - JSON APIResponse on success, 404 if there is no such unused invite, or an error response.
+func (h *Handler) RevokeInvite(c echo.Context) error {
+	ctx := c.Request().Context()
+	inviteID := c.Param("id")
+	logger := slog.With("handler", "RevokeInvite", "inviteID", inviteID)
+
+	if _, err := uuid.Parse(inviteID); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Invite not found.")
+	}
+	tag, err := h.db.Pool.Exec(ctx, `DELETE FROM user_invites WHERE id = $1 AND used_at IS NULL`, inviteID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to revoke invite", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke invite.")
+	}
+	if tag.RowsAffected() == 0 {
+		return echo.NewHTTPError(http.StatusNotFound, "Invite not found.")
+	}
+
+	logger.InfoContext(ctx, "Invite revoked")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Invite revoked.",
+	})
+}
+
+// AcceptInvite creates an account from an invite link, with the role the
+// invite was issued for. Works regardless of AUTH_REGISTRATION_MODE.
+//
+// Request Body:
+//   - Expects JSON matching models.InviteAccept (token, name, password,
+//     confirmPassword; email only if the invite isn't scoped to one).
+//
+// Returns:
+//   - JSON APIResponse (201) with the created user, or an error response.
+func (h *Handler) AcceptInvite(c echo.Context) error {
+	ctx := c.Request().Context()
+	logger := slog.With("handler", "AcceptInvite")
+
+	var req models.InviteAccept
+	if err := c.Bind(&req); err != nil {
+		logger.WarnContext(ctx, "Failed to bind request body", "error", err)
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body: "+err.Error())
+	}
+	if req.Token == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invite token is required.")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Name is required.")
+	}
+	if req.Password != req.ConfirmPassword {
+		return echo.NewHTTPError(http.StatusBadRequest, "Passwords do not match.")
+	}
+	if len(req.Password) < 8 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Password must be at least 8 characters long.")
+	}
+
+	// A scoped invite decides the email; otherwise the invitee supplies it
+	email := strings.TrimSpace(req.Email)
+	var inviteEmail *string
+	err := h.db.Pool.QueryRow(ctx, `SELECT email FROM user_invites WHERE token_hash = $1`, hashToken(req.Token)).Scan(&inviteEmail)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.ErrorContext(ctx, "Failed to look up invite", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to accept invite.")
+	}
+	if inviteEmail != nil {
+		email = *inviteEmail
+	}
+	if email == "" && err == nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Email is required.")
+	}
+	if h.config.Auth.AllowedDomainsForInvites {
+		if err := h.checkEmailDomain(ctx, logger, email); err != nil {
+			return err
+		}
+	}
+
+	createdUser, err := h.createAccount(ctx, logger, models.UserRegister{
+		Name:            strings.TrimSpace(req.Name),
+		Email:           email,
+		Password:        req.Password,
+		ConfirmPassword: req.ConfirmPassword,
+		InviteToken:     req.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.InfoContext(ctx, "Invite accepted", "userID", createdUser.ID, "role", createdUser.Role)
+	return c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Your account has been created. You can now log in.",
+		Data:    createdUser,
+	})
+}
+
+// --- Helpers ---
+
+// inviteURL builds the portal signup link for an invite token.
+func (h *Handler) inviteURL(rawToken string) string {
+	return fmt.Sprintf("%s/register?invite=%s", h.config.Server.PortalBaseURL, url.QueryEscape(rawToken))
+}
+
+// consumeInvite marks an invite used for the given email inside tx and
+// returns its ID and role. The update is conditional, so two registrations
+// racing for one invite cannot both succeed.
+//
+// Returns:
+//   - string: The invite ID.
+//   - models.UserRole: The role the invite grants.
+//   - error: An *apierror.Error (to return as-is) if the invite is unknown,
+//     used, expired or for another email; otherwise a database error.
+func consumeInvite(ctx context.Context, tx pgx.Tx, rawToken, email string) (string, models.UserRole, error) {
+	tokenHash := hashToken(rawToken)
+	var inviteID string
+	var role models.UserRole
+	err := tx.QueryRow(ctx, `
+        UPDATE user_invites SET used_at = NOW()
+        WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+          AND (email IS NULL OR LOWER(email) = LOWER($2))
+        RETURNING id, role`, tokenHash, email).Scan(&inviteID, &role)
+	if err == nil {
+		return inviteID, role, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", "", fmt.Errorf("failed to claim invite: %w", err)
+	}
+
+	// Explain why the invite could not be claimed
+	var inviteEmail *string
+	var usedAt *time.Time
+	var expiresAt time.Time
+	err = tx.QueryRow(ctx, `SELECT email, used_at, expires_at FROM user_invites WHERE token_hash = $1`, tokenHash).
+		Scan(&inviteEmail, &usedAt, &expiresAt)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return "", "", invalidInvite(http.StatusBadRequest, "Invalid invite token.")
+	case err != nil:
+		return "", "", fmt.Errorf("failed to look up invite: %w", err)
+	case usedAt != nil:
+		return "", "", invalidInvite(http.StatusBadRequest, "This invite has already been used.")
+	case !time.Now().Before(expiresAt):
+		return "", "", invalidInvite(http.StatusBadRequest, "This invite has expired. Please ask an administrator for a new one.")
+	default:
+		return "", "", invalidInvite(http.StatusForbidden, "This invite was issued for a different email address.")
+	}
+}
+
+// invalidInvite builds the client error for an unusable invite.
+func invalidInvite(status int, message string) error {
+	return apierror.New(status, apierror.CodeInvalidInvite, message)
+}
@@ -0,0 +1,92 @@
+// backend/internal/api/handlers/user/notification_preferences.go
+// ==========================================================================
+// Handlers for the current user's notification preferences: which categories
+// of notification they receive by email and in-app.
+// ==========================================================================
+
+package user
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preferences"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// GetNotificationPreferences returns the current user's full preference
+// matrix (every category and channel; defaults are enabled).
+//
+// Returns:
+//   - JSON APIResponse containing models.NotificationPreferences, or an error response.
+func (h *Handler) GetNotificationPreferences(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	logger := slog.With("handler", "GetNotificationPreferences", "userID", userID)
+
+	prefs, err := h.preferences.Get(ctx, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to load notification preferences", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve notification preferences.")
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    prefs,
+	})
+}
+
+// UpdateNotificationPreferences changes some or all of the current user's
+// preferences. Categories and channels left out of the body are unchanged.
+//
+// Request Body:
+//   - A models.NotificationPreferences object, e.g. {"assignment": {"email": false}}.
+//
+// Returns:
+//   - JSON APIResponse containing the updated full preference matrix, or an error response.
+func (h *Handler) UpdateNotificationPreferences(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	logger := slog.With("handler", "UpdateNotificationPreferences", "userID", userID)
+
+	var req models.NotificationPreferences
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body.")
+	}
+	for category, channels := range req {
+		for channel := range channels {
+			if !preferences.Valid(category, channel) {
+				return echo.NewHTTPError(http.StatusBadRequest,
+					fmt.Sprintf("Unknown notification preference %q/%q. Categories: %v; channels: %v.",
+						category, channel, preferences.Categories, preferences.Channels))
+			}
+		}
+	}
+
+	if err := h.preferences.Update(ctx, userID, req); err != nil {
+		logger.ErrorContext(ctx, "Failed to save notification preferences", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to save notification preferences.")
+	}
+	prefs, err := h.preferences.Get(ctx, userID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to reload notification preferences", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve notification preferences.")
+	}
+
+	logger.InfoContext(ctx, "Notification preferences updated")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Notification preferences updated.",
+		Data:    prefs,
+	})
+}
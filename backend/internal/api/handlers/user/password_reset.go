@@ -1,7 +1,8 @@
 // backend/internal/api/handlers/user/password_reset.go
 // ==========================================================================
 // Handler functions for requesting and performing password resets.
-// **REVISED**: Switched to storing and querying RAW reset tokens in 'token' column.
+// Only a SHA-256 hash of each emailed token is stored, and a token can reset
+// a password once: it is marked used in the same transaction as the update.
 // ==========================================================================
 
 package user
@@ -13,30 +14,46 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Data models
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
 
 const (
-	// Define password reset token validity duration
-	passwordResetTokenValidity = 1 * time.Hour
-
-	// --- SQL Query Constants for Password Reset (Using RAW Token) ---
-	// *** Use 'token' column (VARCHAR) ***
+	// --- SQL Query Constants for Password Reset (token_hash = hex SHA-256 of the emailed token) ---
 	QueryInsertPasswordResetToken = `
-		INSERT INTO password_reset_tokens (token, user_id, expires_at, created_at)
+		INSERT INTO password_reset_tokens (token_hash, user_id, expires_at, created_at)
 		VALUES ($1, $2, $3, $4)`
 
-	// *** Use 'token' column ***
+	// Claims an unused, unexpired token; it cannot be claimed again
+	QueryUsePasswordResetToken = `
+		UPDATE password_reset_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id`
+
+	// Explains why a token could not be claimed
 	QueryFindPasswordResetToken = `
-		SELECT user_id, expires_at FROM password_reset_tokens WHERE token = $1` // Query by raw token
+		SELECT used_at, expires_at FROM password_reset_tokens WHERE token_hash = $1`
+
+	// Other links already emailed to the user stop working after a reset
+	QueryInvalidateUserResetTokens = `
+		UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL`
+
+	QueryRecentResetTokenExists = `
+		SELECT EXISTS(SELECT 1 FROM password_reset_tokens WHERE user_id = $1 AND created_at > $2)`
 
-	// *** Use 'token' column ***
-	QueryDeletePasswordResetToken = `
-		DELETE FROM password_reset_tokens WHERE token = $1` // Delete by raw token
+	// --- Forgot-password request log (rate limiting) ---
+	QueryCountPasswordResetRequests = `
+		SELECT COUNT(*) FILTER (WHERE email = $1), COUNT(*) FILTER (WHERE ip_address = $2)
+		FROM password_reset_requests
+		WHERE requested_at > $3 AND (email = $1 OR ip_address = $2)`
+
+	QueryInsertPasswordResetRequest = `
+		INSERT INTO password_reset_requests (email, ip_address) VALUES ($1, NULLIF($2, ''))`
 
 	QueryDeleteExpiredTokens = `
 		DELETE FROM password_reset_tokens WHERE expires_at < NOW()`
@@ -57,37 +74,50 @@ func (h *Handler) RequestPasswordReset(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body: "+err.Error())
 	}
 
+	req.Email = strings.TrimSpace(req.Email)
 	logger.InfoContext(ctx, "Password reset requested", "email", req.Email)
 
+	// --- Rate Limit (before the lookup, so limits don't depend on whether the account exists) ---
+	if err := h.checkPasswordResetRate(ctx, req.Email, c.RealIP(), logger); err != nil {
+		return err
+	}
+
 	// --- Find User by Email ---
 	user, err := getUserByEmail(ctx, h.db, req.Email)
 	if err != nil {
-		if errors.Is(err, errors.New("user not found")) {
+		if err.Error() == "user not found" {
 			logger.WarnContext(ctx, "Password reset requested for non-existent email", "email", req.Email)
-			return c.JSON(http.StatusOK, models.APIResponse{
-				Success: true, // Lie about success for security
-				Message: "If an account with that email exists, a password reset link has been sent.",
-			})
+			return passwordResetAccepted(c) // Lie about success for security
 		}
 		logger.ErrorContext(ctx, "Database error looking up user for password reset", "email", req.Email, "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "An internal error occurred.")
 	}
 
+	// --- Throttle Reset Emails per Account ---
+	if interval := h.config.Auth.PasswordResetMinInterval; interval > 0 {
+		var recent bool
+		if err := h.db.Pool.QueryRow(ctx, QueryRecentResetTokenExists, user.ID, time.Now().Add(-interval)).Scan(&recent); err != nil {
+			logger.ErrorContext(ctx, "Failed to check for a recent reset token", "userID", user.ID, "error", err)
+			return echo.NewHTTPError(http.StatusInternalServerError, "An internal error occurred.")
+		}
+		if recent {
+			logger.InfoContext(ctx, "Reset email already sent recently; not issuing another", "userID", user.ID)
+			return passwordResetAccepted(c)
+		}
+	}
+
 	// --- Generate and Store Token ---
-	// Generate a secure random token (the raw token sent to the user AND stored)
+	// Generate a secure random token; only its hash is stored, the raw value is emailed
 	rawToken, err := h.authService.GenerateSecureRandomToken(32)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to generate secure token", "userID", user.ID, "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to initiate password reset.")
 	}
 
-	// *** No Hashing Needed Here ***
+	expiresAt := time.Now().Add(h.config.Auth.PasswordResetTTL)
 
-	expiresAt := time.Now().Add(passwordResetTokenValidity)
-
-	// *** Store the RAW token, user ID, and expiry in the database 'token' column ***
 	_, err = h.db.Pool.Exec(ctx, QueryInsertPasswordResetToken,
-		rawToken, user.ID, expiresAt, time.Now(), // Store rawToken
+		hashToken(rawToken), user.ID, expiresAt, time.Now(),
 	)
 	if err != nil {
 		// Check for potential primary key violation (token collision - extremely rare but possible)
@@ -112,12 +142,50 @@ func (h *Handler) RequestPasswordReset(c echo.Context) error {
 
 	// --- Return Generic Success Response ---
 	logger.InfoContext(ctx, "Password reset initiated successfully", "userID", user.ID, "email", user.Email)
+	return passwordResetAccepted(c)
+}
+
+// passwordResetAccepted writes the response every accepted forgot-password
+// request gets, whether or not an email was actually sent.
+func passwordResetAccepted(c echo.Context) error {
 	return c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
 		Message: "If an account with that email exists, a password reset link has been sent.",
 	})
 }
 
+// checkPasswordResetRate enforces the per-email and per-IP forgot-password
+// limits and records the request when it is allowed.
+//
+// Returns:
+//   - error: 429 when a limit is reached, 500 on a database error, nil otherwise.
+func (h *Handler) checkPasswordResetRate(ctx context.Context, email, ip string, logger *slog.Logger) error {
+	cfg := h.config.Auth
+	if cfg.PasswordResetMaxPerEmail <= 0 && cfg.PasswordResetMaxPerIP <= 0 {
+		return nil
+	}
+	email = strings.ToLower(email)
+	since := time.Now().Add(-cfg.PasswordResetRateWindow)
+
+	var emailCount, ipCount int
+	if err := h.db.Pool.QueryRow(ctx, QueryCountPasswordResetRequests, email, ip, since).Scan(&emailCount, &ipCount); err != nil {
+		logger.ErrorContext(ctx, "Failed to count password reset requests", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "An internal error occurred.")
+	}
+	if (cfg.PasswordResetMaxPerEmail > 0 && emailCount >= cfg.PasswordResetMaxPerEmail) ||
+		(cfg.PasswordResetMaxPerIP > 0 && ipCount >= cfg.PasswordResetMaxPerIP) {
+		logger.WarnContext(ctx, "Password reset rate limit reached", "ip", ip, "emailCount", emailCount, "ipCount", ipCount)
+		return apierror.New(http.StatusTooManyRequests, apierror.CodeRateLimited,
+			"Too many password reset requests. Please wait a while before trying again.")
+	}
+
+	if _, err := h.db.Pool.Exec(ctx, QueryInsertPasswordResetRequest, email, ip); err != nil {
+		logger.ErrorContext(ctx, "Failed to record password reset request", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "An internal error occurred.")
+	}
+	return nil
+}
+
 // ResetPassword handles the request to set a new password using a reset token.
 func (h *Handler) ResetPassword(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -146,54 +214,54 @@ func (h *Handler) ResetPassword(c echo.Context) error {
 
 	logger.DebugContext(ctx, "Password reset attempt received") // Avoid logging token
 
-	// --- Validate Token ---
-	// 1. *** No Hashing Needed Here ***
+	// --- Hash New Password (before taking the token, so a hashing failure doesn't burn it) ---
+	newPasswordHash, err := h.authService.HashPassword(req.NewPassword)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to hash new password", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Error processing reset request.")
+	}
 
-	// 2. *** Find the RAW token in the database 'token' column ***
-	var userID string
-	var expiresAt time.Time
-	err := h.db.Pool.QueryRow(ctx, QueryFindPasswordResetToken, req.Token).Scan(&userID, &expiresAt) // Query by RAW token
+	tokenHash := hashToken(req.Token)
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin password reset transaction", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Error processing reset request.")
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
 
+	// --- Claim the Token (single use) ---
+	var userID string
+	err = tx.QueryRow(ctx, QueryUsePasswordResetToken, tokenHash).Scan(&userID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, sql.ErrNoRows) {
-			logger.WarnContext(ctx, "Invalid or expired password reset token provided (token not found)")
-			return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired password reset token.")
+			return h.rejectResetToken(ctx, tokenHash, logger)
 		}
-		logger.ErrorContext(ctx, "Database error finding password reset token", "error", err)
+		logger.ErrorContext(ctx, "Database error claiming password reset token", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Error processing reset request.")
 	}
 
-	// 3. Check Expiry
-	if time.Now().After(expiresAt) {
-		logger.WarnContext(ctx, "Expired password reset token provided", "userID", userID, "expiresAt", expiresAt)
-		// Attempt to delete the expired token (best effort)
-		_, delErr := h.db.Pool.Exec(ctx, QueryDeletePasswordResetToken, req.Token) // Delete by RAW token
-		if delErr != nil {
-			logger.ErrorContext(ctx, "Failed to delete expired token", "error", delErr)
-		}
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired password reset token.")
+	// --- Update User Password ---
+	if _, err = tx.Exec(ctx, QueryUpdateUserPassword, newPasswordHash, time.Now(), userID); err != nil {
+		logger.ErrorContext(ctx, "Failed to update user password in database", "userID", userID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update password.")
 	}
 
-	// --- Update User Password ---
-	newPasswordHash, err := h.authService.HashPassword(req.NewPassword)
-	if err != nil {
-		logger.ErrorContext(ctx, "Failed to hash new password", "userID", userID, "error", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Error processing reset request.")
+	// --- Invalidate the User's Other Outstanding Tokens ---
+	if _, err = tx.Exec(ctx, QueryInvalidateUserResetTokens, userID); err != nil {
+		logger.ErrorContext(ctx, "Failed to invalidate outstanding reset tokens", "userID", userID, "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update password.")
 	}
 
-	// Update the password in the users table
-	_, err = h.db.Pool.Exec(ctx, QueryUpdateUserPassword, newPasswordHash, time.Now(), userID)
-	if err != nil {
-		logger.ErrorContext(ctx, "Failed to update user password in database", "userID", userID, "error", err)
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit password reset", "userID", userID, "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to update password.")
 	}
 
-	// --- Invalidate Used Token ---
-	// Delete the token now that it's been used successfully
-	_, err = h.db.Pool.Exec(ctx, QueryDeletePasswordResetToken, req.Token) // Delete by RAW token
-	if err != nil {
+	// --- Sign Out Existing Sessions ---
+	// Whoever triggered the reset may be locking out someone holding the old password
+	if _, err := h.sessions.RevokeAll(ctx, userID); err != nil {
 		// Log the error but don't fail the request, password update was successful
-		logger.ErrorContext(ctx, "Failed to delete used password reset token", "userID", userID, "error", err)
+		logger.ErrorContext(ctx, "Failed to revoke sessions after password reset", "userID", userID, "error", err)
 	}
 
 	// --- Return Success Response ---
@@ -204,6 +272,28 @@ func (h *Handler) ResetPassword(c echo.Context) error {
 	})
 }
 
+// rejectResetToken builds the error for a token that could not be claimed,
+// telling replayed and expired links apart from unknown ones.
+func (h *Handler) rejectResetToken(ctx context.Context, tokenHash string, logger *slog.Logger) error {
+	var usedAt *time.Time
+	var expiresAt time.Time
+	err := h.db.Pool.QueryRow(ctx, QueryFindPasswordResetToken, tokenHash).Scan(&usedAt, &expiresAt)
+	switch {
+	case err == nil && usedAt != nil:
+		logger.WarnContext(ctx, "Password reset token reused", "usedAt", *usedAt)
+		return apierror.New(http.StatusBadRequest, apierror.CodeResetTokenUsed, "This password reset link has already been used. Please request a new one.")
+	case err == nil:
+		logger.WarnContext(ctx, "Expired password reset token provided", "expiresAt", expiresAt)
+		return apierror.New(http.StatusBadRequest, apierror.CodeResetTokenExpired, "This password reset link has expired. Please request a new one.")
+	case errors.Is(err, pgx.ErrNoRows):
+		logger.WarnContext(ctx, "Invalid or expired password reset token provided (token not found)")
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid or expired password reset token.")
+	default:
+		logger.ErrorContext(ctx, "Database error finding password reset token", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Error processing reset request.")
+	}
+}
+
 // --- Optional: Background Task for Token Cleanup ---
 
 // CleanupExpiredResetTokens deletes tokens that have passed their expiry time.
@@ -9,37 +9,98 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth" // Auth helpers
 	"github.com/henrythedeveloper/it-ticket-system/internal/models"              // Data models
+	"github.com/henrythedeveloper/it-ticket-system/internal/pagination"
 	"github.com/labstack/echo/v4"
 )
 
 // --- Handler Functions ---
 
-// GetAllUsers retrieves a list of all users in the system.
-// This endpoint is restricted to Admin users via middleware.
+// GetAllUsers retrieves a paginated, filterable list of users.
+// Admins receive full user objects; other callers (Staff) receive only
+// id, name and role (models.UserSummary).
+//
+// Query Parameters:
+//   - page / limit: Pagination (default limit 20; larger limits are clamped to API_MAX_PAGE_SIZE).
+//   - search: Optional case-insensitive match on name or email.
+//   - role: Optional role filter; comma-separated for several (e.g., "Admin,Staff").
+//   - sortBy: "name" (default) or "createdAt".
+//   - sortOrder: "asc" (default) or "desc".
 //
 // Returns:
-//   - JSON response containing an array of user objects (excluding password hashes)
+//   - JSON PaginatedResponse containing user objects (excluding password hashes)
 //     or an error response.
 func (h *Handler) GetAllUsers(c echo.Context) error {
 	ctx := c.Request().Context()
 	logger := slog.With("handler", "GetAllUsers")
 
-	// --- 1. Fetch Users from Database ---
+	// --- 1. Parse Query Parameters ---
+	limit := pagination.Limit(c.QueryParam("limit"), 20, h.config.Server.MaxPageSize)
+	page := 1
+	if parsed, err := strconv.Atoi(c.QueryParam("page")); err == nil && parsed > 0 {
+		page = parsed
+	}
+	filter := userListFilter{
+		Search:    strings.TrimSpace(c.QueryParam("search")),
+		SortBy:    c.QueryParam("sortBy"),
+		SortOrder: c.QueryParam("sortOrder"),
+		Limit:     limit,
+		Offset:    (page - 1) * limit,
+	}
+	if roleParam := c.QueryParam("role"); roleParam != "" {
+		for _, value := range strings.Split(roleParam, ",") {
+			role := models.UserRole(strings.TrimSpace(value))
+			switch role {
+			case models.RoleAdmin, models.RoleStaff, models.RoleUser:
+				filter.Roles = append(filter.Roles, role)
+			case "":
+			default:
+				return echo.NewHTTPError(http.StatusBadRequest, "Invalid role filter. Use 'Admin', 'Staff' or 'User'.")
+			}
+		}
+	}
+	if filter.SortBy != "" {
+		if _, ok := userSortColumns[filter.SortBy]; !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid sortBy. Use 'name' or 'createdAt'.")
+		}
+	}
+
+	// --- 2. Fetch Users from Database ---
 	// Use the helper function which excludes password hashes
-	users, err := getAllUsers(ctx, h.db)
+	users, total, err := listUsers(ctx, h.db, filter)
 	if err != nil {
 		// Error is already logged in the helper
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve users.")
 	}
 
-	// --- 2. Return Success Response ---
-	logger.InfoContext(ctx, "Retrieved all users", "count", len(users))
-	return c.JSON(http.StatusOK, models.APIResponse{
-		Success: true,
-		Data:    users,
+	// --- 3. Restrict Fields for Non-Admins ---
+	var data interface{} = users
+	if role, roleErr := auth.GetUserRoleFromContext(c); roleErr != nil || role != models.RoleAdmin {
+		summaries := make([]models.UserSummary, len(users))
+		for i, u := range users {
+			summaries[i] = models.UserSummary{ID: u.ID, Name: u.Name, Role: u.Role}
+		}
+		data = summaries
+	}
+
+	// --- 4. Return Paginated Response ---
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+	logger.InfoContext(ctx, "Retrieved users", "count", len(users), "total", total, "page", page)
+	return c.JSON(http.StatusOK, models.PaginatedResponse{
+		Success:    true,
+		Data:       data,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: totalPages,
+		HasMore:    page < totalPages,
 	})
 }
 
@@ -93,10 +154,12 @@ func (h *Handler) GetUserByID(c echo.Context) error {
 
 // GetCurrentUser retrieves the profile details of the currently authenticated user.
 // Relies on the JWT middleware to extract the user ID from the token.
+// The response also carries the user's resolved permissions, computed from the
+// role stored in the database (not the possibly stale token role).
 //
 // Returns:
-//   - JSON response containing the current user's object (excluding password hash)
-//     or an error response if not authenticated or user not found.
+//   - JSON response containing models.CurrentUser (profile without password hash,
+//     plus permissions) or an error response if not authenticated or user not found.
 func (h *Handler) GetCurrentUser(c echo.Context) error {
 	ctx := c.Request().Context()
 	logger := slog.With("handler", "GetCurrentUser")
@@ -129,6 +192,9 @@ func (h *Handler) GetCurrentUser(c echo.Context) error {
 	logger.InfoContext(ctx, "Retrieved current user profile successfully")
 	return c.JSON(http.StatusOK, models.APIResponse{
 		Success: true,
-		Data:    user,
+		Data: models.CurrentUser{
+			User:        user,
+			Permissions: auth.ResolvePermissions(user.Role, h.config.Tickets),
+		},
 	})
 }
@@ -1,17 +1,25 @@
 // backend/internal/api/handlers/user/register.go
 // ==========================================================================
 // Handler function for public user registration.
-// Creates a new user with the default 'Staff' role.
+// Creates a new user with the default 'Staff' role, or with the role of the
+// supplied invite. AUTH_REGISTRATION_MODE can require an invite or turn
+// self-registration off entirely.
 // ==========================================================================
 
 package user
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 	"context"
 
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Data models
 	"github.com/labstack/echo/v4"
 )
@@ -19,7 +27,8 @@ import (
 // RegisterUser handles the HTTP request for public user registration.
 //
 // Request Body:
-//   - Expects JSON matching models.UserRegister (name, email, password, confirmPassword).
+//   - Expects JSON matching models.UserRegister (name, email, password, confirmPassword,
+//     and inviteToken when registration is invite-only).
 //
 // Returns:
 //   - JSON response indicating success or failure, potentially including basic user info
@@ -28,6 +37,13 @@ func (h *Handler) RegisterUser(c echo.Context) error {
 	ctx := c.Request().Context()
 	logger := slog.With("handler", "RegisterUser")
 
+	// --- 0. Registration Mode ---
+	mode := h.config.Auth.RegistrationMode
+	if mode == config.RegistrationDisabled {
+		logger.WarnContext(ctx, "Registration attempt while self-registration is disabled")
+		return apierror.New(http.StatusForbidden, apierror.CodeRegistrationClosed, "Self-registration is disabled. Please ask an administrator to create your account.")
+	}
+
 	// --- 1. Bind and Validate Request Body ---
 	var userRegister models.UserRegister
 	if err := c.Bind(&userRegister); err != nil {
@@ -45,44 +61,115 @@ func (h *Handler) RegisterUser(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, "Password must be at least 8 characters long.")
 	}
 
+	if mode == config.RegistrationInvite && userRegister.InviteToken == "" {
+		logger.WarnContext(ctx, "Registration attempt without an invite", "email", userRegister.Email)
+		return apierror.New(http.StatusForbidden, apierror.CodeInviteRequired, "Registration is by invitation only. Please use the link from your invite.")
+	}
+	if userRegister.InviteToken == "" || h.config.Auth.AllowedDomainsForInvites {
+		if err := h.checkEmailDomain(ctx, logger, userRegister.Email); err != nil {
+			return err
+		}
+	}
+
 	logger.DebugContext(ctx, "Registration request received", "email", userRegister.Email, "name", userRegister.Name)
 
-	// --- 2. Check if Email Already Exists ---
-	exists, err := emailExists(ctx, h.db, userRegister.Email)
+	// --- 2. Create the Account ---
+	createdUser, err := h.createAccount(ctx, logger, userRegister)
+	if err != nil {
+		return err
+	}
+
+	// --- 3. Return Success Response ---
+	// Exclude password hash from the response
+	createdUser.PasswordHash = ""
+	logger.InfoContext(ctx, "User registered successfully", "userID", createdUser.ID, "email", createdUser.Email, "role", createdUser.Role)
+	return c.JSON(http.StatusCreated, models.APIResponse{
+		Success: true,
+		Message: "Registration successful! Please check your email for confirmation.",
+		Data:    createdUser, // Return basic user info
+	})
+}
+
+// createAccount creates the account for a registration: it consumes the
+// invite (if any) for the account's role, inserts the user and sends the
+// welcome email. Shared by RegisterUser and AcceptInvite.
+//
+// Returns:
+//   - models.User: The created user (without password hash).
+//   - error: An HTTP error ready to return from the handler.
+func (h *Handler) createAccount(ctx context.Context, logger *slog.Logger, reg models.UserRegister) (models.User, error) {
+	// --- 1. Check if Email Already Exists ---
+	exists, err := emailExists(ctx, h.db, reg.Email)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to check email existence during registration", "error", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Database error checking email.")
+		return models.User{}, echo.NewHTTPError(http.StatusInternalServerError, "Database error checking email.")
 	}
 	if exists {
-		logger.WarnContext(ctx, "Registration attempt with existing email", "email", userRegister.Email)
-		return echo.NewHTTPError(http.StatusConflict, "Email address is already registered.")
+		logger.WarnContext(ctx, "Registration attempt with existing email", "email", reg.Email)
+		return models.User{}, echo.NewHTTPError(http.StatusConflict, "Email address is already registered.")
 	}
 
-	// --- 3. Hash Password ---
-	passwordHash, err := h.authService.HashPassword(userRegister.Password)
+	// --- 2. Hash Password ---
+	passwordHash, err := h.authService.HashPassword(reg.Password)
 	if err != nil {
 		logger.ErrorContext(ctx, "Failed to hash password during registration", "error", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process registration data.")
+		return models.User{}, echo.NewHTTPError(http.StatusInternalServerError, "Failed to process registration data.")
 	}
 
-	// --- 4. Insert User into Database with Default Role 'Staff' ---
-	var createdUser models.User
+	tx, err := h.db.Pool.Begin(ctx)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to begin registration transaction", "error", err)
+		return models.User{}, echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create user account.")
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	// --- 3. Resolve Role: the invite's, or the default 'Staff' ---
 	// ** CHANGE: Set default role to Staff **
-	defaultRole := models.RoleStaff
-	err = h.db.Pool.QueryRow(ctx, QueryCreateUser, // Use the existing create user query
-		userRegister.Name,
-		userRegister.Email,
+	role := models.RoleStaff
+	var inviteID string
+	if reg.InviteToken != "" {
+		inviteID, role, err = consumeInvite(ctx, tx, reg.InviteToken, reg.Email)
+		if err != nil {
+			var apiErr *apierror.Error
+			if errors.As(err, &apiErr) {
+				logger.WarnContext(ctx, "Registration rejected: unusable invite", "email", reg.Email, "reason", apiErr.Message)
+				return models.User{}, apiErr
+			}
+			logger.ErrorContext(ctx, "Failed to claim invite during registration", "error", err)
+			return models.User{}, echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create user account.")
+		}
+	}
+
+	// --- 4. Insert User into Database ---
+	var createdUser models.User
+	err = tx.QueryRow(ctx, QueryCreateUser, // Use the existing create user query
+		reg.Name,
+		reg.Email,
 		passwordHash,
-		defaultRole, // Use the default role variable
-		time.Now(),  // created_at
-		time.Now(),  // updated_at
+		role,
+		time.Now(), // created_at
+		time.Now(), // updated_at
 	).Scan(
 		&createdUser.ID, &createdUser.Name, &createdUser.Email,
 		&createdUser.Role, &createdUser.CreatedAt, &createdUser.UpdatedAt,
 	)
 	if err != nil {
-		logger.ErrorContext(ctx, "Failed to insert user during registration", "email", userRegister.Email, "error", err)
-		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create user account.")
+		if db.IsUniqueViolation(err) { // Lost a race with a concurrent registration for the same email
+			logger.WarnContext(ctx, "Registration attempt with existing email", "email", reg.Email)
+			return models.User{}, echo.NewHTTPError(http.StatusConflict, "Email address is already registered.")
+		}
+		logger.ErrorContext(ctx, "Failed to insert user during registration", "email", reg.Email, "error", err)
+		return models.User{}, echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create user account.")
+	}
+	if inviteID != "" {
+		if _, err = tx.Exec(ctx, `UPDATE user_invites SET used_by_user_id = $1 WHERE id = $2`, createdUser.ID, inviteID); err != nil {
+			logger.ErrorContext(ctx, "Failed to link invite to new user", "inviteID", inviteID, "error", err)
+			return models.User{}, echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create user account.")
+		}
+	}
+	if err = tx.Commit(ctx); err != nil {
+		logger.ErrorContext(ctx, "Failed to commit registration", "email", reg.Email, "error", err)
+		return models.User{}, echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to create user account.")
 	}
 
 	// --- 5. Send Confirmation Email (Asynchronous) ---
@@ -97,14 +184,41 @@ func (h *Handler) RegisterUser(c echo.Context) error {
 		}
 	}(createdUser.Email, createdUser.Name)
 
-	// --- 6. Return Success Response ---
-	// Exclude password hash from the response
-	createdUser.PasswordHash = ""
-	logger.InfoContext(ctx, "User registered successfully", "userID", createdUser.ID, "email", createdUser.Email, "role", createdUser.Role)
-	return c.JSON(http.StatusCreated, models.APIResponse{
-		Success: true,
-		Message: "Registration successful! Please check your email for confirmation.",
-		Data:    createdUser, // Return basic user info
-	})
+	return createdUser, nil
+}
+
+// checkEmailDomain rejects registrations from email domains outside
+// AUTH_ALLOWED_EMAIL_DOMAINS. An empty list allows every domain.
+//
+// Returns:
+//   - error: 403 with the allowed domains when the domain isn't allowed, nil otherwise.
+func (h *Handler) checkEmailDomain(ctx context.Context, logger *slog.Logger, email string) error {
+	allowed := h.config.Auth.AllowedEmailDomains
+	if len(allowed) == 0 || emailDomainAllowed(email, allowed) {
+		return nil
+	}
+	logger.WarnContext(ctx, "Registration rejected: email domain not allowed", "email", email)
+	return apierror.New(http.StatusForbidden, apierror.CodeDomainNotAllowed,
+		fmt.Sprintf("Registration is limited to these email domains: %s.", strings.Join(allowed, ", ")))
 }
 
+// emailDomainAllowed reports whether the email's domain matches one of the
+// allowed entries. "example.com" matches only that domain; "*.example.com"
+// matches its subdomains (e.g. "it.example.com") but not "example.com" itself.
+func emailDomainAllowed(email string, allowed []string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(strings.TrimSpace(email[at+1:]))
+	for _, entry := range allowed {
+		if suffix, wildcard := strings.CutPrefix(entry, "*."); wildcard {
+			if strings.HasSuffix(domain, "."+suffix) {
+				return true
+			}
+		} else if domain == entry {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+// backend/internal/api/handlers/user/sessions.go
+// ==========================================================================
+// Handlers for login sessions: the current user can list their active
+// sessions and sign one out; admins can sign a user out everywhere.
+// ==========================================================================
+
+package user
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Handler Functions ---
+
+// GetMySessions lists the current user's active login sessions, most
+// recently used first. The session making the request has current=true.
+//
+// Returns:
+//   - JSON APIResponse containing []models.Session, or an error response.
+func (h *Handler) GetMySessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	logger := slog.With("handler", "GetMySessions", "userID", userID)
+
+	list, err := h.sessions.List(ctx, userID, auth.OptionalSessionID(c))
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to list sessions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve sessions.")
+	}
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    list,
+	})
+}
+
+// RevokeMySession signs out one of the current user's sessions. Revoking the
+// current session works like logging out.
+//
+// Path Parameters:
+//   - sessionId: The UUID of the session to revoke.
+//
+// Returns:
+//   - JSON APIResponse on success, 404 if the user has no such active session, or an error response.
+func (h *Handler) RevokeMySession(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, err := auth.GetUserIDFromContext(c)
+	if err != nil {
+		return err
+	}
+	sessionID := c.Param("sessionId")
+	logger := slog.With("handler", "RevokeMySession", "userID", userID, "sessionID", sessionID)
+
+	if _, err := uuid.Parse(sessionID); err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "Session not found.")
+	}
+	revoked, err := h.sessions.Revoke(ctx, userID, sessionID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to revoke session", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke session.")
+	}
+	if !revoked {
+		return echo.NewHTTPError(http.StatusNotFound, "Session not found.")
+	}
+
+	logger.InfoContext(ctx, "Session revoked by user")
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: "Session revoked.",
+	})
+}
+
+// RevokeUserSessions signs a user out of every session. (Admin Only)
+//
+// Path Parameters:
+//   - id: The UUID of the user.
+//
+// Returns:
+//   - JSON APIResponse with the number of revoked sessions, 404 if the user does not exist, or an error response.
+func (h *Handler) RevokeUserSessions(c echo.Context) error {
+	ctx := c.Request().Context()
+	targetUserID := c.Param("id")
+	adminID, _ := auth.GetUserIDFromContext(c)
+	logger := slog.With("handler", "RevokeUserSessions", "targetUserID", targetUserID, "adminID", adminID)
+
+	if _, err := getUserByID(ctx, h.db, targetUserID); err != nil {
+		if err.Error() == "user not found" {
+			return echo.NewHTTPError(http.StatusNotFound, "User not found.")
+		}
+		logger.ErrorContext(ctx, "Failed to load user", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke sessions.")
+	}
+
+	count, err := h.sessions.RevokeAll(ctx, targetUserID)
+	if err != nil {
+		logger.ErrorContext(ctx, "Failed to revoke sessions", "error", err)
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to revoke sessions.")
+	}
+
+	logger.InfoContext(ctx, "All sessions revoked by admin", "count", count)
+	return c.JSON(http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Revoked %d session(s).", count),
+		Data:    map[string]int64{"revoked": count},
+	})
+}
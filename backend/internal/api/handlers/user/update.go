@@ -15,14 +15,17 @@ import (
 	"time"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth" // Auth helpers
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/i18n"
 	"github.com/henrythedeveloper/it-ticket-system/internal/models"              // Data models
+	"github.com/henrythedeveloper/it-ticket-system/internal/timezone"
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
 
 // --- Handler Functions ---
 
-// UpdateUser handles requests to modify a user's details (name, email, role, password).
+// UpdateUser handles requests to modify a user's details (name, email, role, password, timezone, locale).
 // Performs authorization checks: Admins can update anyone, regular users can only update themselves.
 //
 // Path Parameters:
@@ -99,6 +102,12 @@ func (h *Handler) UpdateUser(c echo.Context) error {
 			logger.WarnContext(ctx, "Unauthorized attempt to change user role", "requestingUserID", requestingUserID)
 			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to change user roles.")
 		}
+		// Don't trust the token's role for escalation: confirm the requester is still an Admin
+		requester, err := getUserByID(ctx, h.db, requestingUserID)
+		if err != nil || requester.Role != models.RoleAdmin {
+			logger.WarnContext(ctx, "Role change denied: requester is no longer an Admin", "requestingUserID", requestingUserID, "error", err)
+			return echo.NewHTTPError(http.StatusForbidden, "Not authorized to change user roles.")
+		}
 		// Validate the new role value
 		if userUpdate.Role != models.RoleAdmin && userUpdate.Role != models.RoleStaff {
 			logger.WarnContext(ctx, "Invalid role specified in update", "role", userUpdate.Role)
@@ -106,6 +115,24 @@ func (h *Handler) UpdateUser(c echo.Context) error {
 		}
 	}
 
+	// Validate the timezone preference if one was supplied
+	if userUpdate.Timezone != "" {
+		if tzErr := timezone.Validate(userUpdate.Timezone); tzErr != nil {
+			logger.WarnContext(ctx, "Invalid timezone specified in update", "timezone", userUpdate.Timezone)
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid timezone: use an IANA name such as 'America/Chicago'.")
+		}
+	}
+
+	// Validate and canonicalize the language preference if one was supplied
+	if userUpdate.Locale != "" {
+		locale := i18n.Normalize(userUpdate.Locale)
+		if locale == "" {
+			logger.WarnContext(ctx, "Unsupported locale specified in update", "locale", userUpdate.Locale)
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Unsupported locale. Supported locales: %s.", strings.Join(i18n.Supported(), ", ")))
+		}
+		userUpdate.Locale = locale
+	}
+
 	// --- 6. Build Dynamic Update Query ---
 	queryBuilder := strings.Builder{}
 	queryBuilder.WriteString("UPDATE users SET updated_at = $1")
@@ -128,6 +155,16 @@ func (h *Handler) UpdateUser(c echo.Context) error {
 		queryBuilder.WriteString(fmt.Sprintf(", role = $%d", paramCount))
 		args = append(args, userUpdate.Role)
 	}
+	if userUpdate.Timezone != "" && (currentUserData.Timezone == nil || userUpdate.Timezone != *currentUserData.Timezone) {
+		paramCount++
+		queryBuilder.WriteString(fmt.Sprintf(", timezone = $%d", paramCount))
+		args = append(args, userUpdate.Timezone)
+	}
+	if userUpdate.Locale != "" && (currentUserData.Locale == nil || userUpdate.Locale != *currentUserData.Locale) {
+		paramCount++
+		queryBuilder.WriteString(fmt.Sprintf(", locale = $%d", paramCount))
+		args = append(args, userUpdate.Locale)
+	}
 	// Handle password update separately
 	if userUpdate.Password != "" {
 		// Hash the new password
@@ -158,7 +195,7 @@ func (h *Handler) UpdateUser(c echo.Context) error {
 	args = append(args, targetUserID)
 
 	// Add RETURNING clause to get updated data
-	queryBuilder.WriteString(" RETURNING id, name, email, role, created_at, updated_at")
+	queryBuilder.WriteString(" RETURNING id, name, email, role, timezone, locale, created_at, updated_at")
 
 	// --- 7. Execute Update Query ---
 	finalQuery := queryBuilder.String()
@@ -167,7 +204,7 @@ func (h *Handler) UpdateUser(c echo.Context) error {
 	var updatedUser models.User
 	err = h.db.Pool.QueryRow(ctx, finalQuery, args...).Scan(
 		&updatedUser.ID, &updatedUser.Name, &updatedUser.Email,
-		&updatedUser.Role, &updatedUser.CreatedAt, &updatedUser.UpdatedAt,
+		&updatedUser.Role, &updatedUser.Timezone, &updatedUser.Locale, &updatedUser.CreatedAt, &updatedUser.UpdatedAt,
 	)
 	if err != nil {
 		// Check if the error is because the user was not found (should be rare after initial check)
@@ -175,6 +212,10 @@ func (h *Handler) UpdateUser(c echo.Context) error {
 			logger.WarnContext(ctx, "User not found during final update query execution")
 			return echo.NewHTTPError(http.StatusNotFound, "User not found.")
 		}
+		if db.IsUniqueViolation(err) { // Another account took the email after the pre-check
+			logger.WarnContext(ctx, "Attempted to update user to an existing email", "email", userUpdate.Email)
+			return echo.NewHTTPError(http.StatusConflict, "Email address is already in use by another account.")
+		}
 		logger.ErrorContext(ctx, "Failed to execute user update query", "error", err)
 		return echo.NewHTTPError(http.StatusInternalServerError, "Database error: failed to update user.")
 	}
@@ -8,9 +8,12 @@ package user
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/db"    // Corrected import path
 	"github.com/henrythedeveloper/it-ticket-system/internal/models" // Data models
@@ -21,7 +24,7 @@ import (
 // Define SQL queries used by the user handlers and helpers.
 const (
 	QueryGetUserByID = `
-		SELECT id, name, email, role, created_at, updated_at
+		SELECT id, name, email, role, timezone, locale, created_at, updated_at
 		FROM users WHERE id = $1`
 
 	QueryGetUserWithPasswordByID = `
@@ -38,10 +41,6 @@ const (
 	QueryEmailExistsExcept = `
 		SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND id != $2)`
 
-	QueryGetAllUsers = `
-		SELECT id, name, email, role, created_at, updated_at
-		FROM users ORDER BY name ASC`
-
 	QueryCreateUser = `
 		INSERT INTO users (name, email, password_hash, role, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
@@ -68,7 +67,7 @@ func getUserByID(ctx context.Context, db *db.DB, userID string) (models.User, er
 	var user models.User
 	// Use the defined constant
 	err := db.Pool.QueryRow(ctx, QueryGetUserByID, userID).Scan(
-		&user.ID, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt,
+		&user.ID, &user.Name, &user.Email, &user.Role, &user.Timezone, &user.Locale, &user.CreatedAt, &user.UpdatedAt,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -189,44 +188,102 @@ func emailExistsExcept(ctx context.Context, db *db.DB, email string, userID stri
 	return exists, nil
 }
 
-// getAllUsers retrieves all users from the database, excluding password hashes.
-// Typically used by admins.
+// userListFilter holds the validated query options for listUsers.
+type userListFilter struct {
+	Roles     []models.UserRole // Empty means all roles
+	Search    string            // Case-insensitive match on name or email
+	SortBy    string            // "name" or "createdAt"
+	SortOrder string            // "asc" or "desc"
+	Limit     int
+	Offset    int
+}
+
+// userSortColumns maps accepted sortBy values to columns.
+var userSortColumns = map[string]string{"name": "name", "createdAt": "created_at"}
+
+// listUsers retrieves one page of users (excluding password hashes) matching
+// the filter, along with the total number of matches.
 //
 // Parameters:
 //   - ctx: The request context.
 //   - db: The database connection pool.
+//   - filter: Role/search filters, sort and pagination (userListFilter).
 //
 // Returns:
-//   - []models.User: A slice of all user objects.
+//   - []models.User: The users on the requested page.
+//   - int: The total number of matching users.
 //   - error: An error if the database query fails.
-func getAllUsers(ctx context.Context, db *db.DB) ([]models.User, error) {
-	logger := slog.With("helper", "getAllUsers")
-	// Use the defined constant
-	rows, err := db.Pool.Query(ctx, QueryGetAllUsers)
+func listUsers(ctx context.Context, db *db.DB, filter userListFilter) ([]models.User, int, error) {
+	logger := slog.With("helper", "listUsers")
+
+	conditions := []string{}
+	args := []interface{}{}
+	if len(filter.Roles) > 0 {
+		roles := make([]string, len(filter.Roles))
+		for i, role := range filter.Roles {
+			roles[i] = string(role)
+		}
+		args = append(args, roles)
+		conditions = append(conditions, fmt.Sprintf("role = ANY($%d)", len(args)))
+	}
+	if filter.Search != "" {
+		args = append(args, "%"+filter.Search+"%")
+		conditions = append(conditions, fmt.Sprintf("(name ILIKE $%d OR email ILIKE $%d)", len(args), len(args)))
+	}
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int
+	if err := db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM users`+whereClause, args...).Scan(&total); err != nil {
+		logger.ErrorContext(ctx, "Count query failed", "error", err)
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	column, ok := userSortColumns[filter.SortBy]
+	if !ok {
+		column = "name"
+	}
+	order := "ASC"
+	if strings.ToLower(filter.SortOrder) == "desc" {
+		order = "DESC"
+	}
+	query := `SELECT id, name, email, role, created_at, updated_at FROM users` + whereClause +
+		fmt.Sprintf(" ORDER BY %s %s, id %s LIMIT $%d OFFSET $%d", column, order, order, len(args)+1, len(args)+2)
+	rows, err := db.Pool.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
 	if err != nil {
 		logger.ErrorContext(ctx, "Database query failed", "error", err)
-		return nil, fmt.Errorf("failed to get all users: %w", err)
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
 	}
 	defer rows.Close()
 
-	users := make([]models.User, 0)
+	users := make([]models.User, 0, filter.Limit)
 	for rows.Next() {
 		var user models.User
 		if err := rows.Scan(
 			&user.ID, &user.Name, &user.Email, &user.Role, &user.CreatedAt, &user.UpdatedAt,
 		); err != nil {
 			logger.ErrorContext(ctx, "Failed to scan user row", "error", err)
-			// Continue scanning other rows? Or return error? Returning error.
-			return nil, fmt.Errorf("failed to scan user data: %w", err)
+			return nil, 0, fmt.Errorf("failed to scan user data: %w", err)
 		}
 		users = append(users, user)
 	}
 
 	if err = rows.Err(); err != nil {
 		logger.ErrorContext(ctx, "Error iterating user rows", "error", err)
-		return nil, fmt.Errorf("failed to process user results: %w", err)
+		return nil, 0, fmt.Errorf("failed to process user results: %w", err)
 	}
 
-	logger.DebugContext(ctx, "Fetched all users successfully", "count", len(users))
-	return users, nil
+	logger.DebugContext(ctx, "Fetched users successfully", "count", len(users), "total", total)
+	return users, total, nil
+}
+
+// hashToken returns the hex SHA-256 of an emailed token (password reset or
+// invite), the form in which such tokens are stored. The tokens are
+// high-entropy random data, so a fast hash is enough to make a leaked table
+// useless.
+func hashToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
 }
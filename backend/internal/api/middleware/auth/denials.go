@@ -0,0 +1,71 @@
+// backend/internal/api/middleware/auth/denials.go
+// ==========================================================================
+// Audit of authorization failures. Every 403 is recorded in the audit log with
+// the user, route and method, so repeated denials (probing, or a misconfigured
+// role) show up in the admin access-denial report. The response itself is
+// passed through unchanged.
+// ==========================================================================
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
+	"github.com/labstack/echo/v4"
+)
+
+// maxDeniedRouteLength is the longest route stored as the audit resource ID
+// (audit_log.resource_id is VARCHAR(100)).
+const maxDeniedRouteLength = 100
+
+// DenialAuditMiddleware records requests answered with 403 Forbidden. It must
+// wrap the JWT and Admin middleware so the authenticated user is known and
+// their denials are caught too.
+//
+// Parameters:
+//   - auditService: The audit service the denials are recorded with.
+//
+// Returns:
+//   - echo.MiddlewareFunc: The middleware function.
+func DenialAuditMiddleware(auditService audit.Service) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := next(c)
+
+			status := c.Response().Status
+			code := apierror.CodeForbidden
+			if err != nil {
+				status, code, _ = apierror.Resolve(err)
+			}
+			if status != http.StatusForbidden {
+				return err
+			}
+
+			route := c.Path()
+			if len(route) > maxDeniedRouteLength {
+				route = route[:maxDeniedRouteLength]
+			}
+			params := make(map[string]string, len(c.ParamNames()))
+			for _, name := range c.ParamNames() {
+				params[name] = c.Param(name)
+			}
+			auditService.RecordAsync(audit.Event{
+				Action:       audit.ActionAccessDenied,
+				ActorUserID:  OptionalUserID(c),
+				ResourceType: "route",
+				ResourceID:   route,
+				IPAddress:    c.RealIP(),
+				Metadata: map[string]interface{}{
+					"method":     c.Request().Method,
+					"path":       c.Request().URL.Path,
+					"params":     params,
+					"role":       string(OptionalUserRole(c)),
+					"error_code": string(code),
+				},
+			})
+			return err
+		}
+	}
+}
@@ -8,6 +8,7 @@
 package auth
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -25,8 +26,23 @@ const (
 	contextKeyEmail = "email"
 	// contextKeyRole is the key used to store the user role in the Echo context.
 	contextKeyRole = "role"
+	// contextKeySessionID is the key used to store the login session ID in the Echo context.
+	contextKeySessionID = "session_id"
 )
 
+// SessionChecker reports whether a token's login session is still active
+// (implemented by sessions.Service).
+type SessionChecker interface {
+	Active(ctx context.Context, sessionID, userID string) (bool, error)
+}
+
+// RoleLookup returns a user's current role from the database (implemented by
+// sessions.Service), so admin checks don't rely on the role baked into a
+// possibly stale token.
+type RoleLookup interface {
+	CurrentRole(ctx context.Context, userID string) (models.UserRole, error)
+}
+
 // --- Middleware ---
 
 // JWTMiddleware creates an Echo middleware function that validates incoming JWT tokens.
@@ -34,12 +50,16 @@ const (
 // using the provided auth.Service, and stores the user's claims (ID, email, role)
 // in the Echo context for subsequent handlers to use.
 //
+// Tokens carrying a session ID (jti) are rejected once that session has been
+// revoked or has expired.
+//
 // Parameters:
 //   - authService: An implementation of the auth.Service interface used for token validation.
+//   - sessionChecker: Checks the token's login session; nil skips the check.
 //
 // Returns:
 //   - echo.MiddlewareFunc: The middleware function.
-func JWTMiddleware(authService auth.Service) echo.MiddlewareFunc {
+func JWTMiddleware(authService auth.Service, sessionChecker SessionChecker) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			ctx := c.Request().Context()
@@ -75,27 +95,104 @@ func JWTMiddleware(authService auth.Service) echo.MiddlewareFunc {
 				return echo.NewHTTPError(http.StatusUnauthorized, errMsg)
 			}
 
-			// 4. Store Claims in Context
+			// 4. Check the Login Session (tokens issued before session tracking have none)
+			if sessionChecker != nil && claims.ID != "" {
+				active, err := sessionChecker.Active(ctx, claims.ID, claims.UserID)
+				if err != nil {
+					logger.ErrorContext(ctx, "Failed to check login session", "userID", claims.UserID, "error", err)
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify session.")
+				}
+				if !active {
+					logger.WarnContext(ctx, "Token rejected: session revoked or expired", "userID", claims.UserID)
+					return echo.NewHTTPError(http.StatusUnauthorized, "Session has been revoked or has expired.")
+				}
+			}
+
+			// 5. Store Claims in Context
 			// Use constants for context keys for consistency
 			c.Set(contextKeyUserID, claims.UserID)
 			c.Set(contextKeyEmail, claims.Email)
 			c.Set(contextKeyRole, claims.Role)
+			c.Set(contextKeySessionID, claims.ID)
 
 			logger.DebugContext(ctx, "JWT validated successfully", "userID", claims.UserID, "role", claims.Role)
 
-			// 5. Proceed to the next handler
+			// 6. Proceed to the next handler
+			return next(c)
+		}
+	}
+}
+
+// OptionalJWTMiddleware behaves like JWTMiddleware when a valid bearer token is
+// present, but never rejects the request. It is used on public routes that
+// still want to know who the caller is (e.g., for audit logging).
+//
+// Parameters:
+//   - authService: An implementation of the auth.Service interface used for token validation.
+//   - sessionChecker: Checks the token's login session; nil skips the check.
+//
+// Returns:
+//   - echo.MiddlewareFunc: The middleware function.
+func OptionalJWTMiddleware(authService auth.Service, sessionChecker SessionChecker) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			parts := strings.Split(c.Request().Header.Get(echo.HeaderAuthorization), " ")
+			if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+				claims, err := authService.ValidateToken(parts[1])
+				if err == nil && sessionChecker != nil && claims.ID != "" {
+					if active, checkErr := sessionChecker.Active(c.Request().Context(), claims.ID, claims.UserID); checkErr != nil || !active {
+						err = fmt.Errorf("session not active")
+					}
+				}
+				if err == nil {
+					c.Set(contextKeyUserID, claims.UserID)
+					c.Set(contextKeyEmail, claims.Email)
+					c.Set(contextKeyRole, claims.Role)
+					c.Set(contextKeySessionID, claims.ID)
+				} else {
+					slog.DebugContext(c.Request().Context(), "Ignoring invalid token on public route", "error", err)
+				}
+			}
 			return next(c)
 		}
 	}
 }
 
+// OptionalSessionID returns the login session ID of the request's token, or
+// an empty string when there is none.
+func OptionalSessionID(c echo.Context) string {
+	sessionID, _ := c.Get(contextKeySessionID).(string)
+	return sessionID
+}
+
+// OptionalUserID returns the authenticated user's ID, or an empty string when the
+// request is anonymous. Unlike GetUserIDFromContext it never produces an error.
+func OptionalUserID(c echo.Context) string {
+	userID, _ := c.Get(contextKeyUserID).(string)
+	return userID
+}
+
+// OptionalUserRole returns the authenticated user's role, or an empty role when
+// the request is anonymous.
+func OptionalUserRole(c echo.Context) models.UserRole {
+	role, _ := c.Get(contextKeyRole).(models.UserRole)
+	return role
+}
+
 // AdminMiddleware creates an Echo middleware function that checks if the user
 // authenticated by the preceding JWTMiddleware has the 'Admin' role.
 // It should be placed *after* JWTMiddleware in the middleware chain.
 //
+// When a RoleLookup is given, the token's Admin role is re-checked against the
+// database, so a demoted or deleted admin loses access immediately rather than
+// when their token expires.
+//
+// Parameters:
+//   - roleLookup: Source of the user's current role; nil trusts the token.
+//
 // Returns:
 //   - echo.MiddlewareFunc: The middleware function.
-func AdminMiddleware() echo.MiddlewareFunc {
+func AdminMiddleware(roleLookup RoleLookup) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 			ctx := c.Request().Context()
@@ -124,7 +221,21 @@ func AdminMiddleware() echo.MiddlewareFunc {
 				return echo.NewHTTPError(http.StatusForbidden, "Access denied: Administrator role required.")
 			}
 
-			// 4. Proceed if Admin
+			// 4. Re-verify the Role Against the Database
+			if roleLookup != nil {
+				userID, _ := c.Get(contextKeyUserID).(string)
+				currentRole, err := roleLookup.CurrentRole(ctx, userID)
+				if err != nil {
+					logger.ErrorContext(ctx, "Failed to verify admin role", "userID", userID, "error", err)
+					return echo.NewHTTPError(http.StatusInternalServerError, "Failed to verify permissions.")
+				}
+				if currentRole != models.RoleAdmin {
+					logger.WarnContext(ctx, "Admin access denied: token role no longer matches account", "userID", userID, "currentRole", currentRole)
+					return echo.NewHTTPError(http.StatusForbidden, "Access denied: Administrator role required.")
+				}
+			}
+
+			// 5. Proceed if Admin
 			logger.DebugContext(ctx, "Admin access granted", "userID", c.Get(contextKeyUserID))
 			return next(c)
 		}
@@ -0,0 +1,46 @@
+// backend/internal/api/middleware/auth/permissions.go
+// ==========================================================================
+// Resolves a role into the permissions the API enforces, for clients that
+// need to know up front which actions to offer. Each entry mirrors the check
+// made by the middleware or handler that guards the action; update both
+// together.
+// ==========================================================================
+
+package auth
+
+import (
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+)
+
+// IsStaffOrAdmin reports whether the role belongs to helpdesk staff (used for
+// internal tickets and internal notes).
+func IsStaffOrAdmin(role models.UserRole) bool {
+	return role == models.RoleStaff || role == models.RoleAdmin
+}
+
+// ResolvePermissions returns the permissions held by an authenticated user
+// with the given role.
+//
+// Parameters:
+//   - role: The user's role.
+//   - tickets: The ticket lifecycle settings (close rule, "my" scope).
+//
+// Returns:
+//   - models.Permissions: The resolved permissions.
+func ResolvePermissions(role models.UserRole, tickets config.TicketsConfig) models.Permissions {
+	isAdmin := role == models.RoleAdmin
+	return models.Permissions{
+		CanManageUsers:            isAdmin,
+		CanDeleteUsers:            isAdmin,
+		CanChangeRoles:            isAdmin,
+		CanManageFAQ:              true,
+		CanManageTags:             true,
+		CanSeeAllTickets:          isAdmin,
+		CanCreateInternal:         IsStaffOrAdmin(role),
+		CanCloseAnyTicket:         isAdmin || tickets.CloseRule != "assignee",
+		CanEditAnyComment:         isAdmin,
+		CanAccessAdmin:            isAdmin,
+		MyScopeIncludesUnassigned: isAdmin || tickets.MyScopeIncludesUnassigned,
+	}
+}
@@ -0,0 +1,203 @@
+// backend/internal/api/middleware/compress/compress.go
+// ==========================================================================
+// Gzip response compression. Unlike Echo's Gzip middleware, the decision is
+// made once the handler has set its Content-Type and written enough of the
+// body: only allow-listed media types of at least the configured size are
+// compressed, so images, PDFs and other already-compressed payloads pass
+// through untouched.
+// ==========================================================================
+
+package compress
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware gzips responses for clients that accept it.
+//
+// Parameters:
+//   - cfg: The compression settings; a disabled config makes this a no-op.
+//   - excludedPrefixes: Request paths that are never compressed (e.g., attachment downloads).
+//
+// Returns:
+//   - echo.MiddlewareFunc: The middleware function.
+func Middleware(cfg config.CompressionConfig, excludedPrefixes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if !cfg.Enabled || req.Method == http.MethodHead || !acceptsGzip(req.Header.Get(echo.HeaderAcceptEncoding)) {
+				return next(c)
+			}
+			for _, prefix := range excludedPrefixes {
+				if strings.HasPrefix(req.URL.Path, prefix) {
+					return next(c)
+				}
+			}
+
+			res := c.Response()
+			w := &gzipWriter{ResponseWriter: res.Writer, cfg: cfg}
+			res.Writer = w
+			defer func() {
+				w.finish()
+				res.Writer = w.ResponseWriter
+			}()
+			return next(c)
+		}
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") {
+			continue
+		}
+		return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+	}
+	return false
+}
+
+// compressible reports whether a Content-Type is in the allowlist.
+func compressible(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, entry := range allowed {
+		if family, ok := strings.CutSuffix(entry, "/*"); ok {
+			if strings.HasPrefix(mediaType, family+"/") {
+				return true
+			}
+		} else if mediaType == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipWriter buffers the start of a response until it knows whether to
+// compress it: the body reaches cfg.MinSize (compress) or the handler
+// finishes first (send as is).
+type gzipWriter struct {
+	http.ResponseWriter
+	cfg     config.CompressionConfig
+	status  int
+	decided bool
+	gz      *gzip.Writer // Set once compression has started
+	buf     bytes.Buffer
+}
+
+// WriteHeader holds the status until the compression decision is made.
+// Responses that can never be compressed are decided immediately.
+func (w *gzipWriter) WriteHeader(status int) {
+	if w.decided || w.status != 0 {
+		return
+	}
+	w.status = status
+	header := w.Header()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified ||
+		header.Get(echo.HeaderContentEncoding) != "" || !compressible(header.Get(echo.HeaderContentType), w.cfg.ContentTypes) {
+		w.passThrough()
+		return
+	}
+	header.Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+}
+
+// Write buffers or compresses b, depending on the decision so far.
+func (w *gzipWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	switch {
+	case w.gz != nil:
+		return w.gz.Write(b)
+	case w.decided:
+		return w.ResponseWriter.Write(b)
+	}
+	w.buf.Write(b)
+	if w.buf.Len() >= w.cfg.MinSize {
+		if err := w.startGzip(); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Flush starts compression early for streamed responses, then flushes.
+func (w *gzipWriter) Flush() {
+	if !w.decided && w.status != 0 {
+		if err := w.startGzip(); err != nil {
+			return
+		}
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack forwards connection hijacking (e.g., for websockets).
+func (w *gzipWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (w *gzipWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// passThrough sends the held status and any buffered body uncompressed.
+func (w *gzipWriter) passThrough() {
+	w.decided = true
+	w.ResponseWriter.WriteHeader(w.status)
+	if w.buf.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// startGzip sends the held status with gzip headers and compresses the
+// buffered body.
+func (w *gzipWriter) startGzip() error {
+	w.decided = true
+	header := w.Header()
+	header.Set(echo.HeaderContentEncoding, "gzip")
+	header.Del(echo.HeaderContentLength)
+	w.ResponseWriter.WriteHeader(w.status)
+
+	gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.cfg.Level)
+	if err != nil {
+		return err
+	}
+	w.gz = gz
+	_, err = w.gz.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+// finish completes the response once the handler has returned.
+func (w *gzipWriter) finish() {
+	switch {
+	case w.gz != nil:
+		_ = w.gz.Close()
+	case !w.decided && w.status != 0:
+		w.passThrough()
+	}
+}
@@ -0,0 +1,109 @@
+// backend/internal/api/middleware/features/features.go
+// ==========================================================================
+// Feature flags for gradual rollouts. A flag starts from its built-in default
+// (Defaults), FEATURE_FLAGS can override it, and Admins can flip it at runtime;
+// runtime changes are held in memory, per instance. A flag nobody has defined
+// is off. Require gates a route on a flag.
+// ==========================================================================
+
+package features
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/labstack/echo/v4"
+)
+
+// --- Flag Names ---
+
+const (
+	SolutionSearch = "solution_search" // POST /api/solutions/search
+	SpamReport     = "spam_report"     // GET /api/admin/reports/spam
+)
+
+// Defaults are the built-in flag values, used unless FEATURE_FLAGS or an
+// Admin says otherwise. Flags gating existing endpoints default to on.
+var Defaults = map[string]bool{
+	SolutionSearch: true,
+	SpamReport:     true,
+}
+
+// --- Types ---
+
+// Flags is the current set of feature flags. It is safe for concurrent use.
+type Flags struct {
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+// --- Constructor ---
+
+// NewFlags creates the flag set from Defaults and the configured flags.
+//
+// Parameters:
+//   - configured: Flags from FEATURE_FLAGS, which take precedence over Defaults.
+//
+// Returns:
+//   - *Flags: The flag set.
+func NewFlags(configured map[string]bool) *Flags {
+	flags := make(map[string]bool, len(Defaults)+len(configured))
+	for name, enabled := range Defaults {
+		flags[name] = enabled
+	}
+	for name, enabled := range configured {
+		flags[strings.ToLower(name)] = enabled
+	}
+	return &Flags{flags: flags}
+}
+
+// --- Methods ---
+
+// Enabled reports whether a flag is on. Unknown flags are off.
+func (f *Flags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.flags[strings.ToLower(name)]
+}
+
+// Set turns a flag on or off, defining it if it is unknown.
+func (f *Flags) Set(name string, enabled bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.flags[strings.ToLower(name)] = enabled
+}
+
+// All returns a copy of every defined flag.
+func (f *Flags) All() map[string]bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	all := make(map[string]bool, len(f.flags))
+	for name, enabled := range f.flags {
+		all[name] = enabled
+	}
+	return all
+}
+
+// --- Middleware ---
+
+// Require serves a route only while a flag is on. While it is off the route
+// answers 404, as if it were not registered.
+//
+// Parameters:
+//   - flags: The flag set.
+//   - name: The flag gating the route.
+//
+// Returns:
+//   - echo.MiddlewareFunc: The middleware function.
+func Require(flags *Flags, name string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !flags.Enabled(name) {
+				slog.Debug("Request rejected by feature flag", "flag", name, "method", c.Request().Method, "path", c.Path())
+				return echo.ErrNotFound
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// backend/internal/api/middleware/maintenance/maintenance.go
+// ==========================================================================
+// Read-only maintenance mode. While it is on, mutating requests (POST, PUT,
+// PATCH, DELETE) are rejected with 503 and a Retry-After header, so writes
+// stop during deploys and database maintenance while reads keep serving.
+// The mode starts from MAINTENANCE_MODE and Admins can toggle it at runtime;
+// the state is held in memory, per instance.
+// ==========================================================================
+
+package maintenance
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/labstack/echo/v4"
+)
+
+// --- Types ---
+
+// Mode is the current maintenance state. It is safe for concurrent use.
+type Mode struct {
+	mu         sync.RWMutex
+	enabled    bool
+	since      time.Time
+	retryAfter time.Duration
+}
+
+// Status is the maintenance state as reported to Admins.
+type Status struct {
+	Enabled           bool       `json:"enabled"`
+	Since             *time.Time `json:"since,omitempty"` // When maintenance mode was turned on
+	RetryAfterSeconds int        `json:"retry_after_seconds"`
+}
+
+// --- Constructor ---
+
+// NewMode creates the maintenance state.
+//
+// Parameters:
+//   - enabled: Whether to start in maintenance mode (MAINTENANCE_MODE).
+//   - retryAfter: The Retry-After sent with rejected requests (MAINTENANCE_RETRY_AFTER).
+//
+// Returns:
+//   - *Mode: The maintenance state.
+func NewMode(enabled bool, retryAfter time.Duration) *Mode {
+	m := &Mode{retryAfter: retryAfter}
+	m.Set(enabled)
+	return m
+}
+
+// --- Methods ---
+
+// Set turns maintenance mode on or off.
+func (m *Mode) Set(enabled bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if enabled && !m.enabled {
+		m.since = time.Now().UTC()
+	}
+	m.enabled = enabled
+}
+
+// Status returns the current maintenance state.
+func (m *Mode) Status() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status := Status{Enabled: m.enabled, RetryAfterSeconds: int(m.retryAfter.Seconds())}
+	if m.enabled {
+		since := m.since
+		status.Since = &since
+	}
+	return status
+}
+
+// --- Middleware ---
+
+// Middleware rejects mutating requests while maintenance mode is on. Safe
+// methods always pass, as do the exempt routes.
+//
+// Parameters:
+//   - mode: The maintenance state.
+//   - exemptRoutes: Route paths (as registered, e.g. "/api/admin/maintenance")
+//     that stay writable, such as the toggle itself and login.
+//
+// Returns:
+//   - echo.MiddlewareFunc: The middleware function.
+func Middleware(mode *Mode, exemptRoutes ...string) echo.MiddlewareFunc {
+	exempt := make(map[string]bool, len(exemptRoutes))
+	for _, route := range exemptRoutes {
+		exempt[route] = true
+	}
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			switch c.Request().Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+			if exempt[c.Path()] {
+				return next(c)
+			}
+			status := mode.Status()
+			if !status.Enabled {
+				return next(c)
+			}
+			slog.Debug("Request rejected in maintenance mode", "method", c.Request().Method, "path", c.Path())
+			if status.RetryAfterSeconds > 0 {
+				c.Response().Header().Set("Retry-After", strconv.Itoa(status.RetryAfterSeconds))
+			}
+			return apierror.New(http.StatusServiceUnavailable, apierror.CodeServiceUnavailable,
+				"The helpdesk is in read-only maintenance mode. Please try again shortly.")
+		}
+	}
+}
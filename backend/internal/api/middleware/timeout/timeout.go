@@ -0,0 +1,40 @@
+// backend/internal/api/middleware/timeout/timeout.go
+// ==========================================================================
+// Query timeout reporting. Every database query has its own deadline
+// (DATABASE_QUERY_TIMEOUT, applied by the pool's query tracer); this
+// middleware turns a request that failed because one of its queries timed out
+// into a 504, instead of a generic database error. The request itself has no
+// deadline, so long downloads and exports are never cut off.
+// ==========================================================================
+
+package timeout
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware answers 504 when a request fails after one of its database
+// queries exceeded the query timeout.
+//
+// Returns:
+//   - echo.MiddlewareFunc: The middleware function.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, timeouts := db.WithQueryTimeouts(c.Request().Context())
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if err != nil && timeouts.Exceeded() && !c.Response().Committed {
+				slog.Warn("Request failed on a database query timeout", "method", c.Request().Method, "path", c.Path(), "error", err)
+				return apierror.New(http.StatusGatewayTimeout, apierror.CodeTimeout, "The request timed out. Please try again.")
+			}
+			return err
+		}
+	}
+}
@@ -20,19 +20,36 @@ import (
 	"net/http"
 
 	// Corrected handler imports
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/admin"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/dashboard"
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/faq"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/search"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/solution"
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/tag"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/team"
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/ticket"
 	"github.com/henrythedeveloper/it-ticket-system/internal/api/handlers/user" // User handler package
 	authmw "github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth" // Auth middleware
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/compress"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/features"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/maintenance"
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/timeout"
 
 	// Import core services and config
+	"github.com/henrythedeveloper/it-ticket-system/internal/apierror"
+	"github.com/henrythedeveloper/it-ticket-system/internal/audit"
 	"github.com/henrythedeveloper/it-ticket-system/internal/auth"
 	"github.com/henrythedeveloper/it-ticket-system/internal/cache"
+	"github.com/henrythedeveloper/it-ticket-system/internal/captcha"
 	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/etag"
 	"github.com/henrythedeveloper/it-ticket-system/internal/db"
 	"github.com/henrythedeveloper/it-ticket-system/internal/email"
 	"github.com/henrythedeveloper/it-ticket-system/internal/file"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preview"
+	"github.com/henrythedeveloper/it-ticket-system/internal/sessions"
+	"github.com/henrythedeveloper/it-ticket-system/internal/timezone"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
 
 	// Correct echo imports
 	"github.com/labstack/echo/v4"
@@ -52,10 +69,11 @@ type Server struct {
 // --- Constructor ---
 
 // NewServer creates, configures, and returns a new Server instance.
-func NewServer(db *db.DB, emailService email.Service, fileService file.Service, cfg *config.Config) *Server {
+func NewServer(db *db.DB, emailService email.Service, fileService file.Service, captchaService captcha.Service, previewService preview.Service, webhookService *webhook.Service, cfg *config.Config) *Server {
 	slog.Info("Initializing API server...")
 	e := echo.New()
 	e.HideBanner = true
+	e.HTTPErrorHandler = apierror.HTTPErrorHandler // Uniform error body with error_code
 
 	authService := auth.NewService(cfg.Auth)
 	slog.Info("Authentication service initialized")
@@ -101,28 +119,52 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 		},
 	}))
 	e.Use(middleware.Recover())
+	// Attachment bodies are stored as uploaded and streamed as is.
+	e.Use(compress.Middleware(cfg.Compression, "/api/attachments/download/", "/api/attachments/preview/"))
 	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
 		AllowOrigins: []string{"*"}, // CHANGE FOR PRODUCTION
 		AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete, http.MethodOptions},
-		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization},
+		AllowHeaders: []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, timezone.HeaderName, etag.HeaderIfNoneMatch, ticket.SubmitterTokenHeader},
+		ExposeHeaders: []string{etag.HeaderETag},
 	}))
 	slog.Info("Standard middleware configured")
 
+	auditService := audit.NewService(db)
+	maintenanceMode := maintenance.NewMode(cfg.Maintenance.Enabled, cfg.Maintenance.RetryAfter)
+	if cfg.Maintenance.Enabled {
+		slog.Warn("Starting in maintenance mode; write requests will be rejected")
+	}
+	featureFlags := features.NewFlags(cfg.Features.Flags)
+
 	// --- Initialize Handlers ---
 	faqHandler := faq.NewHandler(db)
 	tagHandler := tag.NewHandler(db)
 	// Pass emailService and config to userHandler
 	userHandler := user.NewHandler(db, authService, emailService, cfg)
-	ticketHandler := ticket.NewHandler(db, emailService, fileService)
+	ticketHandler := ticket.NewHandler(db, emailService, fileService, auditService, captchaService, previewService, cfg, webhookService)
+	searchHandler := search.NewHandler(db, cfg)
+	adminHandler := admin.NewHandler(db, auditService, emailService, webhookService, fileService, maintenanceMode, featureFlags, cfg)
+	dashboardHandler := dashboard.NewHandler(db)
+	solutionHandler := solution.NewHandler(db)
+	teamHandler := team.NewHandler(db, auditService)
 	slog.Info("API handlers initialized")
 
 	// --- Setup Authentication Middleware ---
-	jwtMiddleware := authmw.JWTMiddleware(authService)
-	adminMiddleware := authmw.AdminMiddleware() // Middleware specifically for Admin-only actions
+	sessionService := sessions.NewService(db, cfg.Auth.SessionIdleTimeout)
+	jwtMiddleware := authmw.JWTMiddleware(authService, sessionService)
+	adminMiddleware := authmw.AdminMiddleware(sessionService) // Admin-only actions; role re-checked against the DB
 	slog.Info("Authentication middleware configured")
 
 	// --- Define API Route Groups ---
-	apiGroup := e.Group("/api")
+	apiGroup := e.Group("/api",
+		timeout.Middleware(), // 504 when a query exceeds DATABASE_QUERY_TIMEOUT
+		// Read-only maintenance mode; login and the toggle stay writable so an Admin can switch it off
+		maintenance.Middleware(maintenanceMode, "/api/auth/login", "/api/admin/maintenance"),
+	)
+	if cfg.Auth.AuditDenials {
+		// Wraps the JWT and Admin middleware on the route groups below, so every 403 is recorded
+		apiGroup.Use(authmw.DenialAuditMiddleware(auditService))
+	}
 
 	// ================== PUBLIC ROUTES ==================
 	slog.Debug("Registering public routes...")
@@ -132,12 +174,29 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 	user.RegisterAuthRoutes(authPublicGroup, userHandler) // Registers /login, /register, etc.
 
 	// Public Ticket Creation (/api/tickets)
-	apiGroup.POST("/tickets", ticketHandler.CreateTicket)
+	// Optional JWT identifies staff, who may create internal-only tickets.
+	apiGroup.POST("/tickets", ticketHandler.CreateTicket, authmw.OptionalJWTMiddleware(authService, sessionService))
 	slog.Debug("Registered public route", "method", "POST", "path", "/api/tickets")
 
+	// Public Submitter Status & Reply (/api/public/tickets/:number), authenticated by the submitter token
+	publicTicketGroup := apiGroup.Group("/public/tickets")
+	publicTicketGroup.GET("/:number", ticketHandler.GetPublicTicketStatus)
+	publicTicketGroup.POST("/:number/comments", ticketHandler.AddSubmitterComment)
+	publicTicketGroup.POST("/:number/solutions/:solutionId/feedback", ticketHandler.SubmitSolutionFeedback)
+	slog.Debug("Registered public routes", "group", "/api/public/tickets", "methods", "GET, POST")
+
+	// Public Solution Search (/api/solutions/search), so submitters can find a known fix first
+	apiGroup.POST("/solutions/search", solutionHandler.SearchSolutions, features.Require(featureFlags, features.SolutionSearch))
+	slog.Debug("Registered public route", "method", "POST", "path", "/api/solutions/search")
+
+	// Public Feature Flags (/api/features), so clients can hide features that are switched off
+	apiGroup.GET("/features", adminHandler.GetFeatures)
+	slog.Debug("Registered public route", "method", "GET", "path", "/api/features")
+
 	// Public FAQ Routes (GET only) (/api/faq/*)
 	faqGroupPublic := apiGroup.Group("/faq")
 	faqGroupPublic.GET("", faqHandler.GetAllFAQs)
+	faqGroupPublic.GET("/categories", faqHandler.GetFAQCategories)
 	faqGroupPublic.GET("/:id", faqHandler.GetFAQByID)
 	slog.Debug("Registered public routes", "group", "/api/faq", "methods", "GET")
 
@@ -147,8 +206,11 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 	slog.Debug("Registered public route", "method", "GET", "path", "/api/tags")
 
 	// Public Attachment Download (/api/attachments/download/:attachmentId)
-	apiGroup.GET("/attachments/download/:attachmentId", ticketHandler.DownloadAttachment)
+	// Optional JWT lets the audit log attribute downloads to a user when a token is supplied.
+	apiGroup.GET("/attachments/download/:attachmentId", ticketHandler.DownloadAttachment, authmw.OptionalJWTMiddleware(authService, sessionService))
 	slog.Debug("Registered public route", "method", "GET", "path", "/api/attachments/download/:attachmentId")
+	apiGroup.GET("/attachments/preview/:attachmentId", ticketHandler.PreviewAttachment)
+	slog.Debug("Registered public route", "method", "GET", "path", "/api/attachments/preview/:attachmentId")
 
 	// ================== PROTECTED ROUTES (Staff & Admin) ==================
 	slog.Debug("Registering protected routes (JWT required)...")
@@ -158,7 +220,18 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 	// --- Protected Ticket Routes (/api/tickets/*) ---
 	// Assumes ticket management permissions are handled within ticket handlers if needed,
 	// or that all authenticated users (Staff/Admin) can manage tickets.
-	ticket.RegisterRoutes(protectedGroup.Group("/tickets"), ticketHandler)
+	ticketGroup := protectedGroup.Group("/tickets")
+	ticket.RegisterRoutes(ticketGroup, ticketHandler)
+	// POST /api/tickets/:id/resend-notification - *ADMIN ONLY* replay a submitter email
+	ticketGroup.POST("/:id/resend-notification", ticketHandler.ResendNotification, adminMiddleware)
+
+	// --- Protected Global Search Route (/api/search) ---
+	protectedGroup.GET("/search", searchHandler.Search)
+	slog.Debug("Registered protected route", "method", "GET", "path", "/api/search")
+
+	// --- Protected "My Work" Routes (/api/me/*) ---
+	dashboard.RegisterRoutes(protectedGroup.Group("/me"), dashboardHandler)
+	slog.Debug("Registered protected route", "method", "GET", "path", "/api/me/dashboard")
 
 	// --- Protected User Management Routes (/api/users/*) ---
 	userGroup := protectedGroup.Group("/users")
@@ -166,14 +239,20 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 	userGroup.GET("", userHandler.GetAllUsers)
 	// GET /api/users/me - Accessible to logged-in user
 	userGroup.GET("/me", userHandler.GetCurrentUser)
+	// GET /api/users/me/sessions - Current user's active login sessions
+	userGroup.GET("/me/sessions", userHandler.GetMySessions)
+	// DELETE /api/users/me/sessions/:sessionId - Sign out one of the current user's sessions
+	userGroup.DELETE("/me/sessions/:sessionId", userHandler.RevokeMySession)
 	// GET /api/users/:id - Accessible to Staff & Admin (internal checks might apply)
 	userGroup.GET("/:id", userHandler.GetUserByID)
-	// POST /api/users - Accessible to Staff & Admin
-	userGroup.POST("", userHandler.CreateUser)
+	// POST /api/users - *ADMIN ONLY* (the new account's role is chosen by the caller)
+	userGroup.POST("", userHandler.CreateUser, adminMiddleware)
 	// PUT /api/users/:id - Accessible to Staff & Admin (internal checks for self vs others)
 	userGroup.PUT("/:id", userHandler.UpdateUser)
 	// DELETE /api/users/:id - *ADMIN ONLY*
 	userGroup.DELETE("/:id", userHandler.DeleteUser, adminMiddleware) // Apply specific adminMiddleware here
+	// DELETE /api/users/:id/sessions - *ADMIN ONLY* sign a user out everywhere
+	userGroup.DELETE("/:id/sessions", userHandler.RevokeUserSessions, adminMiddleware)
 	slog.Debug("Registered user management routes", "group", "/api/users")
 
 	// --- Protected FAQ Management Routes (/api/faq/*) ---
@@ -183,8 +262,27 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 	faqGroupProtected.POST("", faqHandler.CreateFAQ)
 	faqGroupProtected.PUT("/:id", faqHandler.UpdateFAQ)
 	faqGroupProtected.DELETE("/:id", faqHandler.DeleteFAQ)
+	// POST /api/faq/bulk-recategorize - *ADMIN ONLY* move every entry of a category
+	faqGroupProtected.POST("/bulk-recategorize", faqHandler.BulkRecategorizeFAQs, adminMiddleware)
+	// PUT /api/faq/order - *ADMIN ONLY* set the display order within a category
+	faqGroupProtected.PUT("/order", faqHandler.ReorderFAQs, adminMiddleware)
 	slog.Debug("Registered protected FAQ routes", "group", "/api/faq", "methods", "POST, PUT, DELETE")
 
+	// --- Protected Solution Knowledge Base Routes (/api/solutions/*) ---
+	solutionGroup := protectedGroup.Group("/solutions") // JWT applied
+	// GET routes Accessible to Staff & Admin; POST, PUT, DELETE *ADMIN ONLY*
+	solutionGroup.GET("", solutionHandler.GetAllSolutions)
+	solutionGroup.GET("/:id", solutionHandler.GetSolutionByID)
+	solutionGroup.POST("", solutionHandler.CreateSolution, adminMiddleware)
+	solutionGroup.PUT("/:id", solutionHandler.UpdateSolution, adminMiddleware)
+	solutionGroup.DELETE("/:id", solutionHandler.DeleteSolution, adminMiddleware)
+	slog.Debug("Registered protected solution routes", "group", "/api/solutions", "methods", "GET, POST, PUT, DELETE")
+
+	// --- Protected Team Routes (/api/teams/*) ---
+	// GET routes Accessible to Staff & Admin; team and membership changes *ADMIN ONLY*
+	team.RegisterRoutes(protectedGroup.Group("/teams"), teamHandler, adminMiddleware)
+	slog.Debug("Registered protected team routes", "group", "/api/teams", "methods", "GET, POST, PUT, DELETE")
+
 	// --- Protected Tag Management Routes (/api/tags/*) ---
 	tagGroupProtected := protectedGroup.Group("/tags") // JWT applied
 	// GET route already public
@@ -193,6 +291,30 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 	tagGroupProtected.DELETE("/:id", tagHandler.DeleteTag)
 	slog.Debug("Registered protected Tag routes", "group", "/api/tags", "methods", "POST, DELETE")
 
+	// --- Admin Routes (/api/admin/*) - *ADMIN ONLY* ---
+	adminGroup := protectedGroup.Group("/admin", adminMiddleware)
+	admin.RegisterRoutes(adminGroup, adminHandler)
+	slog.Debug("Registered admin routes", "group", "/api/admin")
+	// Spam quarantine review is served by the ticket handler, which owns the creation side effects
+	adminGroup.GET("/quarantine", ticketHandler.GetQuarantinedTickets)
+	adminGroup.POST("/quarantine/:id/approve", ticketHandler.ApproveQuarantinedTicket)
+	adminGroup.DELETE("/quarantine/:id", ticketHandler.DiscardQuarantinedTicket)
+	slog.Debug("Registered admin routes", "group", "/api/admin/quarantine", "methods", "GET, POST, DELETE")
+	adminGroup.GET("/spam", ticketHandler.GetSpamTickets)
+	adminGroup.POST("/tickets/:id/spam", ticketHandler.MarkTicketSpam)
+	adminGroup.DELETE("/tickets/:id/spam", ticketHandler.UnmarkTicketSpam)
+	adminGroup.GET("/reports/spam", ticketHandler.GetSpamReport, features.Require(featureFlags, features.SpamReport))
+	slog.Debug("Registered admin routes", "group", "/api/admin/spam", "methods", "GET, POST, DELETE")
+	adminGroup.GET("/reports/solutions", solutionHandler.GetSolutionReport)
+	slog.Debug("Registered admin route", "method", "GET", "path", "/api/admin/reports/solutions")
+	adminGroup.POST("/tags/merge-duplicates", tagHandler.MergeDuplicateTags)
+	slog.Debug("Registered admin routes", "group", "/api/admin/tags", "methods", "POST")
+	// Registration invites are issued by the user handler, which consumes them at signup
+	adminGroup.GET("/invites", userHandler.GetPendingInvites)
+	adminGroup.POST("/invites", userHandler.CreateInvite)
+	adminGroup.DELETE("/invites/:id", userHandler.RevokeInvite)
+	slog.Debug("Registered admin routes", "group", "/api/admin/invites", "methods", "GET, POST, DELETE")
+
 
 	// --- Log All Routes and Complete Setup ---
 	logRegisteredRoutes(e) // Log all registered routes at debug level
@@ -210,6 +332,9 @@ func NewServer(db *db.DB, emailService email.Service, fileService file.Service,
 // EchoInstance returns the underlying Echo instance.
 func (s *Server) EchoInstance() *echo.Echo { return s.echo }
 
+// Cache returns the server's shared cache (a no-op cache when caching is disabled).
+func (s *Server) Cache() cache.Cache { return s.cache }
+
 // Start begins listening for HTTP requests on the configured address.
 func (s *Server) Start(address string) error {
 	slog.Info("Starting server", "address", address)
@@ -0,0 +1,224 @@
+// backend/internal/apierror/apierror.go
+// ==========================================================================
+// Machine-readable API error codes. Handlers keep returning *echo.HTTPError
+// (or New for an explicit code); HTTPErrorHandler maps every error to a code
+// and writes a models.APIResponse with both the human message and error_code,
+// so clients can branch on the code instead of matching message text.
+// ==========================================================================
+
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/labstack/echo/v4"
+)
+
+// Code is a stable, machine-readable error identifier.
+type Code string
+
+// --- Generic Codes (derived from the HTTP status) ---
+const (
+	CodeBadRequest         Code = "BAD_REQUEST"
+	CodeUnauthorized       Code = "UNAUTHORIZED"
+	CodeForbidden          Code = "FORBIDDEN"
+	CodeNotFound           Code = "NOT_FOUND"
+	CodeMethodNotAllowed   Code = "METHOD_NOT_ALLOWED"
+	CodeConflict           Code = "CONFLICT"
+	CodePayloadTooLarge    Code = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMedia   Code = "UNSUPPORTED_MEDIA_TYPE"
+	CodeValidationFailed   Code = "VALIDATION_FAILED"
+	CodeRateLimited        Code = "RATE_LIMITED"
+	CodeInternal           Code = "INTERNAL_ERROR"
+	CodeBadGateway         Code = "UPSTREAM_ERROR"
+	CodeServiceUnavailable Code = "SERVICE_UNAVAILABLE"
+	CodeTimeout            Code = "TIMEOUT"
+)
+
+// --- Domain Codes ---
+const (
+	CodeTicketNotFound      Code = "TICKET_NOT_FOUND"
+	CodeTicketClosed        Code = "TICKET_CLOSED"
+	CodeReopenWindowExpired Code = "TICKET_REOPEN_WINDOW_EXPIRED"
+	CodeTicketAccessDenied  Code = "TICKET_ACCESS_DENIED"
+	CodeAlreadyAccepted     Code = "ASSIGNMENT_ALREADY_ACCEPTED"
+	CodeNotAssignee         Code = "NOT_ASSIGNEE"
+	CodeUserNotFound        Code = "USER_NOT_FOUND"
+	CodeEmailInUse          Code = "EMAIL_IN_USE"
+	CodeInvalidCredentials  Code = "INVALID_CREDENTIALS"
+	CodeInvalidResetToken   Code = "INVALID_RESET_TOKEN"
+	CodeResetTokenUsed      Code = "RESET_TOKEN_USED"
+	CodeResetTokenExpired   Code = "RESET_TOKEN_EXPIRED"
+	CodeRegistrationClosed  Code = "REGISTRATION_DISABLED"
+	CodeInviteRequired      Code = "INVITE_REQUIRED"
+	CodeInvalidInvite       Code = "INVALID_INVITE"
+	CodeDomainNotAllowed    Code = "EMAIL_DOMAIN_NOT_ALLOWED"
+	CodeAdminRequired       Code = "ADMIN_REQUIRED"
+	CodeAttachmentNotFound  Code = "ATTACHMENT_NOT_FOUND"
+	CodeAttachmentInvalid   Code = "ATTACHMENT_INVALID"
+	CodeAttachmentNameTaken Code = "ATTACHMENT_NAME_TAKEN"
+	CodeFAQNotFound         Code = "FAQ_NOT_FOUND"
+	CodeTagNotFound         Code = "TAG_NOT_FOUND"
+	CodeTagExists           Code = "TAG_EXISTS"
+	CodeCaptchaFailed       Code = "CAPTCHA_FAILED"
+	CodeEmailDelivery       Code = "EMAIL_DELIVERY_FAILED"
+	CodeCommentNotFound     Code = "COMMENT_NOT_FOUND"
+	CodeCommentNotEditable  Code = "COMMENT_NOT_EDITABLE"
+	CodeEditWindowExpired   Code = "COMMENT_EDIT_WINDOW_EXPIRED"
+	CodeCommentDeleted      Code = "COMMENT_DELETED"
+	CodeAssigneeRequired    Code = "ASSIGNEE_REQUIRED"
+	CodeInvalidTransition   Code = "TICKET_INVALID_TRANSITION"
+)
+
+// --- Error Type ---
+
+// Error is an HTTP error carrying an explicit code. It unwraps to its
+// *echo.HTTPError, so code that inspects echo errors keeps working.
+type Error struct {
+	*echo.HTTPError
+	Code   Code
+	Fields map[string]string // Optional per-field messages, keyed by JSON field name
+}
+
+// Unwrap exposes the underlying *echo.HTTPError to errors.As.
+func (e *Error) Unwrap() error { return e.HTTPError }
+
+// New creates an HTTP error with an explicit code.
+//
+// Parameters:
+//   - status: The HTTP status code.
+//   - code: The machine-readable error code.
+//   - message: The human-readable message.
+//
+// Returns:
+//   - *Error: The error, ready to be returned from a handler.
+func New(status int, code Code, message string) *Error {
+	return &Error{HTTPError: echo.NewHTTPError(status, message), Code: code}
+}
+
+// WithFields attaches per-field messages, returned to the client in the
+// response's fields object.
+func (e *Error) WithFields(fields map[string]string) *Error {
+	e.Fields = fields
+	return e
+}
+
+// --- Mapping ---
+
+// messageCodes maps common handler messages (lowercase, without trailing
+// period) to domain codes, so existing echo.NewHTTPError call sites get
+// specific codes without being rewritten.
+var messageCodes = map[string]Code{
+	"ticket not found":                                   CodeTicketNotFound,
+	"not authorized to access this ticket":               CodeTicketAccessDenied,
+	"not authorized to view this ticket":                 CodeTicketAccessDenied,
+	"not authorized to export this ticket":               CodeTicketAccessDenied,
+	"closed tickets cannot be accepted":                  CodeTicketClosed,
+	"ticket assignment has already been accepted":        CodeAlreadyAccepted,
+	"only the assigned user can accept this ticket":      CodeNotAssignee,
+	"user not found":                                     CodeUserNotFound,
+	"email address is already registered":                CodeEmailInUse,
+	"email address is already in use":                    CodeEmailInUse,
+	"email address is already in use by another account": CodeEmailInUse,
+	"invalid email or password":                          CodeInvalidCredentials,
+	"invalid or expired password reset token":            CodeInvalidResetToken,
+	"access denied: administrator role required":         CodeAdminRequired,
+	"attachment not found":                               CodeAttachmentNotFound,
+	"attachment metadata not found or already deleted":   CodeAttachmentNotFound,
+	"faq entry not found":                                CodeFAQNotFound,
+	"tag not found":                                      CodeTagNotFound,
+	"captcha verification failed":                        CodeCaptchaFailed,
+	"comment not found":                                  CodeCommentNotFound,
+}
+
+// statusCodes maps HTTP statuses to generic codes.
+var statusCodes = map[int]Code{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusMethodNotAllowed:      CodeMethodNotAllowed,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  CodeUnsupportedMedia,
+	http.StatusUnprocessableEntity:   CodeValidationFailed,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusBadGateway:            CodeBadGateway,
+	http.StatusServiceUnavailable:    CodeServiceUnavailable,
+	http.StatusGatewayTimeout:        CodeTimeout,
+}
+
+// CodeFor returns the code for an HTTP status and message: a domain code when
+// the message is a known one, otherwise the generic code for the status.
+func CodeFor(status int, message string) Code {
+	key := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(message)), ".")
+	if code, ok := messageCodes[key]; ok {
+		return code
+	}
+	if code, ok := statusCodes[status]; ok {
+		return code
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return CodeBadRequest
+}
+
+// Resolve converts any handler error into its status, code and message.
+// Errors that are not HTTP errors are reported as a generic 500 so internal
+// details never reach the client.
+func Resolve(err error) (int, Code, string) {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.HTTPError.Code, coded.Code, messageText(coded.HTTPError)
+	}
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		message := messageText(httpErr)
+		return httpErr.Code, CodeFor(httpErr.Code, message), message
+	}
+	return http.StatusInternalServerError, CodeInternal, "Internal server error."
+}
+
+// HTTPErrorHandler is installed as echo.Echo.HTTPErrorHandler. It writes a
+// models.APIResponse with the message in both message and error (for older
+// clients) plus error_code.
+func HTTPErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+	status, code, message := Resolve(err)
+	var fields map[string]string
+	var coded *Error
+	if errors.As(err, &coded) {
+		fields = coded.Fields
+	}
+	var writeErr error
+	if c.Request().Method == http.MethodHead {
+		writeErr = c.NoContent(status)
+	} else {
+		writeErr = c.JSON(status, models.APIResponse{
+			Success:   false,
+			Message:   message,
+			Error:     message,
+			ErrorCode: string(code),
+			Fields:    fields,
+		})
+	}
+	if writeErr != nil {
+		c.Logger().Error(writeErr)
+	}
+}
+
+// messageText renders an echo.HTTPError message as a string.
+func messageText(httpErr *echo.HTTPError) string {
+	if message, ok := httpErr.Message.(string); ok {
+		return message
+	}
+	return fmt.Sprint(httpErr.Message)
+}
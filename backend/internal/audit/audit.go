@@ -0,0 +1,253 @@
+// backend/internal/audit/audit.go
+// ==========================================================================
+// Provides a small service for recording and querying audit events
+// (who did what to which resource, when, and from where). Events are stored
+// in the audit_log table.
+// ==========================================================================
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Known audit actions.
+const (
+	ActionAttachmentDownload = "attachment.download"
+	ActionEmailTest          = "email.test"
+	ActionWebhookRedrive     = "webhook.redrive"
+	ActionDataExport         = "data.export"
+	ActionDataErase          = "data.erase"
+	ActionTicketApprove      = "ticket.quarantine.approve"
+	ActionTicketDiscard      = "ticket.quarantine.discard"
+	ActionTicketSpamMark     = "ticket.spam.mark"
+	ActionTicketSpamUnmark   = "ticket.spam.unmark"
+	ActionNotificationResend = "ticket.notification.resend"
+	ActionQueryExplain       = "admin.query.explain"
+	ActionMaintenanceToggle  = "admin.maintenance.toggle"
+	ActionFeatureToggle      = "admin.feature.toggle"
+	ActionAccessDenied       = "auth.access.denied"
+	ActionTeamMemberAdd      = "team.member.add"
+	ActionTeamMemberRemove   = "team.member.remove"
+)
+
+// PublicActor is the actor label reported for unauthenticated requests.
+const PublicActor = "public"
+
+// asyncRecordTimeout bounds how long a background audit write may take.
+const asyncRecordTimeout = 5 * time.Second
+
+// --- Types ---
+
+// Event describes a single auditable action to be recorded.
+type Event struct {
+	Action       string                 // What happened (e.g., ActionAttachmentDownload)
+	ActorUserID  string                 // ID of the acting user; empty for public/anonymous requests
+	ResourceType string                 // Kind of resource acted on (e.g., "attachment")
+	ResourceID   string                 // ID of the resource acted on
+	IPAddress    string                 // Client IP address, if known
+	Metadata     map[string]interface{} // Optional extra context (stored as JSONB)
+}
+
+// Filter narrows an audit log query. Zero values mean "no filter".
+type Filter struct {
+	Action       string
+	ActorUserID  string // A user ID, or PublicActor for anonymous events
+	ResourceType string
+	ResourceID   string
+	From         *time.Time
+	To           *time.Time
+	Limit        int
+	Offset       int
+}
+
+// --- Service Interface ---
+
+// Service defines the operations for recording and reading audit events.
+type Service interface {
+	// Record synchronously writes an event.
+	Record(ctx context.Context, event Event) error
+	// RecordTx writes an event as part of the caller's transaction, so it
+	// commits (or rolls back) together with the audited change.
+	RecordTx(ctx context.Context, tx pgx.Tx, event Event) error
+	// RecordAsync writes an event in the background; failures are logged, never returned.
+	RecordAsync(event Event)
+	// List returns matching events (newest first) and the total match count.
+	List(ctx context.Context, filter Filter) ([]models.AuditLogEntry, int, error)
+}
+
+// --- Implementation ---
+
+// DBService implements Service on top of the PostgreSQL audit_log table.
+type DBService struct {
+	db     *db.DB
+	logger *slog.Logger
+}
+
+// NewService creates a new audit Service backed by the given database.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//
+// Returns:
+//   - Service: The audit service implementation.
+func NewService(database *db.DB) Service {
+	return &DBService{
+		db:     database,
+		logger: slog.With("service", "AuditService"),
+	}
+}
+
+// Record writes a single audit event.
+func (s *DBService) Record(ctx context.Context, event Event) error {
+	return insertEvent(ctx, s.db.Pool, event)
+}
+
+// RecordTx writes a single audit event within tx.
+func (s *DBService) RecordTx(ctx context.Context, tx pgx.Tx, event Event) error {
+	return insertEvent(ctx, tx, event)
+}
+
+// execer is satisfied by both the connection pool and a transaction.
+type execer interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+}
+
+// insertEvent inserts an audit_log row using conn.
+func insertEvent(ctx context.Context, conn execer, event Event) error {
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+
+	_, err = conn.Exec(ctx, `
+        INSERT INTO audit_log (action, actor_user_id, resource_type, resource_id, ip_address, metadata)
+        VALUES ($1, $2, $3, $4, $5, $6)`,
+		event.Action, nullIfEmpty(event.ActorUserID), event.ResourceType, event.ResourceID,
+		nullIfEmpty(event.IPAddress), metadataJSON,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+	return nil
+}
+
+// RecordAsync writes an event on a separate goroutine with its own timeout, so
+// callers (e.g., file downloads) are never slowed down or failed by auditing.
+func (s *DBService) RecordAsync(event Event) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), asyncRecordTimeout)
+		defer cancel()
+		if err := s.Record(ctx, event); err != nil {
+			s.logger.Error("Failed to record audit event", "action", event.Action, "resourceID", event.ResourceID, "error", err)
+		}
+	}()
+}
+
+// List returns audit events matching the filter, newest first.
+func (s *DBService) List(ctx context.Context, filter Filter) ([]models.AuditLogEntry, int, error) {
+	whereClauses := []string{}
+	args := []interface{}{}
+	argIdx := 1
+	addClause := func(clause string, value interface{}) {
+		whereClauses = append(whereClauses, fmt.Sprintf(clause, argIdx))
+		args = append(args, value)
+		argIdx++
+	}
+
+	if filter.Action != "" {
+		addClause("action = $%d", filter.Action)
+	}
+	if filter.ActorUserID == PublicActor {
+		whereClauses = append(whereClauses, "actor_user_id IS NULL")
+	} else if filter.ActorUserID != "" {
+		addClause("actor_user_id = $%d", filter.ActorUserID)
+	}
+	if filter.ResourceType != "" {
+		addClause("resource_type = $%d", filter.ResourceType)
+	}
+	if filter.ResourceID != "" {
+		addClause("resource_id = $%d", filter.ResourceID)
+	}
+	if filter.From != nil {
+		addClause("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addClause("created_at < $%d", *filter.To)
+	}
+
+	whereClause := ""
+	if len(whereClauses) > 0 {
+		whereClause = " WHERE " + strings.Join(whereClauses, " AND ")
+	}
+
+	var total int
+	if err := s.db.Pool.QueryRow(ctx, `SELECT COUNT(*) FROM audit_log`+whereClause, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count audit events: %w", err)
+	}
+
+	query := `
+        SELECT id, action, actor_user_id, resource_type, resource_id, ip_address, metadata, created_at
+        FROM audit_log` + whereClause +
+		fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", argIdx, argIdx+1)
+	rows, err := s.db.Pool.Query(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]models.AuditLogEntry, 0)
+	for rows.Next() {
+		var entry models.AuditLogEntry
+		var actorUserID, ipAddress *string
+		var metadataJSON []byte
+		if err := rows.Scan(
+			&entry.ID, &entry.Action, &actorUserID, &entry.ResourceType, &entry.ResourceID,
+			&ipAddress, &metadataJSON, &entry.CreatedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+		entry.Actor = PublicActor
+		if actorUserID != nil {
+			entry.ActorUserID = actorUserID
+			entry.Actor = *actorUserID
+		}
+		if ipAddress != nil {
+			entry.IPAddress = *ipAddress
+		}
+		if len(metadataJSON) > 0 {
+			if err := json.Unmarshal(metadataJSON, &entry.Metadata); err != nil {
+				s.logger.WarnContext(ctx, "Failed to decode audit metadata", "auditID", entry.ID, "error", err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating audit events: %w", err)
+	}
+	return entries, total, nil
+}
+
+// --- Helpers ---
+
+// nullIfEmpty maps an empty string to a SQL NULL.
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -30,8 +30,8 @@ type Service interface {
 	HashPassword(password string) (string, error)
 	// CheckPassword compares a plaintext password against a stored hash.
 	CheckPassword(hashedPassword, password string) error
-	// GenerateToken creates a new JWT for a given user.
-	GenerateToken(user models.User) (models.Token, error)
+	// GenerateToken creates a new JWT for a given user and login session.
+	GenerateToken(user models.User, sessionID string) (models.Token, error)
 	// ValidateToken parses and validates a JWT string, returning the claims if valid.
 	ValidateToken(tokenString string) (*Claims, error)
 	// GenerateSecureRandomToken generates a cryptographically secure random token string.
@@ -125,14 +125,16 @@ func (s *AuthService) CheckPassword(hashedPassword, password string) error {
 
 // GenerateToken creates and signs a JWT for the provided user.
 // The token includes user ID, email, and role, along with standard expiration claims.
+// The session ID is stored as the token ID (jti) so the session can be revoked.
 //
 // Parameters:
 //   - user: The user model (models.User) for whom to generate the token.
+//   - sessionID: The login session the token belongs to (see sessions.Service).
 //
 // Returns:
 //   - models.Token: A struct containing the access token string, type ("Bearer"), and expiration time.
 //   - error: An error if token generation or signing fails.
-func (s *AuthService) GenerateToken(user models.User) (models.Token, error) {
+func (s *AuthService) GenerateToken(user models.User, sessionID string) (models.Token, error) {
 	// Calculate expiration time based on configuration
 	expirationTime := time.Now().Add(s.config.JWTExpires)
 
@@ -144,8 +146,11 @@ func (s *AuthService) GenerateToken(user models.User) (models.Token, error) {
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Subject:   user.ID,       // Use user ID as the subject
-			Issuer:    "HelpdeskAPI", // Optional: Identify the issuer
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Subject:   user.ID,                                // Use user ID as the subject
+			ID:        sessionID,                              // Login session, checked by the JWT middleware
+			Issuer:    s.config.JWTIssuer,                     // Checked by ValidateToken
+			Audience:  jwt.ClaimStrings{s.config.JWTAudience}, // Checked by ValidateToken
 		},
 	}
 
@@ -169,6 +174,9 @@ func (s *AuthService) GenerateToken(user models.User) (models.Token, error) {
 
 // ValidateToken parses a JWT string, verifies its signature and standard claims (like expiration),
 // and returns the custom Claims payload if the token is valid.
+// The token must be HS256-signed, carry an expiry, and name the configured
+// issuer and audience; time-based claims are checked with the configured
+// clock skew tolerance.
 //
 // Parameters:
 //   - tokenString: The JWT string extracted from the request header.
@@ -189,7 +197,14 @@ func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
 		}
 		// Return the secret key for verification
 		return []byte(s.config.JWTSecret), nil
-	})
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithIssuer(s.config.JWTIssuer),
+		jwt.WithAudience(s.config.JWTAudience),
+		jwt.WithLeeway(s.config.JWTClockSkew),
+	)
 
 	// Handle parsing errors (e.g., malformed token, signature mismatch, expired)
 	if err != nil {
@@ -202,6 +217,8 @@ func (s *AuthService) ValidateToken(tokenString string) (*Claims, error) {
 			s.logger.Warn("JWT validation failed: Token expired or not yet valid")
 			// Return a specific error for expiration that middleware might handle differently
 			return nil, fmt.Errorf("token has expired or is not yet valid: %w", err)
+		} else if errors.Is(err, jwt.ErrTokenInvalidIssuer) || errors.Is(err, jwt.ErrTokenInvalidAudience) {
+			s.logger.Warn("JWT validation failed: Unexpected issuer or audience", "error", err)
 		} else {
 			s.logger.Warn("JWT validation failed: Unknown parsing error", "error", err)
 		}
@@ -0,0 +1,159 @@
+// backend/internal/captcha/captcha.go
+// ==========================================================================
+// Server-side CAPTCHA verification for public forms. Supports hCaptcha,
+// Google reCAPTCHA and Cloudflare Turnstile, which share the same
+// "siteverify" request/response shape. When disabled, every token passes.
+// ==========================================================================
+
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+)
+
+// Supported providers.
+const (
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderReCaptcha = "recaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// providerVerifyURLs maps each provider to its siteverify endpoint.
+var providerVerifyURLs = map[string]string{
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderReCaptcha: "https://www.google.com/recaptcha/api/siteverify",
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// Verification errors. Callers map ErrMissingToken/ErrInvalidToken to a client
+// error and ErrUnavailable to a temporary server-side failure.
+var (
+	ErrMissingToken = errors.New("captcha token is required")
+	ErrInvalidToken = errors.New("captcha verification failed")
+	ErrUnavailable  = errors.New("captcha provider unavailable")
+)
+
+// --- Service Interface ---
+
+// Service verifies CAPTCHA tokens submitted with public forms.
+type Service interface {
+	// Enabled reports whether CAPTCHA verification is required.
+	Enabled() bool
+	// Verify checks a token with the provider. remoteIP is optional.
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// --- Implementations ---
+
+// disabledService accepts every request; used when CAPTCHA is turned off.
+type disabledService struct{}
+
+func (disabledService) Enabled() bool                                { return false }
+func (disabledService) Verify(context.Context, string, string) error { return nil }
+
+// siteVerifyService calls a provider's siteverify endpoint.
+type siteVerifyService struct {
+	secret    string
+	verifyURL string
+	timeout   time.Duration
+	client    *http.Client
+	logger    *slog.Logger
+}
+
+// siteVerifyResponse is the common subset of the providers' JSON responses.
+type siteVerifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// --- Constructor ---
+
+// NewService creates a CAPTCHA Service from configuration.
+//
+// Parameters:
+//   - cfg: The CAPTCHA configuration (config.CaptchaConfig).
+//
+// Returns:
+//   - Service: A verifying service, or a pass-through service when disabled.
+//   - error: If the provider is unknown.
+func NewService(cfg config.CaptchaConfig) (Service, error) {
+	if !cfg.Enabled {
+		slog.Info("CAPTCHA verification disabled")
+		return disabledService{}, nil
+	}
+
+	provider := strings.ToLower(strings.TrimSpace(cfg.Provider))
+	verifyURL, ok := providerVerifyURLs[provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported CAPTCHA provider %q", cfg.Provider)
+	}
+	if cfg.VerifyURL != "" {
+		verifyURL = cfg.VerifyURL
+	}
+
+	slog.Info("CAPTCHA verification enabled", "provider", provider, "timeout", cfg.Timeout)
+	return &siteVerifyService{
+		secret:    cfg.Secret,
+		verifyURL: verifyURL,
+		timeout:   cfg.Timeout,
+		client:    &http.Client{Timeout: cfg.Timeout},
+		logger:    slog.With("service", "CaptchaService", "provider", provider),
+	}, nil
+}
+
+// Enabled always reports true for a configured provider.
+func (s *siteVerifyService) Enabled() bool { return true }
+
+// Verify posts the token to the provider and interprets the response.
+// The call is bounded by the configured timeout; a timeout or transport
+// failure is reported as ErrUnavailable rather than as an invalid token.
+func (s *siteVerifyService) Verify(ctx context.Context, token, remoteIP string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrMissingToken
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	form := url.Values{"secret": {s.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.logger.WarnContext(ctx, "CAPTCHA verify request failed", "error", err)
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		s.logger.WarnContext(ctx, "CAPTCHA provider returned unexpected status", "status", resp.StatusCode)
+		return fmt.Errorf("%w: provider returned status %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	var result siteVerifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%w: invalid provider response: %v", ErrUnavailable, err)
+	}
+	if !result.Success {
+		s.logger.InfoContext(ctx, "CAPTCHA token rejected", "errorCodes", result.ErrorCodes)
+		return fmt.Errorf("%w: %s", ErrInvalidToken, strings.Join(result.ErrorCodes, ", "))
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	"errors"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/config" // App configuration
+	"github.com/jackc/pgx/v5"                                       // Query tracer interface
 	"github.com/jackc/pgx/v5/pgxpool"                               // PostgreSQL driver/pool
 )
 
@@ -63,6 +64,18 @@ func Connect(cfg config.DatabaseConfig) (*DB, error) {
 	// TODO: Configure pool settings if needed (e.g., MaxConns, MinConns)
 	// poolConfig.MaxConns = 10
 
+	// --- Slow Query Logging and Per-Query Timeout ---
+	var tracer pgx.QueryTracer
+	if cfg.SlowQueryThreshold > 0 {
+		tracer = newSlowQueryTracer(cfg.SlowQueryThreshold)
+	}
+	if cfg.QueryTimeout > 0 {
+		tracer = newQueryTimeoutTracer(cfg.QueryTimeout, tracer)
+	}
+	if tracer != nil {
+		poolConfig.ConnConfig.Tracer = tracer
+	}
+
 	pool, err := pgxpool.NewWithConfig(connectCtx, poolConfig)
 	if err != nil {
 		logger.Error("Failed to create database connection pool", "error", err)
@@ -0,0 +1,46 @@
+// backend/internal/db/errors.go
+// ==========================================================================
+// Helpers for recognizing PostgreSQL constraint violations, so handlers can
+// turn a lost race against a UNIQUE or FOREIGN KEY constraint into a 409 or
+// 400 instead of an opaque 500.
+// ==========================================================================
+
+package db
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL SQLSTATE codes for constraint violations.
+const (
+	codeUniqueViolation     = "23505"
+	codeForeignKeyViolation = "23503"
+)
+
+// IsUniqueViolation reports whether err is a UNIQUE constraint violation.
+func IsUniqueViolation(err error) bool {
+	return hasCode(err, codeUniqueViolation)
+}
+
+// IsForeignKeyViolation reports whether err is a FOREIGN KEY constraint violation.
+func IsForeignKeyViolation(err error) bool {
+	return hasCode(err, codeForeignKeyViolation)
+}
+
+// ConstraintName returns the name of the violated constraint, or "" if err is
+// not a PostgreSQL error.
+func ConstraintName(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName
+	}
+	return ""
+}
+
+// hasCode reports whether err is a PostgreSQL error with the given SQLSTATE.
+func hasCode(err error, code string) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == code
+}
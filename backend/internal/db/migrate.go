@@ -0,0 +1,236 @@
+// backend/internal/db/migrate.go
+// ==========================================================================
+// Embedded schema migrations. Files in migrations/ are named
+// NNNN_description.sql and applied in version order, each in its own
+// transaction, with applied versions recorded in schema_migrations. Running
+// Migrate again is a no-op, and an advisory lock keeps concurrently starting
+// instances from applying the same migration twice.
+// ==========================================================================
+
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the pg_advisory_lock key held while migrating.
+const migrationLockKey = 7_345_001
+
+// migration is one versioned schema change.
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Migrate applies all pending embedded migrations.
+//
+// A database that already has the schema but no schema_migrations table (it
+// was created from db/seed.sql before migrations existed) is baselined: the
+// initial migration is recorded as applied without running it, and the later
+// migrations bring it up to date. Baselining is refused if the tables of the
+// initial migration are not all present.
+//
+// Parameters:
+//   - ctx: Context for the migration queries.
+//
+// Returns:
+//   - error: If a migration file is invalid or a migration fails; a failed
+//     migration is rolled back and later migrations are not attempted.
+func (db *DB) Migrate(ctx context.Context) error {
+	logger := slog.With("component", "Database", "operation", "Migrate")
+	// Migrations may rebuild indexes or wait for another instance's lock.
+	ctx = WithoutQueryTimeout(ctx)
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection for migrations: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			logger.Error("Failed to release migration lock", "error", err)
+		}
+	}()
+
+	var tracked bool
+	if err := conn.QueryRow(ctx, `SELECT to_regclass('public.schema_migrations') IS NOT NULL`).Scan(&tracked); err != nil {
+		return fmt.Errorf("failed to check for schema_migrations: %w", err)
+	}
+	if !tracked {
+		var existingSchema bool
+		if err := conn.QueryRow(ctx, `SELECT to_regclass('public.tickets') IS NOT NULL`).Scan(&existingSchema); err != nil {
+			return fmt.Errorf("failed to check for an existing schema: %w", err)
+		}
+		if existingSchema {
+			if err := checkBaseline(ctx, conn.Conn()); err != nil {
+				return err
+			}
+		}
+		if _, err := conn.Exec(ctx, `
+            CREATE TABLE schema_migrations (
+                version INTEGER PRIMARY KEY,
+                name TEXT NOT NULL,
+                applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+            )`); err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		if existingSchema && len(migrations) > 0 {
+			if _, err := conn.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`,
+				migrations[0].version, migrations[0].name); err != nil {
+				return fmt.Errorf("failed to baseline existing schema: %w", err)
+			}
+			logger.Info("Existing schema baselined", "version", migrations[0].version)
+		}
+	}
+
+	embedded := make(map[int]string, len(migrations))
+	for _, m := range migrations {
+		embedded[m.version] = m.name
+	}
+	applied := make(map[int]bool)
+	rows, err := conn.Query(ctx, `SELECT version, name FROM schema_migrations`)
+	if err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	for rows.Next() {
+		var version int
+		var name string
+		if err := rows.Scan(&version, &name); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan applied migration: %w", err)
+		}
+		// A version recorded under another name means the database was
+		// migrated with a different numbering; applying by version would skip
+		// or repeat changes.
+		if want, ok := embedded[version]; ok && want != name {
+			rows.Close()
+			return fmt.Errorf("applied migration %04d is recorded as %q but the embedded migration is %q; the database was migrated with a different migration set", version, name, want)
+		}
+		applied[version] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+
+	count := 0
+	for _, m := range migrations {
+		if applied[m.version] {
+			continue
+		}
+		if err := applyMigration(ctx, conn.Conn(), m); err != nil {
+			return err
+		}
+		logger.Info("Applied migration", "version", m.version, "name", m.name)
+		count++
+	}
+	logger.Info("Database schema is up to date", "applied", count, "latest", migrations[len(migrations)-1].version)
+	return nil
+}
+
+// baselineTables are the tables created by the initial migration. The FAQ
+// table may already carry its later name.
+var baselineTables = [][]string{
+	{"users"}, {"tags"}, {"tickets"}, {"ticket_tags"}, {"ticket_updates"},
+	{"attachments"}, {"faqs", "faq_entries"}, {"notifications"}, {"password_reset_tokens"},
+}
+
+// checkBaseline verifies that an untracked database has every table of the
+// initial migration before it is recorded as at that version.
+func checkBaseline(ctx context.Context, conn *pgx.Conn) error {
+	var missing []string
+	for _, names := range baselineTables {
+		found := false
+		for _, name := range names {
+			var exists bool
+			if err := conn.QueryRow(ctx, `SELECT to_regclass('public.' || $1) IS NOT NULL`, name).Scan(&exists); err != nil {
+				return fmt.Errorf("failed to check for table %s: %w", name, err)
+			}
+			if exists {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, names[0])
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("cannot baseline existing schema: missing tables %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// applyMigration runs one migration and records it, in a single transaction.
+func applyMigration(ctx context.Context, conn *pgx.Conn, m migration) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %04d: %w", m.version, err)
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	if _, err := tx.Exec(ctx, m.sql); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", m.version, m.name, err)
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.version, m.name); err != nil {
+		return fmt.Errorf("failed to record migration %04d: %w", m.version, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit migration %04d: %w", m.version, err)
+	}
+	return nil
+}
+
+// loadMigrations reads the embedded migration files, sorted by version.
+func loadMigrations() ([]migration, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+	migrations := make([]migration, 0, len(entries))
+	seen := make(map[int]string, len(entries))
+	for _, entry := range entries {
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		prefix, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(prefix)
+		if !ok || err != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration file name %q (want NNNN_description.sql)", entry.Name())
+		}
+		if other, dup := seen[version]; dup {
+			return nil, fmt.Errorf("duplicate migration version %d (%s and %s)", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+		content, err := migrationFiles.ReadFile("migrations/" + entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, migration{version: version, name: name, sql: string(content)})
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migrations embedded")
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].version < migrations[j].version })
+	return migrations, nil
+}
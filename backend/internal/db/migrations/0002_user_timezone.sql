@@ -0,0 +1,3 @@
+-- 0002_user_timezone.sql
+-- Preferred timezone per user, used to interpret date filters.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64); -- IANA timezone name (e.g. 'America/Chicago'); NULL means UTC
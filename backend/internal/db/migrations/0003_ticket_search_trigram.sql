@@ -0,0 +1,8 @@
+-- 0003_ticket_search_trigram.sql
+-- pg_trgm powers fuzzy (typo-tolerant) ticket search.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Trigram indexes for fuzzy ticket search (word_similarity via the <% operator).
+-- GIN indexes also accelerate the ILIKE '%term%' matches used by exact search.
+CREATE INDEX IF NOT EXISTS idx_tickets_subject_trgm ON tickets USING gin (subject gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_tickets_description_trgm ON tickets USING gin (description gin_trgm_ops);
@@ -0,0 +1,14 @@
+-- 0004_audit_log.sql
+-- Audit log (compliance trail of sensitive actions such as attachment downloads).
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    action VARCHAR(100) NOT NULL,          -- e.g. 'attachment.download'
+    actor_user_id UUID,                    -- NULL for public/anonymous requests; no FK so history survives user deletion
+    resource_type VARCHAR(50) NOT NULL,    -- e.g. 'attachment'
+    resource_id VARCHAR(100) NOT NULL,
+    ip_address VARCHAR(64),
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_action_created_at ON audit_log (action, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_log_resource ON audit_log (resource_type, resource_id);
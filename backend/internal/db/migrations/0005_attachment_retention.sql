@@ -0,0 +1,3 @@
+-- 0005_attachment_retention.sql
+-- Attachments whose stored file the retention job removed keep their row.
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP WITH TIME ZONE; -- Set when the retention job removed the stored file
@@ -0,0 +1,20 @@
+-- 0006_assignment_acceptance.sql
+-- Assignment acceptance workflow: assignees accept tickets, and unaccepted
+-- assignments are escalated.
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP WITH TIME ZONE,            -- When the current assignee was assigned
+    ADD COLUMN IF NOT EXISTS accepted_at TIMESTAMP WITH TIME ZONE,            -- When the current assignee accepted (NULL = pending acceptance)
+    ADD COLUMN IF NOT EXISTS acceptance_escalated_at TIMESTAMP WITH TIME ZONE; -- When an unaccepted assignment was escalated
+
+-- Assignment history (assigned / accepted / escalated / unassigned events)
+CREATE TABLE IF NOT EXISTS ticket_assignment_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    event VARCHAR(20) NOT NULL CHECK (event IN ('assigned', 'unassigned', 'accepted', 'escalated')),
+    assignee_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for system events (e.g. escalation)
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_ticket_assignment_history_ticket_id ON ticket_assignment_history (ticket_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_tickets_pending_acceptance ON tickets (assigned_at)
+    WHERE accepted_at IS NULL AND acceptance_escalated_at IS NULL;
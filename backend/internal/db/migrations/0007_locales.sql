@@ -0,0 +1,4 @@
+-- 0007_locales.sql
+-- Preferred notification language for users and ticket submitters.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(16);   -- Preferred language for notifications (e.g. 'es'); NULL means English
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS locale VARCHAR(16); -- Submitter's preferred language (e.g. 'es'); NULL falls back to the user's or English
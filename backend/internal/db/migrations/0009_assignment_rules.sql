@@ -0,0 +1,15 @@
+-- 0009_assignment_rules.sql
+-- Assignment routing rules (submitter email domain -> assignee), evaluated in
+-- position order at ticket creation. domain is stored lowercase; a leading
+-- "*." also matches subdomains.
+CREATE TABLE IF NOT EXISTS assignment_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    domain VARCHAR(255) NOT NULL,
+    assignee_user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position INT NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_assignment_rules_position ON assignment_rules (position, created_at) WHERE enabled;
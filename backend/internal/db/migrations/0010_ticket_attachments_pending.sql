@@ -0,0 +1,7 @@
+-- 0010_ticket_attachments_pending.sql
+-- Two-phase ticket creation: the ticket commits before its attachments are
+-- stored, and a sweep job cleans up creations whose uploads stalled.
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS attachments_pending_since TIMESTAMP WITH TIME ZONE; -- Set while submitted files are still being stored (two-phase create)
+
+CREATE INDEX IF NOT EXISTS idx_tickets_attachments_pending ON tickets (attachments_pending_since)
+    WHERE attachments_pending_since IS NOT NULL;
@@ -0,0 +1,4 @@
+-- 0011_submitter_replies.sql
+-- Submitters check status and reply through a per-ticket token.
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS submitter_token_hash VARCHAR(64);                       -- SHA-256 (hex) of the submitter's status/reply token
+ALTER TABLE ticket_updates ADD COLUMN IF NOT EXISTS from_submitter BOOLEAN NOT NULL DEFAULT FALSE; -- Posted by the submitter through the public status endpoint
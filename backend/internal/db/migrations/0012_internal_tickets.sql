@@ -0,0 +1,3 @@
+-- 0012_internal_tickets.sql
+-- Internal-only tickets, raised by staff, never email the submitter.
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS is_internal BOOLEAN NOT NULL DEFAULT FALSE; -- Staff-only ticket: no submitter emails or public status
@@ -0,0 +1,31 @@
+-- 0013_webhooks.sql
+-- Outbound webhooks: a delivery queue retried on the configured schedule, and
+-- dead letters for deliveries that exhausted their retries.
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL,                    -- Shared by every target of one event
+    event_type VARCHAR(100) NOT NULL,          -- e.g. 'ticket.created'
+    target_url TEXT NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at);
+
+-- Webhook dead letters (admins can re-drive them)
+CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id UUID NOT NULL,                    -- Stable event ID sent as X-Webhook-Event-ID
+    event_type VARCHAR(100) NOT NULL,          -- e.g. 'ticket.created'
+    target_url TEXT NOT NULL,
+    payload JSONB NOT NULL,                    -- The exact body that was POSTed
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'dead' CHECK (status IN ('dead', 'redriven')),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    redriven_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX IF NOT EXISTS idx_webhook_dead_letters_status_created_at ON webhook_dead_letters (status, created_at DESC);
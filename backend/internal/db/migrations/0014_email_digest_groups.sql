@@ -0,0 +1,5 @@
+-- 0014_email_digest_groups.sql
+-- Pending outbox rows sharing a group key are delivered as one digest.
+ALTER TABLE email_outbox ADD COLUMN IF NOT EXISTS group_key VARCHAR(320); -- Pending rows sharing a key are delivered as one digest
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_group_key ON email_outbox (group_key) WHERE status = 'pending' AND group_key IS NOT NULL;
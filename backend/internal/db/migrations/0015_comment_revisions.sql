@@ -0,0 +1,16 @@
+-- 0015_comment_revisions.sql
+-- Comment editing and deletion, with prior versions kept as revisions.
+ALTER TABLE ticket_updates
+    ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP WITH TIME ZONE, -- Last edit; prior text is kept in ticket_update_revisions
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE; -- Tombstone: the comment text is cleared but the row is kept
+
+-- Prior versions of edited or deleted comments (newest last)
+CREATE TABLE IF NOT EXISTS ticket_update_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    update_id UUID NOT NULL REFERENCES ticket_updates(id) ON DELETE CASCADE,
+    previous_comment TEXT NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('edit', 'delete')),
+    actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_ticket_update_revisions_update_id ON ticket_update_revisions (update_id, created_at);
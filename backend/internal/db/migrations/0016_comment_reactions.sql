@@ -0,0 +1,9 @@
+-- 0016_comment_reactions.sql
+-- Emoji reactions on comments; each user reacts at most once per emoji.
+CREATE TABLE IF NOT EXISTS ticket_update_reactions (
+    update_id UUID NOT NULL REFERENCES ticket_updates(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    emoji VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (update_id, user_id, emoji)
+);
@@ -0,0 +1,5 @@
+-- 0017_attachment_previews.sql
+-- Rendered PDF previews of office document attachments.
+ALTER TABLE attachments
+    ADD COLUMN IF NOT EXISTS preview_status VARCHAR(10) CHECK (preview_status IN ('pending', 'ready', 'failed')), -- NULL when no preview applies
+    ADD COLUMN IF NOT EXISTS preview_storage_path VARCHAR(255); -- Rendered PDF preview of an office document
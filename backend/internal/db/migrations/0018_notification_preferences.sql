@@ -0,0 +1,10 @@
+-- 0018_notification_preferences.sql
+-- Per-user notification opt-outs; a missing row means the category/channel is enabled.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    category VARCHAR(30) NOT NULL,  -- 'assignment', 'status_change', 'comment', 'mention', 'digest'
+    channel VARCHAR(10) NOT NULL CHECK (channel IN ('email', 'in_app')),
+    enabled BOOLEAN NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, category, channel)
+);
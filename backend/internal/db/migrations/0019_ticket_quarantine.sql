@@ -0,0 +1,5 @@
+-- 0019_ticket_quarantine.sql
+-- Public tickets matching the spam filter are held for admin review.
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS quarantined_at TIMESTAMP WITH TIME ZONE, -- Held by the spam filter for admin review (hidden from queues)
+    ADD COLUMN IF NOT EXISTS quarantine_reason TEXT;                  -- The spam keyword or pattern that matched
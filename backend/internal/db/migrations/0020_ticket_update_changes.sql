@@ -0,0 +1,3 @@
+-- 0020_ticket_update_changes.sql
+-- Structured field changes recorded with system updates.
+ALTER TABLE ticket_updates ADD COLUMN IF NOT EXISTS changes JSONB; -- Structured field changes for system updates ([{field, from, to, ...}])
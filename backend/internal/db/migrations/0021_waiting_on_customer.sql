@@ -0,0 +1,10 @@
+-- 0021_waiting_on_customer.sql
+-- "Waiting on Customer" status, with submitter reminders and auto-close.
+ALTER TABLE tickets DROP CONSTRAINT IF EXISTS tickets_status_check;
+ALTER TABLE tickets ADD CONSTRAINT tickets_status_check
+    CHECK (status IN ('Open', 'In Progress', 'Waiting on Customer', 'Closed'));
+
+ALTER TABLE tickets
+    ADD COLUMN IF NOT EXISTS waiting_since TIMESTAMP WITH TIME ZONE,            -- When the ticket entered 'Waiting on Customer' (NULL otherwise)
+    ADD COLUMN IF NOT EXISTS waiting_reminders_sent INTEGER NOT NULL DEFAULT 0, -- Reminder emails sent during the current wait
+    ADD COLUMN IF NOT EXISTS waiting_last_reminder_at TIMESTAMP WITH TIME ZONE; -- When the last reminder was sent
@@ -0,0 +1,5 @@
+-- 0022_public_replies.sql
+-- Staff replies posted with reply_to_submitter are emailed to the submitter.
+ALTER TABLE ticket_updates
+    ADD COLUMN IF NOT EXISTS is_public_reply BOOLEAN NOT NULL DEFAULT FALSE, -- Staff reply posted with reply_to_submitter (emailed to the submitter)
+    ADD COLUMN IF NOT EXISTS emailed_at TIMESTAMP WITH TIME ZONE;            -- When the public reply email was handed to the mailer; NULL if not (yet) sent
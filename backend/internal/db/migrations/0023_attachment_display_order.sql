@@ -0,0 +1,3 @@
+-- 0023_attachment_display_order.sql
+-- Staff-defined attachment order (see ReorderAttachments).
+ALTER TABLE attachments ADD COLUMN IF NOT EXISTS display_order INTEGER; -- NULL sorts last, by upload time
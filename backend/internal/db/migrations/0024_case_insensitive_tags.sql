@@ -0,0 +1,24 @@
+-- 0024_case_insensitive_tags.sql
+-- Tag names are unique regardless of case ("VPN" and "vpn" are one tag). The
+-- case-sensitive constraint is replaced by an index on LOWER(name); existing
+-- duplicates are merged first, as POST /api/admin/tags/merge-duplicates does,
+-- keeping the oldest tag of each set.
+ALTER TABLE tags DROP CONSTRAINT IF EXISTS tags_name_key;
+
+CREATE TEMP TABLE tag_merges ON COMMIT DROP AS
+SELECT id, keep_id FROM (
+    SELECT id, FIRST_VALUE(id) OVER (
+        PARTITION BY LOWER(regexp_replace(btrim(name), '\s+', ' ', 'g'))
+        ORDER BY created_at, id) AS keep_id
+    FROM tags
+) ranked
+WHERE id <> keep_id;
+
+INSERT INTO ticket_tags (ticket_id, tag_id)
+SELECT tt.ticket_id, m.keep_id FROM ticket_tags tt JOIN tag_merges m ON m.id = tt.tag_id
+ON CONFLICT DO NOTHING;
+DELETE FROM tags WHERE id IN (SELECT id FROM tag_merges); -- Cascades to their ticket_tags rows
+UPDATE tags SET name = regexp_replace(btrim(name), '\s+', ' ', 'g')
+WHERE name <> regexp_replace(btrim(name), '\s+', ' ', 'g');
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_lower ON tags (LOWER(name));
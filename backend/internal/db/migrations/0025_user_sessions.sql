@@ -0,0 +1,14 @@
+-- 0025_user_sessions.sql
+-- Login sessions. Each issued JWT carries its session ID (jti); revoking the
+-- session invalidates the token before it expires.
+CREATE TABLE user_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX idx_user_sessions_user ON user_sessions (user_id, last_used_at DESC) WHERE revoked_at IS NULL;
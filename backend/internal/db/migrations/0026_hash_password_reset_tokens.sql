@@ -0,0 +1,8 @@
+-- 0026_hash_password_reset_tokens.sql
+-- Password reset tokens are stored as SHA-256 hashes instead of raw values,
+-- and are marked used rather than deleted so a replayed link gets a clear
+-- "already used" error. Outstanding raw tokens are hashed in place so links
+-- already emailed keep working.
+UPDATE password_reset_tokens SET token = encode(sha256(convert_to(token, 'UTF8')), 'hex');
+ALTER TABLE password_reset_tokens RENAME COLUMN token TO token_hash;
+ALTER TABLE password_reset_tokens ADD COLUMN used_at TIMESTAMP WITH TIME ZONE;
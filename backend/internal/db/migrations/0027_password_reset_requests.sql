@@ -0,0 +1,12 @@
+-- 0027_password_reset_requests.sql
+-- Forgot-password requests, counted per email and per client IP to rate
+-- limit the endpoint. Rows are kept whether or not the email has an account,
+-- so the limits behave the same for both.
+CREATE TABLE password_reset_requests (
+    id BIGSERIAL PRIMARY KEY,
+    email VARCHAR(255) NOT NULL,  -- Lowercased
+    ip_address VARCHAR(45),
+    requested_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_password_reset_requests_email ON password_reset_requests (email, requested_at);
+CREATE INDEX idx_password_reset_requests_ip ON password_reset_requests (ip_address, requested_at);
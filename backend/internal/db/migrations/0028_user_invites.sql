@@ -0,0 +1,15 @@
+-- 0028_user_invites.sql
+-- Admin-issued invite tokens for invite-only registration. Only the SHA-256
+-- hash of the token is stored. An invite may be scoped to one email address,
+-- and always fixes the role the new account gets.
+CREATE TABLE user_invites (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    email VARCHAR(255),                   -- NULL: any email may use the invite
+    role VARCHAR(20) NOT NULL CHECK (role IN ('Admin', 'Staff', 'User')),
+    created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    used_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL
+);
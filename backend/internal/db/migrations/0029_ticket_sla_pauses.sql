@@ -0,0 +1,22 @@
+-- 0029_ticket_sla_pauses.sql
+-- Intervals during which a ticket's SLA clock was stopped (while waiting on
+-- the customer, or while closed). resumed_at is NULL while the pause is
+-- still open; a ticket has at most one open pause.
+CREATE TABLE ticket_sla_pauses (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,            -- The status that paused the clock
+    paused_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    resumed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_ticket_sla_pauses_ticket ON ticket_sla_pauses (ticket_id, paused_at);
+CREATE UNIQUE INDEX idx_ticket_sla_pauses_open ON ticket_sla_pauses (ticket_id) WHERE resumed_at IS NULL;
+
+-- Tickets already waiting on the customer start out paused since they began
+-- waiting; closed tickets since they were closed.
+INSERT INTO ticket_sla_pauses (ticket_id, status, paused_at)
+SELECT id, status,
+       CASE WHEN status = 'Closed' THEN COALESCE(closed_at, updated_at) ELSE COALESCE(waiting_since, updated_at) END
+FROM tickets
+WHERE status IN ('Waiting on Customer', 'Closed');
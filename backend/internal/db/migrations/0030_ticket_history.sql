@@ -0,0 +1,21 @@
+-- 0030_ticket_history.sql
+-- Structured ticket timeline (field changes and system events), kept apart
+-- from ticket_updates so that comments hold only human messages. Existing
+-- system updates are moved over.
+CREATE TABLE ticket_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL, -- NULL for system and submitter events
+    summary TEXT NOT NULL,                                       -- Human-readable description of the event
+    changes JSONB,                                               -- Structured field changes ([{field, from, to, ...}]); NULL for plain events
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_ticket_history_ticket ON ticket_history (ticket_id, created_at);
+
+INSERT INTO ticket_history (ticket_id, actor_user_id, summary, changes, created_at)
+SELECT ticket_id, user_id, comment, changes, created_at
+FROM ticket_updates
+WHERE is_system_update AND ticket_id IS NOT NULL;
+
+DELETE FROM ticket_updates WHERE is_system_update;
@@ -0,0 +1,33 @@
+-- 0031_solutions.sql
+-- Solution knowledge base: known fixes matched against new tickets by issue
+-- type and keyword overlap. A solution without an issue type applies to all
+-- types. Keywords are stored lower-cased.
+CREATE TABLE solutions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(255) NOT NULL,
+    body TEXT NOT NULL,
+    issue_type VARCHAR(100),
+    keywords TEXT[] NOT NULL DEFAULT '{}',
+    created_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_solutions_issue_type ON solutions (issue_type);
+CREATE INDEX idx_solutions_keywords ON solutions USING GIN (keywords);
+
+-- How often each solution was suggested to a submitter and how often the
+-- submitter reported that it helped. Solutions that helped an address before
+-- are ranked first when that address matches them again.
+CREATE TABLE solution_effectiveness (
+    solution_id UUID NOT NULL REFERENCES solutions(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,            -- Lower-cased submitter email
+    shown_count INTEGER NOT NULL DEFAULT 0,
+    helpful_count INTEGER NOT NULL DEFAULT 0,
+    not_helpful_count INTEGER NOT NULL DEFAULT 0,
+    last_shown_at TIMESTAMP WITH TIME ZONE,
+    last_feedback_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (solution_id, email)
+);
+
+CREATE INDEX idx_solution_effectiveness_email ON solution_effectiveness (email);
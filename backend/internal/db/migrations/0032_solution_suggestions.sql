@@ -0,0 +1,15 @@
+-- 0032_solution_suggestions.sql
+-- Which solutions were suggested to which submitter for which ticket. Used to
+-- rank previously shown solutions first for the same address and to report
+-- which suggestions tend to precede the ticket being closed.
+CREATE TABLE solution_suggestions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    solution_id UUID NOT NULL REFERENCES solutions(id) ON DELETE CASCADE,
+    ticket_id UUID NOT NULL REFERENCES tickets(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,            -- Lower-cased submitter email
+    shown_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (solution_id, ticket_id)
+);
+
+CREATE INDEX idx_solution_suggestions_email ON solution_suggestions (email, solution_id);
+CREATE INDEX idx_solution_suggestions_ticket ON solution_suggestions (ticket_id);
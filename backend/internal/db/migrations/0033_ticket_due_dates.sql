@@ -0,0 +1,7 @@
+-- 0033_ticket_due_dates.sql
+-- Optional deadline for a ticket. Stored as an instant; a plain date from a
+-- client means the end of that day in the client's timezone.
+ALTER TABLE tickets ADD COLUMN due_date TIMESTAMP WITH TIME ZONE;
+
+-- Overdue and due-soon lookups only concern tickets that are still open.
+CREATE INDEX idx_tickets_due_date_open ON tickets (due_date) WHERE due_date IS NOT NULL AND status <> 'Closed';
@@ -0,0 +1,26 @@
+-- Support teams (e.g., Network, Desktop). A ticket can be assigned to a team
+-- as well as, or instead of, an individual: team tickets without an
+-- individual assignee appear in every member's queue until one of them
+-- claims the ticket.
+CREATE TABLE teams (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_teams_name ON teams (LOWER(name));
+
+CREATE TABLE team_members (
+    team_id UUID NOT NULL REFERENCES teams(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (team_id, user_id)
+);
+
+-- Queue lookups start from the user ("which teams am I in?").
+CREATE INDEX idx_team_members_user ON team_members (user_id);
+
+ALTER TABLE tickets ADD COLUMN assigned_to_team_id UUID REFERENCES teams(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tickets_assigned_to_team ON tickets (assigned_to_team_id) WHERE assigned_to_team_id IS NOT NULL;
@@ -0,0 +1,17 @@
+-- 0035_faq_ordering.sql
+-- The initial schema created the FAQ table as "faqs", while the handlers and
+-- search have always used "faq_entries". Rename it where it is still "faqs".
+DO $$
+BEGIN
+    IF to_regclass('faq_entries') IS NULL THEN
+        ALTER TABLE faqs RENAME TO faq_entries;
+    END IF;
+END $$;
+
+-- Admin-defined ordering within a category (see ReorderFAQs). Pinned entries
+-- are listed first, then by display_order, then by creation time.
+ALTER TABLE faq_entries
+    ADD COLUMN display_order INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN pinned BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_faq_entries_order ON faq_entries (category, pinned DESC, display_order, created_at);
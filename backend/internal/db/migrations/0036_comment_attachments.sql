@@ -0,0 +1,6 @@
+-- 0036_comment_attachments.sql
+-- Attachments uploaded with a specific comment (see UploadCommentAttachment).
+-- They remain ticket attachments; update_id only links them to the comment.
+ALTER TABLE attachments ADD COLUMN update_id UUID REFERENCES ticket_updates(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_attachments_update_id ON attachments (update_id) WHERE update_id IS NOT NULL;
@@ -0,0 +1,4 @@
+-- 0037_emailed_solution_suggestions.sql
+-- When a suggestion was also listed in the submitter's confirmation email
+-- (see TICKET_CONFIRMATION_SUGGESTIONS); NULL if it was only returned by the API.
+ALTER TABLE solution_suggestions ADD COLUMN emailed_at TIMESTAMP WITH TIME ZONE;
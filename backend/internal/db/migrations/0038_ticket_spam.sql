@@ -0,0 +1,10 @@
+-- 0038_ticket_spam.sql
+-- Spam disposition. A ticket marked as spam (by an Admin, usually from the
+-- quarantine queue) is kept for the spam report but excluded from queues,
+-- counts, search and reports. quarantine_reason is left in place, so the
+-- report can tell filter catches from manual marks.
+ALTER TABLE tickets
+    ADD COLUMN spam_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN spam_marked_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tickets_spam_at ON tickets (spam_at) WHERE spam_at IS NOT NULL;
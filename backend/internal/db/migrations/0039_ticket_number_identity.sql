@@ -0,0 +1,20 @@
+-- 0039_ticket_number_identity.sql
+-- ticket_number becomes an identity column, generated only by the database.
+-- The SERIAL default could be bypassed by inserting an explicit number, which
+-- the sequence would later hand out again; GENERATED ALWAYS rejects explicit
+-- values. The counter continues from the highest number already issued.
+-- Numbers can still skip (a rolled-back ticket creation consumes one), but
+-- two tickets never share a number.
+DO $$
+DECLARE
+    serial_seq TEXT := pg_get_serial_sequence('tickets', 'ticket_number');
+    next_number BIGINT;
+BEGIN
+    SELECT GREATEST(COALESCE(MAX(ticket_number), 0), COALESCE(pg_sequence_last_value(serial_seq::regclass), 0)) + 1
+    INTO next_number
+    FROM tickets;
+
+    ALTER TABLE tickets ALTER COLUMN ticket_number DROP DEFAULT;
+    EXECUTE format('DROP SEQUENCE %s', serial_seq);
+    EXECUTE format('ALTER TABLE tickets ALTER COLUMN ticket_number ADD GENERATED ALWAYS AS IDENTITY (START WITH %s)', next_number);
+END $$;
@@ -0,0 +1,100 @@
+// backend/internal/db/query_timeout.go
+// ==========================================================================
+// Per-query timeout. queryTimeoutTracer is installed as the pool's pgx query
+// tracer and gives every query its own deadline (DATABASE_QUERY_TIMEOUT), so a
+// stuck query is cancelled without bounding the rest of the request: large
+// downloads and exports can take as long as they need between queries.
+// A request can carry a QueryTimeouts recorder to learn that one of its
+// queries timed out (see middleware/timeout, which answers 504).
+// ==========================================================================
+
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// queryCancelKey is the context key under which a query's cancel func is stored.
+type queryCancelKey struct{}
+
+// queryTimeoutsKey is the context key of a request's QueryTimeouts recorder.
+type queryTimeoutsKey struct{}
+
+// noQueryTimeoutKey marks contexts whose queries run without the timeout.
+type noQueryTimeoutKey struct{}
+
+// QueryTimeouts records whether any query run on a context timed out.
+type QueryTimeouts struct {
+	exceeded atomic.Bool
+}
+
+// Exceeded reports whether a query timed out.
+func (q *QueryTimeouts) Exceeded() bool {
+	return q.exceeded.Load()
+}
+
+// WithQueryTimeouts returns a context whose queries report timeouts to the
+// returned recorder.
+func WithQueryTimeouts(ctx context.Context) (context.Context, *QueryTimeouts) {
+	recorder := &QueryTimeouts{}
+	return context.WithValue(ctx, queryTimeoutsKey{}, recorder), recorder
+}
+
+// WithoutQueryTimeout returns a context whose queries are not bounded by the
+// per-query timeout, for startup work such as migrations that may
+// legitimately run long or wait on a lock.
+func WithoutQueryTimeout(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noQueryTimeoutKey{}, true)
+}
+
+// queryTimeoutTracer implements pgx.QueryTracer, wrapping another tracer
+// (e.g. the slow query tracer) when one is configured.
+type queryTimeoutTracer struct {
+	timeout time.Duration
+	next    pgx.QueryTracer
+	logger  *slog.Logger
+}
+
+// newQueryTimeoutTracer creates a tracer that bounds each query by timeout.
+func newQueryTimeoutTracer(timeout time.Duration, next pgx.QueryTracer) *queryTimeoutTracer {
+	return &queryTimeoutTracer{timeout: timeout, next: next, logger: slog.With("component", "Database")}
+}
+
+// TraceQueryStart puts the query's deadline on its context. pgx runs the
+// query, and reads its rows, on the returned context.
+func (t *queryTimeoutTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	if exempt, _ := ctx.Value(noQueryTimeoutKey{}).(bool); !exempt {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		ctx = context.WithValue(ctx, queryCancelKey{}, cancel)
+	}
+	if t.next != nil {
+		ctx = t.next.TraceQueryStart(ctx, conn, data)
+	}
+	return ctx
+}
+
+// TraceQueryEnd records a timed-out query and releases its deadline. For
+// Query it runs when the rows are closed.
+func (t *queryTimeoutTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	if t.next != nil {
+		t.next.TraceQueryEnd(ctx, conn, data)
+	}
+	cancel, ok := ctx.Value(queryCancelKey{}).(context.CancelFunc)
+	if !ok {
+		return
+	}
+	if data.Err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.logger.WarnContext(ctx, "Database query exceeded its timeout", "timeout", t.timeout, "error", data.Err)
+		if recorder, ok := ctx.Value(queryTimeoutsKey{}).(*QueryTimeouts); ok {
+			recorder.exceeded.Store(true)
+		}
+	}
+	cancel()
+}
@@ -0,0 +1,27 @@
+// backend/internal/db/ticket_access.go
+// ==========================================================================
+// SQL form of the ticket access rule for non-admins, shared by every query
+// that lists or searches tickets so they agree with the per-ticket check
+// (ticket handler checkTicketAccess).
+// ==========================================================================
+
+package db
+
+import "fmt"
+
+// TicketAccessCondition returns a SQL condition that is true when a non-admin
+// user may open the ticket: it is assigned to them, belongs to one of their
+// teams, or is assigned to neither a user nor a team.
+//
+// Parameters:
+//   - alias: The name or alias of the tickets table in the query (e.g., "t").
+//   - userPlaceholder: The placeholder holding the user's ID (e.g., "$3").
+//
+// Returns:
+//   - string: The parenthesized condition.
+func TicketAccessCondition(alias, userPlaceholder string) string {
+	return fmt.Sprintf(`(%[1]s.assigned_to_user_id = %[2]s
+            OR (%[1]s.assigned_to_user_id IS NULL AND %[1]s.assigned_to_team_id IS NULL)
+            OR EXISTS (SELECT 1 FROM team_members tm
+                WHERE tm.team_id = %[1]s.assigned_to_team_id AND tm.user_id = %[2]s))`, alias, userPlaceholder)
+}
@@ -0,0 +1,82 @@
+// backend/internal/db/ticket_numbers.go
+// ==========================================================================
+// Ticket number counter. Numbers come from the identity sequence on
+// tickets.ticket_number (migration 0039), so concurrent creations never share
+// a number; a rolled-back creation leaves a gap. TICKET_NUMBER_START lets an
+// installation begin numbering above the default of 1.
+// ==========================================================================
+
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// EnsureTicketNumberStart moves the ticket number counter forward so the next
+// ticket is numbered at least start. The counter is never moved back, so a
+// start below the numbers already issued has no effect; in that case (the
+// usual one after the first start-up) the counter is only read, and the table
+// is not locked.
+//
+// Parameters:
+//   - ctx: Context for the queries.
+//   - start: The lowest ticket number to hand out (TICKET_NUMBER_START).
+//
+// Returns:
+//   - error: If the counter cannot be read or updated.
+func (db *DB) EnsureTicketNumberStart(ctx context.Context, start int) (err error) {
+	logger := slog.With("component", "Database", "operation", "EnsureTicketNumberStart")
+	if start <= 1 {
+		return nil
+	}
+	ctx = WithoutQueryTimeout(ctx) // The table lock may wait on in-flight ticket inserts
+
+	// The counter only moves forward, so once it has passed start no lock is needed.
+	var issued int64
+	if err := db.Pool.QueryRow(ctx, `
+        SELECT GREATEST(COALESCE(pg_sequence_last_value(pg_get_serial_sequence('tickets', 'ticket_number')::regclass), 0),
+                        COALESCE((SELECT MAX(ticket_number) FROM tickets), 0))`).Scan(&issued); err != nil {
+		return fmt.Errorf("failed to read ticket number counter: %w", err)
+	}
+	if issued >= int64(start)-1 {
+		return nil
+	}
+
+	tx, err := db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin ticket number transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				logger.ErrorContext(ctx, "Failed to rollback transaction", "rollbackError", rbErr)
+			}
+		}
+	}()
+
+	// Block ticket inserts while the counter is compared and moved, so a
+	// concurrent creation cannot take a number between the two.
+	if _, err = tx.Exec(ctx, `LOCK TABLE tickets IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		return fmt.Errorf("failed to lock tickets table: %w", err)
+	}
+	tag, err := tx.Exec(ctx, `
+        SELECT setval(s.seq, $1 - 1)
+        FROM (SELECT pg_get_serial_sequence('tickets', 'ticket_number')::regclass AS seq) s
+        WHERE $1 - 1 > GREATEST(COALESCE(pg_sequence_last_value(s.seq), 0),
+                                COALESCE((SELECT MAX(ticket_number) FROM tickets), 0))`, start)
+	if err != nil {
+		return fmt.Errorf("failed to move ticket number counter: %w", err)
+	}
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit ticket number counter: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		logger.InfoContext(ctx, "Ticket number counter moved forward", "nextTicketNumber", start)
+	}
+	return nil
+}
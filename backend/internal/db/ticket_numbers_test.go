@@ -0,0 +1,91 @@
+// backend/internal/db/ticket_numbers_test.go
+// ==========================================================================
+// Integration test for ticket numbering under concurrent creation: numbers
+// must be unique and increasing, though they may skip. It needs a
+// disposable PostgreSQL database in TEST_DATABASE_URL (migrated on start) and
+// is skipped when that variable is unset.
+// ==========================================================================
+
+package db
+
+import (
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+)
+
+// connectTestDB connects to TEST_DATABASE_URL and applies the migrations,
+// skipping the test when no test database is configured.
+func connectTestDB(t *testing.T) *DB {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	database, err := Connect(config.DatabaseConfig{URL: url})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(database.Close)
+	if err := database.Migrate(context.Background()); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return database
+}
+
+func TestConcurrentTicketNumbersAreUniqueAndIncreasing(t *testing.T) {
+	database := connectTestDB(t)
+	ctx := context.Background()
+	const workers = 50
+
+	var (
+		mu      sync.Mutex
+		ids     []string
+		numbers []int
+		wg      sync.WaitGroup
+	)
+	errs := make(chan error, workers)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var id string
+			var number int
+			err := database.Pool.QueryRow(ctx, `
+                INSERT INTO tickets (end_user_email, urgency, subject, description, status)
+                VALUES ('numbering-test@example.com', 'Low', 'Numbering test', 'Concurrent creation', 'Open')
+                RETURNING id, ticket_number`).Scan(&id, &number)
+			if err != nil {
+				errs <- err
+				return
+			}
+			mu.Lock()
+			ids = append(ids, id)
+			numbers = append(numbers, number)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	t.Cleanup(func() {
+		if _, err := database.Pool.Exec(context.Background(), `DELETE FROM tickets WHERE id = ANY($1::uuid[])`, ids); err != nil {
+			t.Errorf("cleanup: %v", err)
+		}
+	})
+	for err := range errs {
+		t.Fatalf("insert: %v", err)
+	}
+
+	// Gaps are allowed (a rolled-back insert or another client can consume a
+	// number); duplicates are not.
+	sort.Ints(numbers)
+	for i := 1; i < len(numbers); i++ {
+		if numbers[i] <= numbers[i-1] {
+			t.Fatalf("ticket number %d was issued twice", numbers[i])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// backend/internal/db/tracer.go
+// ==========================================================================
+// Slow query logging. slowQueryTracer is installed as the pool's pgx query
+// tracer and logs every query that runs longer than the configured threshold
+// with its SQL and argument count. Argument values are never logged, since
+// they routinely carry submitter names, emails and ticket text.
+// ==========================================================================
+
+package db
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxLoggedSQLLength caps the SQL text included in a slow query log entry.
+const maxLoggedSQLLength = 2000
+
+// slowQueryKey is the context key under which a query's start is stored.
+type slowQueryKey struct{}
+
+// slowQueryStart records what TraceQueryEnd needs to report a query.
+type slowQueryStart struct {
+	started  time.Time
+	sql      string
+	argCount int
+}
+
+// slowQueryTracer implements pgx.QueryTracer.
+type slowQueryTracer struct {
+	threshold time.Duration
+	logger    *slog.Logger
+}
+
+// newSlowQueryTracer creates a tracer that logs queries slower than threshold.
+func newSlowQueryTracer(threshold time.Duration) *slowQueryTracer {
+	return &slowQueryTracer{threshold: threshold, logger: slog.With("component", "Database")}
+}
+
+// TraceQueryStart notes when the query started.
+func (t *slowQueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, slowQueryKey{}, slowQueryStart{started: time.Now(), sql: data.SQL, argCount: len(data.Args)})
+}
+
+// TraceQueryEnd logs the query at warn level if it exceeded the threshold.
+func (t *slowQueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(slowQueryKey{}).(slowQueryStart)
+	if !ok {
+		return
+	}
+	elapsed := time.Since(start.started)
+	if elapsed < t.threshold {
+		return
+	}
+	attrs := []any{
+		"duration", elapsed,
+		"threshold", t.threshold,
+		"sql", compactSQL(start.sql),
+		"argCount", start.argCount,
+		"rowsAffected", data.CommandTag.RowsAffected(),
+	}
+	if data.Err != nil {
+		attrs = append(attrs, "error", data.Err)
+	}
+	t.logger.WarnContext(ctx, "Slow database query", attrs...)
+}
+
+// compactSQL collapses whitespace so multi-line queries log on one line, and
+// truncates very long statements.
+func compactSQL(sql string) string {
+	sql = strings.Join(strings.Fields(sql), " ")
+	if len(sql) > maxLoggedSQLLength {
+		sql = sql[:maxLoggedSQLLength] + "..."
+	}
+	return sql
+}
@@ -0,0 +1,24 @@
+// backend/internal/db/user_timezone.go
+// ==========================================================================
+// Lookup of a user's saved timezone preference, used to resolve the timezone
+// of a request that does not send a valid X-Timezone header.
+// ==========================================================================
+
+package db
+
+import "context"
+
+// UserTimezone returns the user's saved IANA timezone name, or nil if none is set.
+//
+// Parameters:
+//   - ctx: Context for the query.
+//   - userID: The user's UUID.
+//
+// Returns:
+//   - *string: The saved timezone, or nil.
+//   - error: If the user cannot be loaded.
+func (db *DB) UserTimezone(ctx context.Context, userID string) (*string, error) {
+	var preference *string
+	err := db.Pool.QueryRow(ctx, `SELECT timezone FROM users WHERE id = $1`, userID).Scan(&preference)
+	return preference, err
+}
@@ -2,14 +2,20 @@ package email
 
 import (
 	"bytes"
+	"context"
 	"embed"
 	"errors"
 	"fmt"
 	"html/template"
 	"log/slog"
+	"net"
 	"os" // Needed for RESEND_API_KEY
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/i18n"
 	"github.com/resend/resend-go/v2" // Import the Resend SDK
 )
 
@@ -32,12 +38,47 @@ func init() {
 
 // Service defines the contract for sending different types of emails.
 type Service interface {
-	SendTicketConfirmation(recipient, submitterName, ticketID, subject string) error
-	SendTicketClosure(recipient, ticketID, subject, resolution string) error
-	SendTicketInProgress(recipient, ticketID, subject, assignedStaffName string) error
+	// Submitter-facing notifications are rendered in the given locale (English fallback).
+	// statusLink is the tokenized public status/reply URL ("" omits it); suggestions are
+	// knowledge base solutions listed as self-help (nil omits the section).
+	SendTicketConfirmation(recipient, submitterName, ticketID, subject, locale, statusLink string, suggestions []SuggestedSolution) error
+	SendTicketClosure(recipient, ticketID, subject, resolution, locale string) error
+	SendTicketInProgress(recipient, ticketID, subject, assignedStaffName, locale string) error
 	SendTicketAssignment(recipientEmail, ticketID, subject string) error
+	// SendTicketAssignmentDigest notifies staff of several assignments in one email.
+	SendTicketAssignmentDigest(recipientEmail string, tickets []AssignedTicket) error
+	SendTicketReopened(recipientEmail, ticketID, subject string) error
+	// SendTicketWaitingOnCustomer asks the submitter for information; reminderNumber 0 is the
+	// initial request, 1.. are the job's reminders.
+	SendTicketWaitingOnCustomer(recipient, ticketID, subject, locale string, reminderNumber int) error
+	// SendTicketReply emails a staff reply (a comment posted with reply_to_submitter) to the submitter.
+	SendTicketReply(recipient, ticketID, subject, authorName, comment, locale string) error
+	// SendTicketUrgencyChanged tells a submitter (in their locale) or assignee that a ticket's urgency changed.
+	SendTicketUrgencyChanged(recipientEmail, ticketID, subject, oldUrgency, newUrgency, locale string) error
+	// SendTicketSpikeAlert warns an admin that ticket creation has surged.
+	SendTicketSpikeAlert(recipientEmail, ticketCount, window, topIssueTypes, topTags string) error
 	SendRegistrationConfirmation(recipientEmail, userName string) error
 	SendPasswordReset(recipientEmail, userName, resetLink string) error
+	// SendInvite emails a registration invite; inviteLink carries the invite token.
+	SendInvite(recipientEmail, role, inviteLink, expiresAt string) error
+	// SendTestEmail sends a diagnostic email immediately (never queued) and returns the provider error, if any.
+	SendTestEmail(recipientEmail string) error
+	// CheckConnection verifies the email provider is reachable.
+	CheckConnection(ctx context.Context) error
+}
+
+// AssignedTicket is one entry in an assignment digest email.
+type AssignedTicket struct {
+	TicketID string     `json:"ticket_id"` // Ticket number shown to the recipient
+	Subject  string     `json:"subject"`
+	DueDate  *time.Time `json:"due_date,omitempty"`
+	Overdue  bool       `json:"overdue"` // Past its due date and not closed; listed first
+}
+
+// SuggestedSolution is a knowledge base solution listed in a confirmation email.
+type SuggestedSolution struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
 }
 
 // --- Resend Implementation ---
@@ -123,64 +164,196 @@ func (s *ResendService) sendEmail(templateName, recipient, subject string, data
 	return nil
 }
 
-// --- Interface Implementations (Remain the same logic, just call the new sendEmail) ---
+// --- Localization Helpers ---
 
-func (s *ResendService) SendTicketConfirmation(recipient, submitterName, ticketID, subject string) error {
-	emailSubject := fmt.Sprintf("IT Helpdesk - Ticket Received [#%s]", ticketID)
-	data := map[string]interface{}{
-		"Title":         "Ticket Received",
-		"NotificationType": "new",
-		"Status":        "new",
-		"StatusLabel":   "New",
-		"TicketID":      ticketID,
-		"Subject":       subject,
-		"RecipientName": submitterName, // Name of the person who submitted
+// localizedHTML renders a catalog message as trusted HTML. Catalog strings may
+// contain simple markup (e.g., <strong>); interpolated values are escaped.
+func localizedHTML(locale, key string, args ...interface{}) template.HTML {
+	escaped := make([]interface{}, len(args))
+	for i, arg := range args {
+		escaped[i] = template.HTMLEscapeString(fmt.Sprint(arg))
 	}
-	return s.sendEmail("ticket_notification.html", recipient, emailSubject, data)
+	return template.HTML(i18n.T(locale, key, escaped...))
 }
 
-func (s *ResendService) SendTicketClosure(recipient, ticketID, subject, resolution string) error {
-	emailSubject := fmt.Sprintf("IT Helpdesk - Ticket Closed [#%s]", ticketID)
-	data := map[string]interface{}{
-		"Title":         "Ticket Closed",
-		"NotificationType": "closed",
-		"Status":        "closed",
-		"StatusLabel":   "Closed",
-		"TicketID":      ticketID,
-		"Subject":       subject,
-		"Resolution":    resolution,
+// ticketNotificationData builds the template data for ticket_notification.html,
+// pulling all user-facing text for the given message kind from the catalog.
+//
+// Parameters:
+//   - locale: The recipient's preferred locale (falls back to English).
+//   - kind: The catalog prefix for the message (e.g., "ticket_closure").
+//   - notificationType: The template branch/styling key (e.g., "closed").
+//   - ticketID: The ticket number shown to the recipient.
+//   - subject: The ticket subject.
+//   - recipientName: Optional name used in the greeting.
+func ticketNotificationData(locale, kind, notificationType, ticketID, subject, recipientName string) map[string]interface{} {
+	locale = i18n.Match(locale)
+	key := func(suffix string) string { return "email." + kind + "." + suffix }
+
+	footer := localizedHTML(locale, "email.footer")
+	if kind == "ticket_confirmation" || kind == "ticket_closure" {
+		footer = localizedHTML(locale, key("footer"))
+	}
+	greeting := localizedHTML(locale, "email.greeting_anonymous")
+	if recipientName != "" {
+		greeting = localizedHTML(locale, "email.greeting", recipientName)
 	}
+
+	return map[string]interface{}{
+		"Locale":           locale,
+		"Title":            i18n.T(locale, key("title")),
+		"NotificationType": notificationType,
+		"Status":           notificationType,
+		"StatusLabel":      i18n.T(locale, key("status")),
+		"TicketID":         ticketID,
+		"Subject":          subject,
+		"Text": map[string]template.HTML{
+			"Greeting":        greeting,
+			"Body":            localizedHTML(locale, key("body"), ticketID, subject),
+			"AssignedStaff":   localizedHTML(locale, "email.ticket_in_progress.assigned_staff"),
+			"FollowUp":        localizedHTML(locale, "email.ticket_in_progress.follow_up"),
+			"ResolutionLabel": localizedHTML(locale, "email.ticket_closure.resolution_label"),
+			"Suggestions":     localizedHTML(locale, "email.ticket_confirmation.suggestions"),
+			"MoreHelp":        localizedHTML(locale, "email.ticket_confirmation.more_help"),
+			"MoreHelpLink":    localizedHTML(locale, "email.ticket_confirmation.more_help_link"),
+			"Reopen":          localizedHTML(locale, "email.ticket_closure.reopen"),
+			"ViewTicket":      localizedHTML(locale, "email.view_ticket"),
+			"ViewTicketLink":  localizedHTML(locale, "email.view_ticket_link"),
+			"StatusLink":      localizedHTML(locale, "email.status_link"),
+			"StatusLinkLabel": localizedHTML(locale, "email.status_link_label"),
+			"Signoff":         localizedHTML(locale, "email.signoff"),
+			"Team":            localizedHTML(locale, "email.team"),
+			"Footer":          footer,
+		},
+	}
+}
+
+// --- Interface Implementations ---
+
+func (s *ResendService) SendTicketConfirmation(recipient, submitterName, ticketID, subject, locale, statusLink string, suggestions []SuggestedSolution) error {
+	emailSubject := i18n.T(locale, "email.ticket_confirmation.subject", ticketID)
+	data := ticketNotificationData(locale, "ticket_confirmation", "new", ticketID, subject, submitterName)
+	data["StatusLink"] = statusLink
+	data["Suggestions"] = suggestions
 	return s.sendEmail("ticket_notification.html", recipient, emailSubject, data)
 }
 
-func (s *ResendService) SendTicketInProgress(recipient, ticketID, subject, assignedStaffName string) error {
-	emailSubject := fmt.Sprintf("IT Helpdesk - Ticket In Progress [#%s]", ticketID)
-	data := map[string]interface{}{
-		"Title":             "Ticket Update",
-		"NotificationType": "inprogress",
-		"Status":        "inprogress",
-		"StatusLabel":       "In Progress",
-		"TicketID":          ticketID,
-		"Subject":           subject,
-		"AssignedStaffName": assignedStaffName,
+func (s *ResendService) SendTicketClosure(recipient, ticketID, subject, resolution, locale string) error {
+	emailSubject, data := ticketClosureData(ticketID, subject, resolution, locale)
+	return s.sendEmail("ticket_notification.html", recipient, emailSubject, data)
+}
+
+// RenderTicketClosure renders the closure email exactly as SendTicketClosure
+// would send it, without sending anything (used to preview draft resolution
+// notes).
+//
+// Parameters:
+//   - portalURL: Base URL of the frontend portal, as passed to NewService.
+//   - ticketID, subject, resolution, locale: As for SendTicketClosure.
+//
+// Returns:
+//   - string: The email subject line.
+//   - string: The rendered HTML body.
+//   - error: If the template fails to render.
+func RenderTicketClosure(portalURL, ticketID, subject, resolution, locale string) (string, string, error) {
+	emailSubject, data := ticketClosureData(ticketID, subject, resolution, locale)
+	data["PortalURL"] = portalURL
+	html, err := renderTemplate(slog.With("service", "EmailService"), "ticket_notification.html", data)
+	if err != nil {
+		return "", "", err
 	}
+	return emailSubject, html, nil
+}
+
+// ticketClosureData builds the subject and template data of a closure email.
+func ticketClosureData(ticketID, subject, resolution, locale string) (string, map[string]interface{}) {
+	emailSubject := i18n.T(locale, "email.ticket_closure.subject", ticketID)
+	data := ticketNotificationData(locale, "ticket_closure", "closed", ticketID, subject, "")
+	data["Resolution"] = resolution
+	return emailSubject, data
+}
+
+func (s *ResendService) SendTicketInProgress(recipient, ticketID, subject, assignedStaffName, locale string) error {
+	emailSubject := i18n.T(locale, "email.ticket_in_progress.subject", ticketID)
+	data := ticketNotificationData(locale, "ticket_in_progress", "inprogress", ticketID, subject, "")
+	data["AssignedStaffName"] = assignedStaffName
 	return s.sendEmail("ticket_notification.html", recipient, emailSubject, data)
 }
 
+// SendTicketAssignment notifies staff of a new assignment. Staff-facing mail uses the default locale.
 func (s *ResendService) SendTicketAssignment(recipientEmail, ticketID, subject string) error {
-	emailSubject := fmt.Sprintf("New Ticket Assignment [#%s]", ticketID)
-	data := map[string]interface{}{
-		"Title":            "New Ticket Assignment",
-		"NotificationType": "assignment",
-		"Status":        "assigned", // Use a relevant status for styling if needed
-		"StatusLabel":      "Assigned",
-		"TicketID":         ticketID,
-		"Subject":          subject,
-		"CustomMessage":    fmt.Sprintf("You have been assigned ticket #%s regarding \"%s\". Please review the ticket details in the portal.", ticketID, subject),
+	emailSubject := i18n.T(i18n.DefaultLocale, "email.ticket_assignment.subject", ticketID)
+	data := ticketNotificationData(i18n.DefaultLocale, "ticket_assignment", "assignment", ticketID, subject, "")
+	return s.sendEmail("ticket_notification.html", recipientEmail, emailSubject, data)
+}
+
+// SendTicketAssignmentDigest notifies staff of several assignments at once
+// (e.g., after a bulk reassignment), listing each ticket with its due date
+// and marking overdue ones.
+func (s *ResendService) SendTicketAssignmentDigest(recipientEmail string, tickets []AssignedTicket) error {
+	count := strconv.Itoa(len(tickets))
+	emailSubject := i18n.T(i18n.DefaultLocale, "email.ticket_assignment_digest.subject", count)
+	data := ticketNotificationData(i18n.DefaultLocale, "ticket_assignment_digest", "assignment_digest", "", "", "")
+	text := data["Text"].(map[string]template.HTML)
+	text["Body"] = localizedHTML(i18n.DefaultLocale, "email.ticket_assignment_digest.body", count)
+	text["Overdue"] = localizedHTML(i18n.DefaultLocale, "email.ticket_assignment_digest.overdue")
+	text["Due"] = localizedHTML(i18n.DefaultLocale, "email.ticket_assignment_digest.due")
+	data["Tickets"] = tickets
+	return s.sendEmail("ticket_notification.html", recipientEmail, emailSubject, data)
+}
+
+// SendTicketReopened tells the last assignee that the submitter reopened a closed ticket.
+func (s *ResendService) SendTicketReopened(recipientEmail, ticketID, subject string) error {
+	emailSubject := i18n.T(i18n.DefaultLocale, "email.ticket_reopened.subject", ticketID)
+	data := ticketNotificationData(i18n.DefaultLocale, "ticket_reopened", "reopened", ticketID, subject, "")
+	return s.sendEmail("ticket_notification.html", recipientEmail, emailSubject, data)
+}
+
+// SendTicketWaitingOnCustomer asks the submitter to reply with the information
+// staff need, or reminds them that the ticket is still waiting on them.
+func (s *ResendService) SendTicketWaitingOnCustomer(recipient, ticketID, subject, locale string, reminderNumber int) error {
+	kind := "ticket_waiting_on_customer"
+	if reminderNumber > 0 {
+		kind = "ticket_waiting_reminder"
 	}
+	emailSubject := i18n.T(locale, "email."+kind+".subject", ticketID)
+	data := ticketNotificationData(locale, kind, "waiting", ticketID, subject, "")
+	return s.sendEmail("ticket_notification.html", recipient, emailSubject, data)
+}
+
+// SendTicketReply sends a staff member's public reply to the submitter. The
+// comment is escaped and line breaks are preserved.
+func (s *ResendService) SendTicketReply(recipient, ticketID, subject, authorName, comment, locale string) error {
+	emailSubject := i18n.T(locale, "email.ticket_reply.subject", ticketID)
+	data := ticketNotificationData(locale, "ticket_reply", "reply", ticketID, subject, "")
+	quoted := strings.ReplaceAll(template.HTMLEscapeString(comment), "\n", "<br>")
+	data["Text"].(map[string]template.HTML)["Body"] = localizedHTML(locale, "email.ticket_reply.body", authorName, ticketID, subject) +
+		template.HTML(`<blockquote style="border-left: 3px solid #d1d5db; margin: 12px 0; padding-left: 12px;">`+quoted+`</blockquote>`) +
+		localizedHTML(locale, "email.ticket_reply.respond")
+	return s.sendEmail("ticket_notification.html", recipient, emailSubject, data)
+}
+
+// SendTicketUrgencyChanged reports an urgency change with the old and new values.
+func (s *ResendService) SendTicketUrgencyChanged(recipientEmail, ticketID, subject, oldUrgency, newUrgency, locale string) error {
+	emailSubject := i18n.T(locale, "email.ticket_urgency_changed.subject", ticketID)
+	data := ticketNotificationData(locale, "ticket_urgency_changed", "urgency", ticketID, subject, "")
+	data["Text"].(map[string]template.HTML)["Body"] = localizedHTML(locale, "email.ticket_urgency_changed.body", ticketID, subject, oldUrgency, newUrgency)
 	return s.sendEmail("ticket_notification.html", recipientEmail, emailSubject, data)
 }
 
+// SendTicketSpikeAlert warns an admin that an unusual number of tickets was
+// created recently, listing the most common issue types and tags.
+func (s *ResendService) SendTicketSpikeAlert(recipientEmail, ticketCount, window, topIssueTypes, topTags string) error {
+	emailSubject := fmt.Sprintf("Alert: %s tickets created in the last %s - IT Helpdesk System", ticketCount, window)
+	data := map[string]interface{}{
+		"TicketCount":   ticketCount,
+		"Window":        window,
+		"TopIssueTypes": topIssueTypes,
+		"TopTags":       topTags,
+	}
+	return s.sendEmail("ticket_spike_alert.html", recipientEmail, emailSubject, data)
+}
+
 func (s *ResendService) SendRegistrationConfirmation(recipientEmail, userName string) error {
 	emailSubject := "Welcome to the IT Helpdesk System!"
 	data := map[string]interface{}{"UserName": userName}
@@ -192,3 +365,34 @@ func (s *ResendService) SendPasswordReset(recipientEmail, userName, resetLink st
 	data := map[string]interface{}{"UserName": userName, "ResetLink": resetLink}
 	return s.sendEmail("password_reset.html", recipientEmail, emailSubject, data)
 }
+
+func (s *ResendService) SendInvite(recipientEmail, role, inviteLink, expiresAt string) error {
+	emailSubject := "You're invited to the IT Helpdesk System"
+	data := map[string]interface{}{"Role": role, "InviteLink": inviteLink, "ExpiresAt": expiresAt}
+	return s.sendEmail("user_invite.html", recipientEmail, emailSubject, data)
+}
+
+// SendTestEmail sends a diagnostic message straight through the Resend API.
+func (s *ResendService) SendTestEmail(recipientEmail string) error {
+	emailSubject := "IT Helpdesk - Email Delivery Test"
+	data := map[string]interface{}{"SentAt": time.Now().UTC().Format(time.RFC1123)}
+	return s.sendEmail("email_test.html", recipientEmail, emailSubject, data)
+}
+
+// CheckConnection opens a TCP connection to the Resend API host to confirm it is reachable.
+func (s *ResendService) CheckConnection(ctx context.Context) error {
+	host := s.client.BaseURL.Hostname()
+	port := s.client.BaseURL.Port()
+	if port == "" {
+		port = "443"
+		if s.client.BaseURL.Scheme == "http" {
+			port = "80"
+		}
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return fmt.Errorf("email provider %s unreachable: %w", host, err)
+	}
+	return conn.Close()
+}
@@ -0,0 +1,521 @@
+// backend/internal/email/outbox.go
+// ==========================================================================
+// Persistent email outbox. OutboxService implements Service by writing each
+// email to the email_outbox table instead of sending it; DeliverDue (run by a
+// background worker) sends due rows through the wrapped Service, retrying
+// failures with exponential backoff until they are sent or marked failed.
+// Assignment emails are held for a short window and delivered as a single
+// digest when several are queued for the same recipient.
+// ==========================================================================
+
+package email
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preferences"
+	"github.com/jackc/pgx/v5"
+)
+
+// Outbox email kinds; each maps to one Service method.
+const (
+	KindTicketConfirmation       = "ticket_confirmation"
+	KindTicketClosure            = "ticket_closure"
+	KindTicketInProgress         = "ticket_in_progress"
+	KindTicketAssignment         = "ticket_assignment"
+	KindTicketReopened           = "ticket_reopened"
+	KindTicketUrgencyChanged     = "ticket_urgency_changed"
+	KindTicketWaitingOnCustomer  = "ticket_waiting_on_customer"
+	KindTicketReply              = "ticket_reply"
+	KindTicketSpikeAlert         = "ticket_spike_alert"
+	KindRegistrationConfirmation = "registration_confirmation"
+	KindPasswordReset            = "password_reset"
+	KindInvite                   = "user_invite"
+)
+
+// Outbox row statuses.
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusFailed  = "failed"
+)
+
+// enqueueTimeout bounds the outbox insert performed by each Send* call.
+const enqueueTimeout = 5 * time.Second
+
+// claimLease is how long a claimed row is hidden from other workers while it is
+// being sent. If a worker dies mid-send, the row becomes due again afterwards.
+const claimLease = 2 * time.Minute
+
+// --- Types ---
+
+// OutboxService queues emails in the database and delivers them via a wrapped Service.
+type OutboxService struct {
+	db       *db.DB
+	delivery Service // The Service that actually sends (e.g., ResendService)
+	cfg      config.EmailOutboxConfig
+	prefs    *preferences.Service // Recipients may opt out of digests
+	logger   *slog.Logger
+}
+
+// outboxMessage is a claimed outbox row awaiting delivery.
+type outboxMessage struct {
+	id        string
+	kind      string
+	recipient string
+	payload   map[string]string
+	attempts  int
+	groupKey  *string // Non-nil for messages that may be coalesced into a digest
+}
+
+// --- Constructor ---
+
+// NewOutboxService wraps a sending Service with a persistent outbox.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - delivery: The Service used to actually send emails.
+//   - cfg: The outbox configuration (config.EmailOutboxConfig).
+//
+// Returns:
+//   - *OutboxService: The outbox; use it wherever a Service is expected.
+func NewOutboxService(database *db.DB, delivery Service, cfg config.EmailOutboxConfig) *OutboxService {
+	return &OutboxService{
+		db:       database,
+		delivery: delivery,
+		cfg:      cfg,
+		prefs:    preferences.NewService(database),
+		logger:   slog.With("service", "EmailOutbox"),
+	}
+}
+
+// --- Service Implementation (enqueue) ---
+
+func (o *OutboxService) SendTicketConfirmation(recipient, submitterName, ticketID, subject, locale, statusLink string, suggestions []SuggestedSolution) error {
+	payload := map[string]string{
+		"submitter_name": submitterName, "ticket_id": ticketID, "subject": subject, "locale": locale,
+		"status_link": statusLink,
+	}
+	if len(suggestions) > 0 {
+		encoded, err := json.Marshal(suggestions)
+		if err != nil {
+			return fmt.Errorf("failed to encode suggested solutions: %w", err)
+		}
+		payload["suggestions"] = string(encoded)
+	}
+	return o.enqueue(KindTicketConfirmation, recipient, payload)
+}
+
+func (o *OutboxService) SendTicketClosure(recipient, ticketID, subject, resolution, locale string) error {
+	return o.enqueue(KindTicketClosure, recipient, map[string]string{
+		"ticket_id": ticketID, "subject": subject, "resolution": resolution, "locale": locale,
+	})
+}
+
+func (o *OutboxService) SendTicketInProgress(recipient, ticketID, subject, assignedStaffName, locale string) error {
+	return o.enqueue(KindTicketInProgress, recipient, map[string]string{
+		"ticket_id": ticketID, "subject": subject, "assigned_staff_name": assignedStaffName, "locale": locale,
+	})
+}
+
+// SendTicketAssignment queues an assignment email. With a coalescing window
+// configured it is held for that long so further assignments to the same
+// person can be folded into one digest, unless the recipient opted out of digests.
+func (o *OutboxService) SendTicketAssignment(recipientEmail, ticketID, subject string) error {
+	payload := map[string]string{"ticket_id": ticketID, "subject": subject}
+	if o.cfg.AssignmentCoalesceWindow <= 0 || !o.prefs.AllowsEmail(context.Background(), recipientEmail, preferences.CategoryDigest) {
+		return o.enqueue(KindTicketAssignment, recipientEmail, payload)
+	}
+	return o.enqueueGrouped(KindTicketAssignment, recipientEmail, payload, assignmentGroupKey(recipientEmail), o.cfg.AssignmentCoalesceWindow)
+}
+
+// SendTicketAssignmentDigest queues one assignment row per ticket under a
+// shared group key, so they are delivered together as a digest.
+func (o *OutboxService) SendTicketAssignmentDigest(recipientEmail string, tickets []AssignedTicket) error {
+	groupKey := assignmentGroupKey(recipientEmail)
+	for _, t := range tickets {
+		payload := map[string]string{"ticket_id": t.TicketID, "subject": t.Subject}
+		if err := o.enqueueGrouped(KindTicketAssignment, recipientEmail, payload, groupKey, o.cfg.AssignmentCoalesceWindow); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *OutboxService) SendTicketReopened(recipientEmail, ticketID, subject string) error {
+	return o.enqueue(KindTicketReopened, recipientEmail, map[string]string{
+		"ticket_id": ticketID, "subject": subject,
+	})
+}
+
+func (o *OutboxService) SendTicketWaitingOnCustomer(recipient, ticketID, subject, locale string, reminderNumber int) error {
+	return o.enqueue(KindTicketWaitingOnCustomer, recipient, map[string]string{
+		"ticket_id": ticketID, "subject": subject, "locale": locale, "reminder_number": strconv.Itoa(reminderNumber),
+	})
+}
+
+func (o *OutboxService) SendTicketReply(recipient, ticketID, subject, authorName, comment, locale string) error {
+	return o.enqueue(KindTicketReply, recipient, map[string]string{
+		"ticket_id": ticketID, "subject": subject, "author_name": authorName, "comment": comment, "locale": locale,
+	})
+}
+
+func (o *OutboxService) SendTicketUrgencyChanged(recipientEmail, ticketID, subject, oldUrgency, newUrgency, locale string) error {
+	return o.enqueue(KindTicketUrgencyChanged, recipientEmail, map[string]string{
+		"ticket_id": ticketID, "subject": subject, "old_urgency": oldUrgency, "new_urgency": newUrgency, "locale": locale,
+	})
+}
+
+func (o *OutboxService) SendTicketSpikeAlert(recipientEmail, ticketCount, window, topIssueTypes, topTags string) error {
+	return o.enqueue(KindTicketSpikeAlert, recipientEmail, map[string]string{
+		"ticket_count": ticketCount, "window": window, "top_issue_types": topIssueTypes, "top_tags": topTags,
+	})
+}
+
+func (o *OutboxService) SendRegistrationConfirmation(recipientEmail, userName string) error {
+	return o.enqueue(KindRegistrationConfirmation, recipientEmail, map[string]string{
+		"user_name": userName,
+	})
+}
+
+func (o *OutboxService) SendPasswordReset(recipientEmail, userName, resetLink string) error {
+	return o.enqueue(KindPasswordReset, recipientEmail, map[string]string{
+		"user_name": userName, "reset_link": resetLink,
+	})
+}
+
+func (o *OutboxService) SendInvite(recipientEmail, role, inviteLink, expiresAt string) error {
+	return o.enqueue(KindInvite, recipientEmail, map[string]string{
+		"role": role, "invite_link": inviteLink, "expires_at": expiresAt,
+	})
+}
+
+// SendTestEmail bypasses the queue so the caller sees the provider's error directly.
+func (o *OutboxService) SendTestEmail(recipientEmail string) error {
+	return o.delivery.SendTestEmail(recipientEmail)
+}
+
+// CheckConnection checks the wrapped Service's provider.
+func (o *OutboxService) CheckConnection(ctx context.Context) error {
+	return o.delivery.CheckConnection(ctx)
+}
+
+// enqueue inserts a pending outbox row that is due immediately.
+func (o *OutboxService) enqueue(kind, recipient string, payload map[string]string) error {
+	return o.enqueueGrouped(kind, recipient, payload, "", 0)
+}
+
+// enqueueGrouped inserts a pending outbox row that becomes due after delay.
+// Rows sharing a non-empty groupKey are delivered together as one digest.
+func (o *OutboxService) enqueueGrouped(kind, recipient string, payload map[string]string, groupKey string, delay time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), enqueueTimeout)
+	defer cancel()
+
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode email payload: %w", err)
+	}
+	if _, err := o.db.Pool.Exec(ctx, `
+        INSERT INTO email_outbox (kind, recipient, payload, group_key, next_attempt_at)
+        VALUES ($1, $2, $3, NULLIF($4, ''), NOW() + make_interval(secs => $5))`,
+		kind, recipient, payloadJSON, groupKey, delay.Seconds()); err != nil {
+		o.logger.Error("Failed to enqueue email", "kind", kind, "recipient", recipient, "error", err)
+		return fmt.Errorf("failed to enqueue email: %w", err)
+	}
+	o.logger.Debug("Email enqueued", "kind", kind, "recipient", recipient, "groupKey", groupKey, "delay", delay)
+	return nil
+}
+
+// assignmentGroupKey is the coalescing key for assignment emails to one recipient.
+func assignmentGroupKey(recipient string) string {
+	return KindTicketAssignment + ":" + strings.ToLower(strings.TrimSpace(recipient))
+}
+
+// --- Delivery ---
+
+// DeliverDue claims up to BatchSize due outbox rows and sends each one.
+// Successful sends are marked sent; failures are rescheduled with exponential
+// backoff, or marked failed once MaxAttempts is reached. Claimed rows with a
+// group key are sent together with every other pending row sharing that key
+// as a single digest, and all of them share the outcome.
+//
+// Returns:
+//   - int: Number of emails sent.
+//   - int: Number of failed attempts in this run.
+//   - error: If claiming rows fails.
+func (o *OutboxService) DeliverDue(ctx context.Context) (int, int, error) {
+	messages, err := o.claimDue(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sent, failed := 0, 0
+	for _, batch := range o.groupMessages(ctx, messages) {
+		sendErr := o.deliverBatch(ctx, batch)
+		for _, msg := range batch {
+			if sendErr == nil {
+				sent++
+				o.markSent(ctx, msg)
+				continue
+			}
+			failed++
+			o.markFailedAttempt(ctx, msg, sendErr)
+		}
+	}
+	if len(messages) > 0 {
+		o.logger.Info("Email outbox delivery run complete", "claimed", len(messages), "sent", sent, "failed", failed)
+	}
+	return sent, failed, nil
+}
+
+// claimDue leases due pending rows so concurrent workers never send the same email twice.
+func (o *OutboxService) claimDue(ctx context.Context) ([]outboxMessage, error) {
+	rows, err := o.db.Pool.Query(ctx, `
+        UPDATE email_outbox SET next_attempt_at = NOW() + make_interval(secs => $2)
+        WHERE id IN (
+            SELECT id FROM email_outbox
+            WHERE status = 'pending' AND next_attempt_at <= NOW()
+            ORDER BY next_attempt_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, kind, recipient, payload, attempts, group_key`,
+		o.cfg.BatchSize, claimLease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox rows: %w", err)
+	}
+	return o.scanMessages(rows)
+}
+
+// claimGroup leases the remaining pending rows that share groupKey, including
+// rows whose coalescing window has not yet elapsed, so they join the digest.
+func (o *OutboxService) claimGroup(ctx context.Context, groupKey string, exclude []string) ([]outboxMessage, error) {
+	rows, err := o.db.Pool.Query(ctx, `
+        UPDATE email_outbox SET next_attempt_at = NOW() + make_interval(secs => $3)
+        WHERE id IN (
+            SELECT id FROM email_outbox
+            WHERE status = 'pending' AND group_key = $1 AND NOT (id = ANY($2::uuid[]))
+            ORDER BY created_at
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, kind, recipient, payload, attempts, group_key`,
+		groupKey, exclude, claimLease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox group %q: %w", groupKey, err)
+	}
+	return o.scanMessages(rows)
+}
+
+// scanMessages reads claimed outbox rows.
+func (o *OutboxService) scanMessages(rows pgx.Rows) ([]outboxMessage, error) {
+	defer rows.Close()
+
+	messages := make([]outboxMessage, 0)
+	for rows.Next() {
+		var msg outboxMessage
+		var payloadJSON []byte
+		if err := rows.Scan(&msg.id, &msg.kind, &msg.recipient, &payloadJSON, &msg.attempts, &msg.groupKey); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox row: %w", err)
+		}
+		if err := json.Unmarshal(payloadJSON, &msg.payload); err != nil {
+			o.logger.Error("Invalid outbox payload", "outboxID", msg.id, "error", err)
+			msg.payload = map[string]string{}
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// groupMessages splits claimed messages into delivery batches: ungrouped
+// messages are sent alone, and each group key becomes one batch extended with
+// its other pending rows. If extending a group fails, the claimed rows are
+// still delivered (as a smaller digest).
+func (o *OutboxService) groupMessages(ctx context.Context, messages []outboxMessage) [][]outboxMessage {
+	batches := make([][]outboxMessage, 0, len(messages))
+	groupIndex := make(map[string]int)
+	for _, msg := range messages {
+		if msg.groupKey == nil {
+			batches = append(batches, []outboxMessage{msg})
+			continue
+		}
+		if i, ok := groupIndex[*msg.groupKey]; ok {
+			batches[i] = append(batches[i], msg)
+			continue
+		}
+		groupIndex[*msg.groupKey] = len(batches)
+		batches = append(batches, []outboxMessage{msg})
+	}
+
+	for key, i := range groupIndex {
+		claimed := make([]string, len(batches[i]))
+		for j, msg := range batches[i] {
+			claimed[j] = msg.id
+		}
+		rest, err := o.claimGroup(ctx, key, claimed)
+		if err != nil {
+			o.logger.Error("Failed to collect outbox digest group", "groupKey", key, "error", err)
+			continue
+		}
+		batches[i] = append(batches[i], rest...)
+	}
+	return batches
+}
+
+// deliverBatch sends a batch: a single message normally, or several grouped
+// assignment messages as one digest. Digest entries carry the tickets'
+// current due dates, with overdue tickets listed first.
+func (o *OutboxService) deliverBatch(ctx context.Context, batch []outboxMessage) error {
+	if len(batch) == 1 {
+		return o.deliver(batch[0])
+	}
+	tickets := make([]AssignedTicket, 0, len(batch))
+	for _, msg := range batch {
+		if msg.kind != KindTicketAssignment {
+			return fmt.Errorf("email kind %q cannot be delivered as part of a digest", msg.kind)
+		}
+		tickets = append(tickets, AssignedTicket{TicketID: msg.payload["ticket_id"], Subject: msg.payload["subject"]})
+	}
+	if err := o.addDueDates(ctx, tickets); err != nil {
+		// Due dates are informational; send the digest without them.
+		o.logger.Error("Failed to load due dates for assignment digest", "recipient", batch[0].recipient, "error", err)
+	}
+	sort.SliceStable(tickets, func(i, j int) bool { return tickets[i].Overdue && !tickets[j].Overdue })
+	o.logger.Info("Delivering assignment digest", "recipient", batch[0].recipient, "ticketCount", len(tickets))
+	return o.delivery.SendTicketAssignmentDigest(batch[0].recipient, tickets)
+}
+
+// addDueDates fills in the due date and overdue flag of each digest entry.
+func (o *OutboxService) addDueDates(ctx context.Context, tickets []AssignedTicket) error {
+	ids := make([]string, len(tickets))
+	for i, t := range tickets {
+		ids[i] = t.TicketID
+	}
+	rows, err := o.db.Pool.Query(ctx, `
+        SELECT id::text, due_date, due_date < NOW() AND status <> 'Closed'
+        FROM tickets
+        WHERE id::text = ANY($1) AND due_date IS NOT NULL`, ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	type due struct {
+		date    time.Time
+		overdue bool
+	}
+	dueByID := make(map[string]due, len(tickets))
+	for rows.Next() {
+		var id string
+		var d due
+		if err := rows.Scan(&id, &d.date, &d.overdue); err != nil {
+			return err
+		}
+		dueByID[id] = d
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	for i := range tickets {
+		if d, ok := dueByID[tickets[i].TicketID]; ok {
+			date := d.date
+			tickets[i].DueDate = &date
+			tickets[i].Overdue = d.overdue
+		}
+	}
+	return nil
+}
+
+// deliver sends one message through the wrapped Service.
+func (o *OutboxService) deliver(msg outboxMessage) error {
+	p := msg.payload
+	switch msg.kind {
+	case KindTicketConfirmation:
+		var suggestions []SuggestedSolution
+		if encoded := p["suggestions"]; encoded != "" {
+			if err := json.Unmarshal([]byte(encoded), &suggestions); err != nil {
+				return fmt.Errorf("failed to decode suggested solutions: %w", err)
+			}
+		}
+		return o.delivery.SendTicketConfirmation(msg.recipient, p["submitter_name"], p["ticket_id"], p["subject"], p["locale"], p["status_link"], suggestions)
+	case KindTicketClosure:
+		return o.delivery.SendTicketClosure(msg.recipient, p["ticket_id"], p["subject"], p["resolution"], p["locale"])
+	case KindTicketInProgress:
+		return o.delivery.SendTicketInProgress(msg.recipient, p["ticket_id"], p["subject"], p["assigned_staff_name"], p["locale"])
+	case KindTicketAssignment:
+		return o.delivery.SendTicketAssignment(msg.recipient, p["ticket_id"], p["subject"])
+	case KindTicketReopened:
+		return o.delivery.SendTicketReopened(msg.recipient, p["ticket_id"], p["subject"])
+	case KindTicketWaitingOnCustomer:
+		reminderNumber, _ := strconv.Atoi(p["reminder_number"])
+		return o.delivery.SendTicketWaitingOnCustomer(msg.recipient, p["ticket_id"], p["subject"], p["locale"], reminderNumber)
+	case KindTicketReply:
+		return o.delivery.SendTicketReply(msg.recipient, p["ticket_id"], p["subject"], p["author_name"], p["comment"], p["locale"])
+	case KindTicketUrgencyChanged:
+		return o.delivery.SendTicketUrgencyChanged(msg.recipient, p["ticket_id"], p["subject"], p["old_urgency"], p["new_urgency"], p["locale"])
+	case KindTicketSpikeAlert:
+		return o.delivery.SendTicketSpikeAlert(msg.recipient, p["ticket_count"], p["window"], p["top_issue_types"], p["top_tags"])
+	case KindRegistrationConfirmation:
+		return o.delivery.SendRegistrationConfirmation(msg.recipient, p["user_name"])
+	case KindPasswordReset:
+		return o.delivery.SendPasswordReset(msg.recipient, p["user_name"], p["reset_link"])
+	case KindInvite:
+		return o.delivery.SendInvite(msg.recipient, p["role"], p["invite_link"], p["expires_at"])
+	default:
+		return fmt.Errorf("unknown email kind %q", msg.kind)
+	}
+}
+
+// markSent records a successful delivery. Password reset, invite and ticket
+// status links are credentials, so they are scrubbed from the stored payload once delivered.
+func (o *OutboxService) markSent(ctx context.Context, msg outboxMessage) {
+	_, err := o.db.Pool.Exec(ctx, `
+        UPDATE email_outbox
+        SET status = 'sent', sent_at = NOW(), attempts = attempts + 1, last_error = NULL,
+            payload = payload - 'reset_link' - 'invite_link' - 'status_link'
+        WHERE id = $1`, msg.id)
+	if err != nil {
+		o.logger.Error("Failed to mark outbox email sent", "outboxID", msg.id, "error", err)
+	}
+}
+
+// markFailedAttempt reschedules a failed delivery, or marks it failed after MaxAttempts.
+func (o *OutboxService) markFailedAttempt(ctx context.Context, msg outboxMessage, sendErr error) {
+	attempts := msg.attempts + 1
+	status := OutboxStatusPending
+	if attempts >= o.cfg.MaxAttempts {
+		status = OutboxStatusFailed
+	}
+	delay := o.backoff(attempts)
+	o.logger.Warn("Email delivery attempt failed",
+		"outboxID", msg.id, "kind", msg.kind, "attempt", attempts, "status", status, "retryIn", delay, "error", sendErr)
+
+	_, err := o.db.Pool.Exec(ctx, `
+        UPDATE email_outbox
+        SET status = $2, attempts = $3, last_error = $4, next_attempt_at = NOW() + make_interval(secs => $5)
+        WHERE id = $1`, msg.id, status, attempts, sendErr.Error(), delay.Seconds())
+	if err != nil {
+		o.logger.Error("Failed to record outbox delivery failure", "outboxID", msg.id, "error", err)
+	}
+}
+
+// backoff returns the retry delay after the given number of attempts:
+// BaseBackoff doubled per prior attempt, capped at MaxBackoff.
+func (o *OutboxService) backoff(attempts int) time.Duration {
+	delay := o.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < o.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > o.cfg.MaxBackoff {
+		delay = o.cfg.MaxBackoff
+	}
+	return delay
+}
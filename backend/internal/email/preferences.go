@@ -0,0 +1,106 @@
+// backend/internal/email/preferences.go
+// ==========================================================================
+// Email Service decorator that honours per-user notification preferences.
+// Notification emails whose recipient has opted out of the category are
+// dropped; transactional mail (confirmations, password resets, admin alerts,
+// self-tests) always goes through.
+// ==========================================================================
+
+package email
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/preferences"
+)
+
+// preferenceFilter wraps a Service, skipping notifications the recipient has
+// opted out of. Methods not overridden here pass straight through.
+type preferenceFilter struct {
+	Service
+	prefs  *preferences.Service
+	logger *slog.Logger
+}
+
+// NewPreferenceFilter wraps next so notification emails respect the
+// recipients' notification preferences.
+//
+// Parameters:
+//   - next: The Service that sends (or queues) the email.
+//   - prefs: The notification preferences service.
+//
+// Returns:
+//   - Service: The filtering Service.
+func NewPreferenceFilter(next Service, prefs *preferences.Service) Service {
+	return &preferenceFilter{
+		Service: next,
+		prefs:   prefs,
+		logger:  slog.With("service", "EmailPreferenceFilter"),
+	}
+}
+
+// allows checks the recipient's email preference for the category.
+func (f *preferenceFilter) allows(recipient, category, kind string) bool {
+	if f.prefs.AllowsEmail(context.Background(), recipient, category) {
+		return true
+	}
+	f.logger.Debug("Email suppressed by recipient preference", "kind", kind, "category", category)
+	return false
+}
+
+func (f *preferenceFilter) SendTicketClosure(recipient, ticketID, subject, resolution, locale string) error {
+	if !f.allows(recipient, preferences.CategoryStatusChange, KindTicketClosure) {
+		return nil
+	}
+	return f.Service.SendTicketClosure(recipient, ticketID, subject, resolution, locale)
+}
+
+func (f *preferenceFilter) SendTicketInProgress(recipient, ticketID, subject, assignedStaffName, locale string) error {
+	if !f.allows(recipient, preferences.CategoryStatusChange, KindTicketInProgress) {
+		return nil
+	}
+	return f.Service.SendTicketInProgress(recipient, ticketID, subject, assignedStaffName, locale)
+}
+
+func (f *preferenceFilter) SendTicketAssignment(recipientEmail, ticketID, subject string) error {
+	if !f.allows(recipientEmail, preferences.CategoryAssignment, KindTicketAssignment) {
+		return nil
+	}
+	return f.Service.SendTicketAssignment(recipientEmail, ticketID, subject)
+}
+
+func (f *preferenceFilter) SendTicketAssignmentDigest(recipientEmail string, tickets []AssignedTicket) error {
+	if !f.allows(recipientEmail, preferences.CategoryAssignment, KindTicketAssignment) {
+		return nil
+	}
+	return f.Service.SendTicketAssignmentDigest(recipientEmail, tickets)
+}
+
+func (f *preferenceFilter) SendTicketReopened(recipientEmail, ticketID, subject string) error {
+	if !f.allows(recipientEmail, preferences.CategoryStatusChange, KindTicketReopened) {
+		return nil
+	}
+	return f.Service.SendTicketReopened(recipientEmail, ticketID, subject)
+}
+
+func (f *preferenceFilter) SendTicketWaitingOnCustomer(recipient, ticketID, subject, locale string, reminderNumber int) error {
+	if !f.allows(recipient, preferences.CategoryStatusChange, KindTicketWaitingOnCustomer) {
+		return nil
+	}
+	return f.Service.SendTicketWaitingOnCustomer(recipient, ticketID, subject, locale, reminderNumber)
+}
+
+func (f *preferenceFilter) SendTicketReply(recipient, ticketID, subject, authorName, comment, locale string) error {
+	if !f.allows(recipient, preferences.CategoryComment, KindTicketReply) {
+		return nil
+	}
+	return f.Service.SendTicketReply(recipient, ticketID, subject, authorName, comment, locale)
+}
+
+func (f *preferenceFilter) SendTicketUrgencyChanged(recipientEmail, ticketID, subject, oldUrgency, newUrgency, locale string) error {
+	if !f.allows(recipientEmail, preferences.CategoryUrgency, KindTicketUrgencyChanged) {
+		return nil
+	}
+	return f.Service.SendTicketUrgencyChanged(recipientEmail, ticketID, subject, oldUrgency, newUrgency, locale)
+}
@@ -0,0 +1,73 @@
+// backend/internal/etag/etag.go
+// ==========================================================================
+// Conditional GET support. Responses are tagged with a hash of their encoded
+// body, so the tag changes whenever anything the client would see changes
+// (including derived fields such as SLA status), and a client that sends the
+// tag back in If-None-Match gets an empty 304 instead of the full body.
+// Tags are weak because the compression middleware may re-encode the body.
+// ==========================================================================
+
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Header names used for conditional requests (not defined by echo).
+const (
+	HeaderETag        = "ETag"
+	HeaderIfNoneMatch = "If-None-Match"
+)
+
+// JSON sends v as JSON with an ETag, or 304 Not Modified if the request's
+// If-None-Match already names that tag.
+//
+// Parameters:
+//   - c: The echo context.
+//   - status: The status code for a full response.
+//   - v: The response value.
+//
+// Returns:
+//   - error: An encoding or write error.
+func JSON(c echo.Context, status int, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tag := Compute(body)
+	c.Response().Header().Set(HeaderETag, tag)
+	if Matches(c.Request().Header.Get(HeaderIfNoneMatch), tag) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.JSONBlob(status, body)
+}
+
+// Compute returns the weak ETag for a response body.
+func Compute(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// Matches reports whether an If-None-Match header value names tag, using
+// the weak comparison RFC 9110 prescribes for If-None-Match.
+func Matches(ifNoneMatch, tag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+	want := strings.TrimPrefix(tag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,192 @@
+// backend/internal/file/filename.go
+// ==========================================================================
+// Attachment filename sanitization and storage path construction. Every
+// upload path (ticket creation and later uploads) goes through these helpers
+// so stored names, storage keys and the names returned to clients agree.
+// ==========================================================================
+
+package file
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// fallbackFilename replaces names that sanitize to nothing.
+const fallbackFilename = "file"
+
+// maxExtensionLength is the longest extension kept intact when a name is shortened.
+const maxExtensionLength = 16
+
+// SanitizeFilename turns a client-supplied filename into a safe display and
+// storage name:
+//   - directory components are dropped (both "/" and "\" separators);
+//   - the name is normalized to Unicode NFC;
+//   - invalid UTF-8, control characters and invisible formatting characters
+//     (e.g. right-to-left overrides) are removed;
+//   - characters reserved on common filesystems or in HTTP headers
+//     (< > : " | ? *) become "_", and whitespace runs become one space;
+//   - leading/trailing spaces and dots are trimmed;
+//   - the result is shortened to maxLength bytes, keeping the extension.
+//
+// An empty result becomes "file".
+//
+// Parameters:
+//   - name: The filename as sent by the client.
+//   - maxLength: The maximum length in bytes (<= 0 means no limit).
+//
+// Returns:
+//   - string: The sanitized filename.
+func SanitizeFilename(name string, maxLength int) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = path.Base(name)
+	name = norm.NFC.String(strings.ToValidUTF8(name, ""))
+
+	var b strings.Builder
+	lastSpace := false
+	for _, r := range name {
+		switch {
+		case unicode.IsSpace(r): // Tabs and newlines too
+			if lastSpace {
+				continue
+			}
+			r = ' '
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		case strings.ContainsRune(`<>:"|?*`, r):
+			r = '_'
+		}
+		lastSpace = r == ' '
+		b.WriteRune(r)
+	}
+	name = strings.Trim(b.String(), " .")
+	if name == "" || name == "/" {
+		return fallbackFilename
+	}
+	if maxLength > 0 && len(name) > maxLength {
+		name = truncateFilename(name, maxLength)
+	}
+	return name
+}
+
+// truncateFilename shortens name to at most maxLength bytes on a rune
+// boundary, keeping a short extension when there is room for it.
+func truncateFilename(name string, maxLength int) string {
+	ext := path.Ext(name)
+	if len(ext) > maxExtensionLength || len(ext) >= maxLength {
+		ext = ""
+	}
+	base := strings.TrimSuffix(name, ext)
+	limit := maxLength - len(ext)
+	for len(base) > limit && base != "" {
+		_, size := utf8.DecodeLastRuneInString(base)
+		base = base[:len(base)-size]
+	}
+	base = strings.TrimRight(base, " .")
+	if base == "" {
+		base = fallbackFilename
+	}
+	return base + ext
+}
+
+// NumberedFilename returns the n-th variant of a filename used to keep
+// same-name files apart: "report.pdf", 2 -> "report (2).pdf". The result
+// stays within maxLength bytes (<= 0 means no limit).
+func NumberedFilename(name string, n, maxLength int) string {
+	ext := path.Ext(name)
+	suffix := fmt.Sprintf(" (%d)", n)
+	base := strings.TrimSuffix(name, ext)
+	if maxLength > 0 && len(base)+len(suffix)+len(ext) > maxLength {
+		base = truncateFilename(base, maxLength-len(suffix)-len(ext))
+	}
+	return base + suffix + ext
+}
+
+// ContentDisposition builds a Content-Disposition header value carrying
+// filename both as an ASCII fallback (filename="...") and, when that loses
+// anything, as RFC 5987 encoded UTF-8 (filename*). Control and
+// formatting characters are dropped first, so names stored before
+// SanitizeFilename existed cannot inject header lines.
+//
+// Parameters:
+//   - dispositionType: "attachment" or "inline".
+//   - filename: The filename to offer the client.
+//
+// Returns:
+//   - string: The header value; "Résumé.pdf" gives attachment; filename="Resume.pdf" plus
+//     filename* with the percent-encoded UTF-8 name.
+func ContentDisposition(dispositionType, filename string) string {
+	var clean strings.Builder
+	for _, r := range norm.NFC.String(strings.ToValidUTF8(filename, "")) {
+		if unicode.IsSpace(r) {
+			r = ' '
+		} else if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			continue
+		}
+		clean.WriteRune(r)
+	}
+	name := strings.TrimSpace(clean.String())
+	if name == "" {
+		name = fallbackFilename
+	}
+
+	fallback := asciiFilename(name)
+	value := fmt.Sprintf("%s; filename=\"%s\"", dispositionType, fallback)
+	if fallback != name {
+		value += "; filename*=UTF-8''" + encodeRFC5987(name)
+	}
+	return value
+}
+
+// asciiFilename approximates name in printable ASCII for the quoted filename
+// parameter: accents are stripped ("é" -> "e"), other non-ASCII characters
+// and the quote and backslash (which would need escaping that not every
+// client undoes) become "_".
+func asciiFilename(name string) string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case r < 0x20 || r > 0x7e || r == '"' || r == '\\':
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// encodeRFC5987 percent-encodes s as an RFC 5987 value-chars string: every
+// byte outside attr-char is written as %XX.
+func encodeRFC5987(s string) string {
+	const attrChars = "!#$&+-.^_`|~"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < utf8.RuneSelf && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || strings.IndexByte(attrChars, c) >= 0) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+// AttachmentStoragePath builds the storage key for a ticket attachment. The
+// random prefix keeps keys unique even when files share a name.
+//
+// Parameters:
+//   - ticketID: The ticket the file belongs to.
+//   - uniqueID: A unique ID for this upload (e.g., a UUID).
+//   - filename: The sanitized filename.
+//
+// Returns:
+//   - string: The key, "tickets/<ticketID>/<uniqueID>_<filename>".
+func AttachmentStoragePath(ticketID, uniqueID, filename string) string {
+	return fmt.Sprintf("tickets/%s/%s_%s", ticketID, uniqueID, filename)
+}
@@ -0,0 +1,168 @@
+// backend/internal/i18n/i18n.go
+// ==========================================================================
+// Message catalog for user-facing text (emails, API messages). Each locale is
+// a flat JSON file under locales/ mapping message keys to format strings, so
+// adding a language is a data-only change: drop in locales/<code>.json.
+// Missing keys fall back to English, then to the key itself.
+// ==========================================================================
+
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is used when no preference is known or a key is missing.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// catalogs maps a locale code (e.g., "en", "es") to its messages.
+var catalogs map[string]map[string]string
+
+// init loads every embedded locale file.
+func init() {
+	entries, err := localeFS.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("failed to read embedded locales: %v", err))
+	}
+	catalogs = make(map[string]map[string]string, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		raw, err := localeFS.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("failed to read locale file %s: %v", entry.Name(), err))
+		}
+		messages := make(map[string]string)
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			panic(fmt.Sprintf("failed to parse locale file %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.ToLower(strings.TrimSuffix(entry.Name(), ".json"))] = messages
+	}
+	if _, ok := catalogs[DefaultLocale]; !ok {
+		panic("default locale catalog (en.json) is missing")
+	}
+	slog.Debug("Message catalogs loaded", "locales", Supported())
+}
+
+// --- Lookup ---
+
+// T returns the message for key in the given locale, formatted with args
+// (fmt.Sprintf verbs). It falls back to English and finally to the key.
+//
+// Parameters:
+//   - locale: A supported locale code (unsupported values fall back to English).
+//   - key: The message key (e.g., "email.ticket_closure.subject").
+//   - args: Optional format arguments.
+//
+// Returns:
+//   - string: The localized, formatted message.
+func T(locale, key string, args ...interface{}) string {
+	message, ok := catalogs[Match(locale)][key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+		if !ok {
+			slog.Warn("Missing message catalog key", "key", key, "locale", locale)
+			message = key
+		}
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// --- Locale Resolution ---
+
+// Supported lists the available locale codes, sorted.
+func Supported() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// IsSupported reports whether a catalog exists for the (normalized) locale,
+// either exactly or via its base language (e.g., "es-MX" -> "es").
+func IsSupported(locale string) bool {
+	_, ok := lookupCatalog(locale)
+	return ok
+}
+
+// Match maps a locale tag to a supported catalog code, or DefaultLocale.
+func Match(locale string) string {
+	if code, ok := lookupCatalog(locale); ok {
+		return code
+	}
+	return DefaultLocale
+}
+
+// Resolve returns the first supported locale from the candidates, in order of
+// preference (e.g., the ticket's locale, then the user's, then Accept-Language).
+// Each candidate may be a single tag or an Accept-Language header value.
+func Resolve(candidates ...string) string {
+	for _, candidate := range candidates {
+		if code := Normalize(candidate); code != "" {
+			return code
+		}
+	}
+	return DefaultLocale
+}
+
+// Normalize returns the supported catalog code for a locale tag (or the first
+// supported tag of an Accept-Language value), or "" if none matches. Use it to
+// validate and canonicalize stored preferences.
+func Normalize(value string) string {
+	for _, tag := range parseAcceptLanguage(value) {
+		if code, ok := lookupCatalog(tag); ok {
+			return code
+		}
+	}
+	return ""
+}
+
+// --- Helpers ---
+
+// lookupCatalog finds the catalog code for a tag, trying the full tag first
+// and then its base language.
+func lookupCatalog(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(tag, "_", "-")))
+	if tag == "" {
+		return "", false
+	}
+	if _, ok := catalogs[tag]; ok {
+		return tag, true
+	}
+	if base, _, found := strings.Cut(tag, "-"); found {
+		if _, ok := catalogs[base]; ok {
+			return base, true
+		}
+	}
+	return "", false
+}
+
+// parseAcceptLanguage splits an Accept-Language style value ("es-MX,es;q=0.9,en;q=0.8")
+// into tags, preserving the listed order (quality values are ignored; clients list
+// preferred languages first).
+func parseAcceptLanguage(value string) []string {
+	parts := strings.Split(value, ",")
+	tags := make([]string, 0, len(parts))
+	for _, part := range parts {
+		tag, _, _ := strings.Cut(part, ";")
+		if tag = strings.TrimSpace(tag); tag != "" && tag != "*" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,186 @@
+// backend/internal/jobs/assignment_escalation.go
+// ==========================================================================
+// Assignment escalation job. Finds tickets whose assignee has not accepted
+// the assignment within the configured window, notifies Admins in-app,
+// re-sends the assignment email to the assignee, and records the escalation.
+// Each assignment is escalated at most once.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/preferences"
+)
+
+// escalationBatchSize caps how many assignments a single run escalates.
+const escalationBatchSize = 200
+
+// notificationTypeAssignmentEscalation is the in-app notification type sent to Admins.
+const notificationTypeAssignmentEscalation = "assignment_escalation"
+
+// --- Types ---
+
+// AssignmentEscalationJob escalates assignments that were not accepted in time.
+type AssignmentEscalationJob struct {
+	db           *db.DB
+	emailService email.Service
+	cfg          config.AssignmentConfig
+	logger       *slog.Logger
+}
+
+// pendingAssignment is an overdue, unaccepted assignment.
+type pendingAssignment struct {
+	ticketID      string
+	ticketNumber  int32
+	subject       string
+	assigneeID    string
+	assigneeName  string
+	assigneeEmail string
+	assignedAt    time.Time
+}
+
+// --- Constructor ---
+
+// NewAssignmentEscalationJob creates an escalation job with the given dependencies.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - emailService: The email service used to remind assignees (email.Service).
+//   - cfg: The assignment workflow configuration (config.AssignmentConfig).
+//
+// Returns:
+//   - *AssignmentEscalationJob: The configured job.
+func NewAssignmentEscalationJob(database *db.DB, emailService email.Service, cfg config.AssignmentConfig) *AssignmentEscalationJob {
+	return &AssignmentEscalationJob{
+		db:           database,
+		emailService: emailService,
+		cfg:          cfg,
+		logger:       slog.With("job", "AssignmentEscalation"),
+	}
+}
+
+// --- Job Lifecycle ---
+
+// Start launches the job on its configured interval until ctx is cancelled.
+func (j *AssignmentEscalationJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "AssignmentEscalation", j.cfg.EscalationInterval, j.RunOnce)
+}
+
+// RunOnce escalates every assignment that has been pending longer than the acceptance window.
+//
+// Returns:
+//   - error: If the pending assignment query fails.
+func (j *AssignmentEscalationJob) RunOnce(ctx context.Context) error {
+	cutoff := time.Now().Add(-j.cfg.AcceptanceWindow)
+	pending, err := j.findPending(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		j.logger.Debug("No unaccepted assignments to escalate", "cutoff", cutoff)
+		return nil
+	}
+
+	escalated := 0
+	for _, assignment := range pending {
+		claimed, err := j.escalate(ctx, assignment)
+		if err != nil {
+			j.logger.Error("Failed to escalate assignment", "ticketID", assignment.ticketID, "error", err)
+			continue
+		}
+		if !claimed {
+			continue // Accepted, reassigned or escalated concurrently
+		}
+		escalated++
+
+		// Remind the assignee after the escalation is committed.
+		if err := j.emailService.SendTicketAssignment(assignment.assigneeEmail, assignment.ticketID, assignment.subject); err != nil {
+			j.logger.Error("Failed to send assignment reminder email", "ticketID", assignment.ticketID, "recipient", assignment.assigneeEmail, "error", err)
+		}
+	}
+
+	j.logger.Info("Assignment escalation run complete", "pending", len(pending), "escalated", escalated)
+	return nil
+}
+
+// --- Helpers ---
+
+// findPending lists open assignments made before cutoff that are neither accepted nor escalated.
+func (j *AssignmentEscalationJob) findPending(ctx context.Context, cutoff time.Time) ([]pendingAssignment, error) {
+	rows, err := j.db.Pool.Query(ctx, `
+        SELECT t.id, t.ticket_number, t.subject, u.id, u.name, u.email, t.assigned_at
+        FROM tickets t
+        JOIN users u ON u.id = t.assigned_to_user_id
+        WHERE t.status <> $1
+          AND t.assigned_at IS NOT NULL AND t.assigned_at < $2
+          AND t.accepted_at IS NULL
+          AND t.acceptance_escalated_at IS NULL
+        ORDER BY t.assigned_at ASC
+        LIMIT $3`, models.StatusClosed, cutoff, escalationBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pending assignments: %w", err)
+	}
+	defer rows.Close()
+
+	pending := make([]pendingAssignment, 0)
+	for rows.Next() {
+		var p pendingAssignment
+		if err := rows.Scan(&p.ticketID, &p.ticketNumber, &p.subject, &p.assigneeID, &p.assigneeName, &p.assigneeEmail, &p.assignedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan pending assignment: %w", err)
+		}
+		pending = append(pending, p)
+	}
+	return pending, rows.Err()
+}
+
+// escalate marks one assignment as escalated, records it in the history and
+// notifies all Admins, in a single transaction. It reports false if the
+// assignment no longer qualifies (e.g., it was accepted in the meantime).
+func (j *AssignmentEscalationJob) escalate(ctx context.Context, p pendingAssignment) (bool, error) {
+	tx, err := j.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	tag, err := tx.Exec(ctx, `
+        UPDATE tickets SET acceptance_escalated_at = NOW()
+        WHERE id = $1 AND assigned_to_user_id = $2
+          AND accepted_at IS NULL AND acceptance_escalated_at IS NULL`, p.ticketID, p.assigneeID)
+	if err != nil {
+		return false, fmt.Errorf("failed to mark assignment escalated: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO ticket_assignment_history (ticket_id, event, assignee_user_id, actor_user_id)
+        VALUES ($1, $2, $3, NULL)`, p.ticketID, models.AssignmentEventEscalated, p.assigneeID); err != nil {
+		return false, fmt.Errorf("failed to record escalation history: %w", err)
+	}
+
+	message := fmt.Sprintf("Ticket #%d has not been accepted by %s (assigned %s ago).",
+		p.ticketNumber, p.assigneeName, time.Since(p.assignedAt).Round(time.Minute))
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO notifications (user_id, type, message, related_ticket_id)
+        SELECT id, $1, $2, $3 FROM users WHERE role = $4 AND NOT `+fmt.Sprintf(preferences.InAppOptedOutSQL, "users.id", "$5"),
+		notificationTypeAssignmentEscalation, message, p.ticketID, models.RoleAdmin, preferences.CategoryAssignment); err != nil {
+		return false, fmt.Errorf("failed to notify admins: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit escalation: %w", err)
+	}
+	j.logger.Info("Escalated unaccepted assignment", "ticketNumber", p.ticketNumber, "assigneeID", p.assigneeID)
+	return true, nil
+}
@@ -0,0 +1,40 @@
+// backend/internal/jobs/email_outbox.go
+// ==========================================================================
+// Email outbox worker. Periodically delivers queued emails from the
+// email_outbox table through email.OutboxService.DeliverDue.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+)
+
+// EmailOutboxJob drains the email outbox on a fixed interval.
+type EmailOutboxJob struct {
+	outbox   *email.OutboxService
+	interval time.Duration
+}
+
+// NewEmailOutboxJob creates the outbox delivery job.
+//
+// Parameters:
+//   - outbox: The outbox to deliver from (*email.OutboxService).
+//   - interval: How often to poll for due emails.
+//
+// Returns:
+//   - *EmailOutboxJob: The configured job.
+func NewEmailOutboxJob(outbox *email.OutboxService, interval time.Duration) *EmailOutboxJob {
+	return &EmailOutboxJob{outbox: outbox, interval: interval}
+}
+
+// Start launches the job on its configured interval until ctx is cancelled.
+func (j *EmailOutboxJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "EmailOutbox", j.interval, func(ctx context.Context) error {
+		_, _, err := j.outbox.DeliverDue(ctx)
+		return err
+	})
+}
@@ -0,0 +1,41 @@
+// backend/internal/jobs/jobs.go
+// ==========================================================================
+// Shared plumbing for periodic background jobs (retention, reminders, etc.).
+// Each job runs on its own goroutine until the supplied context is cancelled.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// runPeriodically invokes run immediately and then once per interval until ctx is done.
+// Errors are logged; a failing run never stops subsequent runs.
+//
+// Parameters:
+//   - ctx: Controls the lifetime of the loop.
+//   - name: Job name used in log output.
+//   - interval: Time between runs.
+//   - run: The work to perform on each tick.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, run func(ctx context.Context) error) {
+	logger := slog.With("job", name)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		logger.Info("Background job started", "interval", interval)
+		for {
+			if err := run(ctx); err != nil {
+				logger.Error("Background job run failed", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				logger.Info("Background job stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,71 @@
+// backend/internal/jobs/password_reset_cleanup.go
+// ==========================================================================
+// Password reset housekeeping: deletes reset tokens once they have expired
+// and forgot-password request records once they fall outside the rate limit
+// window.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+)
+
+// passwordResetCleanupInterval is how often the job runs.
+const passwordResetCleanupInterval = time.Hour
+
+// PasswordResetCleanupJob prunes password reset tokens and request records.
+type PasswordResetCleanupJob struct {
+	db     *db.DB
+	cfg    config.AuthConfig
+	logger *slog.Logger
+}
+
+// NewPasswordResetCleanupJob creates the password reset cleanup job.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - cfg: Auth settings (the rate limit window decides how long requests are kept).
+//
+// Returns:
+//   - *PasswordResetCleanupJob: The configured job.
+func NewPasswordResetCleanupJob(database *db.DB, cfg config.AuthConfig) *PasswordResetCleanupJob {
+	return &PasswordResetCleanupJob{db: database, cfg: cfg, logger: slog.With("job", "PasswordResetCleanup")}
+}
+
+// Start launches the job on its fixed interval until ctx is cancelled.
+func (j *PasswordResetCleanupJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "PasswordResetCleanup", passwordResetCleanupInterval, j.RunOnce)
+}
+
+// RunOnce deletes expired tokens and request records older than the rate
+// limit window (and at least a day old, so they remain useful for review).
+//
+// Returns:
+//   - error: If a delete fails.
+func (j *PasswordResetCleanupJob) RunOnce(ctx context.Context) error {
+	tokens, err := j.db.Pool.Exec(ctx, `DELETE FROM password_reset_tokens WHERE expires_at < NOW()`)
+	if err != nil {
+		return fmt.Errorf("failed to delete expired reset tokens: %w", err)
+	}
+
+	keep := j.cfg.PasswordResetRateWindow
+	if keep < 24*time.Hour {
+		keep = 24 * time.Hour
+	}
+	requests, err := j.db.Pool.Exec(ctx, `DELETE FROM password_reset_requests WHERE requested_at < $1`, time.Now().Add(-keep))
+	if err != nil {
+		return fmt.Errorf("failed to delete old reset requests: %w", err)
+	}
+
+	if tokens.RowsAffected() > 0 || requests.RowsAffected() > 0 {
+		j.logger.InfoContext(ctx, "Password reset cleanup complete", "tokensDeleted", tokens.RowsAffected(), "requestsDeleted", requests.RowsAffected())
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+// backend/internal/jobs/retention.go
+// ==========================================================================
+// Attachment retention job. Removes stored attachment files for tickets that
+// have been closed longer than the configured retention period, then records
+// a system comment on each affected ticket. Runs in dry-run mode by default.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/file"
+)
+
+// retentionBatchSize caps how many attachments a single run examines.
+const retentionBatchSize = 500
+
+// --- Types ---
+
+// RetentionJob purges attachments of long-closed tickets according to config.RetentionConfig.
+type RetentionJob struct {
+	db          *db.DB
+	fileService file.Service
+	cfg         config.RetentionConfig
+	logger      *slog.Logger
+}
+
+// RetentionResult summarizes a single retention run.
+type RetentionResult struct {
+	Candidates int  // Attachments eligible for purging
+	Purged     int  // Attachments actually purged (always 0 in dry-run mode)
+	Failed     int  // Attachments whose purge failed and was rolled back
+	DryRun     bool // Whether the run was a dry run
+}
+
+// retentionCandidate is an attachment eligible for purging.
+type retentionCandidate struct {
+	attachmentID string
+	ticketID     string
+	ticketNumber int32
+	filename     string
+	storagePath  string
+	previewPath  *string // Rendered PDF preview, if any
+}
+
+// --- Constructor ---
+
+// NewRetentionJob creates a retention job with the given dependencies.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - fileService: The file storage service used to delete objects (file.Service).
+//   - cfg: The retention policy configuration (config.RetentionConfig).
+//
+// Returns:
+//   - *RetentionJob: The configured job.
+func NewRetentionJob(database *db.DB, fileService file.Service, cfg config.RetentionConfig) *RetentionJob {
+	return &RetentionJob{
+		db:          database,
+		fileService: fileService,
+		cfg:         cfg,
+		logger:      slog.With("job", "AttachmentRetention", "dryRun", cfg.DryRun),
+	}
+}
+
+// --- Job Lifecycle ---
+
+// Start launches the job on its configured interval until ctx is cancelled.
+func (j *RetentionJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "AttachmentRetention", j.cfg.Interval, func(ctx context.Context) error {
+		_, err := j.RunOnce(ctx)
+		return err
+	})
+}
+
+// RunOnce performs a single retention pass.
+//
+// Consistency: each attachment is purged in its own transaction. The row is
+// locked and marked (or deleted) first, then the stored object is removed; if the
+// storage delete fails the transaction is rolled back so the row keeps pointing
+// at the still-existing file and the next run retries it.
+//
+// Returns:
+//   - RetentionResult: Counts for the run.
+//   - error: If the candidate query fails.
+func (j *RetentionJob) RunOnce(ctx context.Context) (RetentionResult, error) {
+	result := RetentionResult{DryRun: j.cfg.DryRun}
+	cutoff := time.Now().Add(-j.cfg.Period)
+
+	candidates, err := j.findCandidates(ctx, cutoff)
+	if err != nil {
+		return result, err
+	}
+	result.Candidates = len(candidates)
+	if len(candidates) == 0 {
+		j.logger.Debug("No attachments eligible for retention purge", "cutoff", cutoff)
+		return result, nil
+	}
+
+	if j.cfg.DryRun {
+		for _, cand := range candidates {
+			j.logger.Info("Dry run: attachment would be purged",
+				"ticketNumber", cand.ticketNumber, "attachmentID", cand.attachmentID, "filename", cand.filename)
+		}
+		j.logger.Info("Retention dry run complete", "candidates", result.Candidates, "cutoff", cutoff)
+		return result, nil
+	}
+
+	// Candidates are ordered by ticket, so purged filenames can be grouped per ticket.
+	var purgedNames []string
+	for i, cand := range candidates {
+		if err := j.purgeAttachment(ctx, cand); err != nil {
+			result.Failed++
+			j.logger.Error("Failed to purge attachment", "attachmentID", cand.attachmentID, "ticketID", cand.ticketID, "error", err)
+		} else {
+			result.Purged++
+			purgedNames = append(purgedNames, cand.filename)
+		}
+
+		lastForTicket := i == len(candidates)-1 || candidates[i+1].ticketID != cand.ticketID
+		if lastForTicket {
+			if len(purgedNames) > 0 {
+				if err := j.addRemovalComment(ctx, cand.ticketID, purgedNames); err != nil {
+					j.logger.Error("Failed to add retention system comment", "ticketID", cand.ticketID, "error", err)
+				}
+			}
+			purgedNames = nil
+		}
+	}
+
+	j.logger.Info("Retention run complete", "candidates", result.Candidates, "purged", result.Purged, "failed", result.Failed)
+	return result, nil
+}
+
+// --- Helpers ---
+
+// findCandidates lists unpurged attachments on tickets closed before cutoff,
+// skipping tickets that carry an exempt tag or issue type.
+func (j *RetentionJob) findCandidates(ctx context.Context, cutoff time.Time) ([]retentionCandidate, error) {
+	rows, err := j.db.Pool.Query(ctx, `
+        SELECT a.id, a.ticket_id, t.ticket_number, a.filename, a.storage_path, a.preview_storage_path
+        FROM attachments a
+        JOIN tickets t ON t.id = a.ticket_id
+        WHERE t.status = 'Closed'
+          AND t.closed_at IS NOT NULL AND t.closed_at < $1
+          AND a.purged_at IS NULL
+          AND NOT (LOWER(COALESCE(t.issue_type, '')) = ANY($2))
+          AND NOT EXISTS (
+              SELECT 1 FROM ticket_tags tt JOIN tags tg ON tg.id = tt.tag_id
+              WHERE tt.ticket_id = t.id AND LOWER(tg.name) = ANY($3)
+          )
+        ORDER BY a.ticket_id, a.uploaded_at
+        LIMIT $4`,
+		cutoff, lowerAll(j.cfg.ExemptIssueTypes), lowerAll(j.cfg.ExemptTags), retentionBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query retention candidates: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make([]retentionCandidate, 0)
+	for rows.Next() {
+		var cand retentionCandidate
+		if err := rows.Scan(&cand.attachmentID, &cand.ticketID, &cand.ticketNumber, &cand.filename, &cand.storagePath, &cand.previewPath); err != nil {
+			return nil, fmt.Errorf("failed to scan retention candidate: %w", err)
+		}
+		candidates = append(candidates, cand)
+	}
+	return candidates, rows.Err()
+}
+
+// purgeAttachment marks or deletes one attachment's row, then removes its stored
+// file once that change is committed. A file that cannot be deleted afterwards
+// is logged for manual cleanup; the attachment stays purged.
+func (j *RetentionJob) purgeAttachment(ctx context.Context, cand retentionCandidate) error {
+	tx, err := j.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	var statement string
+	if j.cfg.DeleteRows {
+		statement = `DELETE FROM attachments WHERE id = $1 AND purged_at IS NULL`
+	} else {
+		statement = `UPDATE attachments SET purged_at = NOW() WHERE id = $1 AND purged_at IS NULL`
+	}
+	tag, err := tx.Exec(ctx, statement, cand.attachmentID)
+	if err != nil {
+		return fmt.Errorf("failed to update attachment row: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return nil // Already purged or deleted concurrently
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit purge: %w", err)
+	}
+	if err := j.fileService.DeleteFile(ctx, cand.storagePath); err != nil {
+		j.logger.Error("Failed to delete purged attachment file; remove it manually", "attachmentID", cand.attachmentID, "storagePath", cand.storagePath, "error", err)
+	}
+	if cand.previewPath != nil {
+		if err := j.fileService.DeleteFile(ctx, *cand.previewPath); err != nil {
+			j.logger.Warn("Failed to delete attachment preview", "attachmentID", cand.attachmentID, "storagePath", *cand.previewPath, "error", err)
+		}
+	}
+	return nil
+}
+
+// addRemovalComment records a ticket history entry listing the purged files.
+func (j *RetentionJob) addRemovalComment(ctx context.Context, ticketID string, filenames []string) error {
+	comment := fmt.Sprintf("Attachment retention policy removed %d file(s) from this closed ticket: %s",
+		len(filenames), strings.Join(filenames, ", "))
+	_, err := j.db.Pool.Exec(ctx, `
+        INSERT INTO ticket_history (ticket_id, summary) VALUES ($1, $2)`, ticketID, comment)
+	return err
+}
+
+// lowerAll returns a lower-cased copy of values (never nil, so it binds as an empty array).
+func lowerAll(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, value := range values {
+		lowered = append(lowered, strings.ToLower(value))
+	}
+	return lowered
+}
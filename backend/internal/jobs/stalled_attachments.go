@@ -0,0 +1,89 @@
+// backend/internal/jobs/stalled_attachments.go
+// ==========================================================================
+// Stalled attachment detection. Ticket creation commits the ticket first and
+// stores attachments afterwards (two-phase create); if the server stops
+// between the phases the ticket stays marked "pending attachments". This job
+// reports such tickets, clears the marker and notes it on the ticket.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+)
+
+const (
+	// stalledAttachmentsAfter is how long a ticket may stay pending before it is considered stalled.
+	stalledAttachmentsAfter = 15 * time.Minute
+	// stalledAttachmentsInterval is how often the job checks for stalled tickets.
+	stalledAttachmentsInterval = 5 * time.Minute
+)
+
+// StalledAttachmentsJob resolves tickets stuck in the pending-attachments state.
+type StalledAttachmentsJob struct {
+	db     *db.DB
+	logger *slog.Logger
+}
+
+// NewStalledAttachmentsJob creates the stalled attachment detection job.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//
+// Returns:
+//   - *StalledAttachmentsJob: The configured job.
+func NewStalledAttachmentsJob(database *db.DB) *StalledAttachmentsJob {
+	return &StalledAttachmentsJob{db: database, logger: slog.With("job", "StalledAttachments")}
+}
+
+// Start launches the job on its fixed interval until ctx is cancelled.
+func (j *StalledAttachmentsJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "StalledAttachments", stalledAttachmentsInterval, j.RunOnce)
+}
+
+// RunOnce clears the pending marker on every stalled ticket and records a
+// history entry so staff know the submitted files were not saved.
+//
+// Returns:
+//   - error: If the update fails.
+func (j *StalledAttachmentsJob) RunOnce(ctx context.Context) error {
+	rows, err := j.db.Pool.Query(ctx, `
+        WITH stalled AS (
+            UPDATE tickets SET attachments_pending_since = NULL
+            WHERE attachments_pending_since < NOW() - make_interval(secs => $1)
+            RETURNING id, ticket_number
+        ), noted AS (
+            INSERT INTO ticket_history (ticket_id, summary)
+            SELECT id, $2 FROM stalled
+        )
+        SELECT id, ticket_number FROM stalled`,
+		stalledAttachmentsAfter.Seconds(),
+		"Attachment upload for this ticket did not complete; some submitted files may be missing.")
+	if err != nil {
+		return fmt.Errorf("failed to resolve stalled attachment uploads: %w", err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var ticketID string
+		var ticketNumber int32
+		if err := rows.Scan(&ticketID, &ticketNumber); err != nil {
+			return fmt.Errorf("failed to scan stalled ticket: %w", err)
+		}
+		count++
+		j.logger.WarnContext(ctx, "Ticket attachment upload stalled; marker cleared", "ticketID", ticketID, "ticketNumber", ticketNumber)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if count > 0 {
+		j.logger.InfoContext(ctx, "Stalled attachment check complete", "resolved", count)
+	}
+	return nil
+}
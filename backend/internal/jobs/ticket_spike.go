@@ -0,0 +1,248 @@
+// backend/internal/jobs/ticket_spike.go
+// ==========================================================================
+// Ticket spike detection job. Counts tickets created within a rolling window
+// and, when the count reaches the configured threshold, alerts Admins (in-app
+// notification and optional email) and emits a webhook event listing the most
+// common issue types and tags in the window. A cooldown, tracked in the shared
+// cache (and in memory when caching is disabled), prevents repeat alerts for
+// the same incident.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/cache"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
+)
+
+// notificationTypeTicketSpike is the in-app notification type sent to Admins.
+const notificationTypeTicketSpike = "ticket_spike"
+
+// spikeTopN is how many issue types and tags are reported with an alert.
+const spikeTopN = 5
+
+// spikeCooldownCacheKey marks an active alert cooldown in the shared cache.
+const spikeCooldownCacheKey = "alerts:ticket_spike:cooldown"
+
+// --- Types ---
+
+// TicketSpikeJob detects and reports surges in ticket creation.
+type TicketSpikeJob struct {
+	db           *db.DB
+	emailService email.Service
+	webhooks     *webhook.Service
+	cache        cache.Cache
+	cfg          config.SpikeAlertConfig
+	logger       *slog.Logger
+
+	mu          sync.Mutex
+	lastAlertAt time.Time // In-memory cooldown fallback for when the cache is a no-op
+}
+
+// SpikeCount is one entry in a spike's breakdown (an issue type or tag and its ticket count).
+type SpikeCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// TicketSpike is the webhook payload for webhook.EventTicketSpike.
+type TicketSpike struct {
+	TicketCount   int          `json:"ticket_count"`
+	Threshold     int          `json:"threshold"`
+	WindowSeconds int          `json:"window_seconds"`
+	TopIssueTypes []SpikeCount `json:"top_issue_types"`
+	TopTags       []SpikeCount `json:"top_tags"`
+}
+
+// --- Constructor ---
+
+// NewTicketSpikeJob creates a spike detection job with the given dependencies.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - emailService: The email service used to alert Admins (email.Service).
+//   - webhookService: The outbound webhook dispatcher (*webhook.Service).
+//   - cacheService: The shared cache used to track the alert cooldown (cache.Cache).
+//   - cfg: The spike alert configuration (config.SpikeAlertConfig).
+//
+// Returns:
+//   - *TicketSpikeJob: The configured job.
+func NewTicketSpikeJob(database *db.DB, emailService email.Service, webhookService *webhook.Service, cacheService cache.Cache, cfg config.SpikeAlertConfig) *TicketSpikeJob {
+	return &TicketSpikeJob{
+		db:           database,
+		emailService: emailService,
+		webhooks:     webhookService,
+		cache:        cacheService,
+		cfg:          cfg,
+		logger:       slog.With("job", "TicketSpike"),
+	}
+}
+
+// --- Job Lifecycle ---
+
+// Start launches the job on its configured interval until ctx is cancelled.
+func (j *TicketSpikeJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "TicketSpike", j.cfg.CheckInterval, j.RunOnce)
+}
+
+// RunOnce evaluates the rolling ticket count and alerts if it crossed the threshold.
+//
+// Returns:
+//   - error: If a query or the admin notification fails.
+func (j *TicketSpikeJob) RunOnce(ctx context.Context) error {
+	since := time.Now().Add(-j.cfg.Window)
+
+	var count int
+	if err := j.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM tickets WHERE created_at >= $1 AND NOT is_internal`, since).Scan(&count); err != nil {
+		return fmt.Errorf("failed to count recent tickets: %w", err)
+	}
+	if count < j.cfg.Threshold {
+		j.logger.Debug("Ticket creation rate normal", "count", count, "threshold", j.cfg.Threshold, "window", j.cfg.Window)
+		return nil
+	}
+	if j.inCooldown(ctx) {
+		j.logger.Debug("Ticket spike ongoing; alert cooldown active", "count", count)
+		return nil
+	}
+
+	spike := TicketSpike{
+		TicketCount:   count,
+		Threshold:     j.cfg.Threshold,
+		WindowSeconds: int(j.cfg.Window.Seconds()),
+	}
+	var err error
+	if spike.TopIssueTypes, err = j.topCounts(ctx, `
+        SELECT COALESCE(NULLIF(issue_type, ''), 'Unspecified'), COUNT(*)
+        FROM tickets WHERE created_at >= $1 AND NOT is_internal
+        GROUP BY 1 ORDER BY 2 DESC, 1 LIMIT $2`, since); err != nil {
+		return err
+	}
+	if spike.TopTags, err = j.topCounts(ctx, `
+        SELECT tg.name, COUNT(*)
+        FROM tickets t
+        JOIN ticket_tags tt ON tt.ticket_id = t.id
+        JOIN tags tg ON tg.id = tt.tag_id
+        WHERE t.created_at >= $1 AND NOT t.is_internal
+        GROUP BY tg.name ORDER BY 2 DESC, 1 LIMIT $2`, since); err != nil {
+		return err
+	}
+
+	j.startCooldown(ctx)
+	j.logger.Warn("Ticket creation spike detected", "count", count, "threshold", j.cfg.Threshold, "window", j.cfg.Window,
+		"topIssueTypes", formatSpikeCounts(spike.TopIssueTypes), "topTags", formatSpikeCounts(spike.TopTags))
+	return j.alert(ctx, spike)
+}
+
+// --- Helpers ---
+
+// topCounts runs a (name, count) breakdown query over the window.
+func (j *TicketSpikeJob) topCounts(ctx context.Context, query string, since time.Time) ([]SpikeCount, error) {
+	rows, err := j.db.Pool.Query(ctx, query, since, spikeTopN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query spike breakdown: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make([]SpikeCount, 0, spikeTopN)
+	for rows.Next() {
+		var sc SpikeCount
+		if err := rows.Scan(&sc.Name, &sc.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan spike breakdown: %w", err)
+		}
+		counts = append(counts, sc)
+	}
+	return counts, rows.Err()
+}
+
+// alert notifies Admins in-app, by email (if enabled) and via webhook.
+func (j *TicketSpikeJob) alert(ctx context.Context, spike TicketSpike) error {
+	window := j.cfg.Window.String()
+	issueTypes := formatSpikeCounts(spike.TopIssueTypes)
+	tags := formatSpikeCounts(spike.TopTags)
+
+	message := fmt.Sprintf("Ticket spike: %d tickets created in the last %s.", spike.TicketCount, window)
+	if issueTypes != "" {
+		message += " Top issue types: " + issueTypes + "."
+	}
+	rows, err := j.db.Pool.Query(ctx, `
+        WITH admins AS (
+            SELECT id, email FROM users WHERE role = $3
+        ), notified AS (
+            INSERT INTO notifications (user_id, type, message)
+            SELECT id, $1, $2 FROM admins
+        )
+        SELECT email FROM admins`,
+		notificationTypeTicketSpike, message, models.RoleAdmin)
+	if err != nil {
+		return fmt.Errorf("failed to notify admins: %w", err)
+	}
+	adminEmails := make([]string, 0)
+	for rows.Next() {
+		var adminEmail string
+		if err := rows.Scan(&adminEmail); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan admin email: %w", err)
+		}
+		adminEmails = append(adminEmails, adminEmail)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to notify admins: %w", err)
+	}
+
+	if j.cfg.EmailAdmins {
+		for _, adminEmail := range adminEmails {
+			if err := j.emailService.SendTicketSpikeAlert(adminEmail, strconv.Itoa(spike.TicketCount), window, issueTypes, tags); err != nil {
+				j.logger.Error("Failed to send ticket spike alert email", "recipient", adminEmail, "error", err)
+			}
+		}
+	}
+	j.webhooks.Dispatch(webhook.EventTicketSpike, spike)
+	return nil
+}
+
+// inCooldown reports whether an alert was sent within the cooldown period.
+func (j *TicketSpikeJob) inCooldown(ctx context.Context) bool {
+	var marker bool
+	if found, err := j.cache.Get(ctx, spikeCooldownCacheKey, &marker); err == nil && found {
+		return true
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return !j.lastAlertAt.IsZero() && time.Since(j.lastAlertAt) < j.cfg.Cooldown
+}
+
+// startCooldown records that an alert is being sent now.
+func (j *TicketSpikeJob) startCooldown(ctx context.Context) {
+	j.mu.Lock()
+	j.lastAlertAt = time.Now()
+	j.mu.Unlock()
+	if j.cfg.Cooldown <= 0 {
+		return
+	}
+	if err := j.cache.Set(ctx, spikeCooldownCacheKey, true, j.cfg.Cooldown); err != nil {
+		j.logger.Warn("Failed to store spike alert cooldown in cache", "error", err)
+	}
+}
+
+// formatSpikeCounts renders a breakdown as "Network (12), Hardware (4)".
+func formatSpikeCounts(counts []SpikeCount) string {
+	parts := make([]string, len(counts))
+	for i, sc := range counts {
+		parts[i] = fmt.Sprintf("%s (%d)", sc.Name, sc.Count)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,225 @@
+// backend/internal/jobs/waiting_on_customer.go
+// ==========================================================================
+// Waiting on Customer reminder job. Finds tickets that have been waiting on
+// the submitter for a full reminder interval since they entered the status
+// (or since the last reminder) and emails the submitter again, noting each
+// reminder in the ticket history. Once the configured number of reminders
+// has gone unanswered, the ticket is closed automatically with a system
+// comment and the usual closure email. A submitter reply resets the count.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/email"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/henrythedeveloper/it-ticket-system/internal/sla"
+)
+
+// waitingBatchSize caps how many tickets a single run processes.
+const waitingBatchSize = 200
+
+// waitingAutoCloseNote is stored as the resolution when a ticket is closed
+// for lack of a reply (existing resolution notes are kept).
+const waitingAutoCloseNote = "Closed automatically: no reply was received to our request for more information."
+
+// --- Types ---
+
+// WaitingOnCustomerJob reminds submitters of tickets waiting on them and
+// auto-closes tickets whose reminders went unanswered.
+type WaitingOnCustomerJob struct {
+	db           *db.DB
+	emailService email.Service
+	cfg          config.WaitingOnCustomerConfig
+	logger       *slog.Logger
+}
+
+// waitingTicket is a ticket due for a reminder or for auto-closing.
+type waitingTicket struct {
+	ticketID      string
+	ticketNumber  int32
+	subject       string
+	endUserEmail  string
+	locale        string
+	remindersSent int
+}
+
+// --- Constructor ---
+
+// NewWaitingOnCustomerJob creates a reminder job with the given dependencies.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - emailService: The email service used to remind submitters (email.Service).
+//   - cfg: The Waiting on Customer configuration (config.WaitingOnCustomerConfig).
+//
+// Returns:
+//   - *WaitingOnCustomerJob: The configured job.
+func NewWaitingOnCustomerJob(database *db.DB, emailService email.Service, cfg config.WaitingOnCustomerConfig) *WaitingOnCustomerJob {
+	return &WaitingOnCustomerJob{
+		db:           database,
+		emailService: emailService,
+		cfg:          cfg,
+		logger:       slog.With("job", "WaitingOnCustomer"),
+	}
+}
+
+// --- Job Lifecycle ---
+
+// Start launches the job on its configured interval until ctx is cancelled.
+func (j *WaitingOnCustomerJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "WaitingOnCustomer", j.cfg.CheckInterval, j.RunOnce)
+}
+
+// RunOnce sends due reminders and closes tickets whose reminders went unanswered.
+//
+// Returns:
+//   - error: If the due ticket query fails.
+func (j *WaitingOnCustomerJob) RunOnce(ctx context.Context) error {
+	cutoff := time.Now().Add(-j.cfg.ReminderInterval)
+	due, err := j.findDue(ctx, cutoff)
+	if err != nil {
+		return err
+	}
+	if len(due) == 0 {
+		j.logger.Debug("No waiting tickets due for a reminder", "cutoff", cutoff)
+		return nil
+	}
+
+	reminded, closed := 0, 0
+	for _, t := range due {
+		if t.remindersSent >= j.cfg.MaxReminders {
+			ok, err := j.autoClose(ctx, t)
+			if err != nil {
+				j.logger.Error("Failed to auto-close waiting ticket", "ticketID", t.ticketID, "error", err)
+				continue
+			}
+			if !ok {
+				continue // Answered or changed by staff concurrently
+			}
+			closed++
+			if err := j.emailService.SendTicketClosure(t.endUserEmail, strconv.Itoa(int(t.ticketNumber)), t.subject, waitingAutoCloseNote, t.locale); err != nil {
+				j.logger.Error("Failed to send auto-close email", "ticketID", t.ticketID, "recipient", t.endUserEmail, "error", err)
+			}
+			continue
+		}
+
+		ok, err := j.claimReminder(ctx, t)
+		if err != nil {
+			j.logger.Error("Failed to record waiting reminder", "ticketID", t.ticketID, "error", err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		reminded++
+		if err := j.emailService.SendTicketWaitingOnCustomer(t.endUserEmail, strconv.Itoa(int(t.ticketNumber)), t.subject, t.locale, t.remindersSent+1); err != nil {
+			j.logger.Error("Failed to send waiting reminder email", "ticketID", t.ticketID, "recipient", t.endUserEmail, "error", err)
+		}
+	}
+
+	j.logger.Info("Waiting on Customer run complete", "due", len(due), "reminded", reminded, "closed", closed)
+	return nil
+}
+
+// --- Helpers ---
+
+// findDue lists external tickets waiting on the submitter with no reminder
+// (or status change) since cutoff.
+func (j *WaitingOnCustomerJob) findDue(ctx context.Context, cutoff time.Time) ([]waitingTicket, error) {
+	rows, err := j.db.Pool.Query(ctx, `
+        SELECT t.id, t.ticket_number, t.subject, t.end_user_email, COALESCE(t.locale, s.locale, ''), t.waiting_reminders_sent
+        FROM tickets t
+        LEFT JOIN users s ON s.email = t.end_user_email
+        WHERE t.status = $1 AND NOT t.is_internal
+          AND COALESCE(t.waiting_last_reminder_at, t.waiting_since) < $2
+        ORDER BY COALESCE(t.waiting_last_reminder_at, t.waiting_since) ASC
+        LIMIT $3`, models.StatusWaitingOnCustomer, cutoff, waitingBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query waiting tickets: %w", err)
+	}
+	defer rows.Close()
+
+	due := make([]waitingTicket, 0)
+	for rows.Next() {
+		var t waitingTicket
+		if err := rows.Scan(&t.ticketID, &t.ticketNumber, &t.subject, &t.endUserEmail, &t.locale, &t.remindersSent); err != nil {
+			return nil, fmt.Errorf("failed to scan waiting ticket: %w", err)
+		}
+		due = append(due, t)
+	}
+	return due, rows.Err()
+}
+
+// claimReminder counts one more reminder for the ticket and records it in the
+// ticket history. It reports false if the ticket left Waiting on Customer or
+// another run already reminded it.
+func (j *WaitingOnCustomerJob) claimReminder(ctx context.Context, t waitingTicket) (bool, error) {
+	summary := fmt.Sprintf("Reminder %d of %d sent to the submitter.", t.remindersSent+1, j.cfg.MaxReminders)
+	if t.remindersSent+1 == j.cfg.MaxReminders {
+		summary += " The ticket closes automatically if there is no reply."
+	}
+	tag, err := j.db.Pool.Exec(ctx, `
+        WITH reminded AS (
+            UPDATE tickets SET waiting_reminders_sent = waiting_reminders_sent + 1, waiting_last_reminder_at = NOW()
+            WHERE id = $1 AND status = $2 AND waiting_reminders_sent = $3
+            RETURNING id
+        )
+        INSERT INTO ticket_history (ticket_id, summary)
+        SELECT id, $4 FROM reminded`,
+		t.ticketID, models.StatusWaitingOnCustomer, t.remindersSent, summary)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// autoClose closes a ticket whose reminders went unanswered and records a
+// system comment, in a single transaction. It reports false if the ticket no
+// longer qualifies.
+func (j *WaitingOnCustomerJob) autoClose(ctx context.Context, t waitingTicket) (bool, error) {
+	tx, err := j.db.Pool.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op after a successful commit
+
+	tag, err := tx.Exec(ctx, `
+        UPDATE tickets
+        SET status = $2, closed_at = NOW(), updated_at = NOW(), waiting_since = NULL,
+            resolution_notes = COALESCE(NULLIF(resolution_notes, ''), $4)
+        WHERE id = $1 AND status = $3 AND waiting_reminders_sent = $5`,
+		t.ticketID, models.StatusClosed, models.StatusWaitingOnCustomer, waitingAutoCloseNote, t.remindersSent)
+	if err != nil {
+		return false, fmt.Errorf("failed to close ticket: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	if err := sla.Sync(ctx, tx, t.ticketID); err != nil {
+		return false, err
+	}
+
+	from, to := string(models.StatusWaitingOnCustomer), string(models.StatusClosed)
+	changes := []models.FieldChange{{Field: "status", From: &from, To: &to, FromDisplay: from, ToDisplay: to}}
+	comment := fmt.Sprintf("Ticket closed automatically after %d unanswered reminder(s). Status changed from '%s' to '%s'.", t.remindersSent, from, to)
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO ticket_history (ticket_id, summary, changes)
+        VALUES ($1, $2, $3)`, t.ticketID, comment, changes); err != nil {
+		return false, fmt.Errorf("failed to record ticket history: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("failed to commit auto-close: %w", err)
+	}
+	j.logger.Info("Closed waiting ticket after unanswered reminders", "ticketNumber", t.ticketNumber, "reminders", t.remindersSent)
+	return true, nil
+}
@@ -0,0 +1,40 @@
+// backend/internal/jobs/webhook_delivery.go
+// ==========================================================================
+// Webhook delivery worker. Periodically delivers queued webhook events from
+// the webhook_deliveries table through webhook.Service.DeliverDue.
+// ==========================================================================
+
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/webhook"
+)
+
+// WebhookDeliveryJob drains the webhook delivery queue on a fixed interval.
+type WebhookDeliveryJob struct {
+	service  *webhook.Service
+	interval time.Duration
+}
+
+// NewWebhookDeliveryJob creates the webhook delivery job.
+//
+// Parameters:
+//   - service: The webhook service to deliver from (*webhook.Service).
+//   - interval: How often to poll for due deliveries.
+//
+// Returns:
+//   - *WebhookDeliveryJob: The configured job.
+func NewWebhookDeliveryJob(service *webhook.Service, interval time.Duration) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{service: service, interval: interval}
+}
+
+// Start launches the job on its configured interval until ctx is cancelled.
+func (j *WebhookDeliveryJob) Start(ctx context.Context) {
+	runPeriodically(ctx, "WebhookDelivery", j.interval, func(ctx context.Context) error {
+		_, _, err := j.service.DeliverDue(ctx)
+		return err
+	})
+}
@@ -0,0 +1,106 @@
+// backend/internal/logging/redact.go
+// ==========================================================================
+// PII redaction for structured logs. RedactAttr is a slog ReplaceAttr hook
+// that masks email addresses and drops free-text ticket fields (description,
+// resolution notes, comments) so debug logging can be enabled in production
+// without copying submitter data into log aggregators.
+// ==========================================================================
+
+package logging
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+)
+
+// Redacted replaces values that are removed entirely.
+const Redacted = "[REDACTED]"
+
+// emailKeys are attribute keys whose values are email addresses; they are
+// masked rather than removed so log lines remain loosely correlatable. Keys
+// are listed in normalized form (see normalizeKey).
+var emailKeys = map[string]bool{
+	"email":          true,
+	"recipient":      true,
+	"enduseremail":   true,
+	"submitteremail": true,
+}
+
+// textKeys are attribute keys holding free text or names supplied by users,
+// in normalized form.
+var textKeys = map[string]bool{
+	"description":     true,
+	"resolution":      true,
+	"resolutionnotes": true,
+	"comment":         true,
+	"commentcontent":  true,
+	"content":         true,
+	"rawbody":         true,
+	"submittername":   true,
+}
+
+// normalizeKey folds an attribute key so "EndUserEmail", "endUserEmail" and
+// "end_user_email" match the same entry.
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+// RedactAttr masks sensitive attributes. Install it as
+// slog.HandlerOptions.ReplaceAttr.
+//
+// Parameters:
+//   - groups: The attribute's enclosing groups (unused).
+//   - a: The attribute being logged.
+//
+// Returns:
+//   - slog.Attr: The attribute, redacted if it is sensitive.
+func RedactAttr(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	key := normalizeKey(a.Key)
+	switch {
+	case emailKeys[key]:
+		if s, ok := a.Value.Any().(string); ok {
+			return slog.String(a.Key, MaskEmail(s))
+		}
+		return slog.String(a.Key, Redacted)
+	case textKeys[key]:
+		return slog.String(a.Key, Redacted)
+	}
+
+	switch v := a.Value.Any().(type) {
+	case models.Ticket:
+		return slog.Attr{Key: a.Key, Value: ticketSummary(&v)}
+	case *models.Ticket:
+		if v != nil {
+			return slog.Attr{Key: a.Key, Value: ticketSummary(v)}
+		}
+	}
+	return a
+}
+
+// MaskEmail keeps the first character of the local part and the domain,
+// e.g. "jane.doe@example.com" becomes "j***@example.com".
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return Redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// ticketSummary logs a ticket's identifying, non-personal fields only.
+func ticketSummary(t *models.Ticket) slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", t.ID),
+		slog.Int("ticket_number", int(t.TicketNumber)),
+		slog.String("status", string(t.Status)),
+		slog.String("urgency", string(t.Urgency)),
+		slog.String("end_user_email", MaskEmail(t.EndUserEmail)),
+	}
+	if t.AssignedToUserID != nil {
+		attrs = append(attrs, slog.String("assigned_to_user_id", *t.AssignedToUserID))
+	}
+	return slog.GroupValue(attrs...)
+}
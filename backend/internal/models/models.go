@@ -8,6 +8,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -29,25 +30,91 @@ type User struct {
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"` // Never expose hash
 	Role         UserRole  `json:"role"`
+	Timezone     *string   `json:"timezone,omitempty"` // IANA timezone preference; nil means UTC
+	Locale       *string   `json:"locale,omitempty"`   // Preferred language (e.g., "es"); nil means English
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// UserSummary is the reduced user view returned to non-Admin callers of the
+// user list (enough for assignee pickers without exposing the directory).
+type UserSummary struct {
+	ID   string   `json:"id"`
+	Name string   `json:"name"`
+	Role UserRole `json:"role"`
+}
+
+// Permissions lists what the current user may do, as enforced by the API.
+// The frontend uses it to enable or hide actions instead of inferring them
+// from the role.
+type Permissions struct {
+	CanManageUsers            bool `json:"can_manage_users"`             // Create users and change other users' details
+	CanDeleteUsers            bool `json:"can_delete_users"`             // Delete users and revoke their sessions
+	CanChangeRoles            bool `json:"can_change_roles"`             // Change a user's role
+	CanManageFAQ              bool `json:"can_manage_faq"`               // Create, edit and delete FAQ entries
+	CanManageTags             bool `json:"can_manage_tags"`              // Create and delete tags
+	CanSeeAllTickets          bool `json:"can_see_all_tickets"`          // Open any ticket, not only assigned or unassigned ones
+	CanCreateInternal         bool `json:"can_create_internal"`          // Create internal tickets and post internal notes
+	CanCloseAnyTicket         bool `json:"can_close_any_ticket"`         // Close tickets assigned to someone else
+	CanEditAnyComment         bool `json:"can_edit_any_comment"`         // Edit or delete other users' comments
+	CanAccessAdmin            bool `json:"can_access_admin"`             // Use the /api/admin endpoints (settings, audit log, quarantine, ...)
+	MyScopeIncludesUnassigned bool `json:"my_scope_includes_unassigned"` // assigned_to=me also lists unassigned tickets
+}
+
+// CurrentUser is the /api/users/me response: the user's profile plus their
+// resolved permissions.
+type CurrentUser struct {
+	User
+	Permissions Permissions `json:"permissions"`
+}
+
 // UserCreate: Used by Admins to create users (requires role)
 type UserCreate struct {
 	Name     string   `json:"name" validate:"required,min=2,max=100"`
 	Email    string   `json:"email" validate:"required,email"`
 	Password string   `json:"password" validate:"required,min=8"`
 	Role     UserRole `json:"role" validate:"required,oneof=Staff Admin User"` // Allow 'User' role creation by admin too
+	Timezone string   `json:"timezone,omitempty"` // Optional IANA timezone (only applied on update)
+	Locale   string   `json:"locale,omitempty"`   // Optional preferred language (only applied on update)
 }
 
 // UserRegister: Used for public self-registration (no role specified, defaults to 'Staff' now)
 type UserRegister struct {
-	Name            string `json:"name" validate:"required,min=2,max=100"`
-	Email           string `json:"email" validate:"required,email"`
-	Password        string `json:"password" validate:"required,min=8"`
+	Name     string `json:"name" validate:"required,min=2,max=100"`
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
 	// *** FIXED: Changed json tag to match frontend ***
 	ConfirmPassword string `json:"confirmPassword" validate:"required,eqfield=Password"`
+	InviteToken     string `json:"inviteToken,omitempty"` // Required when registration is invite-only; sets the account's role
+}
+
+// Invite is an admin-issued registration invite. The raw token is only
+// returned when the invite is created; afterwards only its hash is stored.
+type Invite struct {
+	ID              string     `json:"id"`
+	Email           *string    `json:"email,omitempty"` // If set, only this address may use the invite
+	Role            UserRole   `json:"role"`
+	CreatedByUserID *string    `json:"created_by_user_id,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	UsedAt          *time.Time `json:"used_at,omitempty"`
+	Token           string     `json:"token,omitempty"`            // Raw token (creation response only)
+	RegistrationURL string     `json:"registration_url,omitempty"` // Signup link carrying the token (creation response only)
+}
+
+// InviteCreate: Used by Admins to issue an invite
+type InviteCreate struct {
+	Email string   `json:"email,omitempty"` // Optional; scopes the invite to one address
+	Role  UserRole `json:"role"`
+}
+
+// InviteAccept: Used to create an account from an invite link
+type InviteAccept struct {
+	Token           string `json:"token"`
+	Name            string `json:"name"`
+	Email           string `json:"email,omitempty"` // Required only if the invite isn't scoped to an email
+	Password        string `json:"password"`
+	ConfirmPassword string `json:"confirmPassword"`
 }
 
 type UserLogin struct {
@@ -61,6 +128,17 @@ type Token struct {
 	ExpiresAt   time.Time `json:"expires_at"`
 }
 
+// Session is an active login session (one per issued token).
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  *string   `json:"user_agent,omitempty"`
+	IPAddress  *string   `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // The session making the request
+}
+
 // PasswordResetRequest: Used for the 'forgot password' endpoint
 type PasswordResetRequest struct {
 	Email string `json:"email" validate:"required,email"`
@@ -75,12 +153,13 @@ type PasswordResetPayload struct {
 }
 
 // PasswordResetToken: Represents the structure in the database (used internally)
-// Assumes RAW token is stored in 'token' column based on previous fix.
+// Only the SHA-256 hash of the emailed token is stored.
 type PasswordResetToken struct {
-	Token     string    `db:"token"` // Raw token
-	UserID    string    `db:"user_id"`
-	ExpiresAt time.Time `db:"expires_at"`
-	CreatedAt time.Time `db:"created_at"`
+	TokenHash string     `db:"token_hash"` // Hex SHA-256 of the raw token
+	UserID    string     `db:"user_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	CreatedAt time.Time  `db:"created_at"`
+	UsedAt    *time.Time `db:"used_at"` // Set once the token has reset a password
 }
 
 
@@ -91,11 +170,15 @@ type PasswordResetToken struct {
 type TicketStatus string
 
 const (
-	StatusOpen       TicketStatus = "Open"
-	StatusInProgress TicketStatus = "In Progress"
-	StatusClosed     TicketStatus = "Closed"
+	StatusOpen              TicketStatus = "Open"
+	StatusInProgress        TicketStatus = "In Progress"
+	StatusWaitingOnCustomer TicketStatus = "Waiting on Customer" // Paused until the submitter replies
+	StatusClosed            TicketStatus = "Closed"
 )
 
+// TicketStatuses lists every ticket status, in workflow order.
+var TicketStatuses = []TicketStatus{StatusOpen, StatusInProgress, StatusWaitingOnCustomer, StatusClosed}
+
 type TicketUrgency string
 
 const (
@@ -105,81 +188,225 @@ const (
 	UrgencyCritical TicketUrgency = "Critical"
 )
 
+// UrgencySeverityOrder lists urgencies from least to most severe. Sorting by
+// urgency uses this order, never the alphabetical order of the values.
+var UrgencySeverityOrder = []TicketUrgency{UrgencyLow, UrgencyMedium, UrgencyHigh, UrgencyCritical}
+
 type Ticket struct {
-	ID               string         `json:"id"`
-	TicketNumber     int32          `json:"ticket_number"`
-	SubmitterName    *string        `json:"submitter_name,omitempty"`
-	EndUserEmail     string         `json:"end_user_email"`
-	IssueType        string         `json:"issue_type,omitempty"`
-	Urgency          TicketUrgency  `json:"urgency"`
-	Subject          string         `json:"subject"`
-	Description      string         `json:"description"`
-	Status           TicketStatus   `json:"status"`
-	AssignedToUserID *string        `json:"assigned_to_user_id,omitempty"`
-	AssignedToUser   *User          `json:"assigned_to_user,omitempty"` // Populated by JOIN
-	Submitter        *User          `json:"submitter,omitempty"`       // Populated by JOIN based on email
-	CreatedAt        time.Time      `json:"created_at"`
-	UpdatedAt        time.Time      `json:"updated_at"`
-	ClosedAt         *time.Time     `json:"closed_at,omitempty"`
-	ResolutionNotes  *string        `json:"resolution_notes,omitempty"`
-	Tags             []Tag          `json:"tags,omitempty"`
-	Updates          []TicketUpdate `json:"updates,omitempty"`
-	Attachments      []Attachment   `json:"attachments,omitempty"`
+	ID                 string         `json:"id"`
+	TicketNumber       int32          `json:"ticket_number"`
+	SubmitterName      *string        `json:"submitter_name,omitempty"`
+	EndUserEmail       string         `json:"end_user_email"`
+	IssueType          string         `json:"issue_type,omitempty"`
+	Urgency            TicketUrgency  `json:"urgency"`
+	Subject            string         `json:"subject"`
+	Description        string         `json:"description"`
+	Status             TicketStatus   `json:"status"`
+	AssignedToUserID   *string        `json:"assigned_to_user_id,omitempty"`
+	AssignedToUser     *User          `json:"assigned_to_user,omitempty"` // Populated by JOIN
+	AssignedToTeamID   *string        `json:"assigned_to_team_id,omitempty"`
+	AssignedToTeam     *Team          `json:"assigned_to_team,omitempty"` // Populated by JOIN
+	Submitter          *User          `json:"submitter,omitempty"`        // Populated by JOIN based on email
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	ClosedAt           *time.Time     `json:"closed_at,omitempty"`
+	ResolutionNotes    *string        `json:"resolution_notes,omitempty"`
+	DueDate            *time.Time     `json:"due_date,omitempty"`        // Optional deadline
+	Locale             *string        `json:"locale,omitempty"`          // Submitter's preferred language
+	AssignedAt         *time.Time     `json:"assigned_at,omitempty"`     // When the current assignee was assigned
+	AcceptedAt         *time.Time     `json:"accepted_at,omitempty"`     // When the current assignee accepted the ticket
+	PendingAcceptance  bool           `json:"pending_acceptance"`        // Assigned but not yet accepted (computed)
+	AttachmentsPending bool           `json:"attachments_pending"`       // Submitted files are still being stored
+	SubmitterToken     string         `json:"submitter_token,omitempty"` // Raw status/reply token; only returned at creation
+	IsInternal         bool           `json:"is_internal"`               // Staff-only ticket; the submitter is never emailed
+	QuarantinedAt      *time.Time     `json:"quarantined_at,omitempty"`  // Held by the spam filter pending admin review
+	QuarantineReason   *string        `json:"quarantine_reason,omitempty"` // The spam keyword or pattern that matched
+	SpamAt             *time.Time     `json:"spam_at,omitempty"`           // Marked as spam by an admin (hidden from queues, counts and reports)
+	WaitingSince       *time.Time     `json:"waiting_since,omitempty"`     // When the ticket entered Waiting on Customer (nil otherwise)
+	WaitingReminders   int            `json:"waiting_reminders_sent"`      // Reminder emails sent during the current wait; reset when the submitter replies
+	WaitingRemindedAt  *time.Time     `json:"waiting_last_reminder_at,omitempty"` // When the last of those reminders was sent
+	Tags               []Tag          `json:"tags,omitempty"`
+	Updates            []TicketUpdate `json:"updates,omitempty"`
+	Attachments        []Attachment   `json:"attachments,omitempty"`
+	SLA                *SLAStatus     `json:"sla,omitempty"` // Resolution SLA progress (ticket detail only)
+	SuggestedSolutions []SolutionMatch `json:"suggested_solutions,omitempty"` // Knowledge base matches; only returned at creation
+}
+
+// SLAStatus reports a ticket's progress against its resolution target. Time
+// spent in a pausing status (Waiting on Customer) does not count.
+type SLAStatus struct {
+	TargetSeconds    int64     `json:"target_seconds"`
+	ElapsedSeconds   int64     `json:"elapsed_seconds"`   // Active (unpaused) time so far, or until closing
+	PausedSeconds    int64     `json:"paused_seconds"`    // Total time the clock was stopped
+	RemainingSeconds int64     `json:"remaining_seconds"` // Negative once the target is breached
+	DueAt            time.Time `json:"due_at"`            // Deadline as of now; moves later while paused
+	Paused           bool      `json:"paused"`            // The clock is currently stopped
+	Breached         bool      `json:"breached"`
 }
 
 type TicketCreate struct {
 	SubmitterName *string       `json:"submitter_name,omitempty"`
 	EndUserEmail  string        `json:"end_user_email" validate:"required,email"`
-	IssueType     string        `json:"issue_type" validate:"omitempty"` // Optional
+	IssueType     string        `json:"issue_type" validate:"omitempty"`                            // Optional
 	Urgency       TicketUrgency `json:"urgency" validate:"required,oneof=Low Medium High Critical"`
 	Subject       string        `json:"subject" validate:"required,min=5,max=200"`
 	Description   string        `json:"description" validate:"required"`
-	Tags          []string      `json:"tags,omitempty"` // Tags submitted by name
+	Tags          []string      `json:"tags,omitempty"`                                             // Tags submitted by name
+	Locale        string        `json:"locale,omitempty"`                                           // Optional preferred language (defaults to Accept-Language)
+	CaptchaToken  string        `json:"captcha_token,omitempty"`                                    // Required when CAPTCHA is enabled
+	IsInternal    bool          `json:"is_internal,omitempty"`                                      // Staff-only ticket (requires a Staff/Admin token)
+	DueDate       string        `json:"due_date,omitempty"`                                         // Optional deadline: YYYY-MM-DD (end of that day) or RFC 3339
 }
 
 type TicketUpdate struct {
-	ID             string    `json:"id"`
-	TicketID       string    `json:"ticket_id"`
-	UserID         *string   `json:"user_id,omitempty"`
-	User           *User     `json:"user,omitempty"` // Author of the update
-	Comment        string    `json:"comment"`
-	IsInternalNote bool      `json:"is_internal_note"`
-	IsSystemUpdate bool      `json:"is_system_update,omitempty"`
-	CreatedAt      time.Time `json:"created_at"`
+	ID             string          `json:"id"`
+	TicketID       string          `json:"ticket_id"`
+	UserID         *string         `json:"user_id,omitempty"`
+	User           *User           `json:"user,omitempty"` // Author of the update
+	Comment        string          `json:"comment"`
+	IsInternalNote bool            `json:"is_internal_note"`
+	IsSystemUpdate bool            `json:"is_system_update,omitempty"` // Legacy rows only; system events are now in TicketHistoryEntry
+	FromSubmitter  bool            `json:"from_submitter,omitempty"` // Posted by the submitter via the public status endpoint
+	CreatedAt      time.Time       `json:"created_at"`
+	EditedAt       *time.Time      `json:"edited_at,omitempty"`       // Set once the comment has been edited
+	DeletedAt      *time.Time      `json:"deleted_at,omitempty"`      // Set for deleted (tombstoned) comments; Comment is empty
+	Reactions      []ReactionCount `json:"reactions,omitempty"`       // Aggregated emoji reactions
+	Changes        []FieldChange   `json:"changes,omitempty"`         // Structured field changes recorded with a system update
+	IsPublicReply  bool            `json:"is_public_reply,omitempty"` // Posted with reply_to_submitter
+	EmailedAt      *time.Time      `json:"emailed_at,omitempty"`      // When the public reply was emailed to the submitter
+	Attachments    []Attachment    `json:"attachments,omitempty"`     // Files attached to this comment (also listed on the ticket)
+}
+
+// TicketHistoryEntry is one structured event in a ticket's history (field
+// changes, acceptance, auto-tagging, ...), kept apart from the comment thread.
+type TicketHistoryEntry struct {
+	ID          string        `json:"id"`
+	TicketID    string        `json:"ticket_id"`
+	ActorUserID *string       `json:"actor_user_id,omitempty"` // Nil for system and submitter events
+	ActorName   *string       `json:"actor_name,omitempty"`
+	Summary     string        `json:"summary"` // Human-readable description of the event
+	Changes     []FieldChange `json:"changes,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+// FieldChange is one entry in the structured change set stored with a history
+// entry. From and To hold the raw values (user IDs for the assignee; nil when
+// the field was empty); the display values are what the comment text shows.
+type FieldChange struct {
+	Field       string  `json:"field"` // "status", "assignee", "urgency", "issue_type" or "resolution_notes"
+	From        *string `json:"from"`
+	To          *string `json:"to"`
+	FromDisplay string  `json:"from_display,omitempty"`
+	ToDisplay   string  `json:"to_display,omitempty"`
+}
+
+// ReactionCount is the number of users who reacted to a comment with one emoji.
+type ReactionCount struct {
+	Emoji       string `json:"emoji"`
+	Count       int    `json:"count"`
+	ReactedByMe bool   `json:"reacted_by_me"` // Whether the requesting user is among them
+}
+
+// ReactionToggle is the payload for adding or removing a reaction.
+type ReactionToggle struct {
+	Emoji string `json:"emoji" validate:"required"`
+}
+
+// TicketUpdateRevision is a previous version of an edited or deleted comment.
+type TicketUpdateRevision struct {
+	ID              string    `json:"id"`
+	UpdateID        string    `json:"update_id"`
+	PreviousComment string    `json:"previous_comment"`
+	Action          string    `json:"action"` // "edit" or "delete"
+	ActorUserID     *string   `json:"actor_user_id,omitempty"`
+	ActorName       *string   `json:"actor_name,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
 }
 
 // TicketState: Used internally for checking state before updates
 type TicketState struct {
     Status           TicketStatus
     AssignedToUserID *string
+    AssignedToTeamID *string
     EndUserEmail     string
     Subject          string
     TicketNumber     int32
     ResolutionNotes  *string
+    Locale           string // Submitter's preferred language ("" = default)
+    IsInternal       bool   // Staff-only ticket; submitter emails are suppressed
+    Urgency          TicketUrgency
+    IssueType        *string
+    DueDate          *time.Time
 }
 
 type TicketUpdateCreate struct {
-	Comment        string `json:"content" validate:"required"` // Matches frontend form field name
-	IsInternalNote bool   `json:"is_internal_note"`
+	Comment          string `json:"content" validate:"required"` // Matches frontend form field name
+	IsInternalNote   bool   `json:"is_internal_note"`
+	ReplyToSubmitter bool   `json:"reply_to_submitter"` // Also email the comment to the submitter (staff only; not with is_internal_note)
+}
+
+// PublicTicketStatus is the submitter-facing view of a ticket returned by the
+// public status endpoint. It omits internal notes and staff details.
+type PublicTicketStatus struct {
+	TicketNumber    int32          `json:"ticket_number"`
+	Subject         string         `json:"subject"`
+	Status          TicketStatus   `json:"status"`
+	Urgency         TicketUrgency  `json:"urgency"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	ClosedAt        *time.Time     `json:"closed_at,omitempty"`
+	ResolutionNotes *string        `json:"resolution_notes,omitempty"`
+	AssigneeName    *string        `json:"assignee_name,omitempty"`   // "our support team" when TICKET_SHOW_ASSIGNEE_TO_SUBMITTER is off
+	CanReply        bool           `json:"can_reply"`                 // Whether a reply is accepted (open, or closed within the reopen window)
+	ReopenDeadline  *time.Time     `json:"reopen_deadline,omitempty"` // Last moment a reply reopens a closed ticket
+	Updates         []TicketUpdate `json:"updates"`
+}
+
+// SubmitterCommentCreate is the body accepted by the public reply endpoint.
+type SubmitterCommentCreate struct {
+	Comment string `json:"content"`
+}
+
+// ClosurePreviewRequest is the body of POST /api/tickets/:id/closure-preview.
+type ClosurePreviewRequest struct {
+	ResolutionNotes string `json:"resolution_notes"` // Draft notes; nothing is saved
+}
+
+// EmailPreview is a rendered email that was not sent.
+type EmailPreview struct {
+	Recipient  string `json:"recipient"`
+	Locale     string `json:"locale"`
+	Subject    string `json:"subject"`
+	HTML       string `json:"html"`
+	Suppressed bool   `json:"suppressed"` // The email would not be sent (e.g., internal tickets)
 }
 
 type TicketStatusUpdate struct {
-	Status           TicketStatus `json:"status" validate:"required,oneof=Open In Progress Closed"`
-	AssignedToUserID *string      `json:"assignedToId,omitempty"` // Frontend sends 'assignedToId'
-	ResolutionNotes  *string      `json:"resolution_notes,omitempty"`
+	Status           TicketStatus   `json:"status" validate:"required,oneof=Open 'In Progress' 'Waiting on Customer' Closed"`
+	AssignedToUserID *string        `json:"assignedToId,omitempty"` // Frontend sends 'assignedToId'
+	AssignedToTeamID *string        `json:"assignedToTeamId,omitempty"` // "" removes the team
+	ResolutionNotes  *string        `json:"resolution_notes,omitempty"`
+	Urgency          *TicketUrgency `json:"urgency,omitempty"`
+	IssueType        *string        `json:"issue_type,omitempty"` // "" clears the issue type
+	DueDate          *string        `json:"due_date,omitempty"`   // YYYY-MM-DD (end of that day) or RFC 3339; "" clears the due date
 }
 
 type Attachment struct {
-	ID                string    `json:"id"`
-	TicketID          string    `json:"ticket_id"`
-	Filename          string    `json:"filename"`
-	StoragePath       string    `json:"storage_path"` // Usually internal, might omit from JSON
-	MimeType          string    `json:"mime_type"`
-	Size              int64     `json:"size"`
-	UploadedAt        time.Time `json:"uploaded_at"`
-	URL               string    `json:"url,omitempty"` // Download URL
-	UploadedByUserID  string    `json:"uploaded_by_user_id,omitempty"`
-	UploadedByRole    string    `json:"uploaded_by_role,omitempty"`
+	ID               string     `json:"id"`
+	TicketID         string     `json:"ticket_id"`
+	Filename         string     `json:"filename"`
+	StoragePath      string     `json:"storage_path"` // Usually internal, might omit from JSON
+	MimeType         string     `json:"mime_type"`
+	Size             int64      `json:"size"`
+	UploadedAt       time.Time  `json:"uploaded_at"`
+	URL              string     `json:"url,omitempty"` // Download URL
+	UploadedByUserID string     `json:"uploaded_by_user_id,omitempty"`
+	UploadedByRole   string     `json:"uploaded_by_role,omitempty"`
+	PurgedAt         *time.Time `json:"purged_at,omitempty"`      // Set once the file was removed by the retention policy
+	PreviewStatus    string     `json:"preview_status,omitempty"` // "pending", "ready" or "failed" for office documents
+	PreviewURL       string     `json:"preview_url,omitempty"`    // Inline PDF preview, once ready
+	DisplayOrder     *int       `json:"display_order,omitempty"`  // Staff-defined position; nil sorts after ordered attachments, by upload time
+	UpdateID         *string    `json:"update_id,omitempty"`      // The comment the file was attached to, if any
 }
 
 // ==========================================================================
@@ -187,18 +414,176 @@ type Attachment struct {
 // ==========================================================================
 
 type FAQEntry struct {
-	ID        string    `json:"id"`
-	Question  string    `json:"question"`
-	Answer    string    `json:"answer"`
-	Category  string    `json:"category"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID           string    `json:"id"`
+	Question     string    `json:"question"`
+	Answer       string    `json:"answer"`
+	Category     string    `json:"category"`
+	DisplayOrder int       `json:"display_order"` // Position within the category (see ReorderFAQs)
+	Pinned       bool      `json:"pinned"`        // Pinned entries are listed first in their category
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 }
 
 type FAQCreate struct {
 	Question string `json:"question" validate:"required,min=10"`
 	Answer   string `json:"answer" validate:"required"`
 	Category string `json:"category" validate:"required"`
+	Pinned   *bool  `json:"pinned,omitempty"` // Omitted: false on create, unchanged on update
+}
+
+// FAQOrder is the body of PUT /api/faq/order.
+type FAQOrder struct {
+	Category string   `json:"category"`
+	FAQIDs   []string `json:"faq_ids"` // Every entry of the category, in the desired order
+}
+
+// FAQCategory is a distinct FAQ category with the number of entries in it.
+type FAQCategory struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// FAQRecategorize is the body of POST /api/faq/bulk-recategorize. Moving to a
+// category that does not exist yet renames From.
+type FAQRecategorize struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// FAQRecategorizeResult reports how many entries a bulk recategorization moved.
+type FAQRecategorizeResult struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Updated int64  `json:"updated"`
+}
+
+// ==========================================================================
+// Solution Knowledge Base Models
+// ==========================================================================
+
+// Solution is a known fix, matched against new tickets by issue type and
+// keywords. A nil IssueType applies to every issue type.
+type Solution struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	IssueType *string   `json:"issue_type,omitempty"`
+	Keywords  []string  `json:"keywords"` // Lower-cased
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SolutionCreate is the body for creating or replacing a solution. Keywords
+// default to the words of the title when none are given.
+type SolutionCreate struct {
+	Title     string   `json:"title"`
+	Body      string   `json:"body"`
+	IssueType string   `json:"issue_type,omitempty"` // "" applies to every issue type
+	Keywords  []string `json:"keywords,omitempty"`
+}
+
+// SolutionSearchRequest is the body of POST /api/solutions/search.
+type SolutionSearchRequest struct {
+	IssueType string `json:"issue_type,omitempty"`
+	Text      string `json:"text"`            // Free text to match, e.g. the ticket subject and description
+	Email     string `json:"email,omitempty"` // Submitter email; solutions that helped it before rank first
+}
+
+// SolutionMatch is a solution matched against ticket text.
+type SolutionMatch struct {
+	Solution
+	MatchedKeywords []string `json:"matched_keywords"`
+	HelpedSubmitter bool     `json:"helped_submitter"` // The submitter reported this solution helpful before
+	PreviouslyShown bool     `json:"previously_shown"` // Suggested to the submitter for an earlier ticket
+	HelpfulCount    int      `json:"helpful_count"`    // Helpful reports across all submitters
+}
+
+// SolutionReportEntry summarizes how a solution's suggestions turned out.
+// ClosedAfter counts suggested tickets that have since been closed;
+// TimesEmailed and ClosedAfterEmail do the same for suggestions that were
+// also listed in the confirmation email.
+type SolutionReportEntry struct {
+	SolutionID       string     `json:"solution_id"`
+	Title            string     `json:"title"`
+	IssueType        *string    `json:"issue_type,omitempty"`
+	TimesSuggested   int        `json:"times_suggested"`
+	ClosedAfter      int        `json:"closed_after"`
+	ClosureRate      float64    `json:"closure_rate"` // ClosedAfter / TimesSuggested
+	TimesEmailed     int        `json:"times_emailed"`
+	ClosedAfterEmail int        `json:"closed_after_email"`
+	HelpfulCount     int        `json:"helpful_count"`
+	NotHelpfulCount  int        `json:"not_helpful_count"`
+	LastSuggestedAt  *time.Time `json:"last_suggested_at,omitempty"`
+}
+
+// ==========================================================================
+// Spam Report Models
+// ==========================================================================
+
+// SpamReport summarizes spam volume over a period, to help tune the spam
+// filter (SPAM_FILTER_KEYWORDS / SPAM_FILTER_PATTERNS).
+type SpamReport struct {
+	Since    time.Time          `json:"since"`
+	Interval string             `json:"interval"` // "day" or "week"
+	Total    int                `json:"total"`
+	Buckets  []SpamReportBucket `json:"buckets"`
+	Patterns []SpamPatternCount `json:"patterns"`
+}
+
+// SpamReportBucket counts the tickets marked as spam in one interval.
+// Filter counts tickets the spam filter caught; Manual counts tickets an
+// Admin marked without the filter having matched.
+type SpamReportBucket struct {
+	Start  time.Time `json:"start"`
+	Total  int       `json:"total"`
+	Filter int       `json:"filter"`
+	Manual int       `json:"manual"`
+}
+
+// SpamPatternCount reports how a spam filter pattern performed: Spam counts
+// its catches marked as spam, Approved counts catches released as false
+// positives.
+type SpamPatternCount struct {
+	Pattern  string `json:"pattern"`
+	Spam     int    `json:"spam"`
+	Approved int    `json:"approved"`
+}
+
+// AccessDenialReport summarizes the 403 responses recorded in the audit log
+// over a period, per user and per route.
+type AccessDenialReport struct {
+	Since     time.Time           `json:"since"`
+	Threshold int                 `json:"threshold"` // Denials that flag a user (AUTH_DENIAL_REPORT_THRESHOLD)
+	Total     int                 `json:"total"`
+	Users     []AccessDenialUser  `json:"users"`
+	Routes    []AccessDenialRoute `json:"routes"`
+}
+
+// AccessDenialUser counts one user's denials. Flagged is set once Denials
+// reaches the report threshold, which may indicate probing or a role that is
+// missing a permission.
+type AccessDenialUser struct {
+	UserID      string    `json:"user_id"`
+	Name        *string   `json:"name,omitempty"`  // Nil if the user has since been deleted
+	Email       *string   `json:"email,omitempty"` // Nil if the user has since been deleted
+	Denials     int       `json:"denials"`
+	Routes      int       `json:"routes"` // Distinct routes denied
+	FirstDenied time.Time `json:"first_denied_at"`
+	LastDenied  time.Time `json:"last_denied_at"`
+	Flagged     bool      `json:"flagged"`
+}
+
+// AccessDenialRoute counts the denials on one route.
+type AccessDenialRoute struct {
+	Method  string `json:"method"`
+	Route   string `json:"route"`
+	Denials int    `json:"denials"`
+	Users   int    `json:"users"` // Distinct users denied; anonymous requests are not counted
+}
+
+// SolutionFeedback is a submitter's report on a suggested solution.
+type SolutionFeedback struct {
+	Helpful bool `json:"helpful"`
 }
 
 // ==========================================================================
@@ -211,6 +596,82 @@ type Tag struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ==========================================================================
+// Team Models
+// ==========================================================================
+
+// Team is a group of staff that tickets can be assigned to. Members are only
+// populated by the team endpoints.
+type Team struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	Members   []UserSummary `json:"members,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+// TeamCreate is the body for creating or renaming a team.
+type TeamCreate struct {
+	Name string `json:"name"`
+}
+
+// ==========================================================================
+// Assignment History Models
+// ==========================================================================
+
+// Assignment history events.
+const (
+	AssignmentEventAssigned   = "assigned"
+	AssignmentEventUnassigned = "unassigned"
+	AssignmentEventAccepted   = "accepted"
+	AssignmentEventEscalated  = "escalated"
+)
+
+// AssignmentHistoryEntry records one step in a ticket's assignment lifecycle.
+type AssignmentHistoryEntry struct {
+	ID             string    `json:"id"`
+	TicketID       string    `json:"ticket_id"`
+	Event          string    `json:"event"`
+	AssigneeUserID *string   `json:"assignee_user_id,omitempty"`
+	AssigneeName   *string   `json:"assignee_name,omitempty"`
+	ActorUserID    *string   `json:"actor_user_id,omitempty"`
+	ActorName      *string   `json:"actor_name,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// AssigneeSuggestion ranks a staff member as a possible assignee for a ticket.
+type AssigneeSuggestion struct {
+	UserID          string   `json:"user_id"`
+	Name            string   `json:"name"`
+	Email           string   `json:"email"`
+	Role            UserRole `json:"role"`
+	SimilarResolved int      `json:"similar_resolved"` // Closed tickets they handled with the same issue type or a shared tag
+	OpenTickets     int      `json:"open_tickets"`     // Current workload: their tickets that aren't closed
+}
+
+// AssignmentRule routes new tickets to a user by submitter email domain.
+// Rules are evaluated in ascending Position; the first match wins.
+type AssignmentRule struct {
+	ID             string    `json:"id"`
+	Domain         string    `json:"domain"` // Lowercase; "*.example.com" also matches subdomains
+	AssigneeUserID string    `json:"assignee_user_id"`
+	AssigneeName   string    `json:"assignee_name,omitempty"`
+	Position       int       `json:"position"`
+	Enabled        bool      `json:"enabled"`
+	Description    *string   `json:"description,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// AssignmentRuleInput is the body for creating or replacing an assignment rule.
+type AssignmentRuleInput struct {
+	Domain         string  `json:"domain"`
+	AssigneeUserID string  `json:"assignee_user_id"`
+	Position       int     `json:"position"`
+	Enabled        *bool   `json:"enabled,omitempty"` // Defaults to true
+	Description    *string `json:"description,omitempty"`
+}
+
 // ==========================================================================
 // Notification Models
 // ==========================================================================
@@ -225,22 +686,145 @@ type Notification struct {
 	CreatedAt       time.Time `json:"created_at"`
 }
 
+// NotificationPreferences maps category -> channel -> enabled
+// (e.g. {"assignment": {"email": true, "in_app": false}}).
+type NotificationPreferences map[string]map[string]bool
+
 type NotificationListResponse struct {
 	Success bool           `json:"success"`
 	Data    []Notification `json:"data"`
 	Total   int            `json:"total"`
 }
 
+// ==========================================================================
+// Audit Models
+// ==========================================================================
+
+// AuditLogEntry is a recorded audit event as returned by the admin audit endpoint.
+type AuditLogEntry struct {
+	ID           string                 `json:"id"`
+	Action       string                 `json:"action"`
+	Actor        string                 `json:"actor"` // User ID, or "public" for anonymous requests
+	ActorUserID  *string                `json:"actor_user_id,omitempty"`
+	ResourceType string                 `json:"resource_type"`
+	ResourceID   string                 `json:"resource_id"`
+	IPAddress    string                 `json:"ip_address,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	CreatedAt    time.Time              `json:"created_at"`
+}
+
+// ==========================================================================
+// Query Plan Models
+// ==========================================================================
+
+// ExplainableQuery is an entry in the admin query plan catalog.
+type ExplainableQuery struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+}
+
+// QueryPlan is the EXPLAIN (ANALYZE, BUFFERS) output for a catalog query.
+type QueryPlan struct {
+	ID          string          `json:"id"`
+	Description string          `json:"description"`
+	SQL         string          `json:"sql"`
+	Plan        json.RawMessage `json:"plan"` // PostgreSQL's FORMAT JSON plan
+}
+
+// ==========================================================================
+// Email Outbox Models
+// ==========================================================================
+
+// EmailOutboxEntry is a queued, sent or failed email as shown to admins.
+// The payload is omitted since it can contain sensitive links.
+type EmailOutboxEntry struct {
+	ID            string     `json:"id"`
+	Kind          string     `json:"kind"`
+	Recipient     string     `json:"recipient"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     *string    `json:"last_error,omitempty"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// WebhookDeadLetter is a webhook delivery that exhausted its retries.
+type WebhookDeadLetter struct {
+	ID            string          `json:"id"`
+	EventID       string          `json:"event_id"`
+	EventType     string          `json:"event_type"`
+	TargetURL     string          `json:"target_url"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     *string         `json:"last_error,omitempty"`
+	Status        string          `json:"status"` // "dead" or "redriven"
+	CreatedAt     time.Time       `json:"created_at"`
+	LastAttemptAt time.Time       `json:"last_attempt_at"`
+	RedrivenAt    *time.Time      `json:"redriven_at,omitempty"`
+}
+
+// ==========================================================================
+// Search Models
+// ==========================================================================
+
+// SearchResultType identifies the entity a global search hit belongs to.
+type SearchResultType string
+
+const (
+	SearchTypeTicket SearchResultType = "ticket"
+	SearchTypeFAQ    SearchResultType = "faq"
+)
+
+// SearchResult is a single typed hit returned by the global search endpoint.
+type SearchResult struct {
+	Type      SearchResultType `json:"type"`
+	ID        string           `json:"id"`
+	Title     string           `json:"title"`
+	Snippet   string           `json:"snippet,omitempty"`
+	Score     float64          `json:"score"` // Relevance score in [0, 1]; higher is better
+	Status    string           `json:"status,omitempty"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ==========================================================================
+// Dashboard Models
+// ==========================================================================
+
+// DashboardActivity is a recent comment or system update on one of the user's tickets.
+type DashboardActivity struct {
+	TicketID     string    `json:"ticket_id"`
+	TicketNumber int32     `json:"ticket_number"`
+	Subject      string    `json:"subject"`
+	Comment      string    `json:"comment"`
+	AuthorName   *string   `json:"author_name,omitempty"` // Nil for system/anonymous updates
+	IsSystem     bool      `json:"is_system"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// MyDashboard is the aggregated "my work" view for the authenticated user.
+type MyDashboard struct {
+	AssignedByStatus    map[TicketStatus]int `json:"assigned_by_status"`  // Every status is present, zero if none
+	AssignedOpenTotal   int                  `json:"assigned_open_total"` // Assigned tickets that are not Closed
+	PendingAcceptance   int                  `json:"pending_acceptance"`  // Assigned to me, not yet accepted
+	OverdueAssigned     int                  `json:"overdue_assigned"`    // Assigned to me, not Closed, past the due date
+	TeamUnclaimed       int                  `json:"team_unclaimed"`      // Assigned to one of my teams, not Closed, no individual assignee
+	UnreadNotifications int                  `json:"unread_notifications"`
+	RecentActivity      []DashboardActivity  `json:"recent_activity"`
+}
+
 // ==========================================================================
 // API & Common Models
 // ==========================================================================
 
 // APIResponse is a standard wrapper for single-item API responses.
 type APIResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"` // Can be any type of data
-	Error   string      `json:"error,omitempty"`
+	Success   bool              `json:"success"`
+	Message   string            `json:"message,omitempty"`
+	Data      interface{}       `json:"data,omitempty"` // Can be any type of data
+	Error     string            `json:"error,omitempty"`
+	ErrorCode string            `json:"error_code,omitempty"` // Machine-readable code (see internal/apierror)
+	Fields    map[string]string `json:"fields,omitempty"`     // Per-field validation messages, keyed by JSON field name
 }
 
 // PaginatedResponse is a standard wrapper for list API responses with pagination info.
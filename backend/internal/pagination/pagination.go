@@ -0,0 +1,30 @@
+// backend/internal/pagination/pagination.go
+// ==========================================================================
+// Page size parsing shared by the list endpoints. Oversized limits are
+// clamped to the configured maximum (API_MAX_PAGE_SIZE) rather than
+// rejected, so one request can never pull a whole table.
+// ==========================================================================
+
+package pagination
+
+import "strconv"
+
+// Limit parses a limit query parameter.
+//
+// Parameters:
+//   - raw: The raw parameter value.
+//   - fallback: The limit used when raw is missing or not a positive integer.
+//   - max: The largest limit allowed.
+//
+// Returns:
+//   - int: The limit, never above max.
+func Limit(raw string, fallback, max int) int {
+	limit := fallback
+	if parsed, err := strconv.Atoi(raw); err == nil && parsed > 0 {
+		limit = parsed
+	}
+	if limit > max {
+		return max
+	}
+	return limit
+}
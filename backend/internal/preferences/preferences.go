@@ -0,0 +1,178 @@
+// backend/internal/preferences/preferences.go
+// ==========================================================================
+// Per-user notification preferences. Each user can opt out of a notification
+// category on a channel (email or in-app). Only opt-outs need a row: a missing
+// row means enabled, so users who never touched their preferences keep
+// receiving everything. Lookups fail open -- if preferences cannot be read,
+// the notification is sent.
+// ==========================================================================
+
+package preferences
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+)
+
+// Notification categories.
+const (
+	CategoryAssignment   = "assignment"    // Tickets assigned to (or escalated for) the user
+	CategoryStatusChange = "status_change" // Status changes on the user's tickets (e.g., reopened, closed)
+	CategoryUrgency      = "urgency"       // Urgency changes on the user's tickets
+	CategoryComment      = "comment"       // New comments on the user's tickets
+	CategoryMention      = "mention"       // The user is mentioned in a comment
+	CategoryDigest       = "digest"        // Coalesced digests (otherwise items are sent one by one)
+)
+
+// Notification channels.
+const (
+	ChannelEmail = "email"
+	ChannelInApp = "in_app"
+)
+
+// Categories lists every category, in display order.
+var Categories = []string{CategoryAssignment, CategoryStatusChange, CategoryUrgency, CategoryComment, CategoryMention, CategoryDigest}
+
+// Channels lists every channel.
+var Channels = []string{ChannelEmail, ChannelInApp}
+
+// notificationTypeCategories maps in-app notification types to the category
+// that governs them. Types not listed (e.g., admin alerts) are always delivered.
+var notificationTypeCategories = map[string]string{
+	"status_change":         CategoryStatusChange,
+	"ticket_reopened":       CategoryStatusChange,
+	"urgency_change":        CategoryUrgency,
+	"assignment_escalation": CategoryAssignment,
+	"team_assignment":       CategoryAssignment,
+	"team_claim":            CategoryAssignment,
+}
+
+// InAppOptedOutSQL is a SQL condition that is true when the user identified by
+// the %s expression has turned off in-app notifications for the category in
+// the second %s placeholder. Use with fmt.Sprintf.
+const InAppOptedOutSQL = `EXISTS (SELECT 1 FROM notification_preferences np
+            WHERE np.user_id = %s AND np.category = %s AND np.channel = 'in_app' AND NOT np.enabled)`
+
+// --- Service ---
+
+// Service reads and writes notification preferences.
+type Service struct {
+	db     *db.DB
+	logger *slog.Logger
+}
+
+// NewService creates a preferences Service backed by the given database.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//
+// Returns:
+//   - *Service: The preferences service.
+func NewService(database *db.DB) *Service {
+	return &Service{
+		db:     database,
+		logger: slog.With("service", "NotificationPreferences"),
+	}
+}
+
+// CategoryForNotificationType returns the category governing an in-app
+// notification type, or "" if the type is not user-controllable.
+func CategoryForNotificationType(notifType string) string {
+	return notificationTypeCategories[notifType]
+}
+
+// Valid reports whether category and channel are known.
+func Valid(category, channel string) bool {
+	return contains(Categories, category) && contains(Channels, channel)
+}
+
+// Get returns the user's full preference matrix with defaults applied.
+func (s *Service) Get(ctx context.Context, userID string) (models.NotificationPreferences, error) {
+	prefs := defaults()
+	rows, err := s.db.Pool.Query(ctx, `
+        SELECT category, channel, enabled FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification preferences: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var category, channel string
+		var enabled bool
+		if err := rows.Scan(&category, &channel, &enabled); err != nil {
+			return nil, fmt.Errorf("failed to scan notification preference: %w", err)
+		}
+		if Valid(category, channel) {
+			prefs[category][channel] = enabled
+		}
+	}
+	return prefs, rows.Err()
+}
+
+// Update stores the given (possibly partial) preferences. Entries must be
+// validated with Valid beforehand.
+func (s *Service) Update(ctx context.Context, userID string, prefs models.NotificationPreferences) error {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op after commit
+
+	for category, channels := range prefs {
+		for channel, enabled := range channels {
+			if _, err := tx.Exec(ctx, `
+                INSERT INTO notification_preferences (user_id, category, channel, enabled, updated_at)
+                VALUES ($1, $2, $3, $4, NOW())
+                ON CONFLICT (user_id, category, channel) DO UPDATE SET enabled = EXCLUDED.enabled, updated_at = NOW()`,
+				userID, category, channel, enabled); err != nil {
+				return fmt.Errorf("failed to save notification preference: %w", err)
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// AllowsEmail reports whether email in the category may be sent to the
+// address. Addresses that do not belong to a user (e.g., submitters without
+// an account) are always allowed.
+func (s *Service) AllowsEmail(ctx context.Context, email, category string) bool {
+	var optedOut bool
+	err := s.db.Pool.QueryRow(ctx, `
+        SELECT EXISTS (
+            SELECT 1 FROM notification_preferences np
+            JOIN users u ON u.id = np.user_id
+            WHERE LOWER(u.email) = LOWER($1) AND np.category = $2 AND np.channel = $3 AND NOT np.enabled
+        )`, strings.TrimSpace(email), category, ChannelEmail).Scan(&optedOut)
+	if err != nil {
+		s.logger.Warn("Failed to check email preference; sending anyway", "category", category, "error", err)
+		return true
+	}
+	return !optedOut
+}
+
+// --- Helpers ---
+
+// defaults returns the preference matrix with everything enabled.
+func defaults() models.NotificationPreferences {
+	prefs := make(models.NotificationPreferences, len(Categories))
+	for _, category := range Categories {
+		prefs[category] = make(map[string]bool, len(Channels))
+		for _, channel := range Channels {
+			prefs[category][channel] = true
+		}
+	}
+	return prefs
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
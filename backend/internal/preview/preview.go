@@ -0,0 +1,232 @@
+// backend/internal/preview/preview.go
+// ==========================================================================
+// Office document to PDF conversion for attachment previews. Two converters
+// are supported: a local headless LibreOffice ("soffice --convert-to pdf")
+// and an HTTP conversion API that accepts a multipart upload and responds
+// with the rendered PDF (e.g. Gotenberg's LibreOffice route). When disabled,
+// no document is considered convertible.
+// ==========================================================================
+
+package preview
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+)
+
+// Supported converters.
+const (
+	ConverterLibreOffice = "libreoffice"
+	ConverterHTTP        = "http"
+)
+
+// Preview states stored on attachments.preview_status.
+const (
+	StatusPending = "pending"
+	StatusReady   = "ready"
+	StatusFailed  = "failed"
+)
+
+// maxPreviewSize caps the size of a rendered PDF read back from a converter.
+const maxPreviewSize = 50 * 1024 * 1024 // 50 MB
+
+// officeExtensions lists the document formats that get a PDF preview.
+var officeExtensions = map[string]bool{
+	".doc": true, ".docx": true, ".odt": true, ".rtf": true,
+	".xls": true, ".xlsx": true, ".ods": true,
+	".ppt": true, ".pptx": true, ".odp": true,
+}
+
+// ErrDisabled is returned by Convert when previews are turned off.
+var ErrDisabled = errors.New("attachment previews are disabled")
+
+// --- Service Interface ---
+
+// Service renders office documents to PDF.
+type Service interface {
+	// Supports reports whether a file with this name gets a preview.
+	Supports(filename string) bool
+	// Convert renders the document to PDF, bounded by the configured timeout.
+	Convert(ctx context.Context, filename string, content io.Reader) ([]byte, error)
+}
+
+// --- Implementations ---
+
+// disabledService converts nothing; used when previews are turned off.
+type disabledService struct{}
+
+func (disabledService) Supports(string) bool { return false }
+func (disabledService) Convert(context.Context, string, io.Reader) ([]byte, error) {
+	return nil, ErrDisabled
+}
+
+// libreOfficeService shells out to a headless soffice binary.
+type libreOfficeService struct {
+	command string
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// httpService posts documents to a conversion API.
+type httpService struct {
+	url     string
+	timeout time.Duration
+	client  *http.Client
+	logger  *slog.Logger
+}
+
+// --- Constructor ---
+
+// NewService creates a preview Service from configuration.
+//
+// Parameters:
+//   - cfg: The preview configuration (config.PreviewConfig).
+//
+// Returns:
+//   - Service: A converting service, or one that supports nothing when disabled.
+//   - error: If the converter is unknown.
+func NewService(cfg config.PreviewConfig) (Service, error) {
+	if !cfg.Enabled {
+		slog.Info("Attachment previews disabled")
+		return disabledService{}, nil
+	}
+
+	converter := strings.ToLower(strings.TrimSpace(cfg.Converter))
+	slog.Info("Attachment previews enabled", "converter", converter, "timeout", cfg.Timeout)
+	switch converter {
+	case ConverterLibreOffice:
+		return &libreOfficeService{
+			command: cfg.Command,
+			timeout: cfg.Timeout,
+			logger:  slog.With("service", "PreviewService", "converter", converter),
+		}, nil
+	case ConverterHTTP:
+		return &httpService{
+			url:     cfg.URL,
+			timeout: cfg.Timeout,
+			client:  &http.Client{Timeout: cfg.Timeout},
+			logger:  slog.With("service", "PreviewService", "converter", converter),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported attachment preview converter %q", cfg.Converter)
+	}
+}
+
+// IsOfficeDocument reports whether the filename has an office document extension.
+func IsOfficeDocument(filename string) bool {
+	return officeExtensions[strings.ToLower(filepath.Ext(filename))]
+}
+
+// Supports reports whether the file is an office document.
+func (s *libreOfficeService) Supports(filename string) bool { return IsOfficeDocument(filename) }
+
+// Convert writes the document to a scratch directory and runs
+// "soffice --headless --convert-to pdf" on it.
+func (s *libreOfficeService) Convert(ctx context.Context, filename string, content io.Reader) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	workDir, err := os.MkdirTemp("", "attachment-preview-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scratch directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	// A fixed base name keeps user-supplied filenames off the command line.
+	inputPath := filepath.Join(workDir, "document"+strings.ToLower(filepath.Ext(filename)))
+	if err := writeFile(inputPath, content); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, s.command,
+		"--headless", "--norestore", "--convert-to", "pdf", "--outdir", workDir,
+		// A per-run profile lets conversions run in parallel.
+		"-env:UserInstallation=file://"+filepath.Join(workDir, "profile"),
+		inputPath)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		s.logger.WarnContext(ctx, "soffice conversion failed", "error", err, "output", strings.TrimSpace(string(output)))
+		return nil, fmt.Errorf("soffice conversion failed: %w", err)
+	}
+
+	pdf, err := os.ReadFile(filepath.Join(workDir, "document.pdf"))
+	if err != nil {
+		return nil, fmt.Errorf("soffice produced no PDF: %w", err)
+	}
+	return pdf, nil
+}
+
+// Supports reports whether the file is an office document.
+func (s *httpService) Supports(filename string) bool { return IsOfficeDocument(filename) }
+
+// Convert uploads the document as the multipart field "files" and returns the
+// PDF in the response body.
+func (s *httpService) Convert(ctx context.Context, filename string, content io.Reader) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("files", "document"+strings.ToLower(filepath.Ext(filename)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build conversion request: %w", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to build conversion request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build conversion request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Conversion request failed", "error", err)
+		return nil, fmt.Errorf("conversion request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("converter returned status %d", resp.StatusCode)
+	}
+
+	pdf, err := io.ReadAll(io.LimitReader(resp.Body, maxPreviewSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read converted PDF: %w", err)
+	}
+	if len(pdf) > maxPreviewSize {
+		return nil, fmt.Errorf("converted PDF exceeds %d MB", maxPreviewSize/(1024*1024))
+	}
+	return pdf, nil
+}
+
+// --- Helpers ---
+
+// writeFile copies content to a new file at path.
+func writeFile(path string, content io.Reader) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create scratch file: %w", err)
+	}
+	if _, err := io.Copy(f, content); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write scratch file: %w", err)
+	}
+	return f.Close()
+}
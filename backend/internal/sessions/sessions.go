@@ -0,0 +1,177 @@
+// backend/internal/sessions/sessions.go
+// ==========================================================================
+// Login session tracking. Every login creates a session row whose ID is
+// embedded in the issued JWT; the JWT middleware checks that the session is
+// still active on each request, so users and admins can list sessions and
+// revoke them before the token expires.
+// ==========================================================================
+
+package sessions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// maxUserAgentLength caps the stored user-agent string.
+const maxUserAgentLength = 512
+
+// touchInterval limits how often last_used_at is written for a session.
+const touchInterval = time.Minute
+
+// Service creates, checks and revokes login sessions.
+type Service struct {
+	db          *db.DB
+	idleTimeout time.Duration // Sessions unused for longer are rejected; 0 disables
+	logger      *slog.Logger
+}
+
+// NewService creates a sessions Service backed by the given database.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - idleTimeout: How long a session may go unused; 0 means no idle limit.
+//
+// Returns:
+//   - *Service: The sessions service.
+func NewService(database *db.DB, idleTimeout time.Duration) *Service {
+	return &Service{
+		db:          database,
+		idleTimeout: idleTimeout,
+		logger:      slog.With("service", "Sessions"),
+	}
+}
+
+// Create records a new session for a login.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - userID: The user logging in.
+//   - userAgent: The client's User-Agent header.
+//   - ipAddress: The client's IP address.
+//   - expiresAt: When the session's token expires.
+//
+// Returns:
+//   - string: The session ID, to embed in the token.
+//   - error: If the insert fails.
+func (s *Service) Create(ctx context.Context, userID, userAgent, ipAddress string, expiresAt time.Time) (string, error) {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	var id string
+	if err := s.db.Pool.QueryRow(ctx, `
+        INSERT INTO user_sessions (user_id, user_agent, ip_address, expires_at)
+        VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4)
+        RETURNING id`, userID, userAgent, ipAddress, expiresAt).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to create session: %w", err)
+	}
+	return id, nil
+}
+
+// Active reports whether the session exists for the user and is neither
+// revoked, expired nor idle for longer than the idle timeout. Active sessions
+// have their last-used time refreshed (at most once per touchInterval).
+func (s *Service) Active(ctx context.Context, sessionID, userID string) (bool, error) {
+	var idleCutoff *time.Time
+	if s.idleTimeout > 0 {
+		cutoff := time.Now().Add(-s.idleTimeout)
+		idleCutoff = &cutoff
+	}
+	var active bool
+	err := s.db.Pool.QueryRow(ctx, `
+        WITH session AS (
+            SELECT id, last_used_at FROM user_sessions
+            WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+              AND ($3::timestamptz IS NULL OR last_used_at > $3)
+        ), touched AS (
+            UPDATE user_sessions u SET last_used_at = NOW()
+            FROM session WHERE u.id = session.id AND session.last_used_at < $4
+        )
+        SELECT EXISTS (SELECT 1 FROM session)`,
+		sessionID, userID, idleCutoff, time.Now().Add(-touchInterval)).Scan(&active)
+	if err != nil {
+		return false, fmt.Errorf("failed to check session: %w", err)
+	}
+	return active, nil
+}
+
+// List returns the user's active sessions, most recently used first.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - userID: The user whose sessions to list.
+//   - currentID: The requesting session's ID, flagged as current ("" for none).
+//
+// Returns:
+//   - []models.Session: The active sessions.
+//   - error: If the query fails.
+func (s *Service) List(ctx context.Context, userID, currentID string) ([]models.Session, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+        SELECT id, user_agent, ip_address, created_at, last_used_at, expires_at
+        FROM user_sessions
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+        ORDER BY last_used_at DESC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sessions: %w", err)
+	}
+	defer rows.Close()
+
+	list := make([]models.Session, 0)
+	for rows.Next() {
+		var session models.Session
+		if err := rows.Scan(&session.ID, &session.UserAgent, &session.IPAddress, &session.CreatedAt, &session.LastUsedAt, &session.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		if s.idleTimeout > 0 && time.Since(session.LastUsedAt) > s.idleTimeout {
+			continue // Idle sessions can no longer be used
+		}
+		session.Current = session.ID == currentID
+		list = append(list, session)
+	}
+	return list, rows.Err()
+}
+
+// Revoke ends one of the user's sessions. It reports false if the session
+// does not belong to the user or was already revoked.
+func (s *Service) Revoke(ctx context.Context, userID, sessionID string) (bool, error) {
+	tag, err := s.db.Pool.Exec(ctx, `
+        UPDATE user_sessions SET revoked_at = NOW()
+        WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`, sessionID, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// CurrentRole returns the user's role as stored in the database, or an empty
+// role if the user no longer exists.
+func (s *Service) CurrentRole(ctx context.Context, userID string) (models.UserRole, error) {
+	var role models.UserRole
+	err := s.db.Pool.QueryRow(ctx, `SELECT role FROM users WHERE id = $1`, userID).Scan(&role)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up user role: %w", err)
+	}
+	return role, nil
+}
+
+// RevokeAll ends all of the user's sessions and returns how many were active.
+func (s *Service) RevokeAll(ctx context.Context, userID string) (int64, error) {
+	tag, err := s.db.Pool.Exec(ctx, `
+        UPDATE user_sessions SET revoked_at = NOW()
+        WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()`, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	s.logger.Info("Revoked all sessions for user", "userID", userID, "count", tag.RowsAffected())
+	return tag.RowsAffected(), nil
+}
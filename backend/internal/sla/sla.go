@@ -0,0 +1,139 @@
+// backend/internal/sla/sla.go
+// ==========================================================================
+// SLA clock accounting. While a ticket is in a pausing status (Waiting on
+// Customer, or Closed until it is reopened) its SLA clock is stopped; each
+// stop is recorded as an interval in ticket_sla_pauses. Elapsed SLA time is the ticket's age minus its paused
+// intervals, so tickets that bounce between waiting and active states are
+// measured accurately.
+// ==========================================================================
+
+package sla
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PausingStatuses are the ticket statuses in which the SLA clock is stopped.
+// Closed is included so that the time a ticket spent closed before being
+// reopened does not count against it.
+var PausingStatuses = []models.TicketStatus{models.StatusWaitingOnCustomer, models.StatusClosed}
+
+// conn is satisfied by both the connection pool and a transaction.
+type conn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Sync opens or closes the ticket's SLA pause to match its current status.
+// Call it in the same transaction as any status change: it closes the open
+// pause when the ticket left a pausing status and opens one when it entered
+// one. Calling it without a status change is a no-op.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - c: The pool or the transaction that changed the status.
+//   - ticketID: The ticket whose status may have changed.
+//
+// Returns:
+//   - error: If either statement fails.
+func Sync(ctx context.Context, c conn, ticketID string) error {
+	statuses := pausingStatusNames()
+	if _, err := c.Exec(ctx, `
+        UPDATE ticket_sla_pauses p SET resumed_at = NOW()
+        FROM tickets t
+        WHERE p.ticket_id = t.id AND t.id = $1 AND p.resumed_at IS NULL
+          AND NOT (t.status = ANY($2))`, ticketID, statuses); err != nil {
+		return fmt.Errorf("failed to resume SLA clock: %w", err)
+	}
+	if _, err := c.Exec(ctx, `
+        INSERT INTO ticket_sla_pauses (ticket_id, status, paused_at)
+        SELECT id, status, NOW() FROM tickets
+        WHERE id = $1 AND status = ANY($2)
+        ON CONFLICT (ticket_id) WHERE resumed_at IS NULL DO NOTHING`, ticketID, statuses); err != nil {
+		return fmt.Errorf("failed to pause SLA clock: %w", err)
+	}
+	return nil
+}
+
+// Target returns the resolution target for an urgency (0 = no SLA).
+func Target(cfg config.SLAConfig, urgency models.TicketUrgency) time.Duration {
+	switch urgency {
+	case models.UrgencyLow:
+		return cfg.ResolutionLow
+	case models.UrgencyMedium:
+		return cfg.ResolutionMedium
+	case models.UrgencyHigh:
+		return cfg.ResolutionHigh
+	case models.UrgencyCritical:
+		return cfg.ResolutionCritical
+	}
+	return 0
+}
+
+// Compute measures a ticket against its resolution target. The clock runs
+// from creation until the ticket closes (or now), minus paused intervals.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - c: The pool or a transaction.
+//   - cfg: The configured resolution targets.
+//   - ticket: The ticket (ID, Urgency, CreatedAt and ClosedAt are used).
+//   - now: The current time.
+//
+// Returns:
+//   - *models.SLAStatus: The ticket's SLA status, or nil if its urgency has no target.
+//   - error: If the paused intervals cannot be loaded.
+func Compute(ctx context.Context, c conn, cfg config.SLAConfig, ticket *models.Ticket, now time.Time) (*models.SLAStatus, error) {
+	target := Target(cfg, ticket.Urgency)
+	if target <= 0 {
+		return nil, nil
+	}
+	end := now
+	if ticket.ClosedAt != nil {
+		end = *ticket.ClosedAt
+	}
+
+	var pausedSeconds float64
+	var paused bool
+	err := c.QueryRow(ctx, `
+        SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (LEAST(COALESCE(resumed_at, $2), $2) - paused_at))), 0)::float8,
+               COALESCE(BOOL_OR(resumed_at IS NULL), FALSE)
+        FROM ticket_sla_pauses
+        WHERE ticket_id = $1 AND paused_at < $2`, ticket.ID, end,
+	).Scan(&pausedSeconds, &paused)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SLA pauses: %w", err)
+	}
+
+	pausedFor := time.Duration(pausedSeconds * float64(time.Second))
+	elapsed := end.Sub(ticket.CreatedAt) - pausedFor
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	remaining := target - elapsed
+	return &models.SLAStatus{
+		TargetSeconds:    int64(target / time.Second),
+		ElapsedSeconds:   int64(elapsed / time.Second),
+		PausedSeconds:    int64(pausedFor / time.Second),
+		RemainingSeconds: int64(remaining / time.Second),
+		DueAt:            ticket.CreatedAt.Add(target + pausedFor),
+		Paused:           paused && ticket.ClosedAt == nil,
+		Breached:         remaining < 0,
+	}, nil
+}
+
+// pausingStatusNames returns PausingStatuses as strings for an ANY($n) parameter.
+func pausingStatusNames() []string {
+	names := make([]string, len(PausingStatuses))
+	for i, status := range PausingStatuses {
+		names[i] = string(status)
+	}
+	return names
+}
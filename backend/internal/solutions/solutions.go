@@ -0,0 +1,240 @@
+// backend/internal/solutions/solutions.go
+// ==========================================================================
+// Solution knowledge base matching. Ticket text is reduced to a set of
+// keywords, and solutions are matched by issue type and keyword overlap.
+// Effectiveness is tracked per submitter email: each suggestion is recorded
+// against its ticket and each helpful / not helpful report is counted.
+// Solutions that helped an address before are ranked first when it matches
+// them again, followed by solutions already shown to it.
+// ==========================================================================
+
+package solutions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// minKeywordLength is the shortest word kept as a keyword.
+const minKeywordLength = 3
+
+// stopWords are common words that never count as keywords.
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "your": true, "all": true, "any": true, "can": true, "cannot": true,
+	"was": true, "were": true, "has": true, "have": true, "had": true, "its": true,
+	"this": true, "that": true, "with": true, "from": true, "into": true, "when": true,
+	"what": true, "how": true, "why": true, "who": true, "our": true, "out": true,
+	"get": true, "got": true, "does": true, "doesn": true, "don": true, "did": true,
+	"there": true, "their": true, "then": true, "than": true, "them": true, "they": true,
+	"will": true, "would": true, "could": true, "should": true, "just": true, "also": true,
+	"some": true, "please": true, "help": true, "thanks": true, "hello": true, "issue": true,
+	"problem": true, "working": true, "work": true, "since": true, "after": true, "before": true,
+}
+
+// conn is satisfied by both the connection pool and a transaction.
+type conn interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Keywords extracts the distinct lower-cased keywords of text, in order of
+// first appearance. Words shorter than three characters and stop words are
+// dropped.
+//
+// Parameters:
+//   - text: Free text, e.g. a ticket subject and description.
+//
+// Returns:
+//   - []string: The keywords (never nil, so it binds as an empty array).
+func Keywords(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return normalize(words, true)
+}
+
+// NormalizeKeywords trims, lower-cases and de-duplicates admin-supplied
+// keywords. Unlike Keywords, stop words and short words are kept, since an
+// admin chose them deliberately.
+func NormalizeKeywords(keywords []string) []string {
+	return normalize(keywords, false)
+}
+
+func normalize(words []string, filter bool) []string {
+	seen := make(map[string]bool, len(words))
+	keywords := make([]string, 0, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] {
+			continue
+		}
+		if filter && (len([]rune(word)) < minKeywordLength || stopWords[word]) {
+			continue
+		}
+		seen[word] = true
+		keywords = append(keywords, word)
+	}
+	return keywords
+}
+
+// Match finds the solutions that share at least one keyword with text and
+// apply to issueType. Ranking: solutions the submitter reported helpful
+// before, then solutions previously shown to the submitter, then the number
+// of shared keywords, then an exact issue type match, then helpful reports
+// across all submitters.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - c: The pool or a transaction.
+//   - issueType: The ticket's issue type ("" matches solutions of any type).
+//   - text: The text to match.
+//   - email: The submitter email ("" skips per-submitter ranking).
+//   - limit: The maximum number of matches.
+//
+// Returns:
+//   - []models.SolutionMatch: The matches, best first (never nil).
+//   - error: If the query fails.
+func Match(ctx context.Context, c conn, issueType, text, email string, limit int) ([]models.SolutionMatch, error) {
+	matches := make([]models.SolutionMatch, 0)
+	keywords := Keywords(text)
+	if len(keywords) == 0 || limit <= 0 {
+		return matches, nil
+	}
+	issueType = strings.TrimSpace(issueType)
+	rows, err := c.Query(ctx, `
+        SELECT id, title, body, issue_type, keywords, created_at, updated_at,
+               matched, helped_submitter, previously_shown, helpful_count
+        FROM (
+            SELECT s.*,
+                   ARRAY(SELECT unnest(s.keywords) INTERSECT SELECT unnest($2::text[])) AS matched,
+                   COALESCE(e.helpful_count, 0) > 0 AS helped_submitter,
+                   EXISTS (SELECT 1 FROM solution_suggestions ss
+                           WHERE ss.solution_id = s.id AND ss.email = $3) AS previously_shown,
+                   COALESCE((SELECT SUM(helpful_count) FROM solution_effectiveness se
+                             WHERE se.solution_id = s.id), 0)::int AS helpful_count
+            FROM solutions s
+            LEFT JOIN solution_effectiveness e ON e.solution_id = s.id AND e.email = $3
+            WHERE s.keywords && $2::text[]
+              AND (s.issue_type IS NULL OR $1 = '' OR LOWER(s.issue_type) = LOWER($1))
+        ) m
+        ORDER BY CASE WHEN helped_submitter THEN 0 WHEN previously_shown THEN 1 ELSE 2 END,
+                 cardinality(matched) DESC,
+                 (issue_type IS NOT NULL AND LOWER(issue_type) = LOWER($1)) DESC,
+                 helpful_count DESC, title
+        LIMIT $4`, issueType, keywords, normalizeEmail(email), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to match solutions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var m models.SolutionMatch
+		if err := rows.Scan(
+			&m.ID, &m.Title, &m.Body, &m.IssueType, &m.Keywords, &m.CreatedAt, &m.UpdatedAt,
+			&m.MatchedKeywords, &m.HelpedSubmitter, &m.PreviouslyShown, &m.HelpfulCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan solution match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read solution matches: %w", err)
+	}
+	return matches, nil
+}
+
+// RecordShown records that the matches were suggested to email for a ticket.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - c: The pool or a transaction.
+//   - ticketID: The ticket the suggestions were made for.
+//   - email: The submitter email.
+//   - matches: The suggested solutions.
+//
+// Returns:
+//   - error: If either statement fails.
+func RecordShown(ctx context.Context, c conn, ticketID, email string, matches []models.SolutionMatch) error {
+	email = normalizeEmail(email)
+	if email == "" || len(matches) == 0 {
+		return nil
+	}
+	ids := make([]string, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ID
+	}
+	if _, err := c.Exec(ctx, `
+        INSERT INTO solution_effectiveness (solution_id, email, shown_count, last_shown_at)
+        SELECT unnest($1::uuid[]), $2, 1, NOW()
+        ON CONFLICT (solution_id, email) DO UPDATE
+        SET shown_count = solution_effectiveness.shown_count + 1, last_shown_at = NOW()`,
+		ids, email); err != nil {
+		return fmt.Errorf("failed to record suggested solutions: %w", err)
+	}
+	if _, err := c.Exec(ctx, `
+        INSERT INTO solution_suggestions (solution_id, ticket_id, email)
+        SELECT unnest($1::uuid[]), $2, $3
+        ON CONFLICT (solution_id, ticket_id) DO NOTHING`, ids, ticketID, email); err != nil {
+		return fmt.Errorf("failed to record solution suggestions: %w", err)
+	}
+	return nil
+}
+
+// RecordEmailed records that a ticket's suggestions were included in the
+// submitter's confirmation email.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - c: The pool or a transaction.
+//   - ticketID: The ticket the suggestions were made for.
+//   - solutionIDs: The solutions listed in the email.
+//
+// Returns:
+//   - error: If the update fails.
+func RecordEmailed(ctx context.Context, c conn, ticketID string, solutionIDs []string) error {
+	if len(solutionIDs) == 0 {
+		return nil
+	}
+	if _, err := c.Exec(ctx, `
+        UPDATE solution_suggestions SET emailed_at = NOW()
+        WHERE ticket_id = $1 AND solution_id = ANY($2::uuid[])`, ticketID, solutionIDs); err != nil {
+		return fmt.Errorf("failed to record emailed solution suggestions: %w", err)
+	}
+	return nil
+}
+
+// RecordFeedback counts a submitter's report on a solution that was suggested
+// to them.
+//
+// Parameters:
+//   - ctx: Request context.
+//   - c: The pool or a transaction.
+//   - solutionID: The solution reported on.
+//   - email: The submitter email.
+//   - helpful: Whether the solution helped.
+//
+// Returns:
+//   - bool: False if the solution was never suggested to email.
+//   - error: If the update fails.
+func RecordFeedback(ctx context.Context, c conn, solutionID, email string, helpful bool) (bool, error) {
+	tag, err := c.Exec(ctx, `
+        UPDATE solution_effectiveness
+        SET helpful_count = helpful_count + CASE WHEN $3 THEN 1 ELSE 0 END,
+            not_helpful_count = not_helpful_count + CASE WHEN $3 THEN 0 ELSE 1 END,
+            last_feedback_at = NOW()
+        WHERE solution_id = $1 AND email = $2`, solutionID, normalizeEmail(email), helpful)
+	if err != nil {
+		return false, fmt.Errorf("failed to record solution feedback: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
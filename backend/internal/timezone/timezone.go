@@ -0,0 +1,146 @@
+// backend/internal/timezone/timezone.go
+// ==========================================================================
+// Helpers for interpreting dates in a caller's timezone. All timestamps are
+// stored in UTC; this package only shifts boundary math (day starts, date
+// filters, due-date bucket edges) into the requester's location.
+// ==========================================================================
+
+package timezone
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/henrythedeveloper/it-ticket-system/internal/api/middleware/auth"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderName is the request header clients use to announce their IANA timezone.
+const HeaderName = "X-Timezone"
+
+// Due-date bucket names accepted by the ticket list's due filter.
+const (
+	BucketOverdue = "overdue"
+	BucketToday   = "today"
+	BucketWeek    = "week"
+	BucketLater   = "later"
+	BucketNone    = "none"
+)
+
+// dateLayout is the plain calendar-date format accepted for date filters.
+const dateLayout = "2006-01-02"
+
+// --- Location Resolution ---
+
+// Validate reports whether name is a loadable IANA timezone name (e.g., "America/Chicago").
+func Validate(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("timezone is empty")
+	}
+	if _, err := time.LoadLocation(name); err != nil {
+		return fmt.Errorf("unknown timezone %q", name)
+	}
+	return nil
+}
+
+// Resolve picks the location to use for a request. The explicit header value
+// wins over the stored user preference; anything invalid falls back to UTC.
+//
+// Parameters:
+//   - header: The X-Timezone header value (may be empty).
+//   - preference: The user's saved timezone preference (may be nil/empty).
+//
+// Returns:
+//   - *time.Location: The resolved location (never nil).
+func Resolve(header string, preference *string) *time.Location {
+	candidates := []string{strings.TrimSpace(header)}
+	if preference != nil {
+		candidates = append(candidates, strings.TrimSpace(*preference))
+	}
+	for _, name := range candidates {
+		if name == "" {
+			continue
+		}
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// PreferenceStore loads users' saved timezone preferences (implemented by *db.DB).
+type PreferenceStore interface {
+	UserTimezone(ctx context.Context, userID string) (*string, error)
+}
+
+// RequestLocation resolves the location used for date math on a request: a
+// valid X-Timezone header, else the authenticated user's saved preference
+// (also when the header names an unknown zone), else UTC.
+//
+// Parameters:
+//   - c: The echo context of the request.
+//   - prefs: The store holding saved preferences.
+//
+// Returns:
+//   - *time.Location: The resolved location (never nil).
+func RequestLocation(c echo.Context, prefs PreferenceStore) *time.Location {
+	if header := strings.TrimSpace(c.Request().Header.Get(HeaderName)); header != "" {
+		if loc, err := time.LoadLocation(header); err == nil {
+			return loc
+		}
+	}
+	userID := auth.OptionalUserID(c)
+	if userID == "" {
+		return time.UTC
+	}
+	preference, err := prefs.UserTimezone(c.Request().Context(), userID)
+	if err != nil {
+		slog.Debug("Could not load timezone preference, using UTC", "userID", userID, "error", err)
+		return time.UTC
+	}
+	return Resolve("", preference)
+}
+
+// --- Boundary Math ---
+
+// StartOfDay returns midnight of t's calendar day in loc.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	local := t.In(loc)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+}
+
+// ParseBoundary parses a date filter value. Plain dates (YYYY-MM-DD) are
+// interpreted as calendar days in loc: the start of the day for lower bounds,
+// or the start of the following day for upper bounds (use with "<").
+// RFC 3339 timestamps already carry an offset; as upper bounds they are moved
+// one microsecond (the database's resolution) later so "<" still includes the
+// instant itself.
+//
+// Parameters:
+//   - value: The raw filter value.
+//   - loc: The requester's location.
+//   - upper: True when the value is an inclusive upper bound (e.g., to_date).
+//
+// Returns:
+//   - time.Time: The boundary instant in UTC.
+//   - error: If the value matches neither accepted format.
+func ParseBoundary(value string, loc *time.Location, upper bool) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		if upper {
+			t = t.Truncate(time.Microsecond).Add(time.Microsecond)
+		}
+		return t.UTC(), nil
+	}
+	day, err := time.ParseInLocation(dateLayout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected YYYY-MM-DD or RFC 3339", value)
+	}
+	if upper {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day.UTC(), nil
+}
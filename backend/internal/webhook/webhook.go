@@ -0,0 +1,368 @@
+// backend/internal/webhook/webhook.go
+// ==========================================================================
+// Outbound webhooks for ticket events. Dispatch queues one delivery per
+// configured URL in the webhook_deliveries table; DeliverDue (run by a
+// background worker) POSTs due deliveries and retries failures on the
+// configured backoff schedule. Deliveries that exhaust their retries move to
+// webhook_dead_letters, where admins can inspect and re-drive them.
+// ==========================================================================
+
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/henrythedeveloper/it-ticket-system/internal/config"
+	"github.com/henrythedeveloper/it-ticket-system/internal/db"
+	"github.com/henrythedeveloper/it-ticket-system/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+// Event types delivered to receivers.
+const (
+	EventTicketCreated = "ticket.created"
+	EventTicketUpdated = "ticket.updated"
+	EventTicketSpike   = "tickets.spike" // Ticket creation exceeded the spike alert threshold
+)
+
+// Request headers sent with every delivery.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-ID"
+	HeaderSignature = "X-Webhook-Signature" // "sha256=<hex HMAC of the body>", when a secret is configured
+)
+
+// Dead letter statuses.
+const (
+	DeadLetterStatusDead     = "dead"
+	DeadLetterStatusRedriven = "redriven"
+)
+
+const (
+	// enqueueTimeout bounds the queue insert performed by Dispatch.
+	enqueueTimeout = 5 * time.Second
+	// deliveryBatchSize is the maximum deliveries attempted per worker run.
+	deliveryBatchSize = 20
+	// claimLease hides a claimed delivery from other workers while it is in flight.
+	claimLease = 2 * time.Minute
+	// maxErrorBodyBytes limits how much of a failed response is kept as the error.
+	maxErrorBodyBytes = 512
+)
+
+// Errors returned by Redrive.
+var (
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	ErrAlreadyRedriven    = errors.New("dead letter has already been re-driven")
+)
+
+// --- Types ---
+
+// Envelope is the JSON body POSTed to receivers.
+type Envelope struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// delivery is a claimed webhook_deliveries row.
+type delivery struct {
+	id        string
+	eventID   string
+	eventType string
+	targetURL string
+	payload   []byte
+	attempts  int
+}
+
+// --- Service ---
+
+// Service dispatches webhook events and manages dead letters.
+type Service struct {
+	db     *db.DB
+	cfg    config.WebhookConfig
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewService creates the webhook service.
+//
+// Parameters:
+//   - database: The database connection pool (*db.DB).
+//   - cfg: The webhook configuration (config.WebhookConfig).
+//
+// Returns:
+//   - *Service: The webhook service. Dispatch is a no-op when webhooks are disabled.
+func NewService(database *db.DB, cfg config.WebhookConfig) *Service {
+	return &Service{
+		db:     database,
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		logger: slog.With("service", "WebhookService"),
+	}
+}
+
+// Enabled reports whether events are delivered.
+func (s *Service) Enabled() bool {
+	return s != nil && s.cfg.Enabled && len(s.cfg.URLs) > 0
+}
+
+// Dispatch queues an event for delivery to every configured URL. It never
+// blocks the caller on the network and never returns an error; queue failures
+// are logged.
+//
+// Parameters:
+//   - eventType: The event type (e.g., EventTicketCreated).
+//   - data: The event payload; it must be JSON-serializable.
+func (s *Service) Dispatch(eventType string, data interface{}) {
+	if !s.Enabled() {
+		return
+	}
+	envelope := Envelope{ID: uuid.NewString(), Type: eventType, OccurredAt: time.Now().UTC(), Data: data}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		s.logger.Error("Failed to encode webhook event", "eventType", eventType, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), enqueueTimeout)
+	defer cancel()
+	for _, targetURL := range s.cfg.URLs {
+		if _, err := s.db.Pool.Exec(ctx, `
+            INSERT INTO webhook_deliveries (event_id, event_type, target_url, payload) VALUES ($1, $2, $3, $4)`,
+			envelope.ID, eventType, targetURL, payload); err != nil {
+			s.logger.Error("Failed to queue webhook delivery", "eventType", eventType, "eventID", envelope.ID, "targetURL", targetURL, "error", err)
+		}
+	}
+	s.logger.Debug("Webhook event queued", "eventType", eventType, "eventID", envelope.ID, "targets", len(s.cfg.URLs))
+}
+
+// --- Delivery ---
+
+// DeliverDue claims due deliveries and POSTs each one. Successful deliveries
+// are removed; failures are rescheduled, or moved to the dead-letter table
+// once MaxRetries retries have been used.
+//
+// Returns:
+//   - int: Number of deliveries that succeeded.
+//   - int: Number of failed attempts in this run.
+//   - error: If claiming deliveries fails.
+func (s *Service) DeliverDue(ctx context.Context) (int, int, error) {
+	deliveries, err := s.claimDue(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sent, failed := 0, 0
+	for _, d := range deliveries {
+		postErr := s.post(ctx, d.targetURL, d.eventType, d.eventID, d.payload)
+		if postErr == nil {
+			sent++
+			if _, err := s.db.Pool.Exec(ctx, `DELETE FROM webhook_deliveries WHERE id = $1`, d.id); err != nil {
+				s.logger.Error("Failed to remove delivered webhook", "deliveryID", d.id, "error", err)
+			}
+			continue
+		}
+		failed++
+		s.recordFailure(ctx, d, postErr)
+	}
+	if len(deliveries) > 0 {
+		s.logger.Info("Webhook delivery run complete", "claimed", len(deliveries), "sent", sent, "failed", failed)
+	}
+	return sent, failed, nil
+}
+
+// claimDue leases due deliveries so concurrent workers never send the same one twice.
+func (s *Service) claimDue(ctx context.Context) ([]delivery, error) {
+	rows, err := s.db.Pool.Query(ctx, `
+        UPDATE webhook_deliveries SET next_attempt_at = NOW() + make_interval(secs => $2)
+        WHERE id IN (
+            SELECT id FROM webhook_deliveries
+            WHERE next_attempt_at <= NOW()
+            ORDER BY next_attempt_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        )
+        RETURNING id, event_id, event_type, target_url, payload, attempts`,
+		deliveryBatchSize, claimLease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]delivery, 0)
+	for rows.Next() {
+		var d delivery
+		if err := rows.Scan(&d.id, &d.eventID, &d.eventType, &d.targetURL, &d.payload, &d.attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// recordFailure reschedules a failed delivery, or dead-letters it when its retries are used up.
+func (s *Service) recordFailure(ctx context.Context, d delivery, postErr error) {
+	attempts := d.attempts + 1
+	if attempts > s.cfg.MaxRetries {
+		s.logger.Warn("Webhook delivery exhausted retries; moving to dead letters",
+			"deliveryID", d.id, "eventType", d.eventType, "targetURL", d.targetURL, "attempts", attempts, "error", postErr)
+		_, err := s.db.Pool.Exec(ctx, `
+            WITH moved AS (
+                DELETE FROM webhook_deliveries WHERE id = $1
+                RETURNING event_id, event_type, target_url, payload
+            )
+            INSERT INTO webhook_dead_letters (event_id, event_type, target_url, payload, attempts, last_error)
+            SELECT event_id, event_type, target_url, payload, $2, $3 FROM moved`,
+			d.id, attempts, postErr.Error())
+		if err != nil {
+			s.logger.Error("Failed to dead-letter webhook delivery", "deliveryID", d.id, "error", err)
+		}
+		return
+	}
+
+	delay := s.backoff(attempts)
+	s.logger.Warn("Webhook delivery attempt failed",
+		"deliveryID", d.id, "eventType", d.eventType, "targetURL", d.targetURL, "attempt", attempts, "retryIn", delay, "error", postErr)
+	_, err := s.db.Pool.Exec(ctx, `
+        UPDATE webhook_deliveries
+        SET attempts = $2, last_error = $3, next_attempt_at = NOW() + make_interval(secs => $4)
+        WHERE id = $1`, d.id, attempts, postErr.Error(), delay.Seconds())
+	if err != nil {
+		s.logger.Error("Failed to record webhook delivery failure", "deliveryID", d.id, "error", err)
+	}
+}
+
+// backoff returns the delay before the given retry (1-based). The last
+// configured value repeats once the schedule runs out.
+func (s *Service) backoff(retry int) time.Duration {
+	if len(s.cfg.Backoff) == 0 {
+		return 0
+	}
+	if retry > len(s.cfg.Backoff) {
+		retry = len(s.cfg.Backoff)
+	}
+	return s.cfg.Backoff[retry-1]
+}
+
+// post sends one webhook request. Any non-2xx response is a failure.
+func (s *Service) post(ctx context.Context, targetURL, eventType, eventID string, payload []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, eventType)
+	req.Header.Set(HeaderEventID, eventID)
+	if s.cfg.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+		mac.Write(payload)
+		req.Header.Set(HeaderSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("webhook receiver returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	return nil
+}
+
+// --- Dead Letters ---
+
+// ListDeadLetters returns dead letters (newest first) and the total match count.
+//
+// Parameters:
+//   - status: Optional status filter (DeadLetterStatusDead or DeadLetterStatusRedriven).
+//   - limit, offset: Pagination.
+func (s *Service) ListDeadLetters(ctx context.Context, status string, limit, offset int) ([]models.WebhookDeadLetter, int, error) {
+	var total int
+	if err := s.db.Pool.QueryRow(ctx, `
+        SELECT COUNT(*) FROM webhook_dead_letters WHERE ($1 = '' OR status = $1)`, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook dead letters: %w", err)
+	}
+
+	rows, err := s.db.Pool.Query(ctx, `
+        SELECT id, event_id, event_type, target_url, payload, attempts, last_error, status,
+               created_at, last_attempt_at, redriven_at
+        FROM webhook_dead_letters
+        WHERE ($1 = '' OR status = $1)
+        ORDER BY created_at DESC
+        LIMIT $2 OFFSET $3`, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query webhook dead letters: %w", err)
+	}
+	defer rows.Close()
+
+	letters := make([]models.WebhookDeadLetter, 0)
+	for rows.Next() {
+		var letter models.WebhookDeadLetter
+		if err := rows.Scan(
+			&letter.ID, &letter.EventID, &letter.EventType, &letter.TargetURL, &letter.Payload,
+			&letter.Attempts, &letter.LastError, &letter.Status,
+			&letter.CreatedAt, &letter.LastAttemptAt, &letter.RedrivenAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+	return letters, total, rows.Err()
+}
+
+// Redrive queues a dead letter for delivery again with a fresh retry budget
+// and marks it re-driven. It works even while dispatch is disabled, since it
+// is an explicit admin action against the letter's original target URL.
+//
+// Returns:
+//   - error: ErrDeadLetterNotFound, ErrAlreadyRedriven, or a database error.
+func (s *Service) Redrive(ctx context.Context, id string) error {
+	tx, err := s.db.Pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx) // No-op after commit
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM webhook_dead_letters WHERE id = $1 FOR UPDATE`, id).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDeadLetterNotFound
+		}
+		return fmt.Errorf("failed to load webhook dead letter: %w", err)
+	}
+	if status == DeadLetterStatusRedriven {
+		return ErrAlreadyRedriven
+	}
+
+	if _, err := tx.Exec(ctx, `
+        INSERT INTO webhook_deliveries (event_id, event_type, target_url, payload)
+        SELECT event_id, event_type, target_url, payload FROM webhook_dead_letters WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("failed to re-queue webhook delivery: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+        UPDATE webhook_dead_letters SET status = $2, redriven_at = NOW() WHERE id = $1`,
+		id, DeadLetterStatusRedriven); err != nil {
+		return fmt.Errorf("failed to mark webhook dead letter re-driven: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit re-drive: %w", err)
+	}
+	s.logger.Info("Webhook dead letter re-driven", "deadLetterID", id)
+	return nil
+}
@@ -6,13 +6,13 @@
 // ==========================================================================
 
 import React, { useState } from 'react';
-import { useNavigate } from 'react-router-dom';
+import { useNavigate, useSearchParams } from 'react-router-dom';
 import Input from '../common/Input';
 import Button from '../common/Button';
 import Alert from '../common/Alert';
 import { useFormSubmit } from '../../hooks/useFormSubmit';
 import { UserRegister, User } from '../../types'; // Import types
-import { register as registerService } from '../../services/authService'; // API service call
+import { register as registerService, acceptInvite } from '../../services/authService'; // API service calls
 
 // --- Component ---
 
@@ -22,6 +22,7 @@ import { register as registerService } from '../../services/authService'; // API
 const RegisterForm: React.FC = () => {
   // --- Hooks ---
   const navigate = useNavigate();
+  const [searchParams] = useSearchParams();
 
   // --- State ---
   const [formData, setFormData] = useState<UserRegister>({
@@ -29,6 +30,7 @@ const RegisterForm: React.FC = () => {
     email: '',
     password: '',
     confirmPassword: '',
+    inviteToken: searchParams.get('invite') ?? undefined, // Set when arriving from an invite link
   });
   // Local state to show success message *before* redirecting
   const [showSuccess, setShowSuccess] = useState<boolean>(false);
@@ -41,7 +43,8 @@ const RegisterForm: React.FC = () => {
     clearError,
     // successMessage from hook options is not used directly here
   } = useFormSubmit<UserRegister, User>( // Expects UserRegister, returns User
-    registerService,
+    // Invite links create the account through accept-invite, which works even when self-registration is off
+    (data) => (data.inviteToken ? acceptInvite(data) : registerService(data)),
     {
       onSuccess: (newUser) => {
         console.log('Registration successful:', newUser);
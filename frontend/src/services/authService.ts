@@ -108,6 +108,33 @@ export const register = async (registrationData: UserRegister): Promise<User> =>
     }
 };
 
+/**
+ * Creates an account from an invite link. The invite decides the role (and the
+ * email, if it was issued for one).
+ * @param registrationData - Name, email, password and the invite token.
+ * @returns A Promise resolving with the newly created User object.
+ */
+export const acceptInvite = async (registrationData: UserRegister): Promise<User> => {
+    try {
+        const response = await api.post<APIResponse<any>>('/auth/accept-invite', {
+            token: registrationData.inviteToken,
+            name: registrationData.name,
+            email: registrationData.email,
+            password: registrationData.password,
+            confirmPassword: registrationData.confirmPassword,
+        });
+
+        if (!response.data?.success || !response.data.data) {
+            throw new Error(response.data?.message || "Registration failed.");
+        }
+        return keysToCamel<User>(response.data.data);
+    } catch (error: any) {
+        const errorMessage = error.response?.data?.message || error.message || 'Registration failed.';
+        console.error('Accept invite API error:', { message: errorMessage, error });
+        throw new Error(errorMessage);
+    }
+};
+
 /**
  * Sends a request to the API to initiate the password reset process for an email.
  * @param resetRequest - Object containing the user's email.
@@ -12,6 +12,21 @@ export interface User {
   role: 'Admin' | 'Staff' | 'User';
   createdAt: string;
   updatedAt: string;
+  permissions?: Permissions; // Only returned by /users/me
+}
+// Resolved server-side from the user's role; use these instead of role checks.
+export interface Permissions {
+  canManageUsers: boolean;
+  canDeleteUsers: boolean;
+  canChangeRoles: boolean;
+  canManageFaq: boolean;
+  canManageTags: boolean;
+  canSeeAllTickets: boolean;
+  canCreateInternal: boolean;
+  canCloseAnyTicket: boolean;
+  canEditAnyComment: boolean;
+  canAccessAdmin: boolean;
+  myScopeIncludesUnassigned: boolean;
 }
 export interface AuthState {
   user: User | null;
@@ -32,6 +47,15 @@ export interface Tag {
   createdAt: string;
 }
 
+// --- Team ---
+export interface Team {
+  id: string;
+  name: string;
+  members?: Pick<User, 'id' | 'name' | 'role'>[];
+  createdAt: string;
+  updatedAt: string;
+}
+
 // --- Ticket ---
 export type TicketStatus = 'Open' | 'In Progress' | 'Closed';
 export type TicketUrgency = 'Low' | 'Medium' | 'High' | 'Critical';
@@ -45,6 +69,18 @@ export interface TicketUpdate {
   createdAt: string;
   isInternalNote?: boolean;
   isSystemUpdate?: boolean;
+  fromSubmitter?: boolean;
+  attachments?: TicketAttachment[]; // Files attached to this comment
+}
+
+export interface TicketHistoryEntry {
+  id: string;
+  ticketId: string;
+  actorUserId?: string | null;
+  actorName?: string | null;
+  summary: string;
+  changes?: { field: string; from: string | null; to: string | null }[];
+  createdAt: string;
 }
 
 export interface TicketAttachment {
@@ -57,6 +93,7 @@ export interface TicketAttachment {
   storagePath?: string;
   uploadedByUserId?: string;
   uploadedByRole?: string;
+  updateId?: string | null; // Set for files attached to a comment
 }
 
 export interface Ticket {
@@ -73,12 +110,59 @@ export interface Ticket {
   submitter?: Pick<User, 'id' | 'name' | 'email'> | null;
   assignedToUserId?: string | null;
   assignedTo?: Pick<User, 'id' | 'name'> | null;
+  assignedToTeamId?: string | null;
+  assignedToTeam?: Pick<Team, 'id' | 'name'> | null; // Unclaimed while assignedToUserId is empty
   createdAt: string;
   updatedAt: string;
   closedAt?: string | null;
   resolutionNotes?: string | null;
+  dueDate?: string | null;
+  isInternal?: boolean;
+  waitingSince?: string | null; // Set while the ticket is Waiting on Customer
+  waitingRemindersSent?: number; // Reminders sent during the current wait
+  waitingLastReminderAt?: string | null;
   updates?: TicketUpdate[];
   attachments?: TicketAttachment[];
+  sla?: SLAStatus | null; // Only on ticket detail; absent when the urgency has no SLA target
+  suggestedSolutions?: SolutionMatch[]; // Only in the ticket creation response
+}
+
+// --- Solution Knowledge Base ---
+export interface Solution {
+  id: string;
+  title: string;
+  body: string;
+  issueType?: string | null; // Absent for solutions that apply to every issue type
+  keywords: string[];
+  createdAt: string;
+  updatedAt: string;
+}
+
+export interface SolutionMatch extends Solution {
+  matchedKeywords: string[];
+  helpedSubmitter: boolean;
+  previouslyShown: boolean;
+  helpfulCount: number;
+}
+
+// Resolution SLA progress. Time spent waiting on the customer doesn't count.
+export interface SLAStatus {
+  targetSeconds: number;
+  elapsedSeconds: number;
+  pausedSeconds: number;
+  remainingSeconds: number; // Negative once breached
+  dueAt: string;
+  paused: boolean;
+  breached: boolean;
+}
+
+// Closure email rendered for draft resolution notes (POST /tickets/:id/closure-preview).
+export interface EmailPreview {
+  recipient: string;
+  locale: string;
+  subject: string;
+  html: string;
+  suppressed: boolean; // Internal tickets never email the submitter
 }
 
 // --- Ticket Context Types ---
@@ -174,6 +258,7 @@ export interface APIResponse<T> {
 	message?: string;
 	data?: T;
 	error?: string;
+	error_code?: string; // Machine-readable error code (e.g., 'TICKET_NOT_FOUND')
 }
 export interface PaginatedResponse<T> {
   data: T[];
@@ -197,6 +282,7 @@ export interface UserRegister {
     email: string;
     password?: string; // Optional for update, required for create
     confirmPassword?: string; // Only for frontend validation
+    inviteToken?: string; // From the ?invite= link; sets the role, required when registration is invite-only
 }
 
 export interface PasswordResetRequest {